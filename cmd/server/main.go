@@ -27,15 +27,22 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
 	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/lifecycle"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
 	"github.com/wso2/identity-customer-data-service/internal/system/managers"
-	_ "github.com/wso2/identity-customer-data-service/internal/system/queue/activemq" // registers the ActiveMQ queue provider
+	_ "github.com/wso2/identity-customer-data-service/internal/system/queue/activemq"    // registers the ActiveMQ queue provider
+	_ "github.com/wso2/identity-customer-data-service/internal/system/queue/kafka"       // registers the Kafka queue provider
+	_ "github.com/wso2/identity-customer-data-service/internal/system/queue/kafka/franz" // registers the Kafka client adapter
+	_ "github.com/wso2/identity-customer-data-service/internal/system/queue/postgres"    // registers the PostgreSQL queue provider
 	"github.com/wso2/identity-customer-data-service/internal/system/utils"
 	"github.com/wso2/identity-customer-data-service/internal/system/workers"
 )
@@ -88,6 +95,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Consent receipts name the deployment's PII controller, which has no sensible default
+	if missing := cdsConfig.ConsentReceipt.MissingFields(); len(missing) > 0 {
+		log.GetLogger().Warn(fmt.Sprintf("Consent receipts are not fully configured. Set consent_receipt.%s in "+
+			"deployment.yaml, or the receipts issued for consent changes will leave them empty.",
+			strings.Join(missing, ", consent_receipt.")))
+	}
+
 	// Initialize database
 	initDatabaseFromConfig(cdsConfig)
 
@@ -103,13 +117,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Take over schema migrations left unfinished by instances that stopped
+	schemaService.ResumeSchemaMigrations()
+
 	// Initialize Cookie Cleanup worker
 	if cdsConfig.Cleanup.Cookie.Enabled {
 		workers.StartCookieCleanupWorker(cdsConfig.Cleanup.Cookie)
 	}
 
+	// Initialize Schema Drift worker
+	if cdsConfig.Sync.Schema.Enabled {
+		workers.StartSchemaDriftWorker(cdsConfig.Sync.Schema)
+	}
+
 	serverAddr := fmt.Sprintf("%s:%d", cdsConfig.Addr.Host, cdsConfig.Addr.Port)
-	mux := enableCORS(initMultiplexer())
+	mux := enableCORS(lifecycle.RejectWritesWhileDraining(initMultiplexer()))
 
 	logger := log.GetLogger()
 	logger.Info(fmt.Sprintf("WSO2 CDS starting securely on: https://%s", serverAddr))
@@ -173,23 +195,40 @@ func main() {
 
 	// Block until a signal is received
 	<-quit
-	logger.Info("Shutdown signal received, draining connections...")
-
-	// Give in-flight requests up to 15 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	logger.Info("Shutdown signal received, draining...")
+
+	// Refuse new writes and report not ready, then keep serving for the pre-stop delay, so that the load balancer
+	// sees the pod as not ready and stops routing to it before the listener closes. In-flight requests and queued
+	// work are then given until the drain timeout to complete. Queued work left after it is persisted and resumed
+	// by the next start.
+	lifecycle.BeginDrain()
+	preStopDelay := time.Duration(cdsConfig.Shutdown.PreStopDelay) * time.Millisecond
+	if cdsConfig.Shutdown.PreStopDelay == 0 {
+		preStopDelay = constants.DefaultShutdownPreStopDelay * time.Millisecond
+	}
+	if preStopDelay > 0 {
+		logger.Info(fmt.Sprintf("Waiting %s for the load balancer to stop routing to this instance", preStopDelay))
+		time.Sleep(preStopDelay)
+	}
+	drainTimeout := time.Duration(cdsConfig.Shutdown.DrainTimeout) * time.Millisecond
+	if drainTimeout <= 0 {
+		drainTimeout = constants.DefaultShutdownDrainTimeout * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("HTTP server shutdown error.", log.Error(err))
 	}
-	if err := workers.StopProfileWorker(); err != nil {
-		logger.Error("Failed to stop profile worker.", log.Error(err))
+	if err := workers.DrainProfileWorker(ctx); err != nil {
+		logger.Error("Failed to drain profile worker.", log.Error(err))
 	}
-	if err := workers.StopSchemaSyncWorker(); err != nil {
-		logger.Error("Failed to stop schema sync worker.", log.Error(err))
+	if err := workers.DrainSchemaSyncWorker(ctx); err != nil {
+		logger.Error("Failed to drain schema sync worker.", log.Error(err))
 	}
 
 	workers.StopCookieCleanupWorker()
+	workers.StopSchemaDriftWorker()
 
 	logger.Info("Shutdown complete")
 }
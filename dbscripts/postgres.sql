@@ -29,7 +29,8 @@ CREATE TABLE profiles
     list_profile        BOOLEAN DEFAULT TRUE,
     delete_profile      BOOLEAN DEFAULT FALSE,
     traits              JSONB   DEFAULT '{}'::jsonb,
-    identity_attributes JSONB   DEFAULT '{}'::jsonb
+    identity_attributes JSONB   DEFAULT '{}'::jsonb,
+    attribute_metadata  JSONB   DEFAULT '{}'::jsonb
 );
 
 CREATE TABLE profile_reference
@@ -56,7 +57,31 @@ CREATE TABLE profile_schema
     multi_valued           BOOLEAN DEFAULT FALSE,
     canonical_values       JSONB   DEFAULT '[]'::jsonb,
     sub_attributes         JSONB   DEFAULT '[]'::jsonb,
-    scim_dialect VARCHAR(255)
+    scim_dialect VARCHAR(255),
+    expression             TEXT    NOT NULL DEFAULT '',
+    constraints            JSONB   NOT NULL DEFAULT '{}'::jsonb
+);
+
+CREATE TABLE profile_schema_migrations
+(
+    migration_id           VARCHAR(255) NOT NULL PRIMARY KEY,
+    org_handle             VARCHAR(255) NOT NULL,
+    attribute_id           VARCHAR(255) NOT NULL,
+    attribute_name         VARCHAR(255) NOT NULL,
+    application_identifier VARCHAR(255) NOT NULL DEFAULT '',
+    operation              VARCHAR(50)  NOT NULL DEFAULT 'convert',
+    source_definition      JSONB        NOT NULL,
+    target_definition      JSONB        NOT NULL,
+    status                 VARCHAR(50)  NOT NULL,
+    total_profiles         INT          NOT NULL DEFAULT 0,
+    processed_profiles     INT          NOT NULL DEFAULT 0,
+    converted_values       INT          NOT NULL DEFAULT 0,
+    failed_values          INT          NOT NULL DEFAULT 0,
+    failures               JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    error                  TEXT         NOT NULL DEFAULT '',
+    owner                  VARCHAR(255) NOT NULL DEFAULT '',
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT now()
 );
 
 CREATE TABLE unification_rules
@@ -92,38 +117,64 @@ CREATE TABLE applications
     UNIQUE (org_handle, client_id)
 );
 
+CREATE TABLE application_purposes
+(
+    org_handle     VARCHAR(255) NOT NULL,
+    app_identifier VARCHAR(255) NOT NULL,
+    purposes       TEXT[]       NOT NULL DEFAULT '{}',
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_handle, app_identifier)
+);
+
 CREATE TABLE consent_categories
 (
     id                  SERIAL PRIMARY KEY,
     org_handle           VARCHAR(255)        NOT NULL,
     category_name       VARCHAR(255)        NOT NULL,
-    category_identifier VARCHAR(255) UNIQUE NOT NULL,
+    category_identifier VARCHAR(255)        NOT NULL,
     purpose             VARCHAR(255)        NOT NULL,
     destinations        TEXT[],
-    is_mandatory        BOOLEAN             NOT NULL DEFAULT FALSE
+    is_mandatory        BOOLEAN             NOT NULL DEFAULT FALSE,
+    localizations       JSONB               NOT NULL DEFAULT '{}',
+    UNIQUE (org_handle, category_identifier),
+    UNIQUE (org_handle, category_name)
 );
 
 CREATE TABLE consent_category_attributes
 (
     id                     SERIAL PRIMARY KEY,
-    category_id            VARCHAR(255) REFERENCES consent_categories (category_identifier) ON DELETE CASCADE,
+    org_handle             VARCHAR(255) NOT NULL,
+    category_id            VARCHAR(255) NOT NULL,
     scope                  VARCHAR(50)  NOT NULL,
     attribute_name         VARCHAR(255) NOT NULL,
     attribute_id           VARCHAR(255) REFERENCES profile_schema (attribute_id) ON DELETE CASCADE,
     application_identifier VARCHAR(255) NOT NULL DEFAULT '',
-    UNIQUE (category_id, scope, attribute_name, application_identifier)
+    FOREIGN KEY (org_handle, category_id) REFERENCES consent_categories (org_handle, category_identifier) ON DELETE CASCADE,
+    UNIQUE (org_handle, category_id, scope, attribute_name, application_identifier)
 );
 
 CREATE TABLE profile_consents
 (
     id      SERIAL PRIMARY KEY,
     profile_id       VARCHAR(255) REFERENCES profiles (profile_id) ON DELETE CASCADE,
-    category_id      VARCHAR (255) REFERENCES consent_categories (category_identifier) ON DELETE CASCADE,
+    org_handle       VARCHAR(255) NOT NULL,
+    category_id      VARCHAR(255) NOT NULL,
     consent_status   BOOLEAN     NOT NULL,
     consented_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    receipt_id       VARCHAR(255),
+    FOREIGN KEY (org_handle, category_id) REFERENCES consent_categories (org_handle, category_identifier) ON DELETE CASCADE,
     UNIQUE (profile_id, category_id)
 );
 
+CREATE TABLE profile_consent_receipts
+(
+    receipt_id  VARCHAR(255) PRIMARY KEY,
+    profile_id  VARCHAR(255) NOT NULL REFERENCES profiles (profile_id) ON DELETE CASCADE,
+    org_handle  VARCHAR(255) NOT NULL,
+    receipt     JSONB        NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
 CREATE TABLE profile_cookies (
     cookie_id VARCHAR (255) PRIMARY KEY,
     profile_id VARCHAR (255) NOT NULL REFERENCES profiles (profile_id) ON DELETE CASCADE,
@@ -142,6 +193,64 @@ CREATE TABLE cds_config (
     PRIMARY KEY (org_handle, config)
 );
 
+-- Jobs of the "postgres" message queue provider. A job is claimed by moving available_at past the lease; it is
+-- deleted once processed and kept with dead_lettered_at set once its attempts are exhausted.
+CREATE TABLE queue_jobs
+(
+    job_id           BIGSERIAL PRIMARY KEY,
+    queue_name       VARCHAR(255) NOT NULL,
+    payload          JSONB        NOT NULL,
+    attempts         INT          NOT NULL DEFAULT 0,
+    last_error       TEXT         NOT NULL DEFAULT '',
+    available_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    dead_lettered_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- Profiles to unify, written in the same transaction as the profile write. The outbox relay publishes them to the
+-- configured message queue and deletes them once published.
+CREATE TABLE unification_outbox
+(
+    outbox_id  BIGSERIAL PRIMARY KEY,
+    profile_id VARCHAR(255) NOT NULL,
+    org_handle VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- Schema sync jobs that were still queued in memory when the server shut down. They are enqueued again, and
+-- deleted, when the server starts.
+CREATE TABLE schema_sync_backlog
+(
+    backlog_id BIGSERIAL PRIMARY KEY,
+    payload    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Background jobs, such as schema syncs and the periodic cookie cleanup, with their outcome. Jobs of the whole
+-- deployment belong to the root organization.
+CREATE TABLE jobs
+(
+    job_id      VARCHAR(255) NOT NULL PRIMARY KEY,
+    job_type    VARCHAR(100) NOT NULL,
+    org_handle  VARCHAR(255) NOT NULL,
+    status      VARCHAR(50)  NOT NULL,
+    progress    INT          NOT NULL DEFAULT 0,
+    result      JSONB,
+    error       TEXT         NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    started_at  TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- The next run of each periodic job. An instance runs a periodic job only if it moves next_run_at forward, so that
+-- each run happens on one instance.
+CREATE TABLE job_schedules
+(
+    job_type    VARCHAR(100) NOT NULL PRIMARY KEY,
+    next_run_at TIMESTAMPTZ  NOT NULL
+);
+
 -- ================================
 -- PROFILES (Hot path: tenant + cursor pagination + ordering)
 -- ================================
@@ -209,6 +318,9 @@ CREATE INDEX IF NOT EXISTS idx_profile_schema_org_scope
 CREATE INDEX IF NOT EXISTS idx_profile_schema_org_attr_name
     ON profile_schema (org_handle, attribute_name);
 
+CREATE INDEX IF NOT EXISTS idx_profile_schema_migrations_org_attr
+    ON profile_schema_migrations (org_handle, attribute_id);
+
 
 -- ================================
 -- UNIFICATION_RULES
@@ -218,3 +330,26 @@ CREATE INDEX IF NOT EXISTS idx_unification_rules_org_active_priority
 
 CREATE INDEX IF NOT EXISTS idx_unification_rules_property_id
     ON unification_rules (property_id);
+
+
+-- ================================
+-- QUEUE_JOBS (Claim polling)
+-- ================================
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_claim
+    ON queue_jobs (queue_name, available_at) WHERE dead_lettered_at IS NULL;
+
+
+-- ================================
+-- UNIFICATION_OUTBOX (Debounce lookups)
+-- ================================
+-- The relay checks each profile for entries younger than the debounce window
+-- and for entries older than the maximum wait
+CREATE INDEX IF NOT EXISTS idx_unification_outbox_profile_created
+    ON unification_outbox (profile_id, created_at);
+
+
+-- ================================
+-- JOBS (Listing per organization)
+-- ================================
+CREATE INDEX IF NOT EXISTS idx_jobs_org_created
+    ON jobs (org_handle, created_at);
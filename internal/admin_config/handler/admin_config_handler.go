@@ -59,6 +59,7 @@ func (h *AdminConfigHandler) GetAdminConfig(w http.ResponseWriter, r *http.Reque
 	resp := model.AdminConfigAPI{
 		CDSEnabled:         config.CDSEnabled,
 		SystemApplications: config.SystemApplications,
+		SourcePriority:     config.SourcePriority,
 	}
 	utils.RespondJSON(w, http.StatusOK, resp, constants.AdminConfigResource)
 }
@@ -98,6 +99,7 @@ func (h *AdminConfigHandler) UpdateAdminConfig(w http.ResponseWriter, r *http.Re
 		InitialSchemaSyncDone: existingConfig.InitialSchemaSyncDone,
 		CDSEnabled:            existingConfig.CDSEnabled,
 		SystemApplications:    existingConfig.SystemApplications,
+		SourcePriority:        existingConfig.SourcePriority,
 	}
 
 	// Update only if provided in request
@@ -107,6 +109,9 @@ func (h *AdminConfigHandler) UpdateAdminConfig(w http.ResponseWriter, r *http.Re
 	if config.SystemApplications != nil {
 		configToUpdate.SystemApplications = config.SystemApplications
 	}
+	if config.SourcePriority != nil {
+		configToUpdate.SourcePriority = config.SourcePriority
+	}
 
 	err = adminConfigService.UpdateAdminConfig(configToUpdate, orgHandle)
 	if err != nil {
@@ -117,6 +122,7 @@ func (h *AdminConfigHandler) UpdateAdminConfig(w http.ResponseWriter, r *http.Re
 	resp := model.AdminConfigAPI{
 		CDSEnabled:         configToUpdate.CDSEnabled,
 		SystemApplications: configToUpdate.SystemApplications,
+		SourcePriority:     configToUpdate.SourcePriority,
 	}
 	utils.RespondJSON(w, http.StatusOK, resp, constants.AdminConfigResource)
 }
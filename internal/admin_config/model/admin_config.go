@@ -23,14 +23,18 @@ type AdminConfig struct {
 	CDSEnabled            bool     `json:"cds_enabled" bson:"cds_enabled"`
 	InitialSchemaSyncDone bool     `json:"initial_schema_sync_done" bson:"initial_schema_sync_done"`
 	SystemApplications    []string `json:"system_applications" bson:"system_applications"`
+	// SourcePriority ranks applications by trust, most trusted first, for the source_priority merge strategy.
+	SourcePriority []string `json:"source_priority" bson:"source_priority"`
 }
 
 type AdminConfigAPI struct {
 	CDSEnabled         bool     `json:"cds_enabled" bson:"cds_enabled"`
 	SystemApplications []string `json:"system_applications,omitempty" bson:"system_applications,omitempty"`
+	SourcePriority     []string `json:"source_priority,omitempty" bson:"source_priority,omitempty"`
 }
 
 type AdminConfigUpdateAPI struct {
 	CDSEnabled         *bool    `json:"cds_enabled" bson:"cds_enabled"`
 	SystemApplications []string `json:"system_applications,omitempty"`
+	SourcePriority     []string `json:"source_priority,omitempty"`
 }
@@ -61,6 +61,7 @@ func GetAdminConfig(orgHandle string) (*model.AdminConfig, error) {
 		CDSEnabled:            false,
 		InitialSchemaSyncDone: false,
 		SystemApplications:    []string{},
+		SourcePriority:        []string{},
 	}
 
 	if len(results) == 0 {
@@ -88,12 +89,54 @@ func GetAdminConfig(orgHandle string) (*model.AdminConfig, error) {
 			if err := json.Unmarshal([]byte(value), &apps); err == nil {
 				config.SystemApplications = apps
 			}
+		case constants.ConfigSourcePriority:
+			var apps []string
+			if err := json.Unmarshal([]byte(value), &apps); err == nil {
+				config.SourcePriority = apps
+			}
 		}
 	}
 
 	return config, nil
 }
 
+// GetCDSEnabledOrgs returns the handles of the organizations CDS is enabled for.
+func GetCDSEnabledOrgs() ([]string, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	logger := log.GetLogger()
+	if err != nil {
+		errorMsg := "Failed to get db client for fetching the organizations CDS is enabled for"
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_ADMIN_CONFIG.Code,
+			Message:     errors2.GET_ADMIN_CONFIG.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.GetOrgsWithConfigValue[provider.NewDBProvider().GetDBType()]
+	results, err := dbClient.ExecuteQuery(query, constants.ConfigCDSEnabled, "true")
+	if err != nil {
+		errorMsg := "Failed to execute query for fetching the organizations CDS is enabled for"
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_ADMIN_CONFIG.Code,
+			Message:     errors2.GET_ADMIN_CONFIG.Message,
+			Description: errorMsg,
+		}, err)
+	}
+
+	orgHandles := make([]string, 0, len(results))
+	for _, row := range results {
+		if orgHandle, ok := row["org_handle"].(string); ok {
+			orgHandles = append(orgHandles, orgHandle)
+		}
+	}
+	return orgHandles, nil
+}
+
 // UpdateAdminConfig updates organization-level admin configuration (e.g., CDS enablement, schema sync flags).
 func UpdateAdminConfig(config model.AdminConfig, orgHandle string) error {
 
@@ -179,6 +222,33 @@ func UpdateAdminConfig(config model.AdminConfig, orgHandle string) error {
 		}, err)
 	}
 
+	sourcePriority := config.SourcePriority
+	if sourcePriority == nil {
+		sourcePriority = []string{}
+	}
+	sourcePriorityValue, err := json.Marshal(sourcePriority)
+	if err != nil {
+		_ = tx.Rollback()
+		errorMsg := fmt.Sprintf("Failed to marshal source_priority for organization: %s", orgHandle)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_ADMIN_CONFIG.Code,
+			Message:     errors2.UPDATE_ADMIN_CONFIG.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	_, err = tx.Exec(query, orgHandle, constants.ConfigSourcePriority, string(sourcePriorityValue))
+	if err != nil {
+		_ = tx.Rollback()
+		errorMsg := fmt.Sprintf("Failed to update source_priority for organization: %s", orgHandle)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_ADMIN_CONFIG.Code,
+			Message:     errors2.UPDATE_ADMIN_CONFIG.Message,
+			Description: errorMsg,
+		}, err)
+	}
+
 	return tx.Commit()
 }
 
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/wso2/identity-customer-data-service/internal/application/model"
+	"github.com/wso2/identity-customer-data-service/internal/application/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/security"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+// ApplicationHandler handles the purpose declarations of registered applications.
+type ApplicationHandler struct{}
+
+// NewApplicationHandler returns a new ApplicationHandler instance.
+func NewApplicationHandler() *ApplicationHandler {
+	return &ApplicationHandler{}
+}
+
+// GetApplicationPurposes handles GET /applications/{applicationId}/purposes
+func (h *ApplicationHandler) GetApplicationPurposes(w http.ResponseWriter, r *http.Request) {
+
+	if err := security.AuthnAndAuthz(r, "application:view"); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	appIdentifier := r.PathValue("applicationId")
+
+	appService := provider.NewApplicationProvider().GetApplicationService()
+	purposes, err := appService.GetApplicationPurposes(appIdentifier, orgHandle)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if purposes == nil {
+		purposes = []string{}
+	}
+
+	resp := model.ApplicationPurposes{
+		AppIdentifier: appIdentifier,
+		Purposes:      purposes,
+	}
+	utils.RespondJSON(w, http.StatusOK, resp, constants.ApplicationResource)
+}
+
+// UpdateApplicationPurposes handles PUT /applications/{applicationId}/purposes
+func (h *ApplicationHandler) UpdateApplicationPurposes(w http.ResponseWriter, r *http.Request) {
+
+	if err := security.AuthnAndAuthz(r, "application:update"); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	appIdentifier := r.PathValue("applicationId")
+
+	var req model.ApplicationPurposesUpdateRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		clientError := errors.NewClientError(errors.ErrorMessage{
+			Code:        errors.UPDATE_APPLICATION_PURPOSES_BAD_REQUEST.Code,
+			Message:     errors.UPDATE_APPLICATION_PURPOSES_BAD_REQUEST.Message,
+			Description: utils.HandleDecodeError(err, "application purposes"),
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	appService := provider.NewApplicationProvider().GetApplicationService()
+	purposes, err := appService.UpdateApplicationPurposes(appIdentifier, orgHandle, req.Purposes)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	resp := model.ApplicationPurposes{
+		AppIdentifier: appIdentifier,
+		Purposes:      purposes,
+	}
+	utils.RespondJSON(w, http.StatusOK, resp, constants.ApplicationResource)
+}
@@ -24,3 +24,15 @@ type Application struct {
 	OrgHandle string `json:"org_handle"`
 	ClientID  string `json:"client_id"`
 }
+
+// ApplicationPurposes holds the data-processing purposes an application has declared.
+type ApplicationPurposes struct {
+	AppIdentifier string   `json:"application_identifier"`
+	OrgHandle     string   `json:"-"`
+	Purposes      []string `json:"purposes"`
+}
+
+// ApplicationPurposesUpdateRequest is the payload for declaring an application's purposes.
+type ApplicationPurposesUpdateRequest struct {
+	Purposes []string `json:"purposes"`
+}
@@ -20,11 +20,14 @@ package service
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/wso2/identity-customer-data-service/internal/application/model"
 	"github.com/wso2/identity-customer-data-service/internal/application/store"
 	"github.com/wso2/identity-customer-data-service/internal/system/client"
 	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
 )
 
@@ -32,6 +35,8 @@ import (
 type ApplicationServiceInterface interface {
 	ResolveAndRegisterApplication(appIdentifier, orgHandle string) (bool, error)
 	ResolveAppIdentifierByClientID(orgHandle, clientID string) (string, error)
+	GetApplicationPurposes(appIdentifier, orgHandle string) ([]string, error)
+	UpdateApplicationPurposes(appIdentifier, orgHandle string, purposes []string) ([]string, error)
 }
 
 // ApplicationService is the default implementation of the ApplicationServiceInterface.
@@ -78,3 +83,57 @@ func (as *ApplicationService) ResolveAppIdentifierByClientID(orgHandle, clientID
 
 	return store.GetAppIdentifierByClientID(orgHandle, clientID)
 }
+
+// GetApplicationPurposes returns the purposes the application has declared. A nil slice means the application has
+// not declared any, in which case profile reads are not purpose-restricted.
+func (as *ApplicationService) GetApplicationPurposes(appIdentifier, orgHandle string) ([]string, error) {
+
+	if appIdentifier == "" {
+		return nil, nil
+	}
+	return store.GetApplicationPurposes(orgHandle, appIdentifier)
+}
+
+// UpdateApplicationPurposes validates and replaces the purposes declared for the application. In app_id mode the
+// application must exist in the identity server; it is registered so the caller's clientId can be resolved later.
+func (as *ApplicationService) UpdateApplicationPurposes(appIdentifier, orgHandle string, purposes []string) ([]string, error) {
+
+	normalized := make([]string, 0, len(purposes))
+	seen := make(map[string]bool, len(purposes))
+	for _, purpose := range purposes {
+		if !constants.AllowedConsentPurposes[purpose] {
+			return nil, errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.UPDATE_APPLICATION_PURPOSES_BAD_REQUEST.Code,
+				Message:     errors2.UPDATE_APPLICATION_PURPOSES_BAD_REQUEST.Message,
+				Description: fmt.Sprintf("Invalid purpose '%s'. Allowed values are profiling, personalization, destination.", purpose),
+			}, http.StatusBadRequest)
+		}
+		if !seen[purpose] {
+			seen[purpose] = true
+			normalized = append(normalized, purpose)
+		}
+	}
+
+	if config.GetCDSRuntime().Config.UsesAppIDIdentifier() {
+		exists, err := as.ResolveAndRegisterApplication(appIdentifier, orgHandle)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.APPLICATION_NOT_FOUND.Code,
+				Message:     errors2.APPLICATION_NOT_FOUND.Message,
+				Description: fmt.Sprintf("Application '%s' does not exist in the identity server.", appIdentifier),
+			}, http.StatusNotFound)
+		}
+	}
+
+	if err := store.UpsertApplicationPurposes(model.ApplicationPurposes{
+		AppIdentifier: appIdentifier,
+		OrgHandle:     orgHandle,
+		Purposes:      normalized,
+	}); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
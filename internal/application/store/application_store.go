@@ -19,8 +19,10 @@
 package store
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/lib/pq"
 	"github.com/wso2/identity-customer-data-service/internal/application/model"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
@@ -100,3 +102,94 @@ func GetAppIdentifierByClientID(orgHandle, clientID string) (string, error) {
 	appID, _ := results[0]["app_id"].(string)
 	return appID, nil
 }
+
+// UpsertApplicationPurposes persists the purposes declared for an application, replacing any earlier declaration.
+func UpsertApplicationPurposes(appPurposes model.ApplicationPurposes) error {
+
+	logger := log.GetLogger()
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get database client for persisting purposes of application: %s",
+			appPurposes.AppIdentifier)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_APPLICATION_PURPOSES_FAILED.Code,
+			Message:     errors2.UPDATE_APPLICATION_PURPOSES_FAILED.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	purposes := appPurposes.Purposes
+	if purposes == nil {
+		purposes = []string{}
+	}
+
+	query := scripts.UpsertApplicationPurposes[provider.NewDBProvider().GetDBType()]
+	_, err = dbClient.ExecuteQuery(query, appPurposes.OrgHandle, appPurposes.AppIdentifier, pq.Array(purposes))
+	if err != nil {
+		errorMsg := fmt.Sprintf("Error occurred while persisting purposes of application: %s", appPurposes.AppIdentifier)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_APPLICATION_PURPOSES_FAILED.Code,
+			Message:     errors2.UPDATE_APPLICATION_PURPOSES_FAILED.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return nil
+}
+
+// GetApplicationPurposes returns the purposes declared for an application. An application that never declared
+// purposes returns a nil slice with a nil error.
+func GetApplicationPurposes(orgHandle, appIdentifier string) ([]string, error) {
+
+	logger := log.GetLogger()
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get database client for fetching purposes of application: %s", appIdentifier)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_APPLICATION_PURPOSES_FAILED.Code,
+			Message:     errors2.GET_APPLICATION_PURPOSES_FAILED.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.GetApplicationPurposes[provider.NewDBProvider().GetDBType()]
+	results, err := dbClient.ExecuteQuery(query, orgHandle, appIdentifier)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Error occurred while fetching purposes of application: %s", appIdentifier)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_APPLICATION_PURPOSES_FAILED.Code,
+			Message:     errors2.GET_APPLICATION_PURPOSES_FAILED.Message,
+			Description: errorMsg,
+		}, err)
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	var raw string
+	switch v := results[0]["purposes"].(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	}
+	purposes := []string{}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &purposes); err != nil {
+			errorMsg := fmt.Sprintf("Failed to parse purposes of application: %s", appIdentifier)
+			logger.Debug(errorMsg, log.Error(err))
+			return nil, errors2.NewServerError(errors2.ErrorMessage{
+				Code:        errors2.GET_APPLICATION_PURPOSES_FAILED.Code,
+				Message:     errors2.GET_APPLICATION_PURPOSES_FAILED.Message,
+				Description: errorMsg,
+			}, err)
+		}
+	}
+	return purposes, nil
+}
@@ -24,6 +24,7 @@ import (
 
 	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
 	"github.com/wso2/identity-customer-data-service/internal/consent/provider"
+	consentService "github.com/wso2/identity-customer-data-service/internal/consent/service"
 	"github.com/wso2/identity-customer-data-service/internal/system/errors"
 	"github.com/wso2/identity-customer-data-service/internal/system/security"
 	"github.com/wso2/identity-customer-data-service/internal/system/utils"
@@ -43,15 +44,17 @@ func (h *ConsentCategoryHandler) GetAllConsentCategories(w http.ResponseWriter,
 		utils.HandleError(w, err)
 		return
 	}
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
 	service := provider.NewConsentCategoryProvider().GetConsentCategoryService()
-	categories, err := service.GetAllConsentCategories()
+	categories, err := service.GetAllConsentCategories(orgHandle)
 	if err != nil {
 		utils.HandleError(w, err)
 		return
 	}
 	responses := make([]consentModel.ConsentCategoryResponse, 0, len(categories))
+	locale := r.URL.Query().Get("locale")
 	for _, c := range categories {
-		responses = append(responses, c.ToResponse())
+		responses = append(responses, toCategoryResponse(c, locale))
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(responses)
@@ -112,15 +115,24 @@ func (h *ConsentCategoryHandler) GetConsentCategory(w http.ResponseWriter, r *ht
 		return
 	}
 
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
 	service := provider.NewConsentCategoryProvider().GetConsentCategoryService()
-	category, err := service.GetConsentCategory(categoryId)
+	category, err := service.GetConsentCategory(categoryId, orgHandle)
 	if err != nil {
 		utils.HandleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(category.ToResponse())
+	_ = json.NewEncoder(w).Encode(toCategoryResponse(*category, r.URL.Query().Get("locale")))
+}
+
+// toCategoryResponse renders the category for the requested locale, or with all its localizations when none is given.
+func toCategoryResponse(category consentModel.ConsentCategory, locale string) consentModel.ConsentCategoryResponse {
+	if locale == "" {
+		return category.ToResponse()
+	}
+	return category.ToLocalizedResponse(locale, consentService.DefaultLocale())
 }
 
 // UpdateConsentCategory handles PUT /consent-categories/{id}
@@ -187,8 +199,9 @@ func (h *ConsentCategoryHandler) DeleteConsentCategory(w http.ResponseWriter, r
 		return
 	}
 
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
 	service := provider.NewConsentCategoryProvider().GetConsentCategoryService()
-	if err := service.DeleteConsentCategory(categoryId); err != nil {
+	if err := service.DeleteConsentCategory(categoryId, orgHandle); err != nil {
 		utils.HandleError(w, err)
 		return
 	}
@@ -27,4 +27,6 @@ type ConsentCategory struct {
 	Destinations       []string           `json:"destinations,omitempty" bson:"destinations,omitempty"` // Optional list of destination names
 	Attributes         []ConsentAttribute `json:"attributes,omitempty" bson:"attributes,omitempty"`     // Profile attributes covered by this consent category
 	IsMandatory        bool               `json:"is_mandatory" bson:"is_mandatory"`                     // If true, category is system-managed and cannot be modified or deleted
+	// Localizations holds the display name and purpose description per BCP-47 locale (e.g. "fr-CA").
+	Localizations map[string]ConsentCategoryLocalization `json:"localizations,omitempty" bson:"localizations,omitempty"`
 }
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package models
+
+import "strings"
+
+// ConsentCategoryLocalization holds the locale-specific texts of a consent category.
+type ConsentCategoryLocalization struct {
+	DisplayName        string `json:"display_name"`
+	PurposeDescription string `json:"purpose_description,omitempty"`
+}
+
+// ResolveLocalization picks the localization that best matches the requested locale. Locale tags are matched
+// case-insensitively, and subtags are dropped one at a time ("fr-CA" falls back to "fr") before trying the default
+// locale the same way. It returns the matched locale key, or false if neither locale has a localization.
+func (c ConsentCategory) ResolveLocalization(locale, defaultLocale string) (string, ConsentCategoryLocalization, bool) {
+	if len(c.Localizations) == 0 {
+		return "", ConsentCategoryLocalization{}, false
+	}
+	byLowerKey := make(map[string]string, len(c.Localizations))
+	for key := range c.Localizations {
+		byLowerKey[strings.ToLower(key)] = key
+	}
+	for _, candidate := range []string{locale, defaultLocale} {
+		tag := strings.ToLower(candidate)
+		for tag != "" {
+			if key, ok := byLowerKey[tag]; ok {
+				return key, c.Localizations[key], true
+			}
+			cut := strings.LastIndex(tag, "-")
+			if cut < 0 {
+				break
+			}
+			tag = tag[:cut]
+		}
+	}
+	return "", ConsentCategoryLocalization{}, false
+}
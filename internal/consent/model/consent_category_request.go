@@ -33,6 +33,8 @@ type ConsentCategoryRequest struct {
 	Purpose      string             `json:"purpose"`
 	Destinations []string           `json:"destinations,omitempty"`
 	Attributes   []ConsentAttribute `json:"attributes,omitempty"`
+	// Localizations maps a BCP-47 locale to the category's display name and purpose description in that locale.
+	Localizations map[string]ConsentCategoryLocalization `json:"localizations,omitempty"`
 }
 
 // ToCategory converts the request into the internal ConsentCategory model.
@@ -45,5 +47,6 @@ func (r ConsentCategoryRequest) ToCategory(orgHandle, categoryIdentifier string)
 		Purpose:            r.Purpose,
 		Destinations:       r.Destinations,
 		Attributes:         r.Attributes,
+		Localizations:      r.Localizations,
 	}
 }
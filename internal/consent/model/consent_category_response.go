@@ -34,6 +34,13 @@ type ConsentCategoryResponse struct {
 	Destinations       []string           `json:"destinations,omitempty"`
 	Attributes         []ConsentAttribute `json:"attributes,omitempty"`
 	IsMandatory        bool               `json:"is_mandatory"`
+	// Locale, DisplayName and PurposeDescription are set when a locale was requested and the category has a
+	// localization for it (or for the default locale).
+	Locale             string `json:"locale,omitempty"`
+	DisplayName        string `json:"display_name,omitempty"`
+	PurposeDescription string `json:"purpose_description,omitempty"`
+	// Localizations lists every localization; it is only returned when no locale was requested.
+	Localizations map[string]ConsentCategoryLocalization `json:"localizations,omitempty"`
 }
 
 // ToResponse converts an internal ConsentCategory to its API response form.
@@ -52,9 +59,23 @@ func (c ConsentCategory) ToResponse() ConsentCategoryResponse {
 		Purpose:            c.Purpose,
 		Destinations:       c.Destinations,
 		IsMandatory:        c.IsMandatory,
+		Localizations:      c.Localizations,
 	}
 	if len(attrs) > 0 {
 		resp.Attributes = attrs
 	}
 	return resp
 }
+
+// ToLocalizedResponse converts the category to its API response form with the texts resolved for the requested
+// locale, falling back to defaultLocale. The full localizations map is left out.
+func (c ConsentCategory) ToLocalizedResponse(locale, defaultLocale string) ConsentCategoryResponse {
+	resp := c.ToResponse()
+	resp.Localizations = nil
+	if key, localization, ok := c.ResolveLocalization(locale, defaultLocale); ok {
+		resp.Locale = key
+		resp.DisplayName = localization.DisplayName
+		resp.PurposeDescription = localization.PurposeDescription
+	}
+	return resp
+}
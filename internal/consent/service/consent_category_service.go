@@ -19,6 +19,7 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"strings"
@@ -27,17 +28,20 @@ import (
 	model "github.com/wso2/identity-customer-data-service/internal/consent/model"
 	"github.com/wso2/identity-customer-data-service/internal/consent/store"
 	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
 	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
 )
 
 // ConsentCategoryServiceInterface defines the service interface.
 type ConsentCategoryServiceInterface interface {
-	GetAllConsentCategories() ([]model.ConsentCategory, error)
-	GetConsentCategory(id string) (*model.ConsentCategory, error)
+	GetAllConsentCategories(orgHandle string) ([]model.ConsentCategory, error)
+	GetConsentCategory(id string, orgHandle string) (*model.ConsentCategory, error)
 	AddConsentCategory(category model.ConsentCategory) (*model.ConsentCategory, error)
+	AddConsentCategoryTx(tx *sql.Tx, category model.ConsentCategory) (*model.ConsentCategory, error)
 	UpdateConsentCategory(category model.ConsentCategory) error
-	DeleteConsentCategory(id string) error
+	UpdateConsentCategoryTx(tx *sql.Tx, category model.ConsentCategory) error
+	DeleteConsentCategory(id string, orgHandle string) error
 	SeedDefaultConsentCategory(orgHandle string) error
 }
 
@@ -49,10 +53,10 @@ func GetConsentCategoryService() ConsentCategoryServiceInterface {
 	return &ConsentCategoryService{}
 }
 
-// GetAllConsentCategories retrieves all categories.
-func (cs *ConsentCategoryService) GetAllConsentCategories() ([]model.ConsentCategory, error) {
+// GetAllConsentCategories retrieves all categories of the org.
+func (cs *ConsentCategoryService) GetAllConsentCategories(orgHandle string) ([]model.ConsentCategory, error) {
 
-	consentCat, err := store.GetAllConsentCategories()
+	consentCat, err := store.GetAllConsentCategories(orgHandle)
 
 	if err != nil {
 		return nil, err
@@ -64,10 +68,10 @@ func (cs *ConsentCategoryService) GetAllConsentCategories() ([]model.ConsentCate
 
 }
 
-// GetConsentCategory retrieves a category by ID.
-func (cs *ConsentCategoryService) GetConsentCategory(id string) (*model.ConsentCategory, error) {
+// GetConsentCategory retrieves a category of the org by ID.
+func (cs *ConsentCategoryService) GetConsentCategory(id string, orgHandle string) (*model.ConsentCategory, error) {
 
-	consentCat, err := store.GetConsentCategoryByID(id)
+	consentCat, err := store.GetConsentCategoryByID(id, orgHandle)
 	if err != nil {
 		return nil, err
 	}
@@ -84,13 +88,26 @@ func (cs *ConsentCategoryService) GetConsentCategory(id string) (*model.ConsentC
 // AddConsentCategory adds a new category.
 func (cs *ConsentCategoryService) AddConsentCategory(category model.ConsentCategory) (*model.ConsentCategory, error) {
 
+	var added *model.ConsentCategory
+	err := store.WithTransaction(func(tx *sql.Tx) error {
+		var err error
+		added, err = cs.AddConsentCategoryTx(tx, category)
+		return err
+	})
+	return added, err
+}
+
+// AddConsentCategoryTx adds a new category within the given transaction. Its attributes are looked up in the
+// schema as seen within the transaction.
+func (cs *ConsentCategoryService) AddConsentCategoryTx(tx *sql.Tx, category model.ConsentCategory) (*model.ConsentCategory, error) {
+
 	err, isValid := cs.validateConsentCat(category)
 
 	if !isValid || err != nil {
 		return nil, err
 	}
 
-	existingCat, err := store.GetConsentCategoryByName(category.CategoryName, category.OrgHandle)
+	existingCat, err := store.GetConsentCategoryByNameTx(tx, category.CategoryName, category.OrgHandle)
 
 	if err != nil {
 		return nil, err
@@ -107,13 +124,13 @@ func (cs *ConsentCategoryService) AddConsentCategory(category model.ConsentCateg
 	// category_identifier is always server-generated; ignore any caller-supplied value.
 	category.CategoryIdentifier = uuid.New().String()
 
-	resolved, err := resolveAttributeScopes(category.OrgHandle, category.Attributes)
+	resolved, err := resolveAttributeScopes(tx, category.OrgHandle, category.Attributes)
 	if err != nil {
 		return nil, err
 	}
 	category.Attributes = resolved
 
-	err = store.AddConsentCategory(category)
+	err = store.AddConsentCategoryTx(tx, category)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +155,30 @@ func (cs *ConsentCategoryService) validateConsentCat(category model.ConsentCateg
 		}, http.StatusBadRequest), false
 	}
 
+	for locale, localization := range category.Localizations {
+		if !constants.BCP47LocaleRegex.MatchString(locale) {
+			return errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.CONSENT_CAT_VALIDATION.Code,
+				Message:     errors2.CONSENT_CAT_VALIDATION.Message,
+				Description: fmt.Sprintf("Invalid locale '%s'. Localizations must be keyed by a BCP-47 language tag such as 'fr-CA'.", locale),
+			}, http.StatusBadRequest), false
+		}
+		if strings.TrimSpace(localization.DisplayName) == "" {
+			return errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.CONSENT_CAT_VALIDATION.Code,
+				Message:     errors2.CONSENT_CAT_VALIDATION.Message,
+				Description: fmt.Sprintf("display_name is required for locale '%s'.", locale),
+			}, http.StatusBadRequest), false
+		}
+		if len(localization.PurposeDescription) > constants.MaxPurposeDescriptionLength {
+			return errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.CONSENT_CAT_VALIDATION.Code,
+				Message:     errors2.CONSENT_CAT_VALIDATION.Message,
+				Description: fmt.Sprintf("purpose_description for locale '%s' exceeds %d characters.", locale, constants.MaxPurposeDescriptionLength),
+			}, http.StatusBadRequest), false
+		}
+	}
+
 	for _, attr := range category.Attributes {
 		if attr.AttributeName == "" {
 			return errors2.NewClientError(errors2.ErrorMessage{
@@ -150,14 +191,14 @@ func (cs *ConsentCategoryService) validateConsentCat(category model.ConsentCateg
 	return nil, true
 }
 
-// resolveAttributeScopes looks up each attribute in the profile schema by name and populates
+// resolveAttributeScopes looks up each attribute in the profile schema by name, as seen within tx, and populates
 // Scope (converted to API scope) and AttributeId. Returns an error if any attribute_name is
 // not found in the org's schema. For applicationData scope, app_id must also be provided.
-func resolveAttributeScopes(orgHandle string, attrs []model.ConsentAttribute) ([]model.ConsentAttribute, error) {
+func resolveAttributeScopes(tx *sql.Tx, orgHandle string, attrs []model.ConsentAttribute) ([]model.ConsentAttribute, error) {
 	svc := schemaService.GetProfileSchemaService()
 	resolved := make([]model.ConsentAttribute, 0, len(attrs))
 	for _, attr := range attrs {
-		schemaAttr, err := svc.GetProfileSchemaAttributeByName(attr.AttributeName, orgHandle)
+		schemaAttr, err := svc.GetProfileSchemaAttributeByNameTx(tx, attr.AttributeName, orgHandle)
 		if err != nil || schemaAttr == nil {
 			return nil, errors2.NewClientError(errors2.ErrorMessage{
 				Code:        errors2.CONSENT_CAT_VALIDATION.Code,
@@ -208,6 +249,15 @@ func inferScope(attributeName string) string {
 // UpdateConsentCategory updates an existing category.
 func (cs *ConsentCategoryService) UpdateConsentCategory(category model.ConsentCategory) error {
 
+	return store.WithTransaction(func(tx *sql.Tx) error {
+		return cs.UpdateConsentCategoryTx(tx, category)
+	})
+}
+
+// UpdateConsentCategoryTx updates an existing category within the given transaction. Its attributes are looked up in
+// the schema as seen within the transaction.
+func (cs *ConsentCategoryService) UpdateConsentCategoryTx(tx *sql.Tx, category model.ConsentCategory) error {
+
 	if category.CategoryIdentifier == "" {
 		return errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.BAD_REQUEST.Code,
@@ -220,21 +270,45 @@ func (cs *ConsentCategoryService) UpdateConsentCategory(category model.ConsentCa
 		return err
 	}
 
-	if err := guardMandatoryCategory(category.CategoryIdentifier); err != nil {
+	existing, err := store.GetConsentCategoryByIDTx(tx, category.CategoryIdentifier, category.OrgHandle)
+	if err == nil {
+		err = rejectMandatoryCategory(existing)
+	}
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CONSENT_CAT_NOT_FOUND.Code,
+			Message:     errors2.CONSENT_CAT_NOT_FOUND.Message,
+			Description: fmt.Sprintf("Consent category not found for the provided categoryId: %s", category.CategoryIdentifier),
+		}, http.StatusNotFound)
+	}
+
+	// Names are unique per org; renaming onto another category's name is a conflict.
+	sameName, err := store.GetConsentCategoryByNameTx(tx, category.CategoryName, category.OrgHandle)
+	if err != nil {
 		return err
 	}
+	if sameName != nil && sameName.CategoryIdentifier != category.CategoryIdentifier {
+		return errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CONSENT_CAT_ALREADY_EXISTS.Code,
+			Message:     errors2.CONSENT_CAT_ALREADY_EXISTS.Message,
+			Description: fmt.Sprintf("Category with the same name :%s already exists.", category.CategoryName),
+		}, http.StatusConflict)
+	}
 
-	resolved, err := resolveAttributeScopes(category.OrgHandle, category.Attributes)
+	resolved, err := resolveAttributeScopes(tx, category.OrgHandle, category.Attributes)
 	if err != nil {
 		return err
 	}
 	category.Attributes = resolved
 
-	return store.UpdateConsentCategory(category)
+	return store.UpdateConsentCategoryTx(tx, category)
 }
 
-// DeleteConsentCategory deletes an existing category.
-func (cs *ConsentCategoryService) DeleteConsentCategory(categoryId string) error {
+// DeleteConsentCategory deletes an existing category of the org.
+func (cs *ConsentCategoryService) DeleteConsentCategory(categoryId string, orgHandle string) error {
 	if categoryId == "" {
 		return errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.BAD_REQUEST.Code,
@@ -242,28 +316,45 @@ func (cs *ConsentCategoryService) DeleteConsentCategory(categoryId string) error
 			Description: "Consent category Id is required for update.",
 		}, http.StatusBadRequest)
 	}
-	if err := guardMandatoryCategory(categoryId); err != nil {
+	if _, err := guardMandatoryCategory(categoryId, orgHandle); err != nil {
 		return err
 	}
-	return store.DeleteConsentCategory(categoryId)
+	return store.DeleteConsentCategory(categoryId, orgHandle)
 }
 
-// guardMandatoryCategory rejects mutations on any category flagged is_mandatory in the DB.
-func guardMandatoryCategory(categoryId string) error {
-	cat, err := store.GetConsentCategoryByID(categoryId)
+// guardMandatoryCategory rejects mutations on any category flagged is_mandatory in the DB. It returns the
+// org's category, or nil if the org has no category with that ID.
+func guardMandatoryCategory(categoryId string, orgHandle string) (*model.ConsentCategory, error) {
+	cat, err := store.GetConsentCategoryByID(categoryId, orgHandle)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := rejectMandatoryCategory(cat); err != nil {
+		return nil, err
 	}
+	return cat, nil
+}
+
+// rejectMandatoryCategory returns an error if the category is flagged is_mandatory.
+func rejectMandatoryCategory(cat *model.ConsentCategory) error {
 	if cat != nil && cat.IsMandatory {
 		return errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.CONSENT_CAT_MANDATORY.Code,
 			Message:     errors2.CONSENT_CAT_MANDATORY.Message,
-			Description: fmt.Sprintf("Consent category '%s' is mandatory and cannot be modified or deleted.", categoryId),
+			Description: fmt.Sprintf("Consent category '%s' is mandatory and cannot be modified or deleted.", cat.CategoryIdentifier),
 		}, http.StatusForbidden)
 	}
 	return nil
 }
 
+// DefaultLocale returns the locale consent category localizations fall back to when the requested one is missing.
+func DefaultLocale() string {
+	if locale := config.GetCDSRuntime().Config.Consent.DefaultLocale; locale != "" {
+		return locale
+	}
+	return constants.DefaultConsentLocale
+}
+
 // SeedDefaultConsentCategory seeds the mandatory identity data consent category for the org.
 func (cs *ConsentCategoryService) SeedDefaultConsentCategory(orgHandle string) error {
 	return store.SeedDefaultIdentityDataCategory(orgHandle)
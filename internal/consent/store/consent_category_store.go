@@ -18,11 +18,14 @@
 package store
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	model "github.com/wso2/identity-customer-data-service/internal/consent/model"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
 	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
@@ -30,45 +33,32 @@ import (
 	"strings"
 )
 
+// queryExecutor runs queries either on a database client of their own or inside an open transaction.
+type queryExecutor interface {
+	ExecuteQuery(query string, args ...interface{}) ([]map[string]interface{}, error)
+}
+
+// WithTransaction runs fn in a single database transaction, so that the category changes made through it are
+// persisted together or not at all.
+func WithTransaction(fn func(tx *sql.Tx) error) error {
+	return provider.WithTransaction(errors2.UPDATE_CONSENT_CATEGORY, fn)
+}
+
 // AddConsentCategory inserts a new consent category into the database.
 func AddConsentCategory(category model.ConsentCategory) error {
 
-	dbClient, err := provider.NewDBProvider().GetDBClient()
+	return provider.WithTransaction(errors2.ADD_CONSENT_CATEGORY, func(tx *sql.Tx) error {
+		return AddConsentCategoryTx(tx, category)
+	})
+}
+
+// AddConsentCategoryTx inserts a new consent category, with its attributes, within the given transaction.
+func AddConsentCategoryTx(tx *sql.Tx, category model.ConsentCategory) error {
+
 	logger := log.GetLogger()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to get db client for inserting consent category: %s", category.CategoryIdentifier)
-		logger.Debug(errorMsg, log.Error(err))
-		return errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.ADD_CONSENT_CATEGORY.Code,
-			Message:     errors2.ADD_CONSENT_CATEGORY.Message,
-			Description: errorMsg,
-		}, err)
-	}
-	defer dbClient.Close()
 	query := scripts.InsertConsentCategory[provider.NewDBProvider().GetDBType()]
-	tx, err := dbClient.BeginTx()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to begin transaction for inserting consent category: %s", category.CategoryIdentifier)
-		logger.Debug(errorMsg, log.Error(err))
-		serverError := errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.ADD_CONSENT_CATEGORY.Code,
-			Message:     errors2.ADD_CONSENT_CATEGORY.Message,
-			Description: errorMsg,
-		}, err)
-		return serverError
-	}
-	_, err = tx.Exec(query, category.CategoryName, category.CategoryIdentifier, category.OrgHandle, category.Purpose, pq.Array(category.Destinations), category.IsMandatory)
+	_, err := tx.Exec(query, category.CategoryName, category.CategoryIdentifier, category.OrgHandle, category.Purpose, pq.Array(category.Destinations), category.IsMandatory, marshalLocalizations(category.Localizations))
 	if err != nil {
-		errRollback := tx.Rollback()
-		if errRollback != nil {
-			errorMsg := fmt.Sprintf("Failed to rollback inserting consent category: %s", category.CategoryIdentifier)
-			logger.Debug(errorMsg, log.Error(errRollback))
-			return errors2.NewServerError(errors2.ErrorMessage{
-				Code:        errors2.ADD_CONSENT_CATEGORY.Code,
-				Message:     errors2.ADD_CONSENT_CATEGORY.Message,
-				Description: errorMsg,
-			}, errRollback)
-		}
 		errorMsg := fmt.Sprintf("Failed to execute query for inserting consent category: %s", category.CategoryIdentifier)
 		logger.Debug(errorMsg, log.Error(err))
 		return errors2.NewServerError(errors2.ErrorMessage{
@@ -80,9 +70,8 @@ func AddConsentCategory(category model.ConsentCategory) error {
 
 	attrQuery := scripts.InsertConsentCategoryAttribute[provider.NewDBProvider().GetDBType()]
 	for _, attr := range category.Attributes {
-		_, err = tx.Exec(attrQuery, category.CategoryIdentifier, attr.Scope, attr.AttributeName, attr.AttributeId, attr.ApplicationIdentifier)
+		_, err = tx.Exec(attrQuery, category.OrgHandle, category.CategoryIdentifier, attr.Scope, attr.AttributeName, attr.AttributeId, attr.ApplicationIdentifier)
 		if err != nil {
-			_ = tx.Rollback()
 			errorMsg := fmt.Sprintf("Failed to insert attribute %s for consent category: %s", attr.AttributeName, category.CategoryIdentifier)
 			logger.Debug(errorMsg, log.Error(err))
 			return errors2.NewServerError(errors2.ErrorMessage{
@@ -94,16 +83,16 @@ func AddConsentCategory(category model.ConsentCategory) error {
 	}
 
 	logger.Info(fmt.Sprintf("Successfully inserted consent category: %s", category.CategoryIdentifier))
-	return tx.Commit()
+	return nil
 }
 
-// GetAllConsentCategories retrieves all consent categories from the database.
-func GetAllConsentCategories() ([]model.ConsentCategory, error) {
+// GetAllConsentCategories retrieves all consent categories of the org from the database.
+func GetAllConsentCategories(orgHandle string) ([]model.ConsentCategory, error) {
 
 	dbClient, err := provider.NewDBProvider().GetDBClient()
 	logger := log.GetLogger()
 	if err != nil {
-		errorMsg := "Failed to get db client for fetching consent categories."
+		errorMsg := fmt.Sprintf("Failed to get db client for fetching consent categories for org: %s", orgHandle)
 		logger.Debug(errorMsg, log.Error(err))
 		return nil, errors2.NewServerError(errors2.ErrorMessage{
 			Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
@@ -114,9 +103,9 @@ func GetAllConsentCategories() ([]model.ConsentCategory, error) {
 	defer dbClient.Close()
 
 	query := scripts.GetAllConsentCategories[provider.NewDBProvider().GetDBType()]
-	results, err := dbClient.ExecuteQuery(query)
+	results, err := dbClient.ExecuteQuery(query, orgHandle)
 	if err != nil {
-		errorMsg := "Failed to execute query for fetching consent categories."
+		errorMsg := fmt.Sprintf("Failed to execute query for fetching consent categories for org: %s", orgHandle)
 		logger.Debug(errorMsg, log.Error(err))
 		return nil, errors2.NewServerError(errors2.ErrorMessage{
 			Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
@@ -134,6 +123,7 @@ func GetAllConsentCategories() ([]model.ConsentCategory, error) {
 			Purpose:            row["purpose"].(string),
 			Destinations:       parseStringArray(row["destinations"]),
 			IsMandatory:        parseBool(row["is_mandatory"]),
+			Localizations:      parseLocalizations(row["localizations"]),
 		})
 	}
 	if len(categories) == 0 {
@@ -149,7 +139,7 @@ func GetAllConsentCategories() ([]model.ConsentCategory, error) {
 			regularIds = append(regularIds, c.CategoryIdentifier)
 		}
 	}
-	attrsByCategory, err := getAttributesByCategoryIds(dbClient, regularIds)
+	attrsByCategory, err := getAttributesByCategoryIds(dbClient, orgHandle, regularIds)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +160,7 @@ func GetAllConsentCategories() ([]model.ConsentCategory, error) {
 }
 
 // GetConsentCategoryByID retrieves a consent category by its ID.
-func GetConsentCategoryByID(id string) (*model.ConsentCategory, error) {
+func GetConsentCategoryByID(id string, orgHandle string) (*model.ConsentCategory, error) {
 
 	dbClient, err := provider.NewDBProvider().GetDBClient()
 	logger := log.GetLogger()
@@ -185,8 +175,21 @@ func GetConsentCategoryByID(id string) (*model.ConsentCategory, error) {
 	}
 	defer dbClient.Close()
 
+	return getConsentCategoryByID(dbClient, id, orgHandle)
+}
+
+// GetConsentCategoryByIDTx retrieves a consent category by its ID within the given transaction.
+func GetConsentCategoryByIDTx(tx *sql.Tx, id string, orgHandle string) (*model.ConsentCategory, error) {
+
+	return getConsentCategoryByID(client.NewTxClient(tx), id, orgHandle)
+}
+
+func getConsentCategoryByID(dbClient queryExecutor, id string, orgHandle string) (*model.ConsentCategory, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetConsentCategoryById[provider.NewDBProvider().GetDBType()]
-	results, err := dbClient.ExecuteQuery(query, id)
+	results, err := dbClient.ExecuteQuery(query, id, orgHandle)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to execute query for fetching consent category: %s", id)
 		logger.Debug(errorMsg, log.Error(err))
@@ -209,6 +212,7 @@ func GetConsentCategoryByID(id string) (*model.ConsentCategory, error) {
 		Purpose:            row["purpose"].(string),
 		Destinations:       parseStringArray(row["destinations"]),
 		IsMandatory:        parseBool(row["is_mandatory"]),
+		Localizations:      parseLocalizations(row["localizations"]),
 	}
 
 	if category.IsMandatory {
@@ -218,7 +222,7 @@ func GetConsentCategoryByID(id string) (*model.ConsentCategory, error) {
 		}
 		category.Attributes = attrs
 	} else {
-		attrsByCategory, err := getAttributesByCategoryIds(dbClient, []string{id})
+		attrsByCategory, err := getAttributesByCategoryIds(dbClient, orgHandle, []string{id})
 		if err != nil {
 			return nil, err
 		}
@@ -244,6 +248,19 @@ func GetConsentCategoryByName(name string, orgHandle string) (*model.ConsentCate
 	}
 	defer dbClient.Close()
 
+	return getConsentCategoryByName(dbClient, name, orgHandle)
+}
+
+// GetConsentCategoryByNameTx retrieves a consent category by name within the given transaction.
+func GetConsentCategoryByNameTx(tx *sql.Tx, name string, orgHandle string) (*model.ConsentCategory, error) {
+
+	return getConsentCategoryByName(client.NewTxClient(tx), name, orgHandle)
+}
+
+func getConsentCategoryByName(dbClient queryExecutor, name string, orgHandle string) (*model.ConsentCategory, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetConsentCategoryByName[provider.NewDBProvider().GetDBType()]
 	results, err := dbClient.ExecuteQuery(query, name, orgHandle)
 	if err != nil {
@@ -268,6 +285,7 @@ func GetConsentCategoryByName(name string, orgHandle string) (*model.ConsentCate
 		Purpose:            row["purpose"].(string),
 		Destinations:       parseStringArray(row["destinations"]),
 		IsMandatory:        parseBool(row["is_mandatory"]),
+		Localizations:      parseLocalizations(row["localizations"]),
 	}
 	return &category, nil
 }
@@ -275,35 +293,19 @@ func GetConsentCategoryByName(name string, orgHandle string) (*model.ConsentCate
 // UpdateConsentCategory updates an existing consent category in the database.
 func UpdateConsentCategory(category model.ConsentCategory) error {
 
-	dbClient, err := provider.NewDBProvider().GetDBClient()
-	logger := log.GetLogger()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to get db client for updating consent category: %s", category.CategoryIdentifier)
-		logger.Debug(errorMsg, log.Error(err))
-		return errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.UPDATE_CONSENT_CATEGORY.Code,
-			Message:     errors2.UPDATE_CONSENT_CATEGORY.Message,
-			Description: errorMsg,
-		}, err)
-	}
-	defer dbClient.Close()
-	tx, err := dbClient.BeginTx()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to begin transaction for updating consent category: %s",
-			category.CategoryIdentifier)
-		logger.Debug(errorMsg, log.Error(err))
-		serverError := errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.UPDATE_CONSENT_CATEGORY.Code,
-			Message:     errors2.UPDATE_CONSENT_CATEGORY.Message,
-			Description: errorMsg,
-		}, err)
-		return serverError
-	}
+	return WithTransaction(func(tx *sql.Tx) error {
+		return UpdateConsentCategoryTx(tx, category)
+	})
+}
+
+// UpdateConsentCategoryTx updates an existing consent category, replacing its attributes, within the given
+// transaction.
+func UpdateConsentCategoryTx(tx *sql.Tx, category model.ConsentCategory) error {
 
+	logger := log.GetLogger()
 	query := scripts.UpdateConsentCategory[provider.NewDBProvider().GetDBType()]
-	_, err = tx.Exec(query, category.CategoryName, category.Purpose, pq.Array(category.Destinations), category.CategoryIdentifier)
+	_, err := tx.Exec(query, category.CategoryName, category.Purpose, pq.Array(category.Destinations), marshalLocalizations(category.Localizations), category.CategoryIdentifier, category.OrgHandle)
 	if err != nil {
-		_ = tx.Rollback()
 		logger.Debug("Failed to update consent category", log.Error(err))
 		return errors2.NewServerError(errors2.ErrorMessage{
 			Code:        errors2.UPDATE_CONSENT_CATEGORY.Code,
@@ -313,9 +315,8 @@ func UpdateConsentCategory(category model.ConsentCategory) error {
 	}
 
 	deleteAttrQuery := scripts.DeleteConsentCategoryAttributesByCategoryId[provider.NewDBProvider().GetDBType()]
-	_, err = tx.Exec(deleteAttrQuery, category.CategoryIdentifier)
+	_, err = tx.Exec(deleteAttrQuery, category.CategoryIdentifier, category.OrgHandle)
 	if err != nil {
-		_ = tx.Rollback()
 		errorMsg := fmt.Sprintf("Failed to delete attributes for consent category: %s", category.CategoryIdentifier)
 		logger.Debug(errorMsg, log.Error(err))
 		return errors2.NewServerError(errors2.ErrorMessage{
@@ -327,9 +328,8 @@ func UpdateConsentCategory(category model.ConsentCategory) error {
 
 	insertAttrQuery := scripts.InsertConsentCategoryAttribute[provider.NewDBProvider().GetDBType()]
 	for _, attr := range category.Attributes {
-		_, err = tx.Exec(insertAttrQuery, category.CategoryIdentifier, attr.Scope, attr.AttributeName, attr.AttributeId, attr.ApplicationIdentifier)
+		_, err = tx.Exec(insertAttrQuery, category.OrgHandle, category.CategoryIdentifier, attr.Scope, attr.AttributeName, attr.AttributeId, attr.ApplicationIdentifier)
 		if err != nil {
-			_ = tx.Rollback()
 			errorMsg := fmt.Sprintf("Failed to insert attribute %s for consent category: %s", attr.AttributeName, category.CategoryIdentifier)
 			logger.Debug(errorMsg, log.Error(err))
 			return errors2.NewServerError(errors2.ErrorMessage{
@@ -339,12 +339,11 @@ func UpdateConsentCategory(category model.ConsentCategory) error {
 			}, err)
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
-
-func DeleteConsentCategory(categoryId string) error {
+// DeleteConsentCategory deletes a consent category of the org. Its attributes and profile consents cascade.
+func DeleteConsentCategory(categoryId string, orgHandle string) error {
 	dbClient, err := provider.NewDBProvider().GetDBClient()
 	logger := log.GetLogger()
 	if err != nil {
@@ -372,8 +371,9 @@ func DeleteConsentCategory(categoryId string) error {
 	}
 
 	query := scripts.DeleteConsentCategory[provider.NewDBProvider().GetDBType()]
-	_, err = tx.Exec(query, categoryId)
+	_, err = tx.Exec(query, categoryId, orgHandle)
 	if err != nil {
+		_ = tx.Rollback()
 		errMsg := fmt.Sprintf("Failed to execute query for deleting consent category: %s", categoryId)
 		logger.Debug(errMsg, log.Error(err))
 		return errors2.NewServerError(errors2.ErrorMessage{
@@ -471,6 +471,44 @@ func GetMandatoryConsentCategoryIds(orgHandle string) ([]string, error) {
 	return ids, nil
 }
 
+// GetConsentCategoryIdsByPurposes returns the identifiers of the org's categories whose purpose is one of the given
+// purposes.
+func GetConsentCategoryIdsByPurposes(orgHandle string, purposes []string) ([]string, error) {
+	if len(purposes) == 0 {
+		return []string{}, nil
+	}
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	logger := log.GetLogger()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get db client for fetching category ids by purpose for org: %s", orgHandle)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
+			Message:     errors2.FETCH_CONSENT_CATEGORIES.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.GetConsentCategoryIdsByPurposes[provider.NewDBProvider().GetDBType()]
+	results, err := dbClient.ExecuteQuery(query, orgHandle, pq.Array(purposes))
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to fetch category ids by purpose for org: %s", orgHandle)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
+			Message:     errors2.FETCH_CONSENT_CATEGORIES.Message,
+			Description: errorMsg,
+		}, err)
+	}
+
+	ids := make([]string, 0, len(results))
+	for _, row := range results {
+		ids = append(ids, row["category_identifier"].(string))
+	}
+	return ids, nil
+}
+
 // GetConsentedCategoryAttributesByProfileId returns the allowed attribute sets for each
 // consented category. It only returns attributes for categories the profile has actively consented to.
 // Mandatory categories are always included regardless of profile consent records.
@@ -490,7 +528,7 @@ func GetConsentedCategoryAttributesByProfileId(profileId string, orgHandle strin
 
 	// Fetch which categories the profile has consented to (consent_status = true)
 	consentQuery := scripts.GetProfileConsentsByProfileId[provider.NewDBProvider().GetDBType()]
-	consentResults, err := dbClient.ExecuteQuery(consentQuery, profileId)
+	consentResults, err := dbClient.ExecuteQuery(consentQuery, profileId, orgHandle)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to fetch consents for profile: %s", profileId)
 		logger.Debug(errorMsg, log.Error(err))
@@ -574,7 +612,7 @@ func GetConsentedCategoryAttributesByProfileId(profileId string, orgHandle strin
 
 	// For regular categories: fetch from consent_category_attributes as usual.
 	if len(regularIds) > 0 {
-		regularAttrs, err := getAttributesByCategoryIds(dbClient, regularIds)
+		regularAttrs, err := getAttributesByCategoryIds(dbClient, orgHandle, regularIds)
 		if err != nil {
 			return nil, err
 		}
@@ -586,11 +624,108 @@ func GetConsentedCategoryAttributesByProfileId(profileId string, orgHandle strin
 	return result, nil
 }
 
-// getAttributesByCategoryIds is an internal helper that fetches attributes for a list of category IDs
+// GetConsentedCategoryAttributesByProfileIds is the batch form of GetConsentedCategoryAttributesByProfileId for a
+// page of profiles. It returns, per profile, the attributes of each requested category the profile may be read
+// through. The org's mandatory category IDs are passed in, as the caller has already fetched them.
+func GetConsentedCategoryAttributesByProfileIds(profileIds []string, orgHandle string, categoryIds []string,
+	mandatoryCategoryIds []string) (map[string]map[string][]model.ConsentAttribute, error) {
+
+	result := make(map[string]map[string][]model.ConsentAttribute, len(profileIds))
+	if len(profileIds) == 0 || len(categoryIds) == 0 {
+		return result, nil
+	}
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	logger := log.GetLogger()
+	if err != nil {
+		errorMsg := "Failed to get db client for fetching consented category attributes"
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
+			Message:     errors2.FETCH_CONSENT_CATEGORIES.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	consentQuery := scripts.GetConsentedCategoryIdsByProfileIds[provider.NewDBProvider().GetDBType()]
+	consentResults, err := dbClient.ExecuteQuery(consentQuery, orgHandle, pq.Array(profileIds))
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to fetch consents for %d profiles of org: %s", len(profileIds), orgHandle)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
+			Message:     errors2.FETCH_CONSENT_CATEGORIES.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	consentedSets := make(map[string]map[string]bool)
+	for _, row := range consentResults {
+		profileId := row["profile_id"].(string)
+		if consentedSets[profileId] == nil {
+			consentedSets[profileId] = make(map[string]bool)
+		}
+		consentedSets[profileId][row["category_id"].(string)] = true
+	}
+
+	// Split the requested categories as GetConsentedCategoryAttributesByProfileId does, resolving the attributes of
+	// each category once for the whole page.
+	mandatorySet := make(map[string]bool, len(mandatoryCategoryIds))
+	for _, id := range mandatoryCategoryIds {
+		mandatorySet[id] = true
+	}
+	seen := make(map[string]bool)
+	mandatoryIds := make([]string, 0)
+	regularIds := make([]string, 0)
+	for _, id := range categoryIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if mandatorySet[id] {
+			mandatoryIds = append(mandatoryIds, id)
+		} else {
+			regularIds = append(regularIds, id)
+		}
+	}
+
+	var mandatoryAttrs []model.ConsentAttribute
+	if len(mandatoryIds) > 0 {
+		if mandatoryAttrs, err = resolveMandatoryAttributes(dbClient, orgHandle); err != nil {
+			errorMsg := fmt.Sprintf("Failed to fetch identity attributes from schema for org: %s", orgHandle)
+			logger.Debug(errorMsg, log.Error(err))
+			return nil, errors2.NewServerError(errors2.ErrorMessage{
+				Code:        errors2.FETCH_CONSENT_CATEGORIES.Code,
+				Message:     errors2.FETCH_CONSENT_CATEGORIES.Message,
+				Description: errorMsg,
+			}, err)
+		}
+	}
+	regularAttrs, err := getAttributesByCategoryIds(dbClient, orgHandle, regularIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profileId := range profileIds {
+		attrsByCategory := make(map[string][]model.ConsentAttribute)
+		for _, id := range mandatoryIds {
+			attrsByCategory[id] = mandatoryAttrs
+		}
+		for _, id := range regularIds {
+			if attrs, ok := regularAttrs[id]; ok && consentedSets[profileId][id] {
+				attrsByCategory[id] = attrs
+			}
+		}
+		result[profileId] = attrsByCategory
+	}
+	return result, nil
+}
+
+// getAttributesByCategoryIds is an internal helper that fetches attributes for a list of the org's category IDs
 // using the provided db client (avoids opening a second connection).
 func getAttributesByCategoryIds(dbClient interface {
 	ExecuteQuery(query string, args ...interface{}) ([]map[string]interface{}, error)
-}, categoryIds []string) (map[string][]model.ConsentAttribute, error) {
+}, orgHandle string, categoryIds []string) (map[string][]model.ConsentAttribute, error) {
 	logger := log.GetLogger()
 
 	result := make(map[string][]model.ConsentAttribute)
@@ -598,18 +733,19 @@ func getAttributesByCategoryIds(dbClient interface {
 		return result, nil
 	}
 
-	ids := make([]interface{}, len(categoryIds))
+	args := make([]interface{}, 0, len(categoryIds)+1)
+	args = append(args, orgHandle)
 	placeholders := make([]string, len(categoryIds))
 	for i, id := range categoryIds {
-		ids[i] = id
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
 	}
 	inQuery := fmt.Sprintf(
-		"SELECT category_id, scope, attribute_name, attribute_id, application_identifier FROM consent_category_attributes WHERE category_id IN (%s)",
+		"SELECT category_id, scope, attribute_name, attribute_id, application_identifier FROM consent_category_attributes WHERE org_handle = $1 AND category_id IN (%s)",
 		strings.Join(placeholders, ", "),
 	)
 
-	rows, err := dbClient.ExecuteQuery(inQuery, ids...)
+	rows, err := dbClient.ExecuteQuery(inQuery, args...)
 	if err != nil {
 		errorMsg := "Failed to fetch consent category attributes"
 		logger.Debug(errorMsg, log.Error(err))
@@ -633,6 +769,35 @@ func getAttributesByCategoryIds(dbClient interface {
 	return result, nil
 }
 
+// marshalLocalizations encodes the localizations for the JSONB column, storing an empty object when there are none.
+func marshalLocalizations(localizations map[string]model.ConsentCategoryLocalization) string {
+	if len(localizations) == 0 {
+		return "{}"
+	}
+	raw, err := json.Marshal(localizations)
+	if err != nil {
+		return "{}"
+	}
+	return string(raw)
+}
+
+func parseLocalizations(raw interface{}) map[string]model.ConsentCategoryLocalization {
+	var rawStr string
+	switch v := raw.(type) {
+	case []byte:
+		rawStr = string(v)
+	case string:
+		rawStr = v
+	default:
+		return nil
+	}
+	localizations := map[string]model.ConsentCategoryLocalization{}
+	if err := json.Unmarshal([]byte(rawStr), &localizations); err != nil || len(localizations) == 0 {
+		return nil
+	}
+	return localizations
+}
+
 func parseBool(raw interface{}) bool {
 	if raw == nil {
 		return false
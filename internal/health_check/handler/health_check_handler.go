@@ -21,6 +21,7 @@ package handler
 import (
 	"encoding/json"
 	"github.com/wso2/identity-customer-data-service/internal/health_check/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
 	"net/http"
 )
 
@@ -54,6 +55,11 @@ func (h *HealthHandler) HandleReadiness(w http.ResponseWriter, r *http.Request)
 	writeJSONResponse(w, http.StatusOK, response)
 }
 
+// HandleMetrics responds to /metrics requests with the Prometheus metrics.
+func (h *HealthHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics.Handler(w, r)
+}
+
 // writeJSONResponse is a common helper for JSON encoding.
 func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/lifecycle"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
 )
 
@@ -44,6 +45,11 @@ func (h HealthCheckService) CheckReadiness() error {
 		return errors.New("logger not initialized")
 	}
 
+	// A draining server must be taken out of load balancing.
+	if lifecycle.IsDraining() {
+		return errors.New("server is shutting down")
+	}
+
 	dbProvider := provider.NewDBProvider()
 	dbClient, err := dbProvider.GetDBClient()
 	if err != nil {
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wso2/identity-customer-data-service/internal/job/model"
+	"github.com/wso2/identity-customer-data-service/internal/job/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/security"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+// JobHandler handles the read operations of background jobs.
+type JobHandler struct{}
+
+// NewJobHandler returns a new JobHandler instance.
+func NewJobHandler() *JobHandler {
+	return &JobHandler{}
+}
+
+// GetJobs handles GET /jobs, filtered by the type, status and limit query parameters.
+func (h *JobHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+
+	if err := security.AuthnAndAuthz(r, "job:view"); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	query := r.URL.Query()
+	filter := model.JobFilter{
+		Type:   strings.TrimSpace(query.Get("type")),
+		Status: strings.TrimSpace(query.Get("status")),
+	}
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			clientError := errors.NewClientError(errors.ErrorMessage{
+				Code:        errors.INVALID_JOB_FILTER.Code,
+				Message:     errors.INVALID_JOB_FILTER.Message,
+				Description: fmt.Sprintf("Invalid limit: %s. The limit must be a positive integer.", raw),
+			}, http.StatusBadRequest)
+			utils.HandleError(w, clientError)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	jobService := provider.NewJobProvider().GetJobService()
+	jobs, err := jobService.ListJobs(orgHandle, filter)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, jobs, constants.JobResource)
+}
+
+// GetJob handles GET /jobs/{jobID}
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+
+	if err := security.AuthnAndAuthz(r, "job:view"); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	jobId := r.PathValue("jobID")
+
+	jobService := provider.NewJobProvider().GetJobService()
+	job, err := jobService.GetJob(orgHandle, jobId)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, job, constants.JobResource)
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job is a background task, such as a schema sync or a periodic cleanup, with its progress and outcome. Jobs that
+// concern the whole deployment rather than one organization belong to the root organization.
+type Job struct {
+	JobId      string          `json:"job_id"`
+	Type       string          `json:"type"`
+	OrgHandle  string          `json:"-"`
+	Status     string          `json:"status"`
+	Progress   int             `json:"progress"` // Percentage of the work done
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// JobFilter narrows a job listing down to a type and a status. Empty fields match every job.
+type JobFilter struct {
+	Type   string
+	Status string
+	Limit  int
+}
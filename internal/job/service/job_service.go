@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wso2/identity-customer-data-service/internal/job/model"
+	"github.com/wso2/identity-customer-data-service/internal/job/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// RunFunc does the work of a job. It reports the percentage of the work done through progress, and returns the
+// result to record on the job.
+type RunFunc func(progress func(percent int)) (result interface{}, err error)
+
+// Running jobs record a heartbeat every heartbeatInterval; a running job whose last record is older than
+// staleTimeout belongs to an instance that stopped.
+var (
+	heartbeatInterval = constants.JobHeartbeatInterval * time.Millisecond
+	staleTimeout      = constants.JobStaleTimeout * time.Millisecond
+)
+
+// JobServiceInterface defines the service interface.
+type JobServiceInterface interface {
+	CreateJob(jobType, orgHandle string) (*model.Job, error)
+	RunJob(jobId string, run RunFunc) error
+	FailJob(jobId, reason string) error
+	RunPeriodicJob(jobType string, interval time.Duration, run RunFunc) error
+	GetJob(orgHandle, jobId string) (*model.Job, error)
+	ListJobs(orgHandle string, filter model.JobFilter) ([]model.Job, error)
+}
+
+// JobService is the default implementation.
+type JobService struct{}
+
+// GetJobService returns a new instance.
+func GetJobService() JobServiceInterface {
+	return &JobService{}
+}
+
+// CreateJob records a pending job, to be run with RunJob.
+func (s *JobService) CreateJob(jobType, orgHandle string) (*model.Job, error) {
+
+	now := time.Now().UTC()
+	job := model.Job{
+		JobId:     uuid.New().String(),
+		Type:      jobType,
+		OrgHandle: orgHandle,
+		Status:    constants.JobPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := store.InsertJob(job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// RunJob runs the work of a job, recording its start, progress and outcome, and returns the error of the work.
+// A heartbeat is recorded while the work runs, so that a long job that does not report progress is not taken for
+// one whose instance stopped. Failing to record the job does not stop the work, which matters more than its record.
+func (s *JobService) RunJob(jobId string, run RunFunc) error {
+
+	logger := log.GetLogger()
+	if err := store.StartJob(jobId, time.Now().UTC()); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to record the start of job: %s", jobId), log.Error(err))
+	}
+	stopHeartbeat := heartbeat(jobId)
+
+	progress := 0
+	report := func(percent int) {
+		progress = min(max(percent, 0), 100)
+		if err := store.UpdateJobProgress(jobId, progress, time.Now().UTC()); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to record the progress of job: %s", jobId), log.Error(err))
+		}
+	}
+	result, runErr := run(report)
+	stopHeartbeat()
+
+	status, errorMsg := constants.JobCompleted, ""
+	if runErr != nil {
+		status, errorMsg = constants.JobFailed, runErr.Error()
+	} else {
+		progress = 100
+	}
+	var encoded json.RawMessage
+	if result != nil {
+		var err error
+		if encoded, err = json.Marshal(result); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to encode the result of job: %s", jobId), log.Error(err))
+		}
+	}
+	if err := store.FinishJob(jobId, status, progress, encoded, errorMsg, time.Now().UTC()); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to record the outcome of job: %s", jobId), log.Error(err))
+	}
+	return runErr
+}
+
+// heartbeat records every heartbeatInterval that a job is running, until the returned function is called.
+func heartbeat(jobId string) (stop func()) {
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.TouchJob(jobId, time.Now().UTC()); err != nil {
+					log.GetLogger().Warn(fmt.Sprintf("Failed to record the heartbeat of job: %s", jobId), log.Error(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// FailJob records that a job failed before it could run, e.g. because its work could not be enqueued.
+func (s *JobService) FailJob(jobId, reason string) error {
+	return store.FinishJob(jobId, constants.JobFailed, 0, nil, reason, time.Now().UTC())
+}
+
+// RunPeriodicJob runs a job of the whole deployment if its run is due, i.e. if no instance has run it within the
+// interval. Instances sharing the database run each periodic job in turn: a run is claimed by exactly one of them.
+// Runs left running by a stopped instance, which stopped recording their heartbeat, are failed first.
+func (s *JobService) RunPeriodicJob(jobType string, interval time.Duration, run RunFunc) error {
+
+	claimed, err := store.ClaimPeriodicJob(jobType, interval)
+	if err != nil || !claimed {
+		return err
+	}
+	staleBefore := time.Now().UTC().Add(-staleTimeout)
+	if err := store.FailStaleJobs(jobType, staleBefore, "The instance running the job stopped."); err != nil {
+		log.GetLogger().Warn(fmt.Sprintf("Failed to fail the stale jobs of type: %s", jobType), log.Error(err))
+	}
+	job, err := s.CreateJob(jobType, constants.DefaultTenant)
+	if err != nil {
+		return err
+	}
+	return s.RunJob(job.JobId, run)
+}
+
+// GetJob fetches a job of an org.
+func (s *JobService) GetJob(orgHandle, jobId string) (*model.Job, error) {
+
+	job, err := store.GetJob(orgHandle, jobId)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.NewClientError(errors.ErrorMessage{
+			Code:        errors.JOB_NOT_FOUND.Code,
+			Message:     errors.JOB_NOT_FOUND.Message,
+			Description: fmt.Sprintf("Job: %s not found.", jobId),
+		}, http.StatusNotFound)
+	}
+	return job, nil
+}
+
+// ListJobs fetches the jobs of an org matching the filter, newest first.
+func (s *JobService) ListJobs(orgHandle string, filter model.JobFilter) ([]model.Job, error) {
+
+	switch filter.Status {
+	case "", constants.JobPending, constants.JobRunning, constants.JobCompleted, constants.JobFailed:
+	default:
+		return nil, errors.NewClientError(errors.ErrorMessage{
+			Code:    errors.INVALID_JOB_FILTER.Code,
+			Message: errors.INVALID_JOB_FILTER.Message,
+			Description: fmt.Sprintf("Unknown job status: %s. Use %s, %s, %s or %s.", filter.Status,
+				constants.JobPending, constants.JobRunning, constants.JobCompleted, constants.JobFailed),
+		}, http.StatusBadRequest)
+	}
+	return store.ListJobs(orgHandle, filter)
+}
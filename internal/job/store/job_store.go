@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/job/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+func jobError(errorMessage errors.ErrorMessage, errorMsg string, err error) error {
+	log.GetLogger().Debug(errorMsg, log.Error(err))
+	return errors.NewServerError(errors.ErrorMessage{
+		Code:        errorMessage.Code,
+		Message:     errorMessage.Message,
+		Description: errorMsg,
+	}, err)
+}
+
+// execute runs a query that records a job.
+func execute(queries map[string]string, errorMsg string, args ...interface{}) ([]map[string]interface{}, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return nil, jobError(errors.UPDATE_JOB, "Failed to get database client for recording a job", err)
+	}
+	defer dbClient.Close()
+
+	results, err := dbClient.ExecuteQuery(queries[provider.NewDBProvider().GetDBType()], args...)
+	if err != nil {
+		return nil, jobError(errors.UPDATE_JOB, errorMsg, err)
+	}
+	return results, nil
+}
+
+// InsertJob persists a new job.
+func InsertJob(job model.Job) error {
+	_, err := execute(scripts.InsertJob, fmt.Sprintf("Failed to add job: %s", job.JobId),
+		job.JobId, job.Type, job.OrgHandle, job.Status, job.CreatedAt)
+	return err
+}
+
+// StartJob marks a job as running from the given time.
+func StartJob(jobId string, startedAt time.Time) error {
+	_, err := execute(scripts.StartJob, fmt.Sprintf("Failed to start job: %s", jobId), jobId, startedAt)
+	return err
+}
+
+// UpdateJobProgress records the progress of a running job.
+func UpdateJobProgress(jobId string, progress int, updatedAt time.Time) error {
+	_, err := execute(scripts.UpdateJobProgress, fmt.Sprintf("Failed to update the progress of job: %s", jobId),
+		jobId, progress, updatedAt)
+	return err
+}
+
+// TouchJob records that a running job is still running at the given time.
+func TouchJob(jobId string, updatedAt time.Time) error {
+	_, err := execute(scripts.TouchJob, fmt.Sprintf("Failed to record the heartbeat of job: %s", jobId),
+		jobId, updatedAt)
+	return err
+}
+
+// FinishJob records the outcome of a job: its final status, progress, result and error.
+func FinishJob(jobId, status string, progress int, result json.RawMessage, jobErr string, finishedAt time.Time) error {
+	var resultArg interface{}
+	if len(result) > 0 {
+		resultArg = string(result)
+	}
+	_, err := execute(scripts.FinishJob, fmt.Sprintf("Failed to finish job: %s", jobId),
+		jobId, status, progress, resultArg, jobErr, finishedAt)
+	return err
+}
+
+// FailStaleJobs fails the running jobs of a type that have not reported since staleBefore.
+func FailStaleJobs(jobType string, staleBefore time.Time, reason string) error {
+	_, err := execute(scripts.FailStaleJobs, fmt.Sprintf("Failed to fail the stale jobs of type: %s", jobType),
+		jobType, staleBefore, reason, time.Now().UTC())
+	return err
+}
+
+// ClaimPeriodicJob reports whether this instance is to run the periodic job now. At most one instance claims each
+// run, which is due interval after the previous one.
+func ClaimPeriodicJob(jobType string, interval time.Duration) (bool, error) {
+	results, err := execute(scripts.ClaimPeriodicJob, fmt.Sprintf("Failed to claim the periodic job: %s", jobType),
+		jobType, int64(interval/time.Second))
+	if err != nil {
+		return false, err
+	}
+	return len(results) > 0, nil
+}
+
+// GetJob fetches a job of an org, or nil if there is none with the given id.
+func GetJob(orgHandle, jobId string) (*model.Job, error) {
+	jobs, err := queryJobs(scripts.GetJob, orgHandle, jobId)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// ListJobs fetches the jobs of an org matching the filter, newest first.
+func ListJobs(orgHandle string, filter model.JobFilter) ([]model.Job, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = constants.DefaultLimit
+	}
+	return queryJobs(scripts.ListJobs, orgHandle, filter.Type, filter.Status, limit)
+}
+
+func queryJobs(queries map[string]string, args ...interface{}) ([]model.Job, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return nil, jobError(errors.GET_JOB, "Failed to get database client for fetching jobs", err)
+	}
+	defer dbClient.Close()
+
+	results, err := dbClient.ExecuteQuery(queries[provider.NewDBProvider().GetDBType()], args...)
+	if err != nil {
+		return nil, jobError(errors.GET_JOB, "Failed to fetch jobs", err)
+	}
+
+	jobs := make([]model.Job, 0, len(results))
+	for _, row := range results {
+		job := model.Job{
+			JobId:     row["job_id"].(string),
+			Type:      row["job_type"].(string),
+			OrgHandle: row["org_handle"].(string),
+			Status:    row["status"].(string),
+			Progress:  int(row["progress"].(int64)),
+			Error:     row["error"].(string),
+			CreatedAt: row["created_at"].(time.Time),
+			UpdatedAt: row["updated_at"].(time.Time),
+		}
+		if result, ok := row["result"].(string); ok && result != "" {
+			job.Result = json.RawMessage(result)
+		}
+		if startedAt, ok := row["started_at"].(time.Time); ok {
+			job.StartedAt = &startedAt
+		}
+		if finishedAt, ok := row["finished_at"].(time.Time); ok {
+			job.FinishedAt = &finishedAt
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
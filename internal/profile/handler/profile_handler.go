@@ -95,17 +95,10 @@ func (ph *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filterParams := parseApplicationDataParams(r)
-	// In app_id mode the caller's clientId is resolved to its app ID; the system-app check runs on that identifier.
-	callerClientID := getCallerClientIDFromRequest(r)
-	callerAppIdentifier := callerClientID
-	if config.GetCDSRuntime().Config.UsesAppIDIdentifier() && callerClientID != "" {
-		resolved, resolveErr := appProvider.NewApplicationProvider().GetApplicationService().
-			ResolveAppIdentifierByClientID(orgHandle, callerClientID)
-		if resolveErr != nil {
-			utils.HandleError(w, resolveErr)
-			return
-		}
-		callerAppIdentifier = resolved
+	callerAppIdentifier, err := resolveCallerAppIdentifier(r, orgHandle)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
 	}
 	isSystemApp := isCallerSystemApplication(orgHandle, callerAppIdentifier)
 
@@ -117,7 +110,14 @@ func (ph *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	)
 
 	if !isSystemApp {
-		consentIds := parseCommaSeparatedOrRepeated(r.URL.Query()["consentCategoryId"])
+		// Applications that declared purposes are restricted to the categories serving them; consentCategoryId
+		// can only narrow that set.
+		requestedIds := parseCommaSeparatedOrRepeated(r.URL.Query()["consentCategoryId"])
+		consentIds, _, resolveErr := profileService.ResolvePurposeConsentIds(orgHandle, callerAppIdentifier, requestedIds)
+		if resolveErr != nil {
+			utils.HandleError(w, resolveErr)
+			return
+		}
 		filtered, filterErr := profileService.FilterProfileByConsent(*profile, profileId, orgHandle, consentIds)
 		if filterErr != nil {
 			utils.HandleError(w, filterErr)
@@ -352,6 +352,29 @@ func (ph *ProfileHandler) GetAllProfiles(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Listing is only consent-filtered for non-system callers whose application declared purposes.
+	callerAppIdentifier, err := resolveCallerAppIdentifier(r, orgHandle)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCallerSystemApplication(orgHandle, callerAppIdentifier) {
+		requestedIds := parseCommaSeparatedOrRepeated(r.URL.Query()["consentCategoryId"])
+		consentIds, enforced, resolveErr := profileService.ResolvePurposeConsentIds(orgHandle, callerAppIdentifier, requestedIds)
+		if resolveErr != nil {
+			utils.HandleError(w, resolveErr)
+			return
+		}
+		if enforced {
+			filtered, filterErr := profileService.FilterProfilesByConsent(profiles, orgHandle, consentIds)
+			if filterErr != nil {
+				utils.HandleError(w, filterErr)
+				return
+			}
+			profiles = filtered
+		}
+	}
+
 	items := buildProfileListResponse(profiles, requestedAttrs)
 
 	var nextCursorStr, prevCursorStr string
@@ -576,6 +599,7 @@ func (ph *ProfileHandler) InitProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// If no valid cookie, create a new profile and cookie
+	profile.SourceApplication = callerSourceApplication(r, orgHandle)
 	profileResponse, err := profilesService.CreateProfile(profile, orgHandle)
 	if err != nil {
 		utils.HandleError(w, err)
@@ -693,6 +717,7 @@ func (ph *ProfileHandler) UpdateProfile(writer http.ResponseWriter, request *htt
 	profilesProvider := provider.NewProfilesProvider()
 	profilesService := profilesProvider.GetProfilesService()
 
+	profile.SourceApplication = callerSourceApplication(request, orgHandle)
 	_, err = profilesService.UpdateProfile(profileId, orgHandle, profile)
 	if err != nil {
 		utils.HandleError(writer, err)
@@ -751,7 +776,7 @@ func (ph *ProfileHandler) PatchProfile(w http.ResponseWriter, r *http.Request) {
 
 	profilesProvider := provider.NewProfilesProvider()
 	profilesService := profilesProvider.GetProfilesService()
-	_, err = profilesService.PatchProfile(profileId, orgHandle, patchData)
+	_, err = profilesService.PatchProfile(profileId, orgHandle, patchData, callerSourceApplication(r, orgHandle))
 	if err != nil {
 		utils.HandleError(w, err)
 		return
@@ -863,7 +888,7 @@ func (ph *ProfileHandler) PatchCurrentUserProfile(w http.ResponseWriter, r *http
 	}
 
 	// Apply patch
-	updatedProfile, err := profilesService.PatchProfile(profileId, orgHandle, patchData)
+	updatedProfile, err := profilesService.PatchProfile(profileId, orgHandle, patchData, callerSourceApplication(r, orgHandle))
 	if err != nil {
 		utils.HandleError(w, err)
 		return
@@ -1281,11 +1306,15 @@ func (ph *ProfileHandler) GetProfileConsents(w http.ResponseWriter, r *http.Requ
 	profilesService := profilesProvider.GetProfilesService()
 
 	// Verify profile exists first
-	consentRecords, err := profilesService.GetProfileConsents(profileId)
+	consentRecords, err := profilesService.GetProfileConsents(profileId, orgHandle)
 	if err != nil {
 		utils.HandleError(w, err)
 		return
 	}
+	if err := profileService.LocalizeConsentRecords(orgHandle, r.URL.Query().Get("locale"), consentRecords); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -1342,12 +1371,58 @@ func (ph *ProfileHandler) UpdateProfileConsents(w http.ResponseWriter, r *http.R
 		utils.HandleError(w, err)
 		return
 	}
+	if err := profileService.LocalizeConsentRecords(orgHandle, r.URL.Query().Get("locale"), consentUpdate); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(consentUpdate)
 }
 
+// GetConsentReceipt handles retrieval of a consent receipt issued for a profile's consent change
+func (ph *ProfileHandler) GetConsentReceipt(w http.ResponseWriter, r *http.Request) {
+
+	profileId := r.PathValue("profileId")
+	receiptId := r.PathValue("receiptId")
+	if profileId == "" || receiptId == "" {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.GET_PROFILE_CONSENT.Code,
+			Message:     errors2.GET_PROFILE_CONSENT.Message,
+			Description: "Invalid path for consent receipt retrieval",
+		}, http.StatusNotFound)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	err := security.AuthnAndAuthz(r, "profile:view")
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	profilesService := provider.NewProfilesProvider().GetProfilesService()
+	receipt, err := profilesService.GetConsentReceipt(profileId, orgHandle, receiptId)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, receipt, constants.ProfileResource)
+}
+
 func setNestedMapValue(m map[string]interface{}, path string, value interface{}) {
 	parts := strings.Split(path, ".")
 	current := m
@@ -1387,6 +1462,28 @@ func extractClaimKeyFromLocalURI(localURI string) string {
 	return parts[len(parts)-1]
 }
 
+// resolveCallerAppIdentifier returns the caller's application identifier in the configured mode. In app_id mode the
+// caller's clientId is resolved to its app ID; an unknown clientId resolves to an empty identifier.
+func resolveCallerAppIdentifier(r *http.Request, orgHandle string) (string, error) {
+	callerClientID := getCallerClientIDFromRequest(r)
+	if !config.GetCDSRuntime().Config.UsesAppIDIdentifier() || callerClientID == "" {
+		return callerClientID, nil
+	}
+	return appProvider.NewApplicationProvider().GetApplicationService().
+		ResolveAppIdentifierByClientID(orgHandle, callerClientID)
+}
+
+// callerSourceApplication returns the calling application, recorded as the source of the attributes it writes. An
+// application that cannot be resolved is recorded as unknown rather than failing the write.
+func callerSourceApplication(r *http.Request, orgHandle string) string {
+	appIdentifier, err := resolveCallerAppIdentifier(r, orgHandle)
+	if err != nil {
+		log.GetLogger().Debug("Failed to resolve the source application of a profile write", log.Error(err))
+		return ""
+	}
+	return appIdentifier
+}
+
 func getCallerClientIDFromRequest(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	if !strings.HasPrefix(authHeader, "Bearer ") {
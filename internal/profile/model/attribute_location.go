@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+// AttributeLocation identifies where the values of a top-level schema attribute are stored across the profiles
+// of an organization.
+type AttributeLocation struct {
+	OrgHandle             string
+	Scope                 string // identity_attributes, traits or application_data
+	Key                   string // Attribute name without the scope prefix
+	ApplicationIdentifier string // Set for application_data
+}
+
+// ProfileAttributeValue is the stored value of an attribute in a single profile.
+type ProfileAttributeValue struct {
+	ProfileId string
+	Value     interface{}
+}
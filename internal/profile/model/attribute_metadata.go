@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import "time"
+
+// AttributeMetadata records when a top-level profile attribute was last written and which application wrote it.
+// Timestamp-aware merge strategies (latest, oldest, source_priority) use it to pick between conflicting values.
+type AttributeMetadata struct {
+	UpdatedAt         time.Time `json:"updated_at"`
+	SourceApplication string    `json:"source_application,omitempty"`
+}
+
+// AttributeMetadataKey builds the metadata key of an attribute: "traits.<key>" and "identity_attributes.<key>" for
+// profile-level attributes, "application_data.<appId>.<key>" for application data.
+func AttributeMetadataKey(scope, appId, key string) string {
+	if appId != "" {
+		return scope + "." + appId + "." + key
+	}
+	return scope + "." + key
+}
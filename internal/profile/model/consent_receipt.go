@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import "time"
+
+// ConsentReceiptVersion is the Kantara Consent Receipt specification version the receipts conform to.
+const ConsentReceiptVersion = "KI-CR-v1.1.0"
+
+// ConsentReceipt is a consent receipt as defined by the Kantara Initiative Consent Receipt Specification v1.1.
+type ConsentReceipt struct {
+	Version          string                 `json:"version"`
+	Jurisdiction     string                 `json:"jurisdiction"`
+	ConsentTimestamp int64                  `json:"consentTimestamp"`
+	CollectionMethod string                 `json:"collectionMethod"`
+	ConsentReceiptID string                 `json:"consentReceiptID"`
+	Language         string                 `json:"language,omitempty"`
+	PiiPrincipalID   string                 `json:"piiPrincipalId"`
+	PiiControllers   []ReceiptPiiController `json:"piiControllers"`
+	PolicyURL        string                 `json:"policyUrl"`
+	Services         []ReceiptService       `json:"services"`
+	Sensitive        bool                   `json:"sensitive"`
+	SpiCat           []string               `json:"spiCat"`
+}
+
+// ReceiptPiiController identifies the controller that processes the PII principal's data.
+type ReceiptPiiController struct {
+	PiiController    string `json:"piiController"`
+	OnBehalf         bool   `json:"onBehalf,omitempty"`
+	Contact          string `json:"contact"`
+	Address          string `json:"address"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	PiiControllerURL string `json:"piiControllerUrl,omitempty"`
+}
+
+// ReceiptService groups the purposes consented to for one service. Each consent category is a service.
+type ReceiptService struct {
+	Service  string           `json:"service"`
+	Purposes []ReceiptPurpose `json:"purposes"`
+}
+
+// ReceiptPurpose describes a single purpose of processing and the PII categories it covers.
+type ReceiptPurpose struct {
+	Purpose              string   `json:"purpose"`
+	PurposeCategory      []string `json:"purposeCategory"`
+	ConsentType          string   `json:"consentType"`
+	PiiCategory          []string `json:"piiCategory"`
+	PrimaryPurpose       bool     `json:"primaryPurpose"`
+	Termination          string   `json:"termination"`
+	ThirdPartyDisclosure bool     `json:"thirdPartyDisclosure"`
+	ThirdPartyName       string   `json:"thirdPartyName,omitempty"`
+	// Withdrawn marks a purpose whose consent the change withdrew. It extends the specification, which only
+	// describes consent being given.
+	Withdrawn bool `json:"withdrawn,omitempty"`
+}
+
+// ConsentReceiptRecord is the persisted form of a consent receipt.
+type ConsentReceiptRecord struct {
+	ReceiptId string
+	ProfileId string
+	OrgHandle string
+	CreatedAt time.Time
+	Receipt   ConsentReceipt
+}
@@ -49,6 +49,8 @@ type Profile struct {
 	Traits             map[string]interface{} `json:"traits,omitempty" bson:"traits,omitempty"`
 	ApplicationData    []ApplicationData      `json:"application_data,omitempty" bson:"application_data,omitempty"`
 	ProfileStatus      *ProfileStatus         `json:"profile_status,omitempty" bson:"profile_status,omitempty"`
+	// AttributeMetadata tracks the last write of each top-level attribute, keyed by AttributeMetadataKey.
+	AttributeMetadata map[string]AttributeMetadata `json:"attribute_metadata,omitempty" bson:"attribute_metadata,omitempty"`
 }
 
 type ProfileCookie struct {
@@ -64,9 +66,15 @@ type ProfileConsent struct {
 
 // ConsentRecord represents an individual consent record for a profile
 type ConsentRecord struct {
-	CategoryIdentifier string    `json:"category_identifier" bson:"category_identifier"` // References the consent category
-	IsConsented        bool      `json:"is_consented" bson:"is_consented"`               // Whether the user has given consent
-	ConsentedAt        time.Time `json:"consented_at" bson:"consented_at"`               // Timestamp when consent was given/updated
+	CategoryIdentifier string    `json:"category_identifier" bson:"category_identifier"`   // References the consent category
+	IsConsented        bool      `json:"is_consented" bson:"is_consented"`                 // Whether the user has given consent
+	ConsentedAt        time.Time `json:"consented_at" bson:"consented_at"`                 // Timestamp when consent was given/updated
+	ReceiptId          string    `json:"receipt_id,omitempty" bson:"receipt_id,omitempty"` // Consent receipt issued for the change
+	// CategoryName, Locale, DisplayName and PurposeDescription describe the category in the response; they are not stored.
+	CategoryName       string `json:"category_name,omitempty" bson:"-"`
+	Locale             string `json:"locale,omitempty" bson:"-"`
+	DisplayName        string `json:"display_name,omitempty" bson:"-"`
+	PurposeDescription string `json:"purpose_description,omitempty" bson:"-"`
 }
 
 // ProfileConsentResponse is the response model for profile consents API
@@ -54,6 +54,8 @@ type ProfileRequest struct {
 	IdentityAttributes map[string]interface{} `json:"identity_attributes,omitempty" bson:"identity_attributes,omitempty"`
 	Traits             map[string]interface{} `json:"traits,omitempty" bson:"traits,omitempty"`
 	ApplicationData    map[string]interface{} `json:"application_data"`
+	// SourceApplication is the application writing the profile; it is recorded as the source of changed attributes.
+	SourceApplication string `json:"-" bson:"-"`
 }
 
 type ProfileSync struct {
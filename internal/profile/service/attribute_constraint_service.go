@@ -0,0 +1,240 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+)
+
+// Names of the constraints reported in error details.
+const (
+	constraintRequired  = "required"
+	constraintMinimum   = "minimum"
+	constraintMaximum   = "maximum"
+	constraintMinDate   = "min_date"
+	constraintMaxDate   = "max_date"
+	constraintMinLength = "min_length"
+	constraintMaxLength = "max_length"
+	constraintPattern   = "pattern"
+	constraintFormat    = "format"
+	constraintMaxItems  = "max_items"
+)
+
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// isoCountryCodes holds the ISO 3166-1 alpha-2 country codes.
+var isoCountryCodes = func() map[string]bool {
+	codes := "AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV " +
+		"BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI " +
+		"FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO " +
+		"IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG " +
+		"MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK " +
+		"PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC " +
+		"TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW"
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}()
+
+// patternCache holds compiled constraint patterns, keyed by their source.
+var patternCache sync.Map
+
+// constraintReport collects the constraint violations of a profile write so that all of them are reported at once.
+type constraintReport struct {
+	details []errors2.ErrorDetail
+}
+
+func (r *constraintReport) add(attribute, constraint, message string) {
+	r.details = append(r.details, errors2.ErrorDetail{Attribute: attribute, Constraint: constraint, Message: message})
+}
+
+// err returns a client error listing every violation, or nil if there is none.
+func (r *constraintReport) err() error {
+	if len(r.details) == 0 {
+		return nil
+	}
+	return errors2.NewClientErrorWithDetails(errors2.PROFILE_CONSTRAINT_VIOLATION, http.StatusBadRequest, r.details)
+}
+
+// checkAttributeConstraints checks a value that already matches the attribute's type against its constraints.
+func checkAttributeConstraints(attr model.ProfileSchemaAttribute, val interface{}, path string, report *constraintReport) {
+	c := attr.Constraints
+	if c == nil {
+		return
+	}
+	if attr.MultiValued {
+		items, _ := val.([]interface{})
+		if c.MaxItems != nil && len(items) > *c.MaxItems {
+			report.add(path, constraintMaxItems, fmt.Sprintf("must have at most %d items", *c.MaxItems))
+		}
+		for i, item := range items {
+			checkScalarConstraints(attr, item, fmt.Sprintf("%s[%d]", path, i), report)
+		}
+		return
+	}
+	checkScalarConstraints(attr, val, path, report)
+}
+
+func checkScalarConstraints(attr model.ProfileSchemaAttribute, val interface{}, path string, report *constraintReport) {
+	c := attr.Constraints
+	switch attr.ValueType {
+	case constants.IntegerDataType, constants.DecimalDataType, constants.EpochDataType:
+		n, ok := toFloat64(val)
+		if !ok {
+			return
+		}
+		if c.Minimum != nil && n < *c.Minimum {
+			report.add(path, constraintMinimum, fmt.Sprintf("must be at least %v", *c.Minimum))
+		}
+		if c.Maximum != nil && n > *c.Maximum {
+			report.add(path, constraintMaximum, fmt.Sprintf("must be at most %v", *c.Maximum))
+		}
+	case constants.DateDataType, constants.DateTimeDataType:
+		s, ok := val.(string)
+		if !ok || (c.MinDate == "" && c.MaxDate == "") {
+			return
+		}
+		t, err := schemaService.ParseConstraintDate(s)
+		if err != nil {
+			report.add(path, constraintFormat, "must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+			return
+		}
+		if minDate, err := schemaService.ParseConstraintDate(c.MinDate); err == nil && c.MinDate != "" && t.Before(minDate) {
+			report.add(path, constraintMinDate, fmt.Sprintf("must not be before %s", c.MinDate))
+		}
+		if maxDate, err := schemaService.ParseConstraintDate(c.MaxDate); err == nil && c.MaxDate != "" && t.After(maxDate) {
+			report.add(path, constraintMaxDate, fmt.Sprintf("must not be after %s", c.MaxDate))
+		}
+	case constants.StringDataType:
+		s, ok := val.(string)
+		if !ok {
+			return
+		}
+		length := utf8.RuneCountInString(s)
+		if c.MinLength != nil && length < *c.MinLength {
+			report.add(path, constraintMinLength, fmt.Sprintf("must be at least %d characters long", *c.MinLength))
+		}
+		if c.MaxLength != nil && length > *c.MaxLength {
+			report.add(path, constraintMaxLength, fmt.Sprintf("must be at most %d characters long", *c.MaxLength))
+		}
+		if c.Pattern != "" {
+			if re := compiledPattern(c.Pattern); re != nil && !re.MatchString(s) {
+				report.add(path, constraintPattern, fmt.Sprintf("must match the pattern %s", c.Pattern))
+			}
+		}
+		if c.Format != "" && !matchesFormat(c.Format, s) {
+			report.add(path, constraintFormat, fmt.Sprintf("must be a valid %s", c.Format))
+		}
+	}
+}
+
+// checkRequiredAttributes reports required attributes missing from the profile being written: the new profile on a
+// create, and the resulting profile on an update or a patch. A scope absent from the profile is missing all of its
+// attributes. Application data is only checked for the applications present in the profile.
+func checkRequiredAttributes(profile profileModel.ProfileRequest, schema model.ProfileSchema, report *constraintReport) {
+	checkRequiredInScope(profile.IdentityAttributes, schema.IdentityAttributes, constants.IdentityAttributes+".",
+		constants.IdentityAttributes, report)
+	checkRequiredInScope(profile.Traits, schema.Traits, constants.Traits+".", constants.Traits, report)
+	for appID, rawAttrs := range profile.ApplicationData {
+		attrs, _ := rawAttrs.(map[string]interface{})
+		checkRequiredInScope(attrs, schema.ApplicationData[appID], constants.ApplicationData+".",
+			constants.ApplicationData+"."+appID, report)
+	}
+}
+
+// checkRequiredInScope reports the required attributes directly under namePrefix that are missing from values.
+// Reported paths start with pathPrefix.
+func checkRequiredInScope(values map[string]interface{}, attrs []model.ProfileSchemaAttribute, namePrefix, pathPrefix string,
+	report *constraintReport) {
+	for _, attr := range attrs {
+		if attr.Constraints == nil || !attr.Constraints.Required || !strings.HasPrefix(attr.AttributeName, namePrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(attr.AttributeName, namePrefix)
+		if strings.Contains(key, ".") {
+			continue // sub-attributes are checked within their parent object
+		}
+		if !hasRequiredValue(values[key]) {
+			report.add(pathPrefix+"."+key, constraintRequired, "is required")
+		}
+	}
+}
+
+func hasRequiredValue(v interface{}) bool {
+	if arr, ok := v.([]interface{}); ok {
+		return len(arr) > 0
+	}
+	return hasExistingValue(v)
+}
+
+func compiledPattern(pattern string) *regexp.Regexp {
+	if cached, ok := patternCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil // rejected when the schema was saved
+	}
+	patternCache.Store(pattern, re)
+	return re
+}
+
+func matchesFormat(format, s string) bool {
+	switch format {
+	case constants.ConstraintFormatEmail:
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case constants.ConstraintFormatE164:
+		return e164Regex.MatchString(s)
+	case constants.ConstraintFormatURI:
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	case constants.ConstraintFormatISOCountry:
+		return isoCountryCodes[s]
+	default:
+		return true
+	}
+}
+
+// qualifiedAttributePath turns the label and path used while validating a write into the attribute's full path.
+func qualifiedAttributePath(attributeLabel, attributePath string) string {
+	switch attributeLabel {
+	case "identity attribute":
+		return constants.IdentityAttributes + "." + attributePath
+	case "trait":
+		return constants.Traits + "." + attributePath
+	default:
+		return constants.ApplicationData + "." + attributePath
+	}
+}
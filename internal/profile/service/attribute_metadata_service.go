@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// stampAttributeMetadata returns the attribute metadata of a profile after a write. New and changed top-level
+// attributes are stamped with the write time and source application; unchanged attributes keep their previous
+// metadata, and attributes no longer present lose it.
+func stampAttributeMetadata(previous, updated profileModel.Profile, sourceApp string,
+	writtenAt time.Time) map[string]profileModel.AttributeMetadata {
+
+	stamped := make(map[string]profileModel.AttributeMetadata)
+	stamp := func(key string, oldVal interface{}, existed bool, newVal interface{}) {
+		if existed && valuesEqualForMutability(oldVal, newVal) {
+			if meta, ok := previous.AttributeMetadata[key]; ok {
+				stamped[key] = meta
+			}
+			return
+		}
+		stamped[key] = profileModel.AttributeMetadata{UpdatedAt: writtenAt, SourceApplication: sourceApp}
+	}
+
+	for k, v := range updated.Traits {
+		oldVal, existed := previous.Traits[k]
+		stamp(profileModel.AttributeMetadataKey(constants.Traits, "", k), oldVal, existed, v)
+	}
+	for k, v := range updated.IdentityAttributes {
+		oldVal, existed := previous.IdentityAttributes[k]
+		stamp(profileModel.AttributeMetadataKey(constants.IdentityAttributes, "", k), oldVal, existed, v)
+	}
+
+	previousAppData := make(map[string]map[string]interface{}, len(previous.ApplicationData))
+	for _, app := range previous.ApplicationData {
+		previousAppData[app.AppId] = app.AppSpecificData
+	}
+	for _, app := range updated.ApplicationData {
+		for k, v := range app.AppSpecificData {
+			oldVal, existed := previousAppData[app.AppId][k]
+			stamp(profileModel.AttributeMetadataKey(constants.ApplicationData, app.AppId, k), oldVal, existed, v)
+		}
+		// Application data is upserted per application, so keys of an app are never removed by a write.
+		for k := range previousAppData[app.AppId] {
+			key := profileModel.AttributeMetadataKey(constants.ApplicationData, app.AppId, k)
+			if _, ok := stamped[key]; !ok {
+				if meta, ok := previous.AttributeMetadata[key]; ok {
+					stamped[key] = meta
+				}
+			}
+		}
+	}
+	// Applications absent from the write keep their data, and so their metadata.
+	for key, meta := range previous.AttributeMetadata {
+		if _, ok := stamped[key]; ok {
+			continue
+		}
+		if isApplicationDataKeyOfAbsentApp(key, updated.ApplicationData) {
+			stamped[key] = meta
+		}
+	}
+	return stamped
+}
+
+// isApplicationDataKeyOfAbsentApp reports whether the metadata key belongs to an application that is not part of
+// the written application data.
+func isApplicationDataKeyOfAbsentApp(key string, written []profileModel.ApplicationData) bool {
+	if !strings.HasPrefix(key, constants.ApplicationData+".") {
+		return false
+	}
+	for _, app := range written {
+		if strings.HasPrefix(key, constants.ApplicationData+"."+app.AppId+".") {
+			return false
+		}
+	}
+	return true
+}
@@ -22,12 +22,51 @@ import (
 	"fmt"
 	"strings"
 
+	appProvider "github.com/wso2/identity-customer-data-service/internal/application/provider"
 	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
 	consentStore "github.com/wso2/identity-customer-data-service/internal/consent/store"
 	"github.com/wso2/identity-customer-data-service/internal/profile/model"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
 )
 
+// ResolvePurposeConsentIds returns the consent categories a caller may read through, given the purposes its
+// application has declared.
+//
+// When purposes are declared, the result is every category of the org serving one of those purposes; explicitly
+// requested category IDs can only narrow that set. The second return value reports whether purpose enforcement
+// applied. Callers without declared purposes get the requested IDs back unchanged.
+func ResolvePurposeConsentIds(orgHandle string, appIdentifier string, requestedIds []string) ([]string, bool, error) {
+
+	purposes, err := appProvider.NewApplicationProvider().GetApplicationService().
+		GetApplicationPurposes(appIdentifier, orgHandle)
+	if err != nil {
+		return nil, false, err
+	}
+	if purposes == nil {
+		return requestedIds, false, nil
+	}
+
+	permittedIds, err := consentStore.GetConsentCategoryIdsByPurposes(orgHandle, purposes)
+	if err != nil {
+		return nil, true, err
+	}
+	if len(requestedIds) == 0 {
+		return permittedIds, true, nil
+	}
+
+	permitted := make(map[string]bool, len(permittedIds))
+	for _, id := range permittedIds {
+		permitted[id] = true
+	}
+	narrowed := make([]string, 0, len(requestedIds))
+	for _, id := range requestedIds {
+		if permitted[id] {
+			narrowed = append(narrowed, id)
+		}
+	}
+	return narrowed, true, nil
+}
+
 // FilterProfileByConsent returns a ProfileResponse filtered to only the attributes
 // the profile owner has consented to across all requested consent categories.
 //
@@ -36,19 +75,51 @@ import (
 // If consentCategoryIds is empty, only mandatory identity fields are returned.
 func FilterProfileByConsent(response model.ProfileResponse, profileId string, orgHandle string, consentIds []string) (model.ProfileResponse, error) {
 
-	filtered := model.ProfileResponse{
-		ProfileId:  response.ProfileId,
-		UserId:     response.UserId,
-		Meta:       response.Meta,
-		MergedTo:   response.MergedTo,
-		MergedFrom: response.MergedFrom,
+	merged, _, err := withMandatoryConsentIds(orgHandle, consentIds)
+	if err != nil || len(merged) == 0 {
+		return consentFilteredBase(response), err
+	}
+
+	// Fetch attributes for categories the profile has actively consented to (mandatory categories always included).
+	attrsByCategory, err := consentStore.GetConsentedCategoryAttributesByProfileId(profileId, orgHandle, merged)
+	if err != nil {
+		return consentFilteredBase(response), err
+	}
+	return filterByConsentedAttributes(response, merged, attrsByCategory), nil
+}
+
+// FilterProfilesByConsent filters a page of profiles as FilterProfileByConsent does, looking up the consents of the
+// whole page at once.
+func FilterProfilesByConsent(responses []model.ProfileResponse, orgHandle string, consentIds []string) ([]model.ProfileResponse, error) {
+
+	merged, mandatoryIds, err := withMandatoryConsentIds(orgHandle, consentIds)
+	if err != nil {
+		return nil, err
+	}
+	profileIds := make([]string, len(responses))
+	for i, response := range responses {
+		profileIds[i] = response.ProfileId
+	}
+	attrsByProfile, err := consentStore.GetConsentedCategoryAttributesByProfileIds(profileIds, orgHandle, merged,
+		mandatoryIds)
+	if err != nil {
+		return nil, err
 	}
 
-	// Always include mandatory categories in the allowed set regardless of what
-	// the caller requested.  This ensures identity attributes are never stripped.
+	filtered := make([]model.ProfileResponse, len(responses))
+	for i, response := range responses {
+		filtered[i] = filterByConsentedAttributes(response, merged, attrsByProfile[response.ProfileId])
+	}
+	return filtered, nil
+}
+
+// withMandatoryConsentIds adds the org's mandatory categories to the requested ones, so that identity attributes
+// are never stripped. It returns the merged categories and the mandatory ones.
+func withMandatoryConsentIds(orgHandle string, consentIds []string) ([]string, []string, error) {
+
 	mandatoryIds, err := consentStore.GetMandatoryConsentCategoryIds(orgHandle)
 	if err != nil {
-		return filtered, err
+		return nil, nil, err
 	}
 	seen := make(map[string]bool, len(consentIds)+len(mandatoryIds))
 	merged := make([]string, 0, len(consentIds)+len(mandatoryIds))
@@ -64,15 +135,26 @@ func FilterProfileByConsent(response model.ProfileResponse, profileId string, or
 			merged = append(merged, id)
 		}
 	}
-	if len(merged) == 0 {
-		return filtered, nil
-	}
+	return merged, mandatoryIds, nil
+}
 
-	// Fetch attributes for categories the profile has actively consented to (mandatory categories always included).
-	attrsByCategory, err := consentStore.GetConsentedCategoryAttributesByProfileId(profileId, orgHandle, merged)
-	if err != nil {
-		return filtered, err
+// consentFilteredBase returns the parts of a profile that are not subject to consent.
+func consentFilteredBase(response model.ProfileResponse) model.ProfileResponse {
+	return model.ProfileResponse{
+		ProfileId:  response.ProfileId,
+		UserId:     response.UserId,
+		Meta:       response.Meta,
+		MergedTo:   response.MergedTo,
+		MergedFrom: response.MergedFrom,
 	}
+}
+
+// filterByConsentedAttributes keeps the attributes of a profile covered by the given categories, given the
+// attributes of each category the profile may be read through.
+func filterByConsentedAttributes(response model.ProfileResponse, merged []string,
+	attrsByCategory map[string][]consentModel.ConsentAttribute) model.ProfileResponse {
+
+	filtered := consentFilteredBase(response)
 
 	// Build per-category attribute key sets, then compute the union.
 	// Trait / identity-attribute keys:  "<scope>::<topLevelAttr>"
@@ -94,7 +176,7 @@ func FilterProfileByConsent(response model.ProfileResponse, profileId string, or
 
 	allowed := unionSets(categorySets)
 	if len(allowed) == 0 {
-		return filtered, nil
+		return filtered
 	}
 
 	// Filter identityAttributes
@@ -164,7 +246,7 @@ func FilterProfileByConsent(response model.ProfileResponse, profileId string, or
 		}
 	}
 
-	return filtered, nil
+	return filtered
 }
 
 // attributeKey returns the canonical string key for a ConsentAttribute used for
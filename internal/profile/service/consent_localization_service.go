@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	consentService "github.com/wso2/identity-customer-data-service/internal/consent/service"
+	consentStore "github.com/wso2/identity-customer-data-service/internal/consent/store"
+	"github.com/wso2/identity-customer-data-service/internal/profile/model"
+)
+
+// LocalizeConsentRecords fills in the category name and, for the requested locale (falling back to the default
+// locale), the display name and purpose description of each consent record. Records whose category has no
+// matching localization only get the category name.
+func LocalizeConsentRecords(orgHandle string, locale string, records []model.ConsentRecord) error {
+
+	if len(records) == 0 {
+		return nil
+	}
+	categories, err := consentStore.GetAllConsentCategories(orgHandle)
+	if err != nil {
+		return err
+	}
+	defaultLocale := consentService.DefaultLocale()
+	for i := range records {
+		for _, category := range categories {
+			if category.CategoryIdentifier != records[i].CategoryIdentifier {
+				continue
+			}
+			records[i].CategoryName = category.CategoryName
+			if key, localization, ok := category.ResolveLocalization(locale, defaultLocale); ok {
+				records[i].Locale = key
+				records[i].DisplayName = localization.DisplayName
+				records[i].PurposeDescription = localization.PurposeDescription
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
+	"github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+)
+
+const (
+	receiptCollectionMethod = "Profile consents API"
+	receiptConsentType      = "EXPLICIT"
+	receiptTermination      = "Until the consent is withdrawn"
+	receiptWithdrawn        = "Withdrawn"
+)
+
+// buildConsentReceipt assembles a Kantara consent receipt for a consent change. Each category of the change becomes a
+// service whose single purpose carries the category's purpose, attributes and destinations. The purposes of revoked
+// categories are marked as withdrawn, so that the receipt records what the principal withdrew.
+func buildConsentReceipt(receiptId string, principalId string, issuedAt time.Time,
+	consents []model.ConsentRecord, categories map[string]*consentModel.ConsentCategory) model.ConsentReceipt {
+
+	receiptCfg := config.GetCDSRuntime().Config.ConsentReceipt
+	services := make([]model.ReceiptService, 0, len(consents))
+	for _, consent := range consents {
+		category := categories[consent.CategoryIdentifier]
+		if category == nil {
+			continue
+		}
+		piiCategories := make([]string, 0, len(category.Attributes))
+		for _, attr := range category.Attributes {
+			piiCategories = append(piiCategories, attr.AttributeName)
+		}
+		termination := receiptTermination
+		if !consent.IsConsented {
+			termination = receiptWithdrawn
+		}
+		services = append(services, model.ReceiptService{
+			Service: category.CategoryName,
+			Purposes: []model.ReceiptPurpose{{
+				Purpose:              category.Purpose,
+				PurposeCategory:      []string{category.Purpose},
+				ConsentType:          receiptConsentType,
+				PiiCategory:          piiCategories,
+				PrimaryPurpose:       true,
+				Termination:          termination,
+				ThirdPartyDisclosure: len(category.Destinations) > 0,
+				ThirdPartyName:       strings.Join(category.Destinations, ", "),
+				Withdrawn:            !consent.IsConsented,
+			}},
+		})
+	}
+
+	return model.ConsentReceipt{
+		Version:          model.ConsentReceiptVersion,
+		Jurisdiction:     receiptCfg.Jurisdiction,
+		ConsentTimestamp: issuedAt.Unix(),
+		CollectionMethod: receiptCollectionMethod,
+		ConsentReceiptID: receiptId,
+		Language:         receiptCfg.Language,
+		PiiPrincipalID:   principalId,
+		PiiControllers: []model.ReceiptPiiController{{
+			PiiController:    receiptCfg.Controller.Name,
+			Contact:          receiptCfg.Controller.Contact,
+			Address:          receiptCfg.Controller.Address,
+			Email:            receiptCfg.Controller.Email,
+			Phone:            receiptCfg.Controller.Phone,
+			PiiControllerURL: receiptCfg.Controller.URL,
+		}},
+		PolicyURL: receiptCfg.PolicyURL,
+		Services:  services,
+		Sensitive: false,
+		SpiCat:    []string{},
+	}
+}
@@ -19,6 +19,7 @@
 package service
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -34,6 +35,7 @@ import (
 	"github.com/wso2/identity-customer-data-service/internal/system/utils"
 	"github.com/wso2/identity-customer-data-service/internal/system/workers"
 
+	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
 	consentStore "github.com/wso2/identity-customer-data-service/internal/consent/store"
 	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
 	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
@@ -51,9 +53,10 @@ type ProfilesServiceInterface interface {
 	GetProfile(profileId string) (*profileModel.ProfileResponse, error)
 	FindProfileByUserId(userId string) (*profileModel.ProfileResponse, error)
 	GetAllProfilesWithFilterCursor(orgHandle string, filters []string, limit int, cursor *profileModel.ProfileCursor) ([]profileModel.ProfileResponse, bool, error)
-	GetProfileConsents(profileId string) ([]profileModel.ConsentRecord, error)
+	GetProfileConsents(profileId string, orgHandle string) ([]profileModel.ConsentRecord, error)
 	UpdateProfileConsents(profileId string, orgHandle string, consents []profileModel.ConsentRecord) error
-	PatchProfile(profileId, orgHandle string, data map[string]interface{}) (*profileModel.ProfileResponse, error)
+	GetConsentReceipt(profileId string, orgHandle string, receiptId string) (*profileModel.ConsentReceipt, error)
+	PatchProfile(profileId, orgHandle string, data map[string]interface{}, sourceApp string) (*profileModel.ProfileResponse, error)
 	GetProfileCookieByProfileId(profileId string) (*profileModel.ProfileCookie, error)
 	GetProfileCookieById(cookie string) (*profileModel.ProfileCookie, error)
 	CreateProfileCookie(profileId string) (*profileModel.ProfileCookie, error)
@@ -164,27 +167,34 @@ func (ps *ProfilesService) CreateProfile(profileRequest profileModel.ProfileRequ
 		UpdatedAt: createdTime,
 		Location:  utils.BuildProfileLocation(orgHandle, profileId),
 	}
+	workers.ApplyComputedAttributes(&profile, schemaAttributesOf(schema), createdTime)
+	profile.AttributeMetadata = stampAttributeMetadata(profileModel.Profile{}, profile, profileRequest.SourceApplication, createdTime)
 
-	if err := profileStore.InsertProfile(profile); err != nil {
+	// The profile is added to the unification outbox in the transaction inserting it.
+	config := UnificationModel.DefaultConfig()
+	unify := config.ProfileUnificationTrigger.TriggerType == constants.SyncProfileOnUpdate
+	err = profileStore.WithTransaction(func(tx *sql.Tx) error {
+		if err := profileStore.InsertProfileTx(tx, profile); err != nil {
+			return err
+		}
+		if unify {
+			return profileStore.InsertUnificationOutboxEntry(tx, profile)
+		}
+		return nil
+	})
+	if err != nil {
 		logger.Debug(fmt.Sprintf("Error inserting profile: %s", profile.ProfileId), log.Error(err))
 		return nil, err
 	}
+	if unify {
+		workers.NotifyUnificationOutbox()
+	}
 	profileFetched, errWait := ps.GetProfile(profileId)
 	if errWait != nil || profileFetched == nil {
 		logger.Warn(fmt.Sprintf("Profile: %s not available after insertion: %v", profile.ProfileId, errWait))
 		return nil, errWait
 	}
 
-	queue := &workers.ProfileWorkerQueue{}
-
-	config := UnificationModel.DefaultConfig()
-
-	if config.ProfileUnificationTrigger.TriggerType == constants.SyncProfileOnUpdate {
-		// Set organization handle for the profile before enqueuing
-		profile.OrgHandle = orgHandle
-		queue.Enqueue(profile)
-	}
-
 	logger.Info(fmt.Sprintf("Profile created successfully with profile id: %s", profile.ProfileId))
 	return profileFetched, nil
 }
@@ -192,6 +202,9 @@ func (ps *ProfilesService) CreateProfile(profileRequest profileModel.ProfileRequ
 func ValidateProfileAgainstSchema(profile profileModel.ProfileRequest, existingProfile profileModel.Profile,
 	schema model.ProfileSchema, isUpdate bool) error {
 
+	// Constraint violations are collected across attributes and reported together once the structure is valid.
+	report := &constraintReport{}
+
 	// Validate identity attributes
 	for key, val := range profile.IdentityAttributes {
 		if err := rejectIfFlattenedSubAttribute(key, "identity_attributes", schema.IdentityAttributes); err != nil {
@@ -212,7 +225,7 @@ func ValidateProfileAgainstSchema(profile profileModel.ProfileRequest, existingP
 			existingVal = existingProfile.IdentityAttributes[key]
 		}
 		if err := validateAttributeValueAgainstSchema(attr, val, existingVal, isUpdate, schema.IdentityAttributes,
-			"identity attribute", key, isSystemIdentityAttribute(attr.AttributeName)); err != nil {
+			"identity attribute", key, isSystemIdentityAttribute(attr.AttributeName), report); err != nil {
 			return err
 		}
 	}
@@ -237,7 +250,7 @@ func ValidateProfileAgainstSchema(profile profileModel.ProfileRequest, existingP
 			existingVal = existingProfile.Traits[key]
 		}
 		if err := validateAttributeValueAgainstSchema(attr, val, existingVal, isUpdate, schema.Traits,
-			"trait", key, false); err != nil {
+			"trait", key, false, report); err != nil {
 			return err
 		}
 	}
@@ -273,17 +286,19 @@ func ValidateProfileAgainstSchema(profile profileModel.ProfileRequest, existingP
 			}
 
 			if err := validateAttributeValueAgainstSchema(attr, val, existingVal, isUpdate, schema.ApplicationData[appID],
-				"application_data", appID+"."+key, false); err != nil {
+				"application_data", appID+"."+key, false, report); err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	checkRequiredAttributes(profile, schema, report)
+	return report.err()
 }
 
 func validateAttributeValueAgainstSchema(attr model.ProfileSchemaAttribute, val, existingVal interface{}, isUpdate bool,
-	scopeAttrs []model.ProfileSchemaAttribute, attributeLabel, attributePath string, skipMutability bool) error {
+	scopeAttrs []model.ProfileSchemaAttribute, attributeLabel, attributePath string, skipMutability bool,
+	report *constraintReport) error {
 	if !skipMutability {
 		logger := log.GetLogger()
 		if err := validateMutability(attr.Mutability, isUpdate, existingVal, val); err != nil {
@@ -308,8 +323,13 @@ func validateAttributeValueAgainstSchema(attr model.ProfileSchemaAttribute, val,
 		}, http.StatusBadRequest)
 	}
 
+	// Stored values are only re-checked once they change, so tightening a constraint does not block unrelated writes.
+	if !isUpdate || !valuesEqualForMutability(existingVal, val) {
+		checkAttributeConstraints(attr, val, qualifiedAttributePath(attributeLabel, attributePath), report)
+	}
+
 	if attr.ValueType == constants.ComplexDataType {
-		if err := validateComplexSubAttributes(attr, val, existingVal, isUpdate, scopeAttrs, attributeLabel, attributePath); err != nil {
+		if err := validateComplexSubAttributes(attr, val, existingVal, isUpdate, scopeAttrs, attributeLabel, attributePath, report); err != nil {
 			return err
 		}
 	}
@@ -318,14 +338,14 @@ func validateAttributeValueAgainstSchema(attr model.ProfileSchemaAttribute, val,
 }
 
 func validateComplexSubAttributes(parentAttr model.ProfileSchemaAttribute, val, existingVal interface{}, isUpdate bool,
-	scopeAttrs []model.ProfileSchemaAttribute, attributeLabel, attributePath string) error {
+	scopeAttrs []model.ProfileSchemaAttribute, attributeLabel, attributePath string, report *constraintReport) error {
 	switch v := val.(type) {
 	case map[string]interface{}:
 		var existingMap map[string]interface{}
 		if current, ok := existingVal.(map[string]interface{}); ok {
 			existingMap = current
 		}
-		return validateComplexObject(parentAttr, v, existingMap, isUpdate, scopeAttrs, attributeLabel, attributePath)
+		return validateComplexObject(parentAttr, v, existingMap, isUpdate, scopeAttrs, attributeLabel, attributePath, report)
 	case []interface{}:
 		var existingSlice []interface{}
 		if current, ok := existingVal.([]interface{}); ok {
@@ -345,7 +365,7 @@ func validateComplexSubAttributes(parentAttr model.ProfileSchemaAttribute, val,
 			}
 
 			indexedPath := fmt.Sprintf("%s[%d]", attributePath, i)
-			if err := validateComplexObject(parentAttr, itemMap, existingMap, isUpdate, scopeAttrs, attributeLabel, indexedPath); err != nil {
+			if err := validateComplexObject(parentAttr, itemMap, existingMap, isUpdate, scopeAttrs, attributeLabel, indexedPath, report); err != nil {
 				return err
 			}
 		}
@@ -354,11 +374,16 @@ func validateComplexSubAttributes(parentAttr model.ProfileSchemaAttribute, val,
 }
 
 func validateComplexObject(parentAttr model.ProfileSchemaAttribute, objVal map[string]interface{}, existingObj map[string]interface{},
-	isUpdate bool, scopeAttrs []model.ProfileSchemaAttribute, attributeLabel, attributePath string) error {
+	isUpdate bool, scopeAttrs []model.ProfileSchemaAttribute, attributeLabel, attributePath string, report *constraintReport) error {
 	subAttrSchema := make(map[string]model.ProfileSchemaAttribute, len(parentAttr.SubAttributes))
 	prefix := parentAttr.AttributeName + "."
 	for _, subAttr := range parentAttr.SubAttributes {
-		attr, found := findAttributeInSchema(scopeAttrs, subAttr.AttributeName)
+		// Nested definitions carry their own type, mutability, canonical values and constraints; bare references
+		// are looked up in the scope.
+		attr, found := subAttr, !subAttr.IsReference()
+		if !found {
+			attr, found = findAttributeInSchema(scopeAttrs, subAttr.AttributeName)
+		}
 		if !found {
 			return errors2.NewServerError(errors2.ErrorMessage{
 				Code:        errors2.UPDATE_PROFILE.Code,
@@ -369,6 +394,9 @@ func validateComplexObject(parentAttr model.ProfileSchemaAttribute, objVal map[s
 
 		childKey := strings.TrimPrefix(subAttr.AttributeName, prefix)
 		subAttrSchema[childKey] = attr
+		if attr.Constraints != nil && attr.Constraints.Required && !hasRequiredValue(objVal[childKey]) {
+			report.add(qualifiedAttributePath(attributeLabel, attributePath+"."+childKey), constraintRequired, "is required")
+		}
 	}
 
 	for childKey, childVal := range objVal {
@@ -388,7 +416,7 @@ func validateComplexObject(parentAttr model.ProfileSchemaAttribute, objVal map[s
 
 		childPath := attributePath + "." + childKey
 		if err := validateAttributeValueAgainstSchema(childAttr, childVal, existingChildVal, isUpdate, scopeAttrs,
-			attributeLabel, childPath, false); err != nil {
+			attributeLabel, childPath, false, report); err != nil {
 			return err
 		}
 	}
@@ -461,6 +489,17 @@ func rejectIfFlattenedSubAttribute(key, scope string, scopeAttrs []model.Profile
 	}, http.StatusBadRequest)
 }
 
+// schemaAttributesOf flattens the attributes of all scopes of the schema.
+func schemaAttributesOf(schema model.ProfileSchema) []model.ProfileSchemaAttribute {
+	attrs := make([]model.ProfileSchemaAttribute, 0, len(schema.IdentityAttributes)+len(schema.Traits))
+	attrs = append(attrs, schema.IdentityAttributes...)
+	attrs = append(attrs, schema.Traits...)
+	for _, appAttrs := range schema.ApplicationData {
+		attrs = append(attrs, appAttrs...)
+	}
+	return attrs
+}
+
 func findAttributeInSchema(attrs []model.ProfileSchemaAttribute, name string) (model.ProfileSchemaAttribute, bool) {
 	for _, attr := range attrs {
 		if attr.AttributeName == name {
@@ -559,6 +598,16 @@ func validateMutability(mutability string, isUpdate bool, oldVal, newVal interfa
 				Description: "immutable field cannot be updated",
 			}, http.StatusBadRequest)
 		}
+	case constants.MutabilityComputed:
+		// Echoing the current value back, e.g. in a full update of a fetched profile, is tolerated; the value is
+		// recomputed regardless.
+		if !isUpdate || !valuesEqualForMutability(oldVal, newVal) {
+			return errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.UPDATE_PROFILE.Code,
+				Message:     errors2.UPDATE_PROFILE.Message,
+				Description: "computed field is read-only",
+			}, http.StatusBadRequest)
+		}
 	case constants.MutabilityWriteOnce:
 		if isUpdate && hasExistingValue(oldVal) && !valuesEqualForMutability(oldVal, newVal) {
 			return errors2.NewClientError(errors2.ErrorMessage{
@@ -788,6 +837,7 @@ func (ps *ProfilesService) UpdateProfile(profileId, orgHandle string, updatedPro
 	if err != nil {
 		return nil, err
 	}
+	previousProfile := *profile
 	if profile.ProfileStatus.IsReferenceProfile {
 		// convert profile request to model
 		profileToUpDate = profileModel.Profile{
@@ -826,25 +876,37 @@ func (ps *ProfilesService) UpdateProfile(profileId, orgHandle string, updatedPro
 			Location:           masterProfile.Location,
 			ProfileStatus:      masterProfile.ProfileStatus,
 		}
+		previousProfile = *masterProfile
 	}
+	workers.ApplyComputedAttributes(&profileToUpDate, schemaAttributesOf(schema), updatedTime)
+	profileToUpDate.AttributeMetadata = stampAttributeMetadata(previousProfile, profileToUpDate,
+		updatedProfile.SourceApplication, updatedTime)
 
-	if err := profileStore.UpdateProfile(profileToUpDate); err != nil {
+	// The profile is added to the unification outbox in the transaction updating it.
+	config := UnificationModel.DefaultConfig()
+	unify := config.ProfileUnificationTrigger.TriggerType == constants.SyncProfileOnUpdate
+	profileToUpDate.OrgHandle = orgHandle
+	err = profileStore.WithTransaction(func(tx *sql.Tx) error {
+		if err := profileStore.UpdateProfileTx(tx, profileToUpDate); err != nil {
+			return err
+		}
+		if unify {
+			return profileStore.InsertUnificationOutboxEntry(tx, profileToUpDate)
+		}
+		return nil
+	})
+	if err != nil {
 		logger.Error(fmt.Sprintf("Error updating profile: %s", profileToUpDate.ProfileId), log.Error(err))
 		return nil, err
 	}
+	if unify {
+		workers.NotifyUnificationOutbox()
+	}
 
 	profileFetched, errWait := ps.GetProfile(profile.ProfileId)
 	if errWait != nil || profileFetched == nil {
 		return nil, errWait
 	}
-
-	config := UnificationModel.DefaultConfig()
-	queue := &workers.ProfileWorkerQueue{}
-	if config.ProfileUnificationTrigger.TriggerType == constants.SyncProfileOnUpdate {
-		// Set organization handle for the profile before enqueuing
-		profileToUpDate.OrgHandle = orgHandle
-		queue.Enqueue(profileToUpDate)
-	}
 	logger.Info("Successfully updated profile: " + profileFetched.ProfileId)
 	return profileFetched, nil
 }
@@ -919,6 +981,9 @@ func (ps *ProfilesService) GetProfile(ProfileId string) (*profileModel.ProfileRe
 			},
 			MergedFrom: alias,
 		}
+		if err := hidePurgingAttributes(profileResponse, profile.OrgHandle); err != nil {
+			return nil, err
+		}
 		return profileResponse, nil
 	} else {
 		// fetching merged master profile
@@ -951,7 +1016,9 @@ func (ps *ProfilesService) GetProfile(ProfileId string) (*profileModel.ProfileRe
 				},
 				MergedTo: alias,
 			}
-
+			if err := hidePurgingAttributes(profileResponse, masterProfile.OrgHandle); err != nil {
+				return nil, err
+			}
 			return profileResponse, nil
 		}
 		return nil, err
@@ -959,9 +1026,9 @@ func (ps *ProfilesService) GetProfile(ProfileId string) (*profileModel.ProfileRe
 }
 
 // GetProfileConsents retrieves a profile
-func (ps *ProfilesService) GetProfileConsents(ProfileId string) ([]profileModel.ConsentRecord, error) {
+func (ps *ProfilesService) GetProfileConsents(ProfileId string, orgHandle string) ([]profileModel.ConsentRecord, error) {
 
-	consentRecords, err := profileStore.GetProfileConsents(ProfileId)
+	consentRecords, err := profileStore.GetProfileConsents(ProfileId, orgHandle)
 	if err != nil {
 		return nil, err
 	}
@@ -1000,16 +1067,51 @@ func (ps *ProfilesService) UpdateProfileConsents(profileId string, orgHandle str
 		}
 	}
 
-	// Set the consent timestamp if not already set
+	// Resolve every referenced category; the receipt is built from their purposes and destinations.
+	categories := make(map[string]*consentModel.ConsentCategory, len(consents))
+	for _, c := range consents {
+		category, err := consentStore.GetConsentCategoryByID(c.CategoryIdentifier, orgHandle)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.CONSENT_CAT_NOT_FOUND.Code,
+				Message:     errors2.CONSENT_CAT_NOT_FOUND.Message,
+				Description: fmt.Sprintf("Consent category '%s' does not exist.", c.CategoryIdentifier),
+			}, http.StatusBadRequest)
+		}
+		categories[c.CategoryIdentifier] = category
+	}
+
+	profile, err := profileStore.GetProfile(profileId)
+	if err != nil {
+		return err
+	}
+	principalId := profileId
+	if profile != nil && profile.UserId != "" {
+		principalId = profile.UserId
+	}
+
+	// Set the consent timestamp if not already set, and link every record to the receipt issued for this change.
 	currentTime := time.Now().UTC()
+	receiptId := uuid.New().String()
 	for i := range consents {
 		if consents[i].ConsentedAt.IsZero() {
 			consents[i].ConsentedAt = currentTime
 		}
+		consents[i].ReceiptId = receiptId
+	}
+	receipt := &profileModel.ConsentReceiptRecord{
+		ReceiptId: receiptId,
+		ProfileId: profileId,
+		OrgHandle: orgHandle,
+		CreatedAt: currentTime,
+		Receipt:   buildConsentReceipt(receiptId, principalId, currentTime, consents, categories),
 	}
 
 	// Update the consents in the database
-	err = profileStore.UpdateProfileConsents(profileId, consents)
+	err = profileStore.UpdateProfileConsents(profileId, orgHandle, consents, receipt)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to update consents for profile: %s", profileId)
 		logger.Debug(errorMsg, log.Error(err))
@@ -1019,6 +1121,23 @@ func (ps *ProfilesService) UpdateProfileConsents(profileId string, orgHandle str
 	return nil
 }
 
+// GetConsentReceipt retrieves a consent receipt issued for the profile
+func (ps *ProfilesService) GetConsentReceipt(profileId string, orgHandle string, receiptId string) (*profileModel.ConsentReceipt, error) {
+
+	record, err := profileStore.GetConsentReceipt(profileId, orgHandle, receiptId)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CONSENT_RECEIPT_NOT_FOUND.Code,
+			Message:     errors2.CONSENT_RECEIPT_NOT_FOUND.Message,
+			Description: errors2.CONSENT_RECEIPT_NOT_FOUND.Description,
+		}, http.StatusNotFound)
+	}
+	return &record.Receipt, nil
+}
+
 // DeleteProfile removes a profile from MongoDB by `perma_id`
 func (ps *ProfilesService) DeleteProfile(ProfileId string) error {
 
@@ -1269,6 +1388,15 @@ func (ps *ProfilesService) GetAllProfilesWithFilterCursor(
 
 		field, operator, rawValue := parts[0], parts[1], parts[2]
 
+		if strings.HasPrefix(field, consentFilterScope+".") {
+			consentFilter, err := rewriteConsentFilter(field, operator, rawValue)
+			if err != nil {
+				return nil, false, err
+			}
+			rewrittenFilters = append(rewrittenFilters, consentFilter)
+			continue
+		}
+
 		// Validate operator
 		switch operator {
 		case "eq", "co", "sw":
@@ -1352,6 +1480,61 @@ func (ps *ProfilesService) GetAllProfilesWithFilterCursor(
 	return result, hasMore, nil
 }
 
+const (
+	consentFilterScope       = "consent"
+	consentedAtFilterSubject = "consented_at"
+)
+
+// rewriteConsentFilter validates a consent predicate and normalizes its value. Two forms are supported:
+// "consent.<categoryId> eq true|false" matches on the consent state, and
+// "consent.<categoryId>.consented_at gt|ge|lt|le <date>" matches profiles that granted the consent in that range.
+// Dates are RFC 3339 timestamps or YYYY-MM-DD days and are rewritten to RFC 3339 in UTC.
+func rewriteConsentFilter(field, operator, rawValue string) (string, error) {
+
+	invalid := func(description string) error {
+		return errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.FILTER_PROFILE.Code,
+			Message:     errors2.FILTER_PROFILE.Message,
+			Description: description,
+		}, http.StatusBadRequest)
+	}
+
+	key := strings.TrimPrefix(field, consentFilterScope+".")
+	categoryId, subject, hasSubject := strings.Cut(key, ".")
+	if !isValidFilterKey(categoryId) || strings.Contains(subject, ".") {
+		return "", invalid("Invalid consent filter key: " + field)
+	}
+
+	if !hasSubject {
+		if operator != "eq" {
+			return "", invalid(fmt.Sprintf("Unsupported operator for consent state: %s", operator))
+		}
+		value := strings.ToLower(strings.TrimSpace(rawValue))
+		if value != "true" && value != "false" {
+			return "", invalid("Consent state filters only accept true or false.")
+		}
+		return fmt.Sprintf("%s %s %s", field, operator, value), nil
+	}
+
+	if subject != consentedAtFilterSubject {
+		return "", invalid("Invalid consent filter key: " + field)
+	}
+	switch operator {
+	case "gt", "ge", "lt", "le":
+	default:
+		return "", invalid(fmt.Sprintf("Unsupported operator for consent date: %s", operator))
+	}
+	value := strings.TrimSpace(rawValue)
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		at, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return "", invalid("Consent date filters need an RFC 3339 timestamp or a YYYY-MM-DD date.")
+		}
+	}
+	return fmt.Sprintf("%s %s %s", field, operator, at.UTC().Format(time.RFC3339)), nil
+}
+
 // isValidFilterKey ensures the filter key is valid and does not contain any malicious patterns.
 func isValidFilterKey(key string) bool {
 
@@ -1430,7 +1613,8 @@ func (ps *ProfilesService) FindProfileByUserId(userId string) (*profileModel.Pro
 }
 
 // PatchProfile applies a partial update to an existing profile
-func (ps *ProfilesService) PatchProfile(profileId, orgHandle string, patch map[string]interface{}) (*profileModel.ProfileResponse, error) {
+func (ps *ProfilesService) PatchProfile(profileId, orgHandle string, patch map[string]interface{},
+	sourceApp string) (*profileModel.ProfileResponse, error) {
 
 	existingProfile, err := profileStore.GetProfile(profileId)
 	if err != nil {
@@ -1524,6 +1708,7 @@ func (ps *ProfilesService) PatchProfile(profileId, orgHandle string, patch map[s
 	}
 
 	// Reuse the PUT logic to update the profile
+	updatedProfileReq.SourceApplication = sourceApp
 	return ps.UpdateProfile(profileId, orgHandle, updatedProfileReq)
 }
 
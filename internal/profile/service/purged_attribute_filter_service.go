@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"strings"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// hidePurgingAttributes removes the values of deleted attributes whose data is still being purged, so that
+// orphaned keys are not served while the purge runs.
+func hidePurgingAttributes(profile *profileModel.ProfileResponse, orgHandle string) error {
+
+	purges, err := schemaService.GetProfileSchemaService().GetAttributesBeingPurged(orgHandle)
+	if err != nil || len(purges) == 0 {
+		return err
+	}
+	for _, purge := range purges {
+		segments := strings.Split(purge.AttributeName, ".")
+		if len(segments) < 2 {
+			continue
+		}
+		switch segments[0] {
+		case constants.Traits:
+			removeAttributePath(profile.Traits, segments[1:])
+		case constants.IdentityAttributes:
+			removeAttributePath(profile.IdentityAttributes, segments[1:])
+		case constants.ApplicationData:
+			for appId, appData := range profile.ApplicationData {
+				if purge.ApplicationIdentifier == "" || appId == purge.ApplicationIdentifier {
+					removeAttributePath(appData, segments[1:])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// removeAttributePath deletes the value at path, descending through objects and lists of objects.
+func removeAttributePath(value interface{}, path []string) {
+
+	switch container := value.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(container, path[0])
+			return
+		}
+		removeAttributePath(container[path[0]], path[1:])
+	case []interface{}:
+		for _, item := range container {
+			removeAttributePath(item, path)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// attributeQuery resolves the query and JSON path for the values of an attribute. Application data is kept in its
+// own table under app_specific_data; the other scopes are profiles columns named after the scope.
+func attributeQuery(location model.AttributeLocation, profileQueries, appQueries map[string]string) (string, []string) {
+
+	dbType := provider.NewDBProvider().GetDBType()
+	if location.Scope == constants.ApplicationData {
+		return appQueries[dbType], []string{"app_specific_data", location.Key}
+	}
+	column := constants.Traits
+	if location.Scope == constants.IdentityAttributes {
+		column = constants.IdentityAttributes
+	}
+	return fmt.Sprintf(profileQueries[dbType], column), []string{location.Key}
+}
+
+func attributeValueError(errorMsg string, err error) error {
+	log.GetLogger().Debug(errorMsg, log.Error(err))
+	return errors2.NewServerError(errors2.ErrorMessage{
+		Code:        errors2.UPDATE_PROFILE.Code,
+		Message:     errors2.UPDATE_PROFILE.Message,
+		Description: errorMsg,
+	}, err)
+}
+
+// CountProfileAttributeValues counts the profiles of an org that hold a value for an attribute.
+func CountProfileAttributeValues(location model.AttributeLocation) (int, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return 0, attributeValueError("Failed to get database client for counting attribute values", err)
+	}
+	defer dbClient.Close()
+
+	query, path := attributeQuery(location, scripts.CountProfileAttributeValues, scripts.CountApplicationAttributeValues)
+	args := []interface{}{location.OrgHandle, pq.Array(path)}
+	if location.Scope == constants.ApplicationData {
+		args = []interface{}{location.OrgHandle, location.ApplicationIdentifier, pq.Array(path)}
+	}
+	results, err := dbClient.ExecuteQuery(query, args...)
+	if err != nil {
+		return 0, attributeValueError(fmt.Sprintf("Failed to count the values of '%s'", location.Key), err)
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	count, _ := results[0]["count"].(int64)
+	return int(count), nil
+}
+
+// GetProfileAttributeValues fetches up to limit stored values of an attribute from profiles whose id sorts after
+// afterProfileId.
+func GetProfileAttributeValues(location model.AttributeLocation, afterProfileId string, limit int) ([]model.ProfileAttributeValue, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return nil, attributeValueError("Failed to get database client for fetching attribute values", err)
+	}
+	defer dbClient.Close()
+
+	query, path := attributeQuery(location, scripts.GetProfileAttributeValues, scripts.GetApplicationAttributeValues)
+	args := []interface{}{location.OrgHandle, pq.Array(path), afterProfileId, limit}
+	if location.Scope == constants.ApplicationData {
+		args = []interface{}{location.OrgHandle, location.ApplicationIdentifier, pq.Array(path), afterProfileId, limit}
+	}
+	results, err := dbClient.ExecuteQuery(query, args...)
+	if err != nil {
+		return nil, attributeValueError(fmt.Sprintf("Failed to fetch the values of '%s'", location.Key), err)
+	}
+
+	values := make([]model.ProfileAttributeValue, 0, len(results))
+	for _, row := range results {
+		value := model.ProfileAttributeValue{ProfileId: row["profile_id"].(string)}
+		if raw, ok := row["value"].(string); ok {
+			if err := json.Unmarshal([]byte(raw), &value.Value); err != nil {
+				return nil, attributeValueError(fmt.Sprintf("Failed to parse the value of '%s' in profile: %s",
+					location.Key, value.ProfileId), err)
+			}
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// ReplaceProfileAttributeValue replaces the stored value of an attribute in a profile, or removes it when value is
+// nil. Nothing is written if the profile no longer holds the expected value; the returned flag reports whether
+// the value was written.
+func ReplaceProfileAttributeValue(location model.AttributeLocation, profileId string, expected, value interface{}) (bool, error) {
+
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		return false, attributeValueError(fmt.Sprintf("Failed to marshal the value of '%s' in profile: %s", location.Key, profileId), err)
+	}
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return false, attributeValueError("Failed to get database client for replacing an attribute value", err)
+	}
+	defer dbClient.Close()
+
+	var query string
+	var path []string
+	var args []interface{}
+	if value == nil {
+		query, path = attributeQuery(location, scripts.RemoveProfileAttributeValue, scripts.RemoveApplicationAttributeValue)
+		args = []interface{}{pq.Array(path), profileId, string(expectedJSON)}
+		if location.Scope == constants.ApplicationData {
+			args = []interface{}{profileId, location.ApplicationIdentifier, pq.Array(path), string(expectedJSON)}
+		}
+	} else {
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return false, attributeValueError(fmt.Sprintf("Failed to marshal the value of '%s' in profile: %s", location.Key, profileId), err)
+		}
+		query, path = attributeQuery(location, scripts.ReplaceProfileAttributeValue, scripts.ReplaceApplicationAttributeValue)
+		args = []interface{}{pq.Array(path), string(valueJSON), profileId, string(expectedJSON)}
+		if location.Scope == constants.ApplicationData {
+			args = []interface{}{profileId, location.ApplicationIdentifier, pq.Array(path), string(valueJSON), string(expectedJSON)}
+		}
+	}
+
+	results, err := dbClient.ExecuteQuery(query, args...)
+	if err != nil {
+		return false, attributeValueError(fmt.Sprintf("Failed to replace the value of '%s' in profile: %s", location.Key, profileId), err)
+	}
+	return len(results) > 0, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/wso2/identity-customer-data-service/internal/profile/model"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
 	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
@@ -19,6 +20,56 @@ import (
 )
 
 // Unmarshal JSONB fields separately
+// queryExecutor runs queries either on a database client of their own or inside an open transaction.
+type queryExecutor interface {
+	ExecuteQuery(query string, args ...interface{}) ([]map[string]interface{}, error)
+}
+
+// WithTransaction runs fn in a single database transaction. The transaction is committed when fn succeeds
+// and rolled back when it fails, so that none of the writes made through it are persisted.
+func WithTransaction(fn func(tx *sql.Tx) error) error {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	logger := log.GetLogger()
+	if err != nil {
+		errorMsg := "Failed to get database client for starting a transaction"
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_PROFILE.Code,
+			Message:     errors2.UPDATE_PROFILE.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	tx, err := dbClient.BeginTx()
+	if err != nil {
+		errorMsg := "Failed to begin transaction"
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_PROFILE.Code,
+			Message:     errors2.UPDATE_PROFILE.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	if err := fn(tx); err != nil {
+		if errRoll := tx.Rollback(); errRoll != nil {
+			logger.Debug("Failed to rollback transaction", log.Error(errRoll))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		errorMsg := "Failed to commit transaction"
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_PROFILE.Code,
+			Message:     errors2.UPDATE_PROFILE.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return nil
+}
+
 func scanProfileRow(row map[string]interface{}) (model.Profile, error) {
 	var (
 		profile                       model.Profile
@@ -76,15 +127,49 @@ func scanProfileRow(row map[string]interface{}) (model.Profile, error) {
 		}, err)
 		return model.Profile{}, serverError
 	}
+	profile.AttributeMetadata = parseAttributeMetadata(row["attribute_metadata"])
 	return profile, nil
 }
 
+// marshalAttributeMetadata encodes per-attribute metadata for the JSONB column, storing an empty object when there
+// is none.
+func marshalAttributeMetadata(metadata map[string]model.AttributeMetadata) []byte {
+	if len(metadata) == 0 {
+		return []byte("{}")
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return []byte("{}")
+	}
+	return raw
+}
+
+func parseAttributeMetadata(raw interface{}) map[string]model.AttributeMetadata {
+	var rawBytes []byte
+	switch v := raw.(type) {
+	case []byte:
+		rawBytes = v
+	case string:
+		rawBytes = []byte(v)
+	default:
+		return nil
+	}
+	var metadata map[string]model.AttributeMetadata
+	if err := json.Unmarshal(rawBytes, &metadata); err != nil || len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
 func scanProfileConsentRow(row map[string]interface{}) (model.ConsentRecord, error) {
 	var profileConsent model.ConsentRecord
 
 	profileConsent.CategoryIdentifier = row["category_id"].(string)
 	profileConsent.IsConsented = row["consent_status"].(bool)
 	profileConsent.ConsentedAt = row["consented_at"].(time.Time)
+	if receiptId, ok := row["receipt_id"].(string); ok {
+		profileConsent.ReceiptId = receiptId
+	}
 	return profileConsent, nil
 }
 
@@ -105,6 +190,18 @@ func InsertProfile(profile model.Profile) error {
 	}
 	defer dbClient.Close()
 
+	return insertProfile(dbClient, profile)
+}
+
+// InsertProfileTx inserts a new profile within the given transaction.
+func InsertProfileTx(tx *sql.Tx, profile model.Profile) error {
+
+	return insertProfile(client.NewTxClient(tx), profile)
+}
+
+func insertProfile(dbClient queryExecutor, profile model.Profile) error {
+
+	logger := log.GetLogger()
 	traitsJSON, _ := json.Marshal(profile.Traits)
 	identityJSON, _ := json.Marshal(profile.IdentityAttributes)
 	var profileStatus string
@@ -120,7 +217,7 @@ func InsertProfile(profile model.Profile) error {
 
 	query := scripts.InsertProfile[provider.NewDBProvider().GetDBType()]
 
-	_, err = dbClient.ExecuteQuery(query,
+	_, err := dbClient.ExecuteQuery(query,
 		profile.ProfileId,
 		profile.UserId,
 		profile.OrgHandle,
@@ -131,6 +228,7 @@ func InsertProfile(profile model.Profile) error {
 		false, // delete_profile is not used in this context, set to false
 		traitsJSON,
 		identityJSON,
+		marshalAttributeMetadata(profile.AttributeMetadata),
 	)
 
 	if err != nil {
@@ -166,7 +264,7 @@ func InsertProfile(profile model.Profile) error {
 		return serverError
 	}
 
-	err = InsertApplicationData(profile.ProfileId, profile.ApplicationData)
+	err = insertApplicationData(dbClient, profile.ProfileId, profile.ApplicationData)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to insert profile with Id: %s", profile.ProfileId)
 		logger.Debug(errorMsg, log.Error(err))
@@ -184,6 +282,23 @@ func InsertProfile(profile model.Profile) error {
 
 func InsertApplicationData(profileId string, apps []model.ApplicationData) error {
 
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get database client for inserting application data for profile: %s", profileId)
+		log.GetLogger().Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.ADD_APP_DATA.Code,
+			Message:     errors2.ADD_APP_DATA.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	return insertApplicationData(dbClient, profileId, apps)
+}
+
+func insertApplicationData(dbClient queryExecutor, profileId string, apps []model.ApplicationData) error {
+
 	for _, app := range apps {
 		// Construct the update map
 		updateMap := make(map[string]interface{})
@@ -194,7 +309,7 @@ func InsertApplicationData(profileId string, apps []model.ApplicationData) error
 		}
 
 		// Use the existing upsert method
-		err := UpsertAppDatum(profileId, app.AppId, updateMap)
+		err := upsertAppDatum(dbClient, profileId, app.AppId, updateMap)
 		logger := log.GetLogger()
 		if err != nil {
 			errorMsg := fmt.Sprintf("Failed to insert application data for profile with Id: %s and appId: %s",
@@ -228,6 +343,12 @@ func GetProfile(profileId string) (*model.Profile, error) {
 	}
 	defer dbClient.Close()
 
+	return getProfile(dbClient, profileId)
+}
+
+func getProfile(dbClient queryExecutor, profileId string) (*model.Profile, error) {
+
+	logger := log.GetLogger()
 	query := scripts.GetProfileById[provider.NewDBProvider().GetDBType()]
 
 	results, err := dbClient.ExecuteQuery(query, profileId)
@@ -253,12 +374,12 @@ func GetProfile(profileId string) (*model.Profile, error) {
 		}, err)
 		return nil, serverError
 	}
-	profile.ApplicationData, _ = FetchApplicationData(profileId)
+	profile.ApplicationData, _ = fetchApplicationData(dbClient, profileId)
 	return &profile, nil
 }
 
 // GetProfileConsents retrieves the consents of a profile by its profileId
-func GetProfileConsents(profileId string) ([]model.ConsentRecord, error) {
+func GetProfileConsents(profileId string, orgHandle string) ([]model.ConsentRecord, error) {
 
 	dbClient, err := provider.NewDBProvider().GetDBClient()
 	logger := log.GetLogger()
@@ -276,7 +397,7 @@ func GetProfileConsents(profileId string) ([]model.ConsentRecord, error) {
 
 	query := scripts.GetProfileConsentsByProfileId[provider.NewDBProvider().GetDBType()]
 
-	results, err := dbClient.ExecuteQuery(query, profileId)
+	results, err := dbClient.ExecuteQuery(query, profileId, orgHandle)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		logger.Debug(fmt.Sprintf("No profile found with the given Id: %s", profileId))
@@ -328,6 +449,13 @@ func FetchApplicationData(profileId string) ([]model.ApplicationData, error) {
 		return nil, serverError
 	}
 	defer dbClient.Close()
+
+	return fetchApplicationData(dbClient, profileId)
+}
+
+func fetchApplicationData(dbClient queryExecutor, profileId string) ([]model.ApplicationData, error) {
+
+	logger := log.GetLogger()
 	query := scripts.GetAppDataByProfileId[provider.NewDBProvider().GetDBType()]
 	results, err := dbClient.ExecuteQuery(query, profileId)
 	if err != nil {
@@ -386,6 +514,13 @@ func FetchApplicationDataWithAppId(profileId string, appId string) (model.Applic
 		return model.ApplicationData{}, serverError
 	}
 	defer dbClient.Close()
+
+	return fetchApplicationDataWithAppId(dbClient, profileId, appId)
+}
+
+func fetchApplicationDataWithAppId(dbClient queryExecutor, profileId string, appId string) (model.ApplicationData, error) {
+
+	logger := log.GetLogger()
 	query := scripts.GetAppDataByAppId[provider.NewDBProvider().GetDBType()]
 	results, err := dbClient.ExecuteQuery(query, profileId, appId)
 	var app model.ApplicationData
@@ -444,6 +579,18 @@ func UpdateProfile(profile model.Profile) error {
 	}
 	defer dbClient.Close()
 
+	return updateProfile(dbClient, profile)
+}
+
+// UpdateProfileTx updates the profile within the given transaction.
+func UpdateProfileTx(tx *sql.Tx, profile model.Profile) error {
+
+	return updateProfile(client.NewTxClient(tx), profile)
+}
+
+func updateProfile(dbClient queryExecutor, profile model.Profile) error {
+
+	logger := log.GetLogger()
 	traitsJSON, _ := json.Marshal(profile.Traits)
 	identityJSON, _ := json.Marshal(profile.IdentityAttributes)
 
@@ -460,13 +607,14 @@ func UpdateProfile(profile model.Profile) error {
 
 	query := scripts.UpdateProfile[provider.NewDBProvider().GetDBType()]
 
-	_, err = dbClient.ExecuteQuery(query,
+	_, err := dbClient.ExecuteQuery(query,
 		profile.UserId,
 		profile.ProfileStatus.ListProfile,
 		profile.ProfileStatus.DeleteProfile,
 		traitsJSON,
 		identityJSON,
 		profile.UpdatedAt,
+		marshalAttributeMetadata(profile.AttributeMetadata),
 		profile.ProfileId,
 	)
 	if err != nil {
@@ -500,7 +648,7 @@ func UpdateProfile(profile model.Profile) error {
 		return serverError
 	}
 	// Update application data
-	err = InsertApplicationData(profile.ProfileId, profile.ApplicationData)
+	err = insertApplicationData(dbClient, profile.ProfileId, profile.ApplicationData)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to insert profile with Id: %s", profile.ProfileId)
 		logger.Debug(errorMsg, log.Error(err))
@@ -760,8 +908,26 @@ func DeleteProfile(profileId string) error {
 
 func UpsertAppDatum(profileId string, appId string, updates map[string]interface{}) error {
 
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get database client for upserting application data for profile: %s", profileId)
+		log.GetLogger().Debug(errorMsg, log.Error(err))
+		serverError := errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_APP_DATA.Code,
+			Message:     errors2.UPDATE_APP_DATA.Message,
+			Description: errorMsg,
+		}, err)
+		return serverError
+	}
+	defer dbClient.Close()
+
+	return upsertAppDatum(dbClient, profileId, appId, updates)
+}
+
+func upsertAppDatum(dbClient queryExecutor, profileId string, appId string, updates map[string]interface{}) error {
+
 	// Fetch existing application_data for the given app
-	appData, err := FetchApplicationDataWithAppId(profileId, appId)
+	appData, err := fetchApplicationDataWithAppId(dbClient, profileId, appId)
 	if err != nil {
 		return err
 	}
@@ -805,19 +971,6 @@ func UpsertAppDatum(profileId string, appId string, updates map[string]interface
 	// Upsert into application_data table
 	query := scripts.InsertApplicationData[provider.NewDBProvider().GetDBType()]
 
-	dbClient, err := provider.NewDBProvider().GetDBClient()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to get database client for upserting application data for profile: %s", profileId)
-		logger.Debug(errorMsg, log.Error(err))
-		serverError := errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.UPDATE_APP_DATA.Code,
-			Message:     errors2.UPDATE_APP_DATA.Message,
-			Description: errorMsg,
-		}, err)
-		return serverError
-	}
-	defer dbClient.Close()
-
 	_, err = dbClient.ExecuteQuery(query, profileId, appId, jsonBytes)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to upsert application data for profile: %s", profileId)
@@ -872,10 +1025,11 @@ func DetachRefererProfileFromReference(referenceProfileId, profileId string) err
 	return nil
 }
 
-// InsertMergedMasterProfileAppData adds or updates application-specific context data.
-func InsertMergedMasterProfileAppData(profileId string, newAppCtx model.ApplicationData) error {
+// InsertMergedMasterProfileAppData adds or updates application-specific context data within the given transaction.
+func InsertMergedMasterProfileAppData(tx *sql.Tx, profileId string, newAppCtx model.ApplicationData) error {
 
-	profile, err := GetProfile(profileId)
+	dbClient := client.NewTxClient(tx)
+	profile, err := getProfile(dbClient, profileId)
 	logger := log.GetLogger()
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to fetch profile %s for app data update.", profileId)
@@ -923,13 +1077,14 @@ func InsertMergedMasterProfileAppData(profileId string, newAppCtx model.Applicat
 
 	profile.ApplicationData = resultAppData
 	// this inserts the entire application_data blob with the update.
-	return InsertApplicationData(profile.ProfileId, profile.ApplicationData)
+	return insertApplicationData(dbClient, profile.ProfileId, profile.ApplicationData)
 }
 
-// InsertMergedMasterProfileTraitData replaces (PUT) the traits data inside Profile
-func InsertMergedMasterProfileTraitData(profileId string, traitsData map[string]interface{}) error {
+// InsertMergedMasterProfileTraitData replaces (PUT) the traits data inside Profile within the given transaction.
+func InsertMergedMasterProfileTraitData(tx *sql.Tx, profileId string, traitsData map[string]interface{}) error {
 
-	profile, err := GetProfile(profileId)
+	dbClient := client.NewTxClient(tx)
+	profile, err := getProfile(dbClient, profileId)
 	logger := log.GetLogger()
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to fetch profile %s for trait data update.", profileId)
@@ -953,13 +1108,32 @@ func InsertMergedMasterProfileTraitData(profileId string, traitsData map[string]
 	}
 
 	profile.Traits = traitsData
-	return UpdateProfile(*profile) // Update existing profile
+	return updateProfile(dbClient, *profile) // Update existing profile
 }
 
-// MergeIdentityDataOfProfiles replaces or adds to identity_attributes in Profile
-func MergeIdentityDataOfProfiles(profileId string, identityData map[string]interface{}) error {
+// UpdateProfileAttributeMetadata replaces the per-attribute write metadata of a profile within the given transaction.
+func UpdateProfileAttributeMetadata(tx *sql.Tx, profileId string, metadata map[string]model.AttributeMetadata) error {
 
-	profile, err := GetProfile(profileId)
+	logger := log.GetLogger()
+	query := scripts.UpdateProfileAttributeMetadata[provider.NewDBProvider().GetDBType()]
+	_, err := client.NewTxClient(tx).ExecuteQuery(query, marshalAttributeMetadata(metadata), profileId)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed updating attribute metadata of profile: %s", profileId)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.UPDATE_PROFILE.Code,
+			Message:     errors2.UPDATE_PROFILE.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return nil
+}
+
+// MergeIdentityDataOfProfiles replaces or adds to identity_attributes in Profile within the given transaction.
+func MergeIdentityDataOfProfiles(tx *sql.Tx, profileId string, identityData map[string]interface{}) error {
+
+	dbClient := client.NewTxClient(tx)
+	profile, err := getProfile(dbClient, profileId)
 	logger := log.GetLogger()
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to fetch profile %s for identity data update.", profileId)
@@ -989,7 +1163,7 @@ func MergeIdentityDataOfProfiles(profileId string, identityData map[string]inter
 		profile.IdentityAttributes[k] = v // Overwrites or adds
 	}
 
-	return UpdateProfile(*profile)
+	return updateProfile(dbClient, *profile)
 }
 
 // GetAllProfilesWithFilter retrieves profiles using dynamic filters and cursor-based pagination.
@@ -1029,6 +1203,9 @@ func GetAllProfilesWithFilter(
 	var args []interface{}
 	argID := 1
 	joinedAppIDs := map[string]bool{}
+	// joinedConsentIDs maps each filtered consent category to the alias of its join. Aliases are numbered, as
+	// sanitizing the category ids could give two categories the same alias.
+	joinedConsentIDs := map[string]string{}
 
 	conditions = append(conditions, fmt.Sprintf("p.org_handle = $%d", argID))
 	args = append(args, orgHandle)
@@ -1174,6 +1351,42 @@ func GetAllProfilesWithFilter(
 			default:
 				continue
 			}
+
+		case "consent":
+			// key is "<categoryId>" for the consent state or "<categoryId>.consented_at" for the grant time.
+			categoryId, subject, _ := strings.Cut(key, ".")
+			consentAlias, joined := joinedConsentIDs[categoryId]
+			if !joined {
+				consentAlias = fmt.Sprintf("c_%d", len(joinedConsentIDs))
+				baseSQL += fmt.Sprintf(`
+                LEFT JOIN profile_consents %s
+                  ON %s.profile_id = p.profile_id AND %s.org_handle = p.org_handle AND %s.category_id = $%d`,
+					consentAlias, consentAlias, consentAlias, consentAlias, argID)
+				args = append(args, categoryId)
+				argID++
+				joinedConsentIDs[categoryId] = consentAlias
+			}
+
+			if subject == "" {
+				if operator != "eq" {
+					continue
+				}
+				// A profile without a consent record has not consented.
+				conditions = append(conditions, fmt.Sprintf("COALESCE(%s.consent_status, FALSE) = $%d", consentAlias, argID))
+				args = append(args, value == "true")
+				argID++
+				continue
+			}
+
+			comparators := map[string]string{"gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+			comparator, ok := comparators[operator]
+			if !ok {
+				continue
+			}
+			conditions = append(conditions, fmt.Sprintf("%s.consent_status = TRUE AND %s.consented_at %s $%d::timestamptz",
+				consentAlias, consentAlias, comparator, argID))
+			args = append(args, value)
+			argID++
 		}
 	}
 
@@ -1376,6 +1589,7 @@ func GetAllReferenceProfilesExceptForCurrent(currentProfile model.Profile) ([]mo
 			return nil, serverError
 		}
 
+		profile.AttributeMetadata = parseAttributeMetadata(row["attribute_metadata"])
 		profile.ApplicationData, _ = FetchApplicationData(profile.ProfileId)
 
 		profiles = append(profiles, profile)
@@ -1384,52 +1598,16 @@ func GetAllReferenceProfilesExceptForCurrent(currentProfile model.Profile) ([]mo
 	return profiles, nil
 }
 
-// UpdateProfileReferences updates the references of a parent profile with the provided child profiles.
-func UpdateProfileReferences(parentProfile model.Profile, children []model.Reference) error {
+// UpdateProfileReferences updates the references of a parent profile with the provided child profiles
+// within the given transaction.
+func UpdateProfileReferences(tx *sql.Tx, parentProfile model.Profile, children []model.Reference) error {
 
-	dbClient, err := provider.NewDBProvider().GetDBClient()
 	logger := log.GetLogger()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to get database client for adding child profiles for parent: %s",
-			parentProfile.ProfileId)
-		logger.Debug(errorMsg, log.Error(err))
-		serverError := errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.UPDATE_PROFILE.Code,
-			Message:     errors2.UPDATE_PROFILE.Message,
-			Description: errorMsg,
-		}, err)
-		return serverError
-	}
-	defer dbClient.Close()
-
-	tx, err := dbClient.BeginTx()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to begin transaction for adding child profiles for parent: %s",
-			parentProfile.ProfileId)
-		logger.Debug(errorMsg, log.Error(err))
-		serverError := errors2.NewServerError(errors2.ErrorMessage{
-			Code:        errors2.UPDATE_PROFILE.Code,
-			Message:     errors2.UPDATE_PROFILE.Message,
-			Description: errorMsg,
-		}, err)
-		return serverError
-	}
 	query := scripts.UpdateProfileReference[provider.NewDBProvider().GetDBType()]
 
 	for _, child := range children {
 		_, err := tx.Exec(query, parentProfile.ProfileId, child.Reason, constants.MergedTo, child.ProfileId)
 		if err != nil {
-			errRoll := tx.Rollback()
-			if errRoll != nil {
-				errorMsg := fmt.Sprintf("Failed to rollback transaction after error: %s", err)
-				logger.Debug(errorMsg, log.Error(err))
-				serverError := errors2.NewServerError(errors2.ErrorMessage{
-					Code:        errors2.UPDATE_PROFILE.Code,
-					Message:     errors2.UPDATE_PROFILE.Message,
-					Description: errorMsg,
-				}, errRoll)
-				return serverError
-			}
 			errorMsg := fmt.Sprintf("Failed to insert referenced profile: %s for parent profile: %s", child.ProfileId, parentProfile.ProfileId)
 			logger.Debug(errorMsg, log.Error(err))
 			serverError := errors2.NewServerError(errors2.ErrorMessage{
@@ -1440,8 +1618,7 @@ func UpdateProfileReferences(parentProfile model.Profile, children []model.Refer
 			return serverError
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 func FetchReferencedProfiles(referenceProfileId string) ([]model.Reference, error) {
@@ -1913,7 +2090,7 @@ func DeleteInactiveCookieProfiles(batchSize int) (int, error) {
 }
 
 // UpdateProfileConsents updates or creates consent records for a profile
-func UpdateProfileConsents(profileId string, consents []model.ConsentRecord) error {
+func UpdateProfileConsents(profileId string, orgHandle string, consents []model.ConsentRecord, receipt *model.ConsentReceiptRecord) error {
 	dbClient, err := provider.NewDBProvider().GetDBClient()
 	logger := log.GetLogger()
 	if err != nil {
@@ -1944,7 +2121,7 @@ func UpdateProfileConsents(profileId string, consents []model.ConsentRecord) err
 	// First, delete existing consents for this profile to ensure a clean slate
 
 	deleteQuery := scripts.DeleteProfileConsentsByProfileId[provider.NewDBProvider().GetDBType()]
-	_, err = tx.Exec(deleteQuery, profileId)
+	_, err = tx.Exec(deleteQuery, profileId, orgHandle)
 	if err != nil {
 		_ = tx.Rollback()
 		errorMsg := fmt.Sprintf("Failed to delete existing consents for profile: %s", profileId)
@@ -1957,15 +2134,46 @@ func UpdateProfileConsents(profileId string, consents []model.ConsentRecord) err
 		return serverError
 	}
 
+	// Persist the receipt first so the consent rows can reference it
+	var receiptId interface{}
+	if receipt != nil {
+		receiptJSON, err := json.Marshal(receipt.Receipt)
+		if err != nil {
+			_ = tx.Rollback()
+			errorMsg := fmt.Sprintf("Failed to marshal consent receipt for profile: %s", profileId)
+			logger.Debug(errorMsg, log.Error(err))
+			return errors2.NewServerError(errors2.ErrorMessage{
+				Code:        errors2.UPDATE_PROFILE.Code,
+				Message:     errors2.UPDATE_PROFILE.Message,
+				Description: errorMsg,
+			}, err)
+		}
+		receiptQuery := scripts.InsertConsentReceipt[provider.NewDBProvider().GetDBType()]
+		_, err = tx.Exec(receiptQuery, receipt.ReceiptId, profileId, orgHandle, receiptJSON, receipt.CreatedAt)
+		if err != nil {
+			_ = tx.Rollback()
+			errorMsg := fmt.Sprintf("Failed to insert consent receipt for profile: %s", profileId)
+			logger.Debug(errorMsg, log.Error(err))
+			return errors2.NewServerError(errors2.ErrorMessage{
+				Code:        errors2.UPDATE_PROFILE.Code,
+				Message:     errors2.UPDATE_PROFILE.Message,
+				Description: errorMsg,
+			}, err)
+		}
+		receiptId = receipt.ReceiptId
+	}
+
 	// Insert new consent records
 	insertQuery := scripts.InsertProfileConsentsByProfileId[provider.NewDBProvider().GetDBType()]
 	for _, consent := range consents {
 
 		_, err = tx.Exec(insertQuery,
 			profileId,
+			orgHandle,
 			consent.CategoryIdentifier,
 			consent.IsConsented,
-			consent.ConsentedAt)
+			consent.ConsentedAt,
+			receiptId)
 
 		if err != nil {
 			_ = tx.Rollback()
@@ -1996,3 +2204,63 @@ func UpdateProfileConsents(profileId string, consents []model.ConsentRecord) err
 	logger.Info(fmt.Sprintf("Successfully updated consents for profile: %s", profileId))
 	return nil
 }
+
+// GetConsentReceipt fetches a consent receipt issued for the given profile of the org. Returns nil if no such
+// receipt exists.
+func GetConsentReceipt(profileId, orgHandle, receiptId string) (*model.ConsentReceiptRecord, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	logger := log.GetLogger()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get db client while fetching consent receipt: %s", receiptId)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_CONSENT_RECEIPT.Code,
+			Message:     errors2.GET_CONSENT_RECEIPT.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.GetConsentReceipt[provider.NewDBProvider().GetDBType()]
+	results, err := dbClient.ExecuteQuery(query, profileId, orgHandle, receiptId)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to fetch consent receipt: %s", receiptId)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_CONSENT_RECEIPT.Code,
+			Message:     errors2.GET_CONSENT_RECEIPT.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	row := results[0]
+	record := &model.ConsentReceiptRecord{
+		ReceiptId: row["receipt_id"].(string),
+		ProfileId: row["profile_id"].(string),
+		OrgHandle: row["org_handle"].(string),
+	}
+	if createdAt, ok := row["created_at"].(time.Time); ok {
+		record.CreatedAt = createdAt
+	}
+	var receiptJSON []byte
+	switch v := row["receipt"].(type) {
+	case string:
+		receiptJSON = []byte(v)
+	case []byte:
+		receiptJSON = v
+	}
+	if err := json.Unmarshal(receiptJSON, &record.Receipt); err != nil {
+		errorMsg := fmt.Sprintf("Failed to parse consent receipt: %s", receiptId)
+		logger.Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.GET_CONSENT_RECEIPT.Code,
+			Message:     errors2.GET_CONSENT_RECEIPT.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return record, nil
+}
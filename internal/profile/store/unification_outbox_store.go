@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// InsertUnificationOutboxEntry adds a profile to the unification outbox within the transaction that writes the
+// profile, so that the profile is enqueued for unification if and only if its write is committed.
+func InsertUnificationOutboxEntry(tx *sql.Tx, profile model.Profile) error {
+
+	query := scripts.InsertUnificationOutboxEntry[provider.NewDBProvider().GetDBType()]
+	_, err := client.NewTxClient(tx).ExecuteQuery(query, profile.ProfileId, profile.OrgHandle)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to add profile: %s to the unification outbox", profile.ProfileId)
+		log.GetLogger().Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.ADD_PROFILE.Code,
+			Message:     errors2.ADD_PROFILE.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return nil
+}
+
+// LockUnificationOutboxEntries returns up to limit of the oldest outbox entries, locked until the transaction
+// ends. The entries of a profile written within the last debounce milliseconds are left out, so that a burst of
+// writes to a profile is published once it settles, unless the profile has waited longer than maxWait milliseconds:
+// a profile written continuously is still published. Entries locked by another transaction are skipped.
+func LockUnificationOutboxEntries(tx *sql.Tx, limit, debounce, maxWait int) ([]model.UnificationOutboxEntry, error) {
+
+	query := scripts.LockUnificationOutboxEntries[provider.NewDBProvider().GetDBType()]
+	results, err := client.NewTxClient(tx).ExecuteQuery(query, limit, debounce, maxWait)
+	if err != nil {
+		errorMsg := "Failed to fetch the entries of the unification outbox"
+		log.GetLogger().Debug(errorMsg, log.Error(err))
+		return nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.RELAY_UNIFICATION_OUTBOX.Code,
+			Message:     errors2.RELAY_UNIFICATION_OUTBOX.Message,
+			Description: errorMsg,
+		}, err)
+	}
+
+	entries := make([]model.UnificationOutboxEntry, 0, len(results))
+	for _, row := range results {
+		entries = append(entries, model.UnificationOutboxEntry{
+			Id: row["outbox_id"].(int64),
+			Message: model.UnificationMessage{
+				ProfileId: row["profile_id"].(string),
+				OrgHandle: row["org_handle"].(string),
+			},
+		})
+	}
+	return entries, nil
+}
+
+// DeleteUnificationOutboxEntry removes a published entry from the unification outbox.
+func DeleteUnificationOutboxEntry(tx *sql.Tx, id int64) error {
+
+	query := scripts.DeleteUnificationOutboxEntry[provider.NewDBProvider().GetDBType()]
+	if _, err := client.NewTxClient(tx).ExecuteQuery(query, id); err != nil {
+		errorMsg := fmt.Sprintf("Failed to delete unification outbox entry: %d", id)
+		log.GetLogger().Debug(errorMsg, log.Error(err))
+		return errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.RELAY_UNIFICATION_OUTBOX.Code,
+			Message:     errors2.RELAY_UNIFICATION_OUTBOX.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return nil
+}
+
+func requeueError(errorMsg string, err error) error {
+	log.GetLogger().Debug(errorMsg, log.Error(err))
+	return errors2.NewServerError(errors2.ErrorMessage{
+		Code:        errors2.PERSIST_PENDING_QUEUE_ITEMS.Code,
+		Message:     errors2.PERSIST_PENDING_QUEUE_ITEMS.Message,
+		Description: errorMsg,
+	}, err)
+}
+
+// RequeueUnificationMessages adds messages that were taken from the outbox, but not processed, back to the outbox,
+// so that they are published again.
+func RequeueUnificationMessages(messages []model.UnificationMessage) error {
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(messages)
+	if err != nil {
+		return requeueError("Failed to encode the unification messages to return to the outbox", err)
+	}
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return requeueError("Failed to get database client for returning unification messages to the outbox", err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.RequeueUnificationMessages[provider.NewDBProvider().GetDBType()]
+	if _, err := dbClient.ExecuteQuery(query, string(payload)); err != nil {
+		return requeueError(fmt.Sprintf("Failed to return %d unification messages to the outbox", len(messages)), err)
+	}
+	return nil
+}
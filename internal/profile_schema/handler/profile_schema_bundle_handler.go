@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	bundleProvider "github.com/wso2/identity-customer-data-service/internal/schema_bundle/provider"
+	bundleService "github.com/wso2/identity-customer-data-service/internal/schema_bundle/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/security"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+// A bundle covers unification rules and consent categories as well, so handling one requires the permissions of
+// each of them.
+var (
+	exportBundlePermissions = []string{"profile_schema:view", "unification_rules:view", "consent_category:view"}
+	importBundlePermissions = []string{"profile_schema:update", "unification_rules:update", "consent_category:update"}
+)
+
+// ExportProfileSchema handles exporting the profile schema as a portable bundle.
+func (psh *ProfileSchemaHandler) ExportProfileSchema(w http.ResponseWriter, r *http.Request) {
+
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	if err := authorizeAll(r, exportBundlePermissions); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	format, err := bundleFormat(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	bundle, err := bundleProvider.NewSchemaBundleProvider().GetSchemaBundleService().ExportSchemaBundle(orgHandle)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	body, err := bundleService.EncodeSchemaBundle(*bundle, format)
+	if err != nil {
+		utils.HandleError(w, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.EXPORT_PROFILE_SCHEMA.Code,
+			Message:     errors2.EXPORT_PROFILE_SCHEMA.Message,
+			Description: "Failed to encode the " + constants.SchemaBundleResource,
+		}, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", bundleContentType(format))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// ImportProfileSchema handles importing a profile schema bundle. With dry_run=true it only reports the changes
+// the import would make.
+func (psh *ProfileSchemaHandler) ImportProfileSchema(w http.ResponseWriter, r *http.Request) {
+
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.HandleError(w, errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.INVALID_SCHEMA_BUNDLE.Code,
+				Message:     errors2.INVALID_SCHEMA_BUNDLE.Message,
+				Description: "dry_run must be either true or false.",
+			}, http.StatusBadRequest))
+			return
+		}
+		dryRun = parsed
+	}
+
+	permissions := importBundlePermissions
+	if dryRun {
+		permissions = exportBundlePermissions
+	}
+	if err := authorizeAll(r, permissions); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	format, err := bundleFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type"))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.HandleError(w, errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.INVALID_SCHEMA_BUNDLE.Code,
+			Message:     errors2.INVALID_SCHEMA_BUNDLE.Message,
+			Description: "Failed to read the " + constants.SchemaBundleResource,
+		}, http.StatusBadRequest))
+		return
+	}
+	bundle, err := bundleService.DecodeSchemaBundle(body, format)
+	if err != nil {
+		utils.HandleError(w, errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.INVALID_SCHEMA_BUNDLE.Code,
+			Message:     errors2.INVALID_SCHEMA_BUNDLE.Message,
+			Description: utils.HandleDecodeError(err, constants.SchemaBundleResource),
+		}, http.StatusBadRequest))
+		return
+	}
+
+	summary, err := bundleProvider.NewSchemaBundleProvider().GetSchemaBundleService().
+		ImportSchemaBundle(orgHandle, bundle, dryRun)
+	if err != nil && summary != nil && summary.Failure != nil {
+		// The import was rolled back; report the summary, with nothing created or updated, along with the error.
+		status := http.StatusInternalServerError
+		var clientError *errors2.ClientError
+		if errors.As(err, &clientError) {
+			status = clientError.StatusCode
+		}
+		utils.RespondJSON(w, status, summary, constants.SchemaBundleResource)
+		return
+	}
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, summary, constants.SchemaBundleResource)
+}
+
+func authorizeAll(r *http.Request, permissions []string) error {
+	for _, permission := range permissions {
+		if err := security.AuthnAndAuthz(r, permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bundleFormat resolves the bundle format from the format query parameter, falling back to the media type of the
+// given header. JSON is the default.
+func bundleFormat(format, mediaType string) (string, error) {
+	switch strings.ToLower(format) {
+	case constants.SchemaBundleFormatJSON, constants.SchemaBundleFormatYAML:
+		return strings.ToLower(format), nil
+	case "":
+		if strings.Contains(strings.ToLower(mediaType), "yaml") {
+			return constants.SchemaBundleFormatYAML, nil
+		}
+		return constants.SchemaBundleFormatJSON, nil
+	default:
+		return "", errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.INVALID_SCHEMA_BUNDLE.Code,
+			Message:     errors2.INVALID_SCHEMA_BUNDLE.Message,
+			Description: "Unsupported bundle format: " + format + ". Must be json or yaml.",
+		}, http.StatusBadRequest)
+	}
+}
+
+func bundleContentType(format string) string {
+	if format == constants.SchemaBundleFormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
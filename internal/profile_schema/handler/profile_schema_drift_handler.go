@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/security"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+// GetSchemaDriftReport handles comparing the identity attributes of the schema with the identity server claims.
+func (psh *ProfileSchemaHandler) GetSchemaDriftReport(w http.ResponseWriter, r *http.Request) {
+
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	err := security.AuthnAndAuthz(r, "profile_schema:view")
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	schemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
+	report, err := schemaService.GetSchemaDriftReport(orgHandle)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, report, constants.SchemaDriftResource)
+}
+
+// ReconcileSchemaDrift handles bringing the identity attributes of the schema in line with the identity server.
+func (psh *ProfileSchemaHandler) ReconcileSchemaDrift(w http.ResponseWriter, r *http.Request) {
+
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	err := security.AuthnAndAuthz(r, "profile_schema:update")
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	schemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
+	report, err := schemaService.ReconcileSchemaDrift(orgHandle)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, report, constants.SchemaDriftResource)
+}
@@ -22,10 +22,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
 	adminConfigService "github.com/wso2/identity-customer-data-service/internal/admin_config/service"
+	jobProvider "github.com/wso2/identity-customer-data-service/internal/job/provider"
 	"github.com/wso2/identity-customer-data-service/internal/system/security"
 	"github.com/wso2/identity-customer-data-service/internal/system/workers"
 
@@ -170,8 +172,20 @@ func (psh *ProfileSchemaHandler) GetProfileSchema(w http.ResponseWriter, r *http
 	}
 	schemaProvider := provider.NewProfileSchemaProvider()
 	schemaService := schemaProvider.GetProfileSchemaService()
-	profileSchema, err := schemaService.GetProfileSchema(orgHandle)
 
+	var profileSchema map[string]interface{}
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+		profileSchema, err = schemaService.GetProfileSchema(orgHandle)
+	case constants.ProfileSchemaFormatJSONSchema:
+		profileSchema, err = schemaService.GetProfileSchemaAsJSONSchema(orgHandle)
+	default:
+		err = errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.INVALID_PROFILE_SCHEMA_FORMAT.Code,
+			Message:     errors2.INVALID_PROFILE_SCHEMA_FORMAT.Message,
+			Description: "Unsupported profile schema format: " + format + ". Must be " + constants.ProfileSchemaFormatJSONSchema + ".",
+		}, http.StatusBadRequest)
+	}
 	if err != nil {
 		utils.HandleError(w, err)
 		return
@@ -405,14 +419,38 @@ func (psh *ProfileSchemaHandler) DeleteProfileSchemaAttributeById(w http.Respons
 		utils.WriteErrorResponse(w, clientError)
 		return
 	}
+	purgeData := false
+	if raw := r.URL.Query().Get("purge_data"); raw != "" {
+		if purgeData, err = strconv.ParseBool(raw); err != nil {
+			clientError := errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.PROFILE_SCHEMA_ADD_BAD_REQUEST.Code,
+				Message:     errors2.PROFILE_SCHEMA_ADD_BAD_REQUEST.Message,
+				Description: "purge_data must be true or false",
+			}, http.StatusBadRequest)
+			utils.HandleError(w, clientError)
+			return
+		}
+	}
 	schemaProvider := provider.NewProfileSchemaProvider()
 	schemaService := schemaProvider.GetProfileSchemaService()
 
-	err = schemaService.DeleteProfileSchemaAttributeById(orgHandle, attributeId)
-
-	if err != nil {
-		utils.HandleError(w, err)
-		return
+	if purgeData {
+		// The attribute is gone straight away; its values are removed from profiles in the background.
+		purge, err := schemaService.DeleteProfileSchemaAttributeAndPurgeData(orgHandle, attributeId)
+		if err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+		if purge != nil {
+			utils.RespondJSON(w, http.StatusAccepted, purge, constants.SchemaMigrationResource)
+			return
+		}
+	} else {
+		err = schemaService.DeleteProfileSchemaAttributeById(orgHandle, attributeId)
+		if err != nil {
+			utils.HandleError(w, err)
+			return
+		}
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNoContent)
@@ -509,9 +547,20 @@ func (psh *ProfileSchemaHandler) SyncProfileSchema(w http.ResponseWriter, r *htt
 	if schemaSync.Event == constants.AddScimAttributeEvent || schemaSync.Event == constants.UpdateScimAttributeEvent ||
 		schemaSync.Event == constants.DeleteScimAttributeEvent ||
 		schemaSync.Event == constants.UpdateLocalAttributeEvent || schemaSync.Event == constants.DeleteLocalClaimEvent {
-		// Enqueue the schema sync job for asynchronous processing
-		err := workers.EnqueueSchemaSyncJob(schemaSync)
+		// Record the sync as a job, so that its outcome can be followed, and enqueue it for asynchronous processing
+		jobService := jobProvider.NewJobProvider().GetJobService()
+		job, err := jobService.CreateJob(constants.JobTypeSchemaSync, orgId)
 		if err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+		schemaSync.JobId = job.JobId
+		err = workers.EnqueueSchemaSyncJob(schemaSync)
+		if err != nil {
+			if failErr := jobService.FailJob(job.JobId, fmt.Sprintf("The schema sync could not be enqueued: %v",
+				err)); failErr != nil {
+				logger.Warn(fmt.Sprintf("Failed to record the failure of job: %s", job.JobId), log.Error(failErr))
+			}
 			errMsg := fmt.Sprintf("Unable to process schema sync request for organization: %s. The system is currently at capacity. Please try again in a few moments.", schemaSync.OrgId)
 			logger.Error(errMsg)
 			serverError := errors2.NewServerError(errors2.ErrorMessage{
@@ -527,7 +576,10 @@ func (psh *ProfileSchemaHandler) SyncProfileSchema(w http.ResponseWriter, r *htt
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(map[string]string{"message": "Profile schema sync request accepted for processing."})
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"message": "Profile schema sync request accepted for processing.",
+			"job_id":  job.JobId,
+		})
 		return
 	}
 
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/security"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+// PreviewSchemaMigration handles previewing how an attribute update would migrate the stored profile values.
+func (psh *ProfileSchemaHandler) PreviewSchemaMigration(w http.ResponseWriter, r *http.Request) {
+
+	attributeId := r.PathValue("attrID")
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	err := security.AuthnAndAuthz(r, "profile_schema:update")
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+	scope := r.PathValue("scope")
+	if !constants.AllowedAttributesScope[scope] || scope == constants.IdentityAttributes {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.PROFILE_SCHEMA_ADD_BAD_REQUEST.Code,
+			Message:     errors2.PROFILE_SCHEMA_ADD_BAD_REQUEST.Message,
+			Description: "Invalid scope for a schema migration: " + scope,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+	var updates map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.PROFILE_SCHEMA_ADD_BAD_REQUEST.Code,
+			Message:     errors2.PROFILE_SCHEMA_ADD_BAD_REQUEST.Message,
+			Description: "Invalid request payload",
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	schemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
+	preview, err := schemaService.PreviewSchemaMigration(orgHandle, attributeId, updates, scope)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, preview, constants.SchemaMigrationResource)
+}
+
+// GetSchemaMigrationsForAttribute handles listing the schema migrations of an attribute.
+func (psh *ProfileSchemaHandler) GetSchemaMigrationsForAttribute(w http.ResponseWriter, r *http.Request) {
+
+	attributeId := r.PathValue("attrID")
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	err := security.AuthnAndAuthz(r, "profile_schema:view")
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	schemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
+	migrations, err := schemaService.GetSchemaMigrationsForAttribute(orgHandle, attributeId)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, migrations, constants.SchemaMigrationResource)
+}
+
+// GetSchemaMigration handles fetching the progress of a schema migration.
+func (psh *ProfileSchemaHandler) GetSchemaMigration(w http.ResponseWriter, r *http.Request) {
+
+	migrationId := r.PathValue("migrationID")
+	orgHandle := utils.ExtractOrgHandleFromPath(r)
+	err := security.AuthnAndAuthz(r, "profile_schema:view")
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	if !isCDSEnabled(orgHandle) {
+		clientError := errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.CDS_NOT_ENABLED.Code,
+			Message:     errors2.CDS_NOT_ENABLED.Message,
+			Description: errors2.CDS_NOT_ENABLED.Description,
+		}, http.StatusBadRequest)
+		utils.HandleError(w, clientError)
+		return
+	}
+
+	schemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
+	migration, err := schemaService.GetSchemaMigration(orgHandle, migrationId)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, migration, constants.SchemaMigrationResource)
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+// AttributeConstraints restricts the values a schema attribute accepts, on top of its value type and canonical
+// values. Unset fields impose no restriction.
+type AttributeConstraints struct {
+	Required  bool     `json:"required,omitempty" bson:"required,omitempty"`     // Must be present whenever its scope is written
+	Minimum   *float64 `json:"minimum,omitempty" bson:"minimum,omitempty"`       // Numbers: smallest allowed value
+	Maximum   *float64 `json:"maximum,omitempty" bson:"maximum,omitempty"`       // Numbers: largest allowed value
+	MinDate   string   `json:"min_date,omitempty" bson:"min_date,omitempty"`     // Dates: earliest allowed date or timestamp
+	MaxDate   string   `json:"max_date,omitempty" bson:"max_date,omitempty"`     // Dates: latest allowed date or timestamp
+	MinLength *int     `json:"min_length,omitempty" bson:"min_length,omitempty"` // Strings: fewest characters
+	MaxLength *int     `json:"max_length,omitempty" bson:"max_length,omitempty"` // Strings: most characters
+	Pattern   string   `json:"pattern,omitempty" bson:"pattern,omitempty"`       // Strings: regular expression the value must match
+	Format    string   `json:"format,omitempty" bson:"format,omitempty"`         // Strings: one of the built-in formats
+	MaxItems  *int     `json:"max_items,omitempty" bson:"max_items,omitempty"`   // Multi-valued: most elements
+}
+
+// IsEmpty reports whether no constraint is set.
+func (c AttributeConstraints) IsEmpty() bool {
+	return c == AttributeConstraints{}
+}
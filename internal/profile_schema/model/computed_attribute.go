@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/expression"
+)
+
+// ComputedAttribute is a computed schema attribute together with its parsed expression.
+type ComputedAttribute struct {
+	Attribute  ProfileSchemaAttribute
+	Expression *expression.Expression
+}
+
+// IsComputed reports whether the attribute's value is derived from an expression.
+func (a ProfileSchemaAttribute) IsComputed() bool {
+	return a.Mutability == constants.MutabilityComputed
+}
+
+// ValuePath returns the path expressions use to reference the attribute's value. Application data is addressed
+// per application, e.g. application_data.<application_identifier>.<name>.
+func (a ProfileSchemaAttribute) ValuePath() string {
+	if strings.HasPrefix(a.AttributeName, constants.ApplicationData+".") && a.ApplicationIdentifier != "" {
+		return constants.ApplicationData + "." + a.ApplicationIdentifier +
+			strings.TrimPrefix(a.AttributeName, constants.ApplicationData)
+	}
+	return a.AttributeName
+}
+
+// PathMatches reports whether the expression reference ref reads the value at path. Wildcard segments in ref
+// match any single segment.
+func PathMatches(ref, path string) bool {
+	refSegments := strings.Split(ref, ".")
+	pathSegments := strings.Split(path, ".")
+	if len(refSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range refSegments {
+		if segment != expression.Wildcard && segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// OrderComputedAttributes parses the expressions of the computed attributes in attrs and orders them so that every
+// attribute comes after the computed attributes it reads. It fails if an expression does not parse or if the
+// attributes depend on each other in a cycle.
+func OrderComputedAttributes(attrs []ProfileSchemaAttribute) ([]ComputedAttribute, error) {
+	computed := make([]ComputedAttribute, 0)
+	for _, attr := range attrs {
+		if !attr.IsComputed() {
+			continue
+		}
+		expr, err := expression.Parse(attr.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression for computed attribute '%s': %w", attr.AttributeName, err)
+		}
+		computed = append(computed, ComputedAttribute{Attribute: attr, Expression: expr})
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(computed))
+	ordered := make([]ComputedAttribute, 0, len(computed))
+
+	var visit func(i int, chain []string) error
+	visit = func(i int, chain []string) error {
+		chain = append(chain, computed[i].Attribute.ValuePath())
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("computed attributes depend on each other in a cycle: %s", strings.Join(chain, " -> "))
+		}
+		state[i] = visiting
+		for _, ref := range computed[i].Expression.References() {
+			for j := range computed {
+				if PathMatches(ref, computed[j].Attribute.ValuePath()) {
+					if err := visit(j, chain); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		state[i] = done
+		ordered = append(ordered, computed[i])
+		return nil
+	}
+	for i := range computed {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -21,25 +21,26 @@ package model
 import "github.com/wso2/identity-customer-data-service/internal/system/constants"
 
 type ProfileSchemaAttribute struct {
-	OrgId                 string           `json:"org_id,omitempty" bson:"org_id,omitempty"`
-	AttributeId           string           `json:"attribute_id" bson:"attribute_id"`
-	AttributeName         string           `json:"attribute_name" bson:"attribute_name" binding:"required"`
-	Scope                 string           `json:"scope,omitempty" bson:"scope,omitempty"`
-	DisplayName           string           `json:"display_name,omitempty" bson:"display_name,omitempty"`
-	ValueType             string           `json:"value_type" bson:"value_type" binding:"required"`
-	MergeStrategy         string           `json:"merge_strategy" bson:"merge_strategy" binding:"required"`
-	Mutability            string           `json:"mutability" bson:"mutability"`
-	ApplicationIdentifier string           `json:"application_identifier,omitempty" bson:"application_identifier,omitempty"`
-	MultiValued           bool             `json:"multi_valued,omitempty" bson:"multi_valued,omitempty"`         // Means the data type is an array of chosen data type
-	CanonicalValues       []CanonicalValue `json:"canonical_values,omitempty" bson:"canonical_values,omitempty"` // String of options for the attribute
-	SubAttributes         []SubAttribute   `json:"sub_attributes,omitempty" bson:"sub_attributes,omitempty"`     // If the datatype is object
-	SCIMDialect           string           `json:"scim_dialect,omitempty" bson:"scim_dialect,omitempty"`         // Need to skip this in the response
+	OrgId                 string                   `json:"org_id,omitempty" bson:"org_id,omitempty"`
+	AttributeId           string                   `json:"attribute_id" bson:"attribute_id"`
+	AttributeName         string                   `json:"attribute_name" bson:"attribute_name" binding:"required"`
+	Scope                 string                   `json:"scope,omitempty" bson:"scope,omitempty"`
+	DisplayName           string                   `json:"display_name,omitempty" bson:"display_name,omitempty"`
+	ValueType             string                   `json:"value_type" bson:"value_type" binding:"required"`
+	MergeStrategy         string                   `json:"merge_strategy" bson:"merge_strategy" binding:"required"`
+	Mutability            string                   `json:"mutability" bson:"mutability"`
+	ApplicationIdentifier string                   `json:"application_identifier,omitempty" bson:"application_identifier,omitempty"`
+	MultiValued           bool                     `json:"multi_valued,omitempty" bson:"multi_valued,omitempty"`         // Means the data type is an array of chosen data type
+	CanonicalValues       []CanonicalValue         `json:"canonical_values,omitempty" bson:"canonical_values,omitempty"` // String of options for the attribute
+	SubAttributes         []ProfileSchemaAttribute `json:"sub_attributes,omitempty" bson:"sub_attributes,omitempty"`     // If the datatype is object
+	SCIMDialect           string                   `json:"scim_dialect,omitempty" bson:"scim_dialect,omitempty"`         // Need to skip this in the response
+	Expression            string                   `json:"expression,omitempty" bson:"expression,omitempty"`             // Derives the value of a computed attribute
+	Constraints           *AttributeConstraints    `json:"constraints,omitempty" bson:"constraints,omitempty"`           // Restricts the accepted values
 }
 
-type SubAttribute struct {
-	AttributeId   string `json:"attribute_id" bson:"attribute_id" binding:"required"`
-	AttributeName string `json:"attribute_name" bson:"attribute_name" binding:"required"`
-}
+// SubAttribute is a nested attribute definition of a complex attribute. A sub-attribute that only carries its id
+// and name refers to an attribute defined separately in the same scope.
+type SubAttribute = ProfileSchemaAttribute
 
 type CanonicalValue struct {
 	Value string `json:"value" bson:"value"`
@@ -19,6 +19,22 @@
 package model
 
 type ProfileSchemaSync struct {
-	OrgId string `json:"orgHandle" bson:"orgHandle"`
-	Event string `json:"event" bson:"event"`
+	OrgId string           `json:"orgHandle" bson:"orgHandle"`
+	Event string           `json:"event" bson:"event"`
+	Claim *SchemaSyncClaim `json:"claim,omitempty" bson:"claim,omitempty"`
+	JobId string           `json:"jobId,omitempty" bson:"jobId,omitempty"` // Job recording the sync, set on receipt
+}
+
+// SchemaSyncClaim is the claim a schema sync event is about. For external claim events ClaimURI is the SCIM claim
+// and MappedLocalClaimURI the local claim it maps to; for local claim events ClaimURI is the local claim. The
+// remaining fields describe the local claim as it is after the change.
+type SchemaSyncClaim struct {
+	ClaimURI            string           `json:"claimURI" bson:"claimURI"`
+	DialectURI          string           `json:"dialectURI,omitempty" bson:"dialectURI,omitempty"`
+	MappedLocalClaimURI string           `json:"mappedLocalClaimURI,omitempty" bson:"mappedLocalClaimURI,omitempty"`
+	DisplayName         string           `json:"displayName,omitempty" bson:"displayName,omitempty"`
+	DataType            string           `json:"dataType,omitempty" bson:"dataType,omitempty"`
+	ReadOnly            bool             `json:"readOnly,omitempty" bson:"readOnly,omitempty"`
+	MultiValued         bool             `json:"multiValued,omitempty" bson:"multiValued,omitempty"`
+	CanonicalValues     []CanonicalValue `json:"canonicalValues,omitempty" bson:"canonicalValues,omitempty"`
 }
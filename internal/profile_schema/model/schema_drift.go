@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import "time"
+
+// SchemaDriftReport lists how the identity attributes of an organization's schema differ from the claims of the
+// identity server.
+type SchemaDriftReport struct {
+	OrgHandle  string                 `json:"org_handle"`
+	InSync     bool                   `json:"in_sync"`
+	Reconciled bool                   `json:"reconciled,omitempty"`
+	Missing    []SchemaDriftAttribute `json:"missing"`    // Claims with no attribute in the schema
+	Extra      []SchemaDriftAttribute `json:"extra"`      // Attributes with no claim in the identity server
+	Mismatched []SchemaDriftMismatch  `json:"mismatched"` // Attributes that differ from their claim
+	CheckedAt  time.Time              `json:"checked_at"`
+}
+
+// SchemaDriftAttribute is an attribute present on only one side of a drift report.
+type SchemaDriftAttribute struct {
+	AttributeId   string `json:"attribute_id,omitempty"`
+	AttributeName string `json:"attribute_name"`
+	ValueType     string `json:"value_type"`
+	MultiValued   bool   `json:"multi_valued"`
+}
+
+// SchemaDriftMismatch is an attribute whose definition differs from the claim it is synced from.
+type SchemaDriftMismatch struct {
+	AttributeId   string                  `json:"attribute_id"`
+	AttributeName string                  `json:"attribute_name"`
+	Differences   []SchemaDriftDifference `json:"differences"`
+}
+
+// SchemaDriftDifference is a field of an attribute that differs from the claim.
+type SchemaDriftDifference struct {
+	Field    string      `json:"field"`
+	Stored   interface{} `json:"stored"`
+	Expected interface{} `json:"expected"`
+}
+
+// DriftAttributeOf returns the drift report entry of an attribute.
+func DriftAttributeOf(attr ProfileSchemaAttribute) SchemaDriftAttribute {
+	return SchemaDriftAttribute{
+		AttributeId:   attr.AttributeId,
+		AttributeName: attr.AttributeName,
+		ValueType:     attr.ValueType,
+		MultiValued:   attr.MultiValued,
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import "time"
+
+// SchemaMigration converts the stored profile values of an attribute after its value type, multi-valued flag or
+// canonical values change. Values that cannot be converted are left as they are and reported as failures. A purge
+// migration removes the values of a deleted attribute instead; its converted values are the values removed.
+type SchemaMigration struct {
+	MigrationId           string                    `json:"migration_id"`
+	OrgHandle             string                    `json:"-"`
+	AttributeId           string                    `json:"attribute_id"`
+	AttributeName         string                    `json:"attribute_name"`
+	ApplicationIdentifier string                    `json:"application_identifier,omitempty"`
+	Operation             string                    `json:"operation"` // convert or purge
+	From                  SchemaMigrationDefinition `json:"from"`
+	To                    SchemaMigrationDefinition `json:"to"`
+	Status                string                    `json:"status"`
+	TotalProfiles         int                       `json:"total_profiles"`     // Profiles holding a value when the migration started
+	ProcessedProfiles     int                       `json:"processed_profiles"` // Profiles visited so far
+	ConvertedValues       int                       `json:"converted_values"`
+	FailedValues          int                       `json:"failed_values"`
+	Failures              []SchemaMigrationFailure  `json:"failures"` // The first failures, up to the sample size
+	Error                 string                    `json:"error,omitempty"`
+	Owner                 string                    `json:"-"` // The instance running the migration
+	CreatedAt             time.Time                 `json:"created_at"`
+	UpdatedAt             time.Time                 `json:"updated_at"`
+}
+
+// SchemaMigrationDefinition holds the parts of an attribute definition that decide the shape of its values.
+type SchemaMigrationDefinition struct {
+	ValueType       string           `json:"value_type"`
+	MultiValued     bool             `json:"multi_valued"`
+	CanonicalValues []CanonicalValue `json:"canonical_values,omitempty"`
+}
+
+// SchemaMigrationFailure is a stored value that cannot be converted to the new definition.
+type SchemaMigrationFailure struct {
+	ProfileId string      `json:"profile_id"`
+	Value     interface{} `json:"value"`
+	Reason    string      `json:"reason"`
+}
+
+// SchemaMigrationPreview reports what migrating an attribute to a proposed definition would do, without changing
+// any profile.
+type SchemaMigrationPreview struct {
+	AttributeName         string                    `json:"attribute_name"`
+	ApplicationIdentifier string                    `json:"application_identifier,omitempty"`
+	From                  SchemaMigrationDefinition `json:"from"`
+	To                    SchemaMigrationDefinition `json:"to"`
+	MigrationRequired     bool                      `json:"migration_required"`
+	TotalProfiles         int                       `json:"total_profiles"`
+	ConvertibleValues     int                       `json:"convertible_values"`
+	FailedValues          int                       `json:"failed_values"`
+	Failures              []SchemaMigrationFailure  `json:"failures"`
+}
+
+// MigrationDefinitionOf returns the value-shaping parts of an attribute definition.
+func MigrationDefinitionOf(attr ProfileSchemaAttribute) SchemaMigrationDefinition {
+	return SchemaMigrationDefinition{
+		ValueType:       attr.ValueType,
+		MultiValued:     attr.MultiValued,
+		CanonicalValues: attr.CanonicalValues,
+	}
+}
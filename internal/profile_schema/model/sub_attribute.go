@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+// SubAttributeReference is the stored form of a sub-attribute. Each sub-attribute is persisted as its own schema
+// attribute and the parent only keeps references to them.
+type SubAttributeReference struct {
+	AttributeId   string `json:"attribute_id"`
+	AttributeName string `json:"attribute_name"`
+}
+
+// IsReference reports whether the sub-attribute only refers to an attribute defined elsewhere instead of carrying
+// its own definition.
+func (a ProfileSchemaAttribute) IsReference() bool {
+	return a.ValueType == ""
+}
+
+// SubAttributeReferences returns the references persisted for the given sub-attributes.
+func SubAttributeReferences(subAttributes []ProfileSchemaAttribute) []SubAttributeReference {
+	references := make([]SubAttributeReference, 0, len(subAttributes))
+	for _, subAttr := range subAttributes {
+		references = append(references, SubAttributeReference{
+			AttributeId:   subAttr.AttributeId,
+			AttributeName: subAttr.AttributeName,
+		})
+	}
+	return references
+}
+
+// ResolveSubAttributes replaces the sub-attribute references of the given attributes with the full definitions
+// they refer to, at every depth. attrs must hold the referenced attributes; unknown references are kept as is.
+func ResolveSubAttributes(attrs []ProfileSchemaAttribute) []ProfileSchemaAttribute {
+	byId := make(map[string]ProfileSchemaAttribute, len(attrs))
+	for _, attr := range attrs {
+		byId[attr.AttributeId] = attr
+	}
+
+	resolved := make([]ProfileSchemaAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		resolved = append(resolved, resolveSubAttributes(attr, byId, map[string]bool{}))
+	}
+	return resolved
+}
+
+func resolveSubAttributes(attr ProfileSchemaAttribute, byId map[string]ProfileSchemaAttribute,
+	ancestors map[string]bool) ProfileSchemaAttribute {
+
+	if len(attr.SubAttributes) == 0 {
+		return attr
+	}
+	ancestors[attr.AttributeId] = true
+	defer delete(ancestors, attr.AttributeId)
+
+	subAttributes := make([]ProfileSchemaAttribute, 0, len(attr.SubAttributes))
+	for _, subAttr := range attr.SubAttributes {
+		if definition, ok := byId[subAttr.AttributeId]; ok && subAttr.IsReference() && !ancestors[subAttr.AttributeId] {
+			subAttr = definition
+		}
+		if !ancestors[subAttr.AttributeId] {
+			subAttr = resolveSubAttributes(subAttr, byId, ancestors)
+		}
+		subAttributes = append(subAttributes, subAttr)
+	}
+	attr.SubAttributes = subAttributes
+	return attr
+}
+
+// FlattenSubAttributes returns the given attributes followed by every nested sub-attribute definition, depth
+// first. References are skipped as the attributes they refer to are defined on their own.
+func FlattenSubAttributes(attrs []ProfileSchemaAttribute) []ProfileSchemaAttribute {
+	flattened := make([]ProfileSchemaAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		flattened = appendWithSubAttributes(flattened, attr, 0)
+	}
+	return flattened
+}
+
+// maxSubAttributeNesting guards against self-referencing definitions; attribute names grow with each level, so
+// real hierarchies never come close.
+const maxSubAttributeNesting = 64
+
+func appendWithSubAttributes(flattened []ProfileSchemaAttribute, attr ProfileSchemaAttribute, depth int) []ProfileSchemaAttribute {
+	flattened = append(flattened, attr)
+	if depth >= maxSubAttributeNesting {
+		return flattened
+	}
+	for _, subAttr := range attr.SubAttributes {
+		if !subAttr.IsReference() {
+			flattened = appendWithSubAttributes(flattened, subAttr, depth+1)
+		}
+	}
+	return flattened
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// validateAttributeConstraints checks that the constraints of an attribute fit its value type and are consistent
+// with each other.
+func validateAttributeConstraints(attr model.ProfileSchemaAttribute) error {
+	c := attr.Constraints
+	if c == nil {
+		return nil
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return invalidAttribute(fmt.Sprintf("Invalid constraints for attribute '%s': ", attr.AttributeName) +
+			fmt.Sprintf(format, args...))
+	}
+
+	isNumeric := attr.ValueType == constants.IntegerDataType || attr.ValueType == constants.DecimalDataType ||
+		attr.ValueType == constants.EpochDataType
+	isDate := attr.ValueType == constants.DateDataType || attr.ValueType == constants.DateTimeDataType
+	isString := attr.ValueType == constants.StringDataType
+
+	if c.Required && attr.IsComputed() {
+		return invalid("computed attributes cannot be required")
+	}
+
+	if (c.Minimum != nil || c.Maximum != nil) && !isNumeric {
+		return invalid("minimum and maximum apply only to numeric value types")
+	}
+	if c.Minimum != nil && c.Maximum != nil && *c.Minimum > *c.Maximum {
+		return invalid("minimum %v is greater than maximum %v", *c.Minimum, *c.Maximum)
+	}
+
+	if (c.MinDate != "" || c.MaxDate != "") && !isDate {
+		return invalid("min_date and max_date apply only to date value types")
+	}
+	var minDate, maxDate time.Time
+	var err error
+	if c.MinDate != "" {
+		if minDate, err = ParseConstraintDate(c.MinDate); err != nil {
+			return invalid("min_date '%s' is not a date (YYYY-MM-DD) or RFC 3339 timestamp", c.MinDate)
+		}
+	}
+	if c.MaxDate != "" {
+		if maxDate, err = ParseConstraintDate(c.MaxDate); err != nil {
+			return invalid("max_date '%s' is not a date (YYYY-MM-DD) or RFC 3339 timestamp", c.MaxDate)
+		}
+	}
+	if c.MinDate != "" && c.MaxDate != "" && minDate.After(maxDate) {
+		return invalid("min_date is after max_date")
+	}
+
+	if (c.MinLength != nil || c.MaxLength != nil || c.Pattern != "" || c.Format != "") && !isString {
+		return invalid("min_length, max_length, pattern and format apply only to the string value type")
+	}
+	if (c.MinLength != nil && *c.MinLength < 0) || (c.MaxLength != nil && *c.MaxLength < 0) {
+		return invalid("lengths cannot be negative")
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+		return invalid("min_length %d is greater than max_length %d", *c.MinLength, *c.MaxLength)
+	}
+	if c.Pattern != "" {
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return invalid("pattern does not compile: %v", err)
+		}
+	}
+	if c.Format != "" && !constants.AllowedConstraintFormats[c.Format] {
+		return invalid("unknown format '%s'. Must be one of %v", c.Format, keysOf(constants.AllowedConstraintFormats))
+	}
+
+	if c.MaxItems != nil {
+		if !attr.MultiValued {
+			return invalid("max_items applies only to multi-valued attributes")
+		}
+		if *c.MaxItems < 0 {
+			return invalid("max_items cannot be negative")
+		}
+	}
+	return nil
+}
+
+// ParseConstraintDate parses the bound of a date constraint, given as a date (YYYY-MM-DD) or an RFC 3339 timestamp.
+func ParseConstraintDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
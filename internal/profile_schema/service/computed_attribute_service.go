@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/expression"
+)
+
+// validateComputedAttribute checks the definition of a computed attribute on its own: where it may live, what it
+// may hold and that its expression parses and only reads attribute paths.
+func validateComputedAttribute(attr model.ProfileSchemaAttribute) error {
+	if !attr.IsComputed() {
+		if attr.Expression != "" {
+			return invalidAttribute(fmt.Sprintf("Attribute '%s' defines an expression but its mutability is not '%s'",
+				attr.AttributeName, constants.MutabilityComputed))
+		}
+		return nil
+	}
+
+	parts := strings.Split(attr.AttributeName, ".")
+	if parts[0] != constants.Traits && parts[0] != constants.ApplicationData {
+		return invalidAttribute(fmt.Sprintf("Computed attribute '%s' must be in the traits or application_data scope",
+			attr.AttributeName))
+	}
+	if len(parts) != 2 {
+		return invalidAttribute(fmt.Sprintf("Computed attribute '%s' cannot be a sub-attribute", attr.AttributeName))
+	}
+	if attr.ValueType == constants.ComplexDataType || attr.MultiValued {
+		return invalidAttribute(fmt.Sprintf("Computed attribute '%s' must hold a single, non-complex value",
+			attr.AttributeName))
+	}
+	if strings.TrimSpace(attr.Expression) == "" {
+		return invalidAttribute(fmt.Sprintf("Computed attribute '%s' requires an expression", attr.AttributeName))
+	}
+
+	expr, err := expression.Parse(attr.Expression)
+	if err != nil {
+		return invalidAttribute(fmt.Sprintf("Invalid expression for computed attribute '%s': %v", attr.AttributeName, err))
+	}
+	for _, ref := range expr.References() {
+		if err := validateExpressionReference(ref); err != nil {
+			return invalidAttribute(fmt.Sprintf("Invalid expression for computed attribute '%s': %v", attr.AttributeName, err))
+		}
+		if model.PathMatches(ref, attr.ValuePath()) {
+			return invalidAttribute(fmt.Sprintf("Computed attribute '%s' cannot reference itself", attr.AttributeName))
+		}
+	}
+	return nil
+}
+
+// validateExpressionReference checks that ref is a fully qualified attribute path. Wildcards are only accepted in
+// place of the application identifier of application data.
+func validateExpressionReference(ref string) error {
+	segments := strings.Split(ref, ".")
+	switch segments[0] {
+	case constants.IdentityAttributes, constants.Traits:
+	case constants.ApplicationData:
+		if len(segments) < 3 {
+			return fmt.Errorf("'%s' must be of the form application_data.<application_identifier>.<name>", ref)
+		}
+	default:
+		return fmt.Errorf("'%s' must start with identity_attributes, traits or application_data", ref)
+	}
+	for i, segment := range segments {
+		if segment == expression.Wildcard && !(segments[0] == constants.ApplicationData && i == 1) {
+			return fmt.Errorf("'%s' may only use '%s' in place of the application identifier", ref, expression.Wildcard)
+		}
+	}
+	return nil
+}
+
+// validateComputedDependencies checks the computed attributes in pending against the rest of the organization's
+// schema, as seen within tx: every referenced attribute must exist, and computed attributes must not depend on each
+// other in a cycle.
+func validateComputedDependencies(tx *sql.Tx, orgId string, pending []model.ProfileSchemaAttribute) error {
+	hasComputed := false
+	for _, attr := range pending {
+		hasComputed = hasComputed || attr.IsComputed()
+	}
+	if !hasComputed {
+		return nil
+	}
+
+	existing, err := psstr.GetProfileSchemaAttributesForOrgTx(tx, orgId)
+	if err != nil {
+		return err
+	}
+	replaced := make(map[string]bool, len(pending))
+	for _, attr := range pending {
+		replaced[attr.AttributeId] = true
+	}
+	all := make([]model.ProfileSchemaAttribute, 0, len(existing)+len(pending))
+	for _, attr := range existing {
+		if !replaced[attr.AttributeId] {
+			all = append(all, attr)
+		}
+	}
+	all = append(all, pending...)
+
+	for _, attr := range pending {
+		if !attr.IsComputed() {
+			continue
+		}
+		expr, err := expression.Parse(attr.Expression)
+		if err != nil {
+			return invalidAttribute(fmt.Sprintf("Invalid expression for computed attribute '%s': %v", attr.AttributeName, err))
+		}
+		for _, ref := range expr.References() {
+			if !isReferenceDefined(ref, all) {
+				return invalidAttribute(fmt.Sprintf("Computed attribute '%s' references '%s', which is not defined in the schema",
+					attr.AttributeName, ref))
+			}
+		}
+	}
+
+	if _, err := model.OrderComputedAttributes(all); err != nil {
+		return invalidAttribute(err.Error())
+	}
+	return nil
+}
+
+// computedAttributesDependingOn returns the names of the computed attributes that would be left without an input
+// if the attribute were removed from the schema.
+func computedAttributesDependingOn(removed model.ProfileSchemaAttribute, all []model.ProfileSchemaAttribute) []string {
+	remaining := make([]model.ProfileSchemaAttribute, 0, len(all))
+	for _, attr := range all {
+		if attr.AttributeId != removed.AttributeId {
+			remaining = append(remaining, attr)
+		}
+	}
+
+	dependents := make([]string, 0)
+	for _, attr := range remaining {
+		if !attr.IsComputed() {
+			continue
+		}
+		expr, err := expression.Parse(attr.Expression)
+		if err != nil {
+			continue
+		}
+		for _, ref := range expr.References() {
+			if model.PathMatches(ref, removed.ValuePath()) && !isReferenceDefined(ref, remaining) {
+				dependents = append(dependents, attr.AttributeName)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+func isReferenceDefined(ref string, attrs []model.ProfileSchemaAttribute) bool {
+	for _, attr := range attrs {
+		if model.PathMatches(ref, attr.ValuePath()) {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidAttribute(description string) error {
+	return errors2.NewClientError(errors2.ErrorMessage{
+		Code:        errors2.INVALID_ATTRIBUTE.Code,
+		Message:     errors2.INVALID_ATTRIBUTE.Message,
+		Description: description,
+	}, http.StatusBadRequest)
+}
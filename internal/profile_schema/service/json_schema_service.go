@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"strings"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// jsonSchemaFormats maps attribute constraint formats onto the JSON Schema formats with the same meaning. Formats
+// that JSON Schema does not define are rendered as patterns instead.
+var jsonSchemaFormats = map[string]string{
+	constants.ConstraintFormatEmail: "email",
+	constants.ConstraintFormatURI:   "uri",
+}
+
+var jsonSchemaFormatPatterns = map[string]string{
+	constants.ConstraintFormatE164:       `^\+[1-9][0-9]{1,14}$`,
+	constants.ConstraintFormatISOCountry: `^[A-Z]{2}$`,
+}
+
+// GetProfileSchemaAsJSONSchema renders the organization's profile schema as a JSON Schema 2020-12 document
+// describing the profile resource.
+func (s *ProfileSchemaService) GetProfileSchemaAsJSONSchema(orgId string) (map[string]interface{}, error) {
+
+	attributes, err := s.GetProfileSchemaAttributesForOrg(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	identityAttrs := make([]model.ProfileSchemaAttribute, 0)
+	traitsAttrs := make([]model.ProfileSchemaAttribute, 0)
+	appDataAttrs := make(map[string][]model.ProfileSchemaAttribute)
+	for _, attr := range attributes {
+		// Sub-attributes are rendered inside their parent object.
+		if strings.Count(attr.AttributeName, ".") != 1 {
+			continue
+		}
+		switch strings.SplitN(attr.AttributeName, ".", 2)[0] {
+		case constants.IdentityAttributes:
+			identityAttrs = append(identityAttrs, attr)
+		case constants.Traits:
+			traitsAttrs = append(traitsAttrs, attr)
+		case constants.ApplicationData:
+			if attr.ApplicationIdentifier != "" {
+				appDataAttrs[attr.ApplicationIdentifier] = append(appDataAttrs[attr.ApplicationIdentifier], attr)
+			}
+		}
+	}
+
+	applications := make(map[string]interface{}, len(appDataAttrs))
+	for appId, attrs := range appDataAttrs {
+		applications[appId] = jsonSchemaObject(attrs, constants.ApplicationData+".")
+	}
+
+	properties := map[string]interface{}{
+		constants.IdentityAttributes: jsonSchemaObject(identityAttrs, constants.IdentityAttributes+"."),
+		constants.Traits:             jsonSchemaObject(traitsAttrs, constants.Traits+"."),
+		constants.ApplicationData: map[string]interface{}{
+			"type":                 "object",
+			"properties":           applications,
+			"additionalProperties": false,
+		},
+	}
+	meta := make(map[string]interface{})
+	for attrName, core := range model.CoreSchema {
+		property := jsonSchemaType(core[constants.ValueType])
+		applyJSONSchemaMutability(property, core[constants.Mutability])
+		if field, isMeta := strings.CutPrefix(attrName, "meta."); isMeta {
+			meta[field] = property
+		} else {
+			properties[attrName] = property
+		}
+	}
+	properties["meta"] = map[string]interface{}{
+		"type":       "object",
+		"properties": meta,
+		"readOnly":   true,
+	}
+
+	return map[string]interface{}{
+		"$schema":    constants.JSONSchemaDialect,
+		"title":      "Profile",
+		"type":       "object",
+		"properties": properties,
+	}, nil
+}
+
+// jsonSchemaObject renders attributes as the properties of an object schema. Property names are the attribute
+// names without the given prefix.
+func jsonSchemaObject(attrs []model.ProfileSchemaAttribute, prefix string) map[string]interface{} {
+
+	properties := make(map[string]interface{}, len(attrs))
+	required := make([]string, 0)
+	for _, attr := range attrs {
+		name := strings.TrimPrefix(attr.AttributeName, prefix)
+		properties[name] = jsonSchemaAttribute(attr)
+		if attr.Constraints != nil && attr.Constraints.Required {
+			required = append(required, name)
+		}
+	}
+
+	object := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// jsonSchemaAttribute renders a single attribute, wrapping it in an array schema when it is multi-valued.
+func jsonSchemaAttribute(attr model.ProfileSchemaAttribute) map[string]interface{} {
+
+	var value map[string]interface{}
+	if attr.ValueType == constants.ComplexDataType {
+		value = jsonSchemaObject(attr.SubAttributes, attr.AttributeName+".")
+	} else {
+		value = jsonSchemaType(attr.ValueType)
+	}
+	if len(attr.CanonicalValues) > 0 {
+		enum := make([]string, 0, len(attr.CanonicalValues))
+		for _, canonical := range attr.CanonicalValues {
+			enum = append(enum, canonical.Value)
+		}
+		value["enum"] = enum
+	}
+	if attr.Constraints != nil {
+		applyJSONSchemaConstraints(value, *attr.Constraints)
+	}
+
+	property := value
+	if attr.MultiValued {
+		property = map[string]interface{}{"type": "array", "items": value}
+		if attr.Constraints != nil && attr.Constraints.MaxItems != nil {
+			property["maxItems"] = *attr.Constraints.MaxItems
+		}
+	}
+	if attr.DisplayName != "" {
+		property["title"] = attr.DisplayName
+	}
+	applyJSONSchemaMutability(property, attr.Mutability)
+	return property
+}
+
+// jsonSchemaType renders the JSON Schema type of a scalar value type.
+func jsonSchemaType(valueType string) map[string]interface{} {
+
+	switch valueType {
+	case constants.IntegerDataType, constants.EpochDataType:
+		return map[string]interface{}{"type": "integer"}
+	case constants.DecimalDataType:
+		return map[string]interface{}{"type": "number"}
+	case constants.BooleanDataType:
+		return map[string]interface{}{"type": "boolean"}
+	case constants.DateTimeDataType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case constants.DateDataType:
+		return map[string]interface{}{"type": "string", "format": "date"}
+	case constants.ComplexDataType:
+		return map[string]interface{}{"type": "object"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// applyJSONSchemaConstraints adds the keywords for the constraints JSON Schema can express. Date bounds have no
+// JSON Schema equivalent and are left out.
+func applyJSONSchemaConstraints(schema map[string]interface{}, c model.AttributeConstraints) {
+
+	if c.Minimum != nil {
+		schema["minimum"] = *c.Minimum
+	}
+	if c.Maximum != nil {
+		schema["maximum"] = *c.Maximum
+	}
+	if c.MinLength != nil {
+		schema["minLength"] = *c.MinLength
+	}
+	if c.MaxLength != nil {
+		schema["maxLength"] = *c.MaxLength
+	}
+	if c.Pattern != "" {
+		schema["pattern"] = c.Pattern
+	}
+	if format, ok := jsonSchemaFormats[c.Format]; ok {
+		schema["format"] = format
+	} else if pattern, ok := jsonSchemaFormatPatterns[c.Format]; ok && c.Pattern == "" {
+		schema["pattern"] = pattern
+	}
+}
+
+// applyJSONSchemaMutability marks attributes clients cannot write as readOnly and attributes that are never
+// returned as writeOnly.
+func applyJSONSchemaMutability(schema map[string]interface{}, mutability string) {
+
+	switch mutability {
+	case constants.MutabilityReadOnly, constants.MutabilityComputed:
+		schema["readOnly"] = true
+	case constants.MutabilityWriteOnly:
+		schema["writeOnly"] = true
+	}
+}
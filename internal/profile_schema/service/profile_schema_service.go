@@ -19,6 +19,7 @@
 package service
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -37,16 +38,29 @@ import (
 
 type ProfileSchemaServiceInterface interface {
 	GetProfileSchema(orgId string) (map[string]interface{}, error)
+	GetProfileSchemaAsJSONSchema(orgId string) (map[string]interface{}, error)
 	DeleteProfileSchema(orgId string) error
 	AddProfileSchemaAttributesForScope(attrs []model.ProfileSchemaAttribute, scope, orgId string) ([]model.ProfileSchemaAttribute, error)
+	AddProfileSchemaAttributesForScopeTx(tx *sql.Tx, attrs []model.ProfileSchemaAttribute, scope, orgId string) ([]model.ProfileSchemaAttribute, error)
 	GetProfileSchemaAttributesByScope(orgId, scope string) (interface{}, error)
+	GetProfileSchemaAttributesForOrg(orgId string) ([]model.ProfileSchemaAttribute, error)
 	GetProfileSchemaAttributesByScopeAndFilter(id, scope string, filters []string) (interface{}, error)
 	DeleteProfileSchemaAttributesByScope(orgId, scope string) error
 	GetProfileSchemaAttributeById(orgId, attributeId string) (model.ProfileSchemaAttribute, error)
 	GetProfileSchemaAttributeByName(attributeName, orgId string) (*model.ProfileSchemaAttribute, error)
+	GetProfileSchemaAttributeByNameTx(tx *sql.Tx, attributeName, orgId string) (*model.ProfileSchemaAttribute, error)
 	UpdateProfileSchemaAttributeById(orgId, attributeId string, updates map[string]interface{}, scope string) error
+	UpdateProfileSchemaAttributeByIdTx(tx *sql.Tx, orgId, attributeId string, updates map[string]interface{}, scope string) (func(), error)
 	DeleteProfileSchemaAttributeById(orgId, attributeId string) error
 	SyncProfileSchema(orgId string) error
+	ApplySchemaSyncEvent(schemaSync model.ProfileSchemaSync) error
+	GetSchemaDriftReport(orgId string) (*model.SchemaDriftReport, error)
+	ReconcileSchemaDrift(orgId string) (*model.SchemaDriftReport, error)
+	PreviewSchemaMigration(orgId, attributeId string, updates map[string]interface{}, scope string) (*model.SchemaMigrationPreview, error)
+	GetSchemaMigration(orgId, migrationId string) (*model.SchemaMigration, error)
+	GetSchemaMigrationsForAttribute(orgId, attributeId string) ([]model.SchemaMigration, error)
+	DeleteProfileSchemaAttributeAndPurgeData(orgId, attributeId string) (*model.SchemaMigration, error)
+	GetAttributesBeingPurged(orgId string) ([]model.SchemaMigration, error)
 }
 
 // ProfileSchemaService is the default implementation of the ProfileSchemaServiceInterface.
@@ -61,9 +75,35 @@ func GetProfileSchemaService() ProfileSchemaServiceInterface {
 // AddProfileSchemaAttributesForScope adds profile schema attributes to the specific scope.
 func (s *ProfileSchemaService) AddProfileSchemaAttributesForScope(schemaAttributes []model.ProfileSchemaAttribute, scope, orgId string) ([]model.ProfileSchemaAttribute, error) {
 
+	var added []model.ProfileSchemaAttribute
+	err := psstr.WithTransaction(func(tx *sql.Tx) error {
+		var err error
+		added, err = s.AddProfileSchemaAttributesForScopeTx(tx, schemaAttributes, scope, orgId)
+		return err
+	})
+	return added, err
+}
+
+// AddProfileSchemaAttributesForScopeTx adds profile schema attributes to the specific scope within the given
+// transaction. The attributes are checked against the schema as seen within the transaction.
+func (s *ProfileSchemaService) AddProfileSchemaAttributesForScopeTx(tx *sql.Tx, schemaAttributes []model.ProfileSchemaAttribute, scope, orgId string) ([]model.ProfileSchemaAttribute, error) {
+
+	// Sub-attributes defined inline are added as attributes of their own, alongside their parent.
+	schemaAttributes = expandSubAttributeDefinitions(schemaAttributes)
+	pending := make(map[string]model.ProfileSchemaAttribute, len(schemaAttributes))
+	for _, attr := range schemaAttributes {
+		pending[attr.AttributeId] = attr
+	}
+
+	// The data of a deleted attribute must be gone before the name can be used again.
+	purges, err := psstr.GetUnfinishedSchemaPurges(orgId)
+	if err != nil {
+		return nil, err
+	}
+
 	validAttrs := make([]model.ProfileSchemaAttribute, 0, len(schemaAttributes))
 	for _, attr := range schemaAttributes {
-		if err, isValid := s.validateSchemaAttribute(attr); isValid {
+		if err, isValid := s.validateSchemaAttribute(tx, attr, pending); isValid {
 			// Ensure the scope is valid
 			parts := strings.SplitN(attr.AttributeName, ".", 2)
 			scopeOfAttr := parts[0]
@@ -77,7 +117,7 @@ func (s *ProfileSchemaService) AddProfileSchemaAttributesForScope(schemaAttribut
 				return nil, clientError
 			}
 
-			existing, err := psstr.GetProfileSchemaAttributeByName(attr.OrgId, attr.AttributeName)
+			existing, err := psstr.GetProfileSchemaAttributeByNameTx(tx, attr.OrgId, attr.AttributeName)
 			if err != nil {
 				return nil, err
 			}
@@ -105,6 +145,14 @@ func (s *ProfileSchemaService) AddProfileSchemaAttributesForScope(schemaAttribut
 					return nil, clientError
 				}
 			}
+			if purge := purgeOfAttribute(purges, attr); purge != nil {
+				clientError := errors2.NewClientError(errors2.ErrorMessage{
+					Code:        errors2.ATTRIBUTE_DATA_BEING_PURGED.Code,
+					Message:     errors2.ATTRIBUTE_DATA_BEING_PURGED.Message,
+					Description: fmt.Sprintf("The data of a deleted attribute named '%s' is still being purged by migration '%s'. Try again once it completes.", attr.AttributeName, purge.MigrationId),
+				}, http.StatusConflict)
+				return nil, clientError
+			}
 
 			if attr.DisplayName == "" {
 				log.GetLogger().Debug(fmt.Sprintf("Display name not provided for attribute: %s. "+
@@ -118,13 +166,19 @@ func (s *ProfileSchemaService) AddProfileSchemaAttributesForScope(schemaAttribut
 		}
 	}
 
-	return validAttrs, psstr.AddProfileSchemaAttributesForScope(validAttrs, scope, orgId)
+	if err := validateComputedDependencies(tx, orgId, validAttrs); err != nil {
+		return nil, err
+	}
+
+	return validAttrs, psstr.AddProfileSchemaAttributesForScopeTx(tx, validAttrs, scope, orgId)
 }
 
-func (s *ProfileSchemaService) validateSchemaAttribute(attr model.ProfileSchemaAttribute) (error, bool) {
+// validateSchemaAttribute validates a single attribute definition. pending holds the attributes being added in the
+// same request, by Id, so that sub-attributes may refer to them before they are persisted.
+func (s *ProfileSchemaService) validateSchemaAttribute(tx *sql.Tx, attr model.ProfileSchemaAttribute,
+	pending map[string]model.ProfileSchemaAttribute) (error, bool) {
 
 	parts := strings.Split(attr.AttributeName, ".")
-	logger := log.GetLogger()
 	if len(parts) < 2 {
 		clientError := errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.INVALID_ATTRIBUTE.Code,
@@ -134,16 +188,6 @@ func (s *ProfileSchemaService) validateSchemaAttribute(attr model.ProfileSchemaA
 		return clientError, false
 	}
 
-	if len(parts) > 5 {
-		logger.Warn("Attribute exceeds supported nesting. Maximum allowed depth is 4.")
-		clientError := errors2.NewClientError(errors2.ErrorMessage{
-			Code:        errors2.INVALID_ATTRIBUTE.Code,
-			Message:     errors2.INVALID_ATTRIBUTE.Message,
-			Description: "Attribute exceeds the maximum depth of 4.",
-		}, http.StatusBadRequest)
-		return clientError, false
-	}
-
 	scope := parts[0]
 	if !constants.AllowedAttributesScope[scope] {
 		clientError := errors2.NewClientError(errors2.ErrorMessage{
@@ -230,7 +274,11 @@ func (s *ProfileSchemaService) validateSchemaAttribute(attr model.ProfileSchemaA
 				return clientError, false
 			}
 
-			subAttribute, err := s.GetProfileSchemaAttributeById(attr.OrgId, subAttr.AttributeId)
+			var err error
+			subAttribute, found := pending[subAttr.AttributeId]
+			if !found {
+				subAttribute, err = psstr.GetProfileSchemaAttributeByIdTx(tx, attr.OrgId, subAttr.AttributeId)
+			}
 			if err != nil {
 				clientError := errors2.NewClientError(errors2.ErrorMessage{
 					Code:        errors2.INVALID_ATTRIBUTE.Code,
@@ -267,6 +315,14 @@ func (s *ProfileSchemaService) validateSchemaAttribute(attr model.ProfileSchemaA
 		return clientError, false
 	}
 
+	if err := validateComputedAttribute(attr); err != nil {
+		return err, false
+	}
+
+	if err := validateAttributeConstraints(attr); err != nil {
+		return err, false
+	}
+
 	if !constants.AllowedMergeStrategies[attr.MergeStrategy] {
 		clientError := errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.INVALID_ATTRIBUTE.Code,
@@ -320,11 +376,36 @@ func defaultValidateApplicationIdentifier(appIdentifier, orgHandle string) (erro
 }
 
 func (s *ProfileSchemaService) GetProfileSchemaAttributeById(orgId, attributeId string) (model.ProfileSchemaAttribute, error) {
-	return psstr.GetProfileSchemaAttributeById(orgId, attributeId)
+	attribute, err := psstr.GetProfileSchemaAttributeById(orgId, attributeId)
+	if err != nil {
+		return attribute, err
+	}
+	return resolveAttributeSubAttributes(orgId, attribute)
 }
 
 func (s *ProfileSchemaService) GetProfileSchemaAttributeByName(attributeName, orgId string) (*model.ProfileSchemaAttribute, error) {
-	return psstr.GetProfileSchemaAttributeByName(orgId, attributeName)
+	attribute, err := psstr.GetProfileSchemaAttributeByName(orgId, attributeName)
+	if err != nil || attribute == nil {
+		return attribute, err
+	}
+	resolved, err := resolveAttributeSubAttributes(orgId, *attribute)
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// GetProfileSchemaAttributeByNameTx retrieves an attribute by name as seen within the given transaction.
+func (s *ProfileSchemaService) GetProfileSchemaAttributeByNameTx(tx *sql.Tx, attributeName, orgId string) (*model.ProfileSchemaAttribute, error) {
+	attribute, err := psstr.GetProfileSchemaAttributeByNameTx(tx, orgId, attributeName)
+	if err != nil || attribute == nil {
+		return attribute, err
+	}
+	resolved, err := resolveAttributeSubAttributesTx(tx, orgId, *attribute)
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
 }
 
 // GetProfileSchemaAttributesByScope retrieves profile schema attributes for a specific scope.
@@ -334,6 +415,7 @@ func (s *ProfileSchemaService) GetProfileSchemaAttributesByScope(orgId, scope st
 	if err != nil {
 		return nil, err
 	}
+	schemaAttributes = model.ResolveSubAttributes(schemaAttributes)
 
 	// todo: ensure to have the addition also in same format
 	if scope == constants.ApplicationData {
@@ -352,21 +434,70 @@ func (s *ProfileSchemaService) GetProfileSchemaAttributesByScope(orgId, scope st
 	return schemaAttributes, nil
 }
 
+// GetProfileSchemaAttributesForOrg retrieves all profile schema attributes of the organization, with their
+// sub-attributes resolved.
+func (s *ProfileSchemaService) GetProfileSchemaAttributesForOrg(orgId string) ([]model.ProfileSchemaAttribute, error) {
+
+	schemaAttributes, err := psstr.GetProfileSchemaAttributesForOrg(orgId)
+	if err != nil {
+		return nil, err
+	}
+	return model.ResolveSubAttributes(schemaAttributes), nil
+}
+
 func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attributeId string, updates map[string]interface{}, scope string) error {
 
+	var startMigration func()
+	err := psstr.WithTransaction(func(tx *sql.Tx) error {
+		var err error
+		startMigration, err = s.UpdateProfileSchemaAttributeByIdTx(tx, orgId, attributeId, updates, scope)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	startMigration()
+	return nil
+}
+
+// UpdateProfileSchemaAttributeByIdTx updates an attribute within the given transaction. When the change affects the
+// shape of stored values, a migration is recorded; the returned function starts it and must be called once the
+// transaction is committed.
+func (s *ProfileSchemaService) UpdateProfileSchemaAttributeByIdTx(tx *sql.Tx, orgId, attributeId string,
+	updates map[string]interface{}, scope string) (func(), error) {
+
+	attribute, updatedAttribute, err := s.prepareAttributeUpdate(tx, orgId, attributeId, updates, scope)
+	if err != nil {
+		return nil, err
+	}
+	if err := psstr.PatchProfileSchemaAttributeByIdTx(tx, orgId, attributeId, updates); err != nil {
+		return nil, err
+	}
+	// Stored values are converted in the background when the change affects their shape.
+	return recordSchemaMigration(tx, attribute, updatedAttribute)
+}
+
+// prepareAttributeUpdate validates the updates of an attribute and returns the attribute as it is and as it would
+// be after the updates, as seen within the given transaction. Sub-attribute updates are rewritten as references.
+func (s *ProfileSchemaService) prepareAttributeUpdate(tx *sql.Tx, orgId, attributeId string,
+	updates map[string]interface{}, scope string) (model.ProfileSchemaAttribute, model.ProfileSchemaAttribute, error) {
+
 	if len(updates) == 0 {
-		return errors2.NewClientError(errors2.ErrorMessage{
+		return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.INVALID_ATTRIBUTE.Code,
 			Message:     errors2.INVALID_ATTRIBUTE.Message,
 			Description: "No updates provided for the profile schema attribute",
 		}, http.StatusBadRequest)
 	}
-	attribute, err := s.GetProfileSchemaAttributeById(orgId, attributeId)
+	attribute, err := psstr.GetProfileSchemaAttributeByIdTx(tx, orgId, attributeId)
+	if err == nil {
+		attribute, err = resolveAttributeSubAttributesTx(tx, orgId, attribute)
+	}
 	if err != nil {
-		return err
+		return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, err
 	}
 	if attribute.AttributeId == "" {
-		return errors2.NewClientError(errors2.ErrorMessage{
+		return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.INVALID_ATTRIBUTE.Code,
 			Message:     errors2.INVALID_ATTRIBUTE.Message,
 			Description: fmt.Sprintf("Attribute with Id '%s' does not exist", attributeId),
@@ -376,14 +507,20 @@ func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attribute
 	// attribute id cannot be there and also org id. attribute name only can be updated not the scope.
 	var canonicalValues []model.CanonicalValue
 	if cv, ok := updates["canonical_values"]; ok && cv != nil {
-		if cvSlice, ok := cv.([]model.CanonicalValue); ok {
+		switch cvSlice := cv.(type) {
+		case []model.CanonicalValue:
 			canonicalValues = cvSlice
+		case []interface{}:
+			// Decoded request bodies carry canonical values as plain JSON objects.
+			if cvBytes, err := json.Marshal(cvSlice); err == nil {
+				_ = json.Unmarshal(cvBytes, &canonicalValues)
+			}
 		}
 	} else {
 		canonicalValues = attribute.CanonicalValues // Keep existing canonical values if not updated
 	}
 
-	var subAttributes []model.SubAttribute
+	var subAttributes []model.ProfileSchemaAttribute
 	if saRaw, ok := updates["sub_attributes"]; ok && saRaw != nil {
 		saSlice, ok := saRaw.([]interface{})
 		if ok {
@@ -393,18 +530,20 @@ func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attribute
 					continue
 				}
 
-				// Convert map to JSON, then to SubAttribute
+				// Convert map to JSON, then to the sub-attribute definition
 				itemBytes, err := json.Marshal(itemMap)
 				if err != nil {
 					continue
 				}
 
-				var subAttr model.SubAttribute
+				var subAttr model.ProfileSchemaAttribute
 				if err := json.Unmarshal(itemBytes, &subAttr); err == nil {
 					subAttributes = append(subAttributes, subAttr)
 				}
 			}
 		}
+		// Sub-attributes are defined on their own; the parent only keeps references to them.
+		updates["sub_attributes"] = subAttributeReferenceUpdate(subAttributes)
 	} else {
 		subAttributes = attribute.SubAttributes // fallback to existing
 	}
@@ -428,7 +567,7 @@ func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attribute
 		if mvBool, ok := mv.(bool); ok {
 			multiValued = mvBool
 		} else {
-			return errors2.NewClientError(errors2.ErrorMessage{
+			return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
 				Code:        errors2.INVALID_ATTRIBUTE.Code,
 				Message:     "Invalid value for multi_valued",
 				Description: "multi_valued must be a boolean",
@@ -438,11 +577,42 @@ func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attribute
 		log.GetLogger().Debug(fmt.Sprintf("multi_valued not provided in patch; defaulting to false for attribute: %s", attributeId))
 	}
 
+	expressionSource := attribute.Expression // Keep the existing expression if not updated
+	if expr, ok := updates["expression"]; ok && expr != nil {
+		exprStr, ok := expr.(string)
+		if !ok {
+			return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.INVALID_ATTRIBUTE.Code,
+				Message:     errors2.INVALID_ATTRIBUTE.Message,
+				Description: "expression must be a string",
+			}, http.StatusBadRequest)
+		}
+		expressionSource = exprStr
+	}
+
+	constraints := attribute.Constraints // Keep the existing constraints if not updated
+	if raw, ok := updates["constraints"]; ok && raw != nil {
+		rawMap, ok := raw.(map[string]interface{})
+		var parsed model.AttributeConstraints
+		if ok {
+			rawBytes, err := json.Marshal(rawMap)
+			ok = err == nil && json.Unmarshal(rawBytes, &parsed) == nil
+		}
+		if !ok {
+			return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
+				Code:        errors2.INVALID_ATTRIBUTE.Code,
+				Message:     errors2.INVALID_ATTRIBUTE.Message,
+				Description: "constraints must be an object of constraint settings",
+			}, http.StatusBadRequest)
+		}
+		constraints = &parsed
+	}
+
 	requiredFields := []string{"attribute_name", "value_type", "merge_strategy", "mutability"}
 	for _, field := range requiredFields {
 		v, ok := updates[field].(string)
 		if !ok || v == "" {
-			return errors2.NewClientError(errors2.ErrorMessage{
+			return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
 				Code:        errors2.INVALID_ATTRIBUTE.Code,
 				Message:     errors2.INVALID_ATTRIBUTE.Message,
 				Description: fmt.Sprintf("Required field '%s' is missing or empty", field),
@@ -450,7 +620,7 @@ func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attribute
 		}
 	}
 
-	err, isValid := s.validateSchemaAttribute(model.ProfileSchemaAttribute{
+	updatedAttribute := model.ProfileSchemaAttribute{
 		OrgId:                 orgId,
 		AttributeId:           attributeId,
 		AttributeName:         updates["attribute_name"].(string),
@@ -461,19 +631,25 @@ func (s *ProfileSchemaService) UpdateProfileSchemaAttributeById(orgId, attribute
 		CanonicalValues:       canonicalValues,
 		SubAttributes:         subAttributes,
 		ApplicationIdentifier: applicationIdentifier,
-	})
+		Expression:            expressionSource,
+		Constraints:           constraints,
+	}
+	err, isValid := s.validateSchemaAttribute(tx, updatedAttribute, nil)
 	if !isValid {
 		if err != nil {
-			return err
+			return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, err
 		}
 		// If validation fails, return a bad request error
-		return errors2.NewClientError(errors2.ErrorMessage{
+		return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.INVALID_ATTRIBUTE.Code,
 			Message:     errors2.INVALID_ATTRIBUTE.Message,
 			Description: "Invalid updates provided for the profile schema attribute",
 		}, http.StatusBadRequest)
 	}
-	return psstr.PatchProfileSchemaAttributeById(orgId, attributeId, updates)
+	if err := validateComputedDependencies(tx, orgId, []model.ProfileSchemaAttribute{updatedAttribute}); err != nil {
+		return model.ProfileSchemaAttribute{}, model.ProfileSchemaAttribute{}, err
+	}
+	return attribute, updatedAttribute, nil
 }
 
 // DeleteProfileSchemaAttributeById deletes a profile schema attribute by its Id.
@@ -524,6 +700,19 @@ func (s *ProfileSchemaService) DeleteProfileSchemaAttributeById(orgId, attribute
 		}
 	}
 
+	// Block the deletion while a computed attribute still reads this attribute.
+	orgAttributes, err := psstr.GetProfileSchemaAttributesForOrg(orgId)
+	if err != nil {
+		return err
+	}
+	if dependents := computedAttributesDependingOn(attribute, orgAttributes); len(dependents) > 0 {
+		return errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.DELETE_PROFILE_SCHEMA.Code,
+			Message:     errors2.DELETE_PROFILE_SCHEMA.Message,
+			Description: fmt.Sprintf("Cannot delete attribute '%s' as computed attribute(s) %s depend on it. Update them first.", attribute.AttributeName, strings.Join(dependents, ", ")),
+		}, http.StatusBadRequest)
+	}
+
 	return psstr.DeleteProfileSchemaAttributeById(orgId, attributeId)
 }
 
@@ -567,6 +756,7 @@ func (s *ProfileSchemaService) GetProfileSchema(orgId string) (map[string]interf
 			Description: errMsg,
 		}, err)
 	}
+	schemaAttributes = model.ResolveSubAttributes(schemaAttributes)
 
 	// Step 3: Group them by scope
 	identityAttrs := make([]model.ProfileSchemaAttribute, 0)
@@ -623,6 +813,7 @@ func GetProfileSchemaAttributesWithFilter(orgId string, filters []string) ([]mod
 	if err != nil {
 		return nil, err
 	}
+	allAttrs = model.ResolveSubAttributes(allAttrs)
 
 	filtered := make([]model.ProfileSchemaAttribute, 0)
 
@@ -757,6 +948,11 @@ func (s *ProfileSchemaService) GetProfileSchemaAttributesByScopeAndFilter(orgId,
 	if err != nil {
 		return nil, err
 	}
+	for i, attr := range schemaAttributes {
+		if schemaAttributes[i], err = resolveAttributeSubAttributes(orgId, attr); err != nil {
+			return nil, err
+		}
+	}
 
 	if scope == constants.ApplicationData {
 		grouped := make(map[string][]model.ProfileSchemaAttribute)
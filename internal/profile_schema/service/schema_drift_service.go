@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/client"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+var fetchIdentitySchemaFn = defaultFetchIdentitySchema
+
+// defaultFetchIdentitySchema builds the identity attributes of an organization from the claims of the identity
+// server, the same way the full schema sync does.
+func defaultFetchIdentitySchema(orgHandle string) ([]model.ProfileSchemaAttribute, error) {
+	identityClient := client.NewIdentityClient(config.GetCDSRuntime().Config)
+	return identityClient.GetProfileSchema(orgHandle)
+}
+
+// GetSchemaDriftReport compares the identity attributes of the schema with the claims of the identity server.
+func (s *ProfileSchemaService) GetSchemaDriftReport(orgId string) (*model.SchemaDriftReport, error) {
+
+	stored, expected, err := identitySchemaStates(orgId)
+	if err != nil {
+		return nil, err
+	}
+	return schemaDriftReport(orgId, stored, expected), nil
+}
+
+// ReconcileSchemaDrift brings the identity attributes of the schema in line with the claims of the identity server.
+// Missing attributes are added, mismatched ones are updated in place and extra ones are removed. The returned report
+// lists the drift that was reconciled.
+func (s *ProfileSchemaService) ReconcileSchemaDrift(orgId string) (*model.SchemaDriftReport, error) {
+
+	stored, expected, err := identitySchemaStates(orgId)
+	if err != nil {
+		return nil, err
+	}
+	report := schemaDriftReport(orgId, stored, expected)
+	if report.InSync {
+		return report, nil
+	}
+
+	// Attributes keep their ids, so sub-attribute references are pointed at the stored attributes by name.
+	ids := make(map[string]string, len(stored)+len(expected))
+	for _, attr := range expected {
+		ids[attr.AttributeName] = attr.AttributeId
+	}
+	storedByName := make(map[string]model.ProfileSchemaAttribute, len(stored))
+	for _, attr := range stored {
+		ids[attr.AttributeName] = attr.AttributeId
+		storedByName[attr.AttributeName] = attr
+	}
+	expectedByName := make(map[string]model.ProfileSchemaAttribute, len(expected))
+	for _, attr := range expected {
+		if _, seen := expectedByName[attr.AttributeName]; !seen {
+			expectedByName[attr.AttributeName] = attr
+		}
+	}
+
+	changed := make([]string, 0, len(report.Missing)+len(report.Mismatched))
+	for _, missing := range report.Missing {
+		changed = append(changed, missing.AttributeName)
+	}
+	for _, mismatch := range report.Mismatched {
+		changed = append(changed, mismatch.AttributeName)
+	}
+	for _, name := range changed {
+		attr := expectedByName[name]
+		attr.AttributeId = ids[name]
+		if existing, ok := storedByName[name]; ok {
+			attr.MergeStrategy = existing.MergeStrategy
+		}
+		subAttributes := make([]model.SubAttribute, 0, len(attr.SubAttributes))
+		for _, subAttr := range attr.SubAttributes {
+			if id, ok := ids[subAttr.AttributeName]; ok {
+				subAttributes = append(subAttributes, model.SubAttribute{
+					AttributeId:   id,
+					AttributeName: subAttr.AttributeName,
+				})
+			}
+		}
+		attr.SubAttributes = subAttributes
+		if err := psstr.UpsertIdentityAttribute(orgId, attr); err != nil {
+			return nil, err
+		}
+	}
+	for _, extra := range report.Extra {
+		if err := psstr.DeleteProfileSchemaAttributeById(orgId, extra.AttributeId); err != nil {
+			return nil, err
+		}
+	}
+
+	log.GetLogger().Info(fmt.Sprintf("Reconciled schema drift for organization: %s. Added: %d, updated: %d, "+
+		"removed: %d", orgId, len(report.Missing), len(report.Mismatched), len(report.Extra)))
+	report.Reconciled = true
+	return report, nil
+}
+
+// identitySchemaStates returns the stored identity attributes and the ones the identity server claims describe.
+func identitySchemaStates(orgId string) ([]model.ProfileSchemaAttribute, []model.ProfileSchemaAttribute, error) {
+
+	expected, err := fetchIdentitySchemaFn(orgId)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to fetch the claims of the identity server for organization: %s", orgId)
+		log.GetLogger().Debug(errMsg, log.Error(err))
+		return nil, nil, errors2.NewServerError(errors2.ErrorMessage{
+			Code:        errors2.DETECT_SCHEMA_DRIFT.Code,
+			Message:     errors2.DETECT_SCHEMA_DRIFT.Message,
+			Description: errMsg,
+		}, err)
+	}
+	stored, err := psstr.GetProfileSchemaAttributesByScope(orgId, constants.IdentityAttributes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stored, expected, nil
+}
+
+// schemaDriftReport compares stored identity attributes with the expected ones by name.
+func schemaDriftReport(orgId string, stored, expected []model.ProfileSchemaAttribute) *model.SchemaDriftReport {
+
+	report := &model.SchemaDriftReport{
+		OrgHandle:  orgId,
+		Missing:    []model.SchemaDriftAttribute{},
+		Extra:      []model.SchemaDriftAttribute{},
+		Mismatched: []model.SchemaDriftMismatch{},
+		CheckedAt:  time.Now().UTC(),
+	}
+
+	storedByName := make(map[string]model.ProfileSchemaAttribute, len(stored))
+	for _, attr := range stored {
+		storedByName[attr.AttributeName] = attr
+	}
+	expectedNames := make(map[string]bool, len(expected))
+	for _, attr := range expected {
+		// A local claim mapped from several dialects is synced once.
+		if expectedNames[attr.AttributeName] {
+			continue
+		}
+		expectedNames[attr.AttributeName] = true
+
+		existing, ok := storedByName[attr.AttributeName]
+		if !ok {
+			missing := model.DriftAttributeOf(attr)
+			missing.AttributeId = ""
+			report.Missing = append(report.Missing, missing)
+			continue
+		}
+		if differences := attributeDifferences(existing, attr); len(differences) > 0 {
+			report.Mismatched = append(report.Mismatched, model.SchemaDriftMismatch{
+				AttributeId:   existing.AttributeId,
+				AttributeName: existing.AttributeName,
+				Differences:   differences,
+			})
+		}
+	}
+	for _, attr := range stored {
+		if !expectedNames[attr.AttributeName] {
+			report.Extra = append(report.Extra, model.DriftAttributeOf(attr))
+		}
+	}
+
+	sort.Slice(report.Missing, func(i, j int) bool {
+		return report.Missing[i].AttributeName < report.Missing[j].AttributeName
+	})
+	sort.Slice(report.Extra, func(i, j int) bool {
+		return report.Extra[i].AttributeName < report.Extra[j].AttributeName
+	})
+	sort.Slice(report.Mismatched, func(i, j int) bool {
+		return report.Mismatched[i].AttributeName < report.Mismatched[j].AttributeName
+	})
+	report.InSync = len(report.Missing) == 0 && len(report.Extra) == 0 && len(report.Mismatched) == 0
+	return report
+}
+
+// attributeDifferences returns the fields of a stored attribute that differ from the expected one.
+func attributeDifferences(stored, expected model.ProfileSchemaAttribute) []model.SchemaDriftDifference {
+
+	var differences []model.SchemaDriftDifference
+	if stored.ValueType != expected.ValueType {
+		differences = append(differences, model.SchemaDriftDifference{
+			Field: "value_type", Stored: stored.ValueType, Expected: expected.ValueType,
+		})
+	}
+	if stored.MultiValued != expected.MultiValued {
+		differences = append(differences, model.SchemaDriftDifference{
+			Field: "multi_valued", Stored: stored.MultiValued, Expected: expected.MultiValued,
+		})
+	}
+	if stored.Mutability != expected.Mutability {
+		differences = append(differences, model.SchemaDriftDifference{
+			Field: "mutability", Stored: stored.Mutability, Expected: expected.Mutability,
+		})
+	}
+	storedSubs, expectedSubs := subAttributeNames(stored), subAttributeNames(expected)
+	if !reflect.DeepEqual(storedSubs, expectedSubs) {
+		differences = append(differences, model.SchemaDriftDifference{
+			Field: "sub_attributes", Stored: storedSubs, Expected: expectedSubs,
+		})
+	}
+	return differences
+}
+
+// subAttributeNames returns the sorted sub-attribute names of an attribute.
+func subAttributeNames(attr model.ProfileSchemaAttribute) []string {
+	names := make([]string, 0, len(attr.SubAttributes))
+	for _, subAttr := range attr.SubAttributes {
+		names = append(names, subAttr.AttributeName)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// migrationRequired reports whether values stored under the from definition may be invalid under the to
+// definition. Adding canonical values keeps existing values valid; removing one does not.
+func migrationRequired(from, to model.SchemaMigrationDefinition) bool {
+
+	if from.ValueType != to.ValueType || from.MultiValued != to.MultiValued {
+		return true
+	}
+	if len(to.CanonicalValues) == 0 {
+		return false
+	}
+	if len(from.CanonicalValues) == 0 {
+		return true
+	}
+	allowed := canonicalValueSet(to.CanonicalValues)
+	for _, canonical := range from.CanonicalValues {
+		if !allowed[canonical.Value] {
+			return true
+		}
+	}
+	return false
+}
+
+func canonicalValueSet(values []model.CanonicalValue) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, canonical := range values {
+		set[canonical.Value] = true
+	}
+	return set
+}
+
+// convertAttributeValue converts a value stored under the from definition to the to definition. A nil result
+// with no error means the value should be removed, which is the case for an empty list becoming single-valued.
+func convertAttributeValue(value interface{}, from, to model.SchemaMigrationDefinition) (interface{}, error) {
+
+	if value == nil {
+		return nil, nil
+	}
+	items, isList := value.([]interface{})
+	if to.MultiValued {
+		if !isList {
+			items = []interface{}{value}
+		}
+		converted := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			convertedItem, err := convertSingleValue(item, from, to)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, convertedItem)
+		}
+		return converted, nil
+	}
+
+	if isList {
+		switch len(items) {
+		case 0:
+			return nil, nil
+		case 1:
+			value = items[0]
+		default:
+			return nil, fmt.Errorf("holds %d values but the attribute is no longer multi-valued", len(items))
+		}
+	}
+	return convertSingleValue(value, from, to)
+}
+
+// convertSingleValue converts a single value to the target value type and checks it against the target canonical
+// values.
+func convertSingleValue(value interface{}, from, to model.SchemaMigrationDefinition) (interface{}, error) {
+
+	converted := value
+	if from.ValueType != to.ValueType {
+		var err error
+		if converted, err = convertValueType(value, to.ValueType); err != nil {
+			return nil, err
+		}
+	}
+	if len(to.CanonicalValues) > 0 && !canonicalValueSet(to.CanonicalValues)[fmt.Sprint(converted)] {
+		return nil, fmt.Errorf("'%v' is not one of the canonical values", converted)
+	}
+	return converted, nil
+}
+
+// convertValueType converts a JSON value to the representation profiles use for a value type. Numbers are
+// float64, epochs are strings of seconds and dates are ISO 8601 strings.
+func convertValueType(value interface{}, valueType string) (interface{}, error) {
+
+	unconvertible := fmt.Errorf("cannot convert %s to %s", describeValue(value), valueType)
+	switch valueType {
+	case constants.StringDataType:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+
+	case constants.IntegerDataType:
+		switch v := value.(type) {
+		case float64:
+			if v == float64(int64(v)) {
+				return v, nil
+			}
+		case string:
+			if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && n == float64(int64(n)) {
+				return n, nil
+			}
+		}
+
+	case constants.DecimalDataType:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return n, nil
+			}
+		}
+
+	case constants.BooleanDataType:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, nil
+			}
+		}
+
+	case constants.EpochDataType:
+		if t, ok := timeOfValue(value); ok {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+
+	case constants.DateTimeDataType:
+		if t, ok := timeOfValue(value); ok {
+			return t.Format(time.RFC3339), nil
+		}
+
+	case constants.DateDataType:
+		if t, ok := timeOfValue(value); ok {
+			return t.Format(time.DateOnly), nil
+		}
+
+	case constants.ComplexDataType:
+		if v, ok := value.(map[string]interface{}); ok {
+			return v, nil
+		}
+	}
+	return nil, unconvertible
+}
+
+// timeOfValue reads a point in time from an ISO 8601 date or timestamp, or from epoch seconds given as a number
+// or a string.
+func timeOfValue(value interface{}) (time.Time, bool) {
+
+	switch v := value.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			return time.Unix(int64(v), 0).UTC(), true
+		}
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if seconds, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC(), true
+		}
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly} {
+			if t, err := time.Parse(layout, trimmed); err == nil {
+				return t.UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return fmt.Sprintf("string '%v'", value)
+	case float64:
+		return fmt.Sprintf("number %v", value)
+	case bool:
+		return fmt.Sprintf("boolean %v", value)
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// migrationOwner identifies this instance on the migrations it runs.
+var migrationOwner = uuid.New().String()
+
+// Running migrations record a heartbeat every migrationHeartbeatInterval; an unfinished migration whose last record
+// is older than migrationStaleTimeout belongs to an instance that stopped.
+var (
+	migrationHeartbeatInterval = constants.SchemaMigrationHeartbeatInterval * time.Millisecond
+	migrationStaleTimeout      = constants.SchemaMigrationStaleTimeout * time.Millisecond
+)
+
+// recordSchemaMigration records, within the given transaction, a migration of the stored values of an attribute
+// from its previous definition. Unfinished migrations of the same attribute are superseded. The returned function
+// runs the migration in the background and must be called once the transaction is committed.
+func recordSchemaMigration(tx *sql.Tx, before, after model.ProfileSchemaAttribute) (func(), error) {
+
+	from, to := model.MigrationDefinitionOf(before), model.MigrationDefinitionOf(after)
+	if !migrationRequired(from, to) {
+		return func() {}, nil
+	}
+	if err := psstr.SupersedeSchemaMigrationsTx(tx, after.OrgId, after.AttributeId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	migration := model.SchemaMigration{
+		MigrationId:           uuid.New().String(),
+		OrgHandle:             after.OrgId,
+		AttributeId:           after.AttributeId,
+		AttributeName:         after.AttributeName,
+		ApplicationIdentifier: after.ApplicationIdentifier,
+		Operation:             constants.SchemaMigrationConvert,
+		From:                  from,
+		To:                    to,
+		Status:                constants.SchemaMigrationPending,
+		Failures:              []model.SchemaMigrationFailure{},
+		Owner:                 migrationOwner,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if err := psstr.InsertSchemaMigrationTx(tx, migration); err != nil {
+		return nil, err
+	}
+	return func() {
+		log.GetLogger().Info(fmt.Sprintf("Started schema migration %s for attribute '%s' in organization '%s'",
+			migration.MigrationId, migration.AttributeName, migration.OrgHandle))
+		go runSchemaMigration(migration)
+	}, nil
+}
+
+// ResumeSchemaMigrations takes over, in the background, the unfinished migrations of instances that stopped: those
+// without a heartbeat for migrationStaleTimeout. It looks for them at startup and then every heartbeat interval, as
+// the migrations of an instance only become stale a while after it stops. Each migration is claimed by a single
+// instance. Values already converted are left as they are, so a migration can safely run again.
+func ResumeSchemaMigrations() {
+
+	go func() {
+		resumeStaleSchemaMigrations()
+		ticker := time.NewTicker(migrationHeartbeatInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			resumeStaleSchemaMigrations()
+		}
+	}()
+}
+
+// resumeStaleSchemaMigrations claims and runs stale migrations one at a time until none is left.
+func resumeStaleSchemaMigrations() {
+
+	for {
+		migration, err := psstr.ClaimStaleSchemaMigration(migrationOwner,
+			time.Now().UTC().Add(-migrationStaleTimeout))
+		if err != nil {
+			log.GetLogger().Error("Failed to claim a stale schema migration", log.Error(err))
+			return
+		}
+		if migration == nil {
+			return
+		}
+		log.GetLogger().Info(fmt.Sprintf("Resuming schema migration %s for attribute '%s' in organization '%s'",
+			migration.MigrationId, migration.AttributeName, migration.OrgHandle))
+		runSchemaMigration(*migration)
+	}
+}
+
+// runSchemaMigration converts the stored values of the migration's attribute batch by batch, recording progress
+// after each batch. It stops early once the migration is superseded or claimed by another instance.
+func runSchemaMigration(migration model.SchemaMigration) {
+
+	stopHeartbeat := migrationHeartbeat(migration.MigrationId, migration.Owner)
+	defer stopHeartbeat()
+
+	logger := log.GetLogger()
+	location, path := attributeLocation(migration.OrgHandle, migration.AttributeName, migration.ApplicationIdentifier)
+	run := &migrationRun{location: location, path: path, from: migration.From, to: migration.To, write: true,
+		purge: migration.Operation == constants.SchemaMigrationPurge}
+
+	save := func(status string, runErr error) bool {
+		migration.Status = status
+		migration.ProcessedProfiles = run.processed
+		migration.ConvertedValues = run.converted
+		migration.FailedValues = run.failed
+		migration.Failures = run.failures
+		if runErr != nil {
+			migration.Error = runErr.Error()
+		}
+		migration.UpdatedAt = time.Now().UTC()
+		active, err := psstr.UpdateSchemaMigrationProgress(migration)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to record the progress of schema migration %s", migration.MigrationId), log.Error(err))
+			return true
+		}
+		return active
+	}
+
+	total, err := profileStore.CountProfileAttributeValues(location)
+	if err != nil {
+		save(constants.SchemaMigrationFailed, err)
+		return
+	}
+	migration.TotalProfiles = total
+	if !save(constants.SchemaMigrationRunning, nil) {
+		return
+	}
+
+	if err := run.run(func() bool { return save(constants.SchemaMigrationRunning, nil) }); err != nil {
+		logger.Error(fmt.Sprintf("Schema migration %s failed", migration.MigrationId), log.Error(err))
+		save(constants.SchemaMigrationFailed, err)
+		return
+	}
+	if save(constants.SchemaMigrationCompleted, nil) {
+		logger.Info(fmt.Sprintf("Schema migration %s completed: %d value(s) converted, %d failed",
+			migration.MigrationId, run.converted, run.failed))
+	}
+}
+
+// migrationHeartbeat records every migrationHeartbeatInterval that a migration is running, until the returned
+// function is called.
+func migrationHeartbeat(migrationId, owner string) (stop func()) {
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(migrationHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := psstr.TouchSchemaMigration(migrationId, owner); err != nil {
+					log.GetLogger().Warn(fmt.Sprintf("Failed to record the heartbeat of schema migration: %s",
+						migrationId), log.Error(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// PreviewSchemaMigration reports how the stored values of an attribute would be migrated if the updates were
+// applied, including a sample of the values that cannot be converted. Nothing is written.
+func (s *ProfileSchemaService) PreviewSchemaMigration(orgId, attributeId string, updates map[string]interface{},
+	scope string) (*model.SchemaMigrationPreview, error) {
+
+	var attribute, updatedAttribute model.ProfileSchemaAttribute
+	err := psstr.WithTransaction(func(tx *sql.Tx) error {
+		var err error
+		attribute, updatedAttribute, err = s.prepareAttributeUpdate(tx, orgId, attributeId, updates, scope)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	preview := &model.SchemaMigrationPreview{
+		AttributeName:         updatedAttribute.AttributeName,
+		ApplicationIdentifier: updatedAttribute.ApplicationIdentifier,
+		From:                  model.MigrationDefinitionOf(attribute),
+		To:                    model.MigrationDefinitionOf(updatedAttribute),
+		Failures:              []model.SchemaMigrationFailure{},
+	}
+	preview.MigrationRequired = migrationRequired(preview.From, preview.To)
+
+	location, path := attributeLocation(orgId, updatedAttribute.AttributeName, updatedAttribute.ApplicationIdentifier)
+	if !preview.MigrationRequired {
+		preview.TotalProfiles, err = profileStore.CountProfileAttributeValues(location)
+		return preview, err
+	}
+	run := &migrationRun{location: location, path: path, from: preview.From, to: preview.To}
+	if err := run.run(nil); err != nil {
+		return nil, err
+	}
+	preview.TotalProfiles = run.processed
+	preview.ConvertibleValues = run.converted
+	preview.FailedValues = run.failed
+	preview.Failures = run.failures
+	return preview, nil
+}
+
+// GetSchemaMigration fetches a schema migration of the organization.
+func (s *ProfileSchemaService) GetSchemaMigration(orgId, migrationId string) (*model.SchemaMigration, error) {
+
+	migration, err := psstr.GetSchemaMigration(orgId, migrationId)
+	if err != nil {
+		return nil, err
+	}
+	if migration == nil {
+		return nil, errors2.NewClientError(errors2.ErrorMessage{
+			Code:        errors2.SCHEMA_MIGRATION_NOT_FOUND.Code,
+			Message:     errors2.SCHEMA_MIGRATION_NOT_FOUND.Message,
+			Description: fmt.Sprintf("Schema migration '%s' does not exist", migrationId),
+		}, http.StatusNotFound)
+	}
+	return migration, nil
+}
+
+// GetSchemaMigrationsForAttribute fetches the migrations of an attribute, newest first.
+func (s *ProfileSchemaService) GetSchemaMigrationsForAttribute(orgId, attributeId string) ([]model.SchemaMigration, error) {
+	return psstr.GetSchemaMigrationsByAttribute(orgId, attributeId)
+}
+
+// attributeLocation resolves where the values of an attribute are stored, and the path of a sub-attribute inside
+// the value of its top-level attribute.
+func attributeLocation(orgId, attributeName, applicationIdentifier string) (profileModel.AttributeLocation, []string) {
+
+	segments := strings.Split(attributeName, ".")
+	location := profileModel.AttributeLocation{OrgHandle: orgId, Scope: segments[0]}
+	if len(segments) > 1 {
+		location.Key = segments[1]
+	}
+	if location.Scope == constants.ApplicationData {
+		location.ApplicationIdentifier = applicationIdentifier
+	}
+	if len(segments) > 2 {
+		return location, segments[2:]
+	}
+	return location, nil
+}
+
+// migrationRun walks the stored values of an attribute, converting each one, or removing it when purge is set.
+// Converted values are written back only when write is set.
+type migrationRun struct {
+	location  profileModel.AttributeLocation
+	path      []string
+	from, to  model.SchemaMigrationDefinition
+	write     bool
+	purge     bool
+	processed int
+	converted int
+	failed    int
+	failures  []model.SchemaMigrationFailure
+}
+
+// run visits the stored values in batches. afterBatch is called after each full batch and stops the run by
+// returning false.
+func (r *migrationRun) run(afterBatch func() bool) error {
+
+	if r.failures == nil {
+		r.failures = []model.SchemaMigrationFailure{}
+	}
+	after := ""
+	for {
+		values, err := profileStore.GetProfileAttributeValues(r.location, after, constants.SchemaMigrationBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, stored := range values {
+			if err := r.migrateProfile(stored); err != nil {
+				return err
+			}
+			after = stored.ProfileId
+			r.processed++
+		}
+		if len(values) < constants.SchemaMigrationBatchSize {
+			return nil
+		}
+		if afterBatch != nil && !afterBatch() {
+			return nil
+		}
+	}
+}
+
+func (r *migrationRun) migrateProfile(stored profileModel.ProfileAttributeValue) error {
+
+	report := &valueReport{}
+	migrated, changed := r.migrateAt(stored.Value, r.path, report)
+	if changed && r.write {
+		written, err := profileStore.ReplaceProfileAttributeValue(r.location, stored.ProfileId, stored.Value, migrated)
+		if err != nil {
+			return err
+		}
+		if !written {
+			// The profile was written since it was read, which validates it against the new definition already.
+			return nil
+		}
+	}
+	r.converted += report.converted
+	for _, failure := range report.failures {
+		r.failed++
+		if len(r.failures) < constants.SchemaMigrationFailureSampleSize {
+			failure.ProfileId = stored.ProfileId
+			r.failures = append(r.failures, failure)
+		}
+	}
+	return nil
+}
+
+type valueReport struct {
+	converted int
+	failures  []model.SchemaMigrationFailure
+}
+
+// migrateAt converts the values found at path inside value, descending through objects and lists of objects. It
+// returns a converted copy and whether anything changed; value itself is never modified.
+func (r *migrationRun) migrateAt(value interface{}, path []string, report *valueReport) (interface{}, bool) {
+
+	if len(path) == 0 {
+		if r.purge {
+			report.converted++
+			return nil, true
+		}
+		converted, err := convertAttributeValue(value, r.from, r.to)
+		if err != nil {
+			report.failures = append(report.failures, model.SchemaMigrationFailure{Value: value, Reason: err.Error()})
+			return value, false
+		}
+		if reflect.DeepEqual(converted, value) {
+			return value, false
+		}
+		report.converted++
+		return converted, true
+	}
+
+	switch container := value.(type) {
+	case map[string]interface{}:
+		child, ok := container[path[0]]
+		if !ok {
+			return value, false
+		}
+		migrated, changed := r.migrateAt(child, path[1:], report)
+		if !changed {
+			return value, false
+		}
+		copied := make(map[string]interface{}, len(container))
+		for key, item := range container {
+			copied[key] = item
+		}
+		if migrated == nil {
+			delete(copied, path[0])
+		} else {
+			copied[path[0]] = migrated
+		}
+		return copied, true
+	case []interface{}:
+		copied := make([]interface{}, len(container))
+		changed := false
+		for i, item := range container {
+			migrated, itemChanged := r.migrateAt(item, path, report)
+			copied[i] = migrated
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return value, false
+		}
+		return copied, true
+	}
+	return value, false
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// DeleteProfileSchemaAttributeAndPurgeData deletes an attribute and removes its values from every profile of the
+// organization in the background. It returns the purge, or nil if the attribute does not exist.
+func (s *ProfileSchemaService) DeleteProfileSchemaAttributeAndPurgeData(orgId, attributeId string) (*model.SchemaMigration, error) {
+
+	attribute, err := psstr.GetProfileSchemaAttributeById(orgId, attributeId)
+	var clientError *errors2.ClientError
+	if errors.As(err, &clientError) && clientError.Code == errors2.ATTRIBUTE_NOT_FOUND.Code {
+		// A missing attribute is deleted as usual, which treats it as a no-op.
+		return nil, s.DeleteProfileSchemaAttributeById(orgId, attributeId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DeleteProfileSchemaAttributeById(orgId, attributeId); err != nil {
+		return nil, err
+	}
+	if err := psstr.SupersedeSchemaMigrations(orgId, attributeId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	purge := model.SchemaMigration{
+		MigrationId:           uuid.New().String(),
+		OrgHandle:             orgId,
+		AttributeId:           attributeId,
+		AttributeName:         attribute.AttributeName,
+		ApplicationIdentifier: attribute.ApplicationIdentifier,
+		Operation:             constants.SchemaMigrationPurge,
+		From:                  model.MigrationDefinitionOf(attribute),
+		Status:                constants.SchemaMigrationPending,
+		Failures:              []model.SchemaMigrationFailure{},
+		Owner:                 migrationOwner,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if err := psstr.InsertSchemaMigration(purge); err != nil {
+		return nil, err
+	}
+	log.GetLogger().Info(fmt.Sprintf("Started purging the data of attribute '%s' in organization '%s'",
+		purge.AttributeName, orgId))
+	go runSchemaMigration(purge)
+	return &purge, nil
+}
+
+// GetAttributesBeingPurged returns the unfinished purges of the organization. Profile reads hide the values of
+// these attributes until their purge completes.
+func (s *ProfileSchemaService) GetAttributesBeingPurged(orgId string) ([]model.SchemaMigration, error) {
+	return psstr.GetUnfinishedSchemaPurges(orgId)
+}
+
+// purgeOfAttribute returns the purge that still holds the name of the given attribute, if any.
+func purgeOfAttribute(purges []model.SchemaMigration, attr model.ProfileSchemaAttribute) *model.SchemaMigration {
+
+	for i, purge := range purges {
+		if purge.AttributeName != attr.AttributeName {
+			continue
+		}
+		if purge.ApplicationIdentifier == "" || purge.ApplicationIdentifier == attr.ApplicationIdentifier {
+			return &purges[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/client"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+// unsyncedParentAttribute is the complex parent the full sync never derives, as it is configured separately.
+const unsyncedParentAttribute = "identity_attributes.emailaddress"
+
+// ApplySchemaSyncEvent applies a claim change of the identity server to the identity attributes of the schema,
+// touching only the attribute the claim is synced into. The full schema is synced instead when the event carries no
+// claim, or when the change does not fit the stored schema, e.g. an update of an attribute that was never synced.
+func (s *ProfileSchemaService) ApplySchemaSyncEvent(schemaSync model.ProfileSchemaSync) error {
+
+	applied, err := applyClaimChange(schemaSync)
+	if err != nil {
+		return err
+	}
+	if applied {
+		log.GetLogger().Info(fmt.Sprintf("Applied schema sync event: %s for organization: %s",
+			schemaSync.Event, schemaSync.OrgId))
+		return nil
+	}
+	log.GetLogger().Info(fmt.Sprintf("Schema sync event: %s cannot be applied incrementally for organization: %s. "+
+		"Syncing the full profile schema.", schemaSync.Event, schemaSync.OrgId))
+	return s.SyncProfileSchema(schemaSync.OrgId)
+}
+
+// applyClaimChange applies the claim change of the event and reports whether it could be applied on its own.
+func applyClaimChange(schemaSync model.ProfileSchemaSync) (bool, error) {
+
+	claim := schemaSync.Claim
+	if claim == nil || claim.ClaimURI == "" {
+		return false, nil
+	}
+	orgId := schemaSync.OrgId
+	switch schemaSync.Event {
+	case constants.AddScimAttributeEvent, constants.UpdateScimAttributeEvent:
+		if !client.IsSyncedDialect(claim.DialectURI) {
+			return true, nil
+		}
+		if claim.MappedLocalClaimURI == "" {
+			return false, nil
+		}
+		return upsertClaimAttribute(orgId, claim, schemaSync.Event == constants.AddScimAttributeEvent)
+	case constants.DeleteScimAttributeEvent:
+		if !client.IsSyncedDialect(claim.DialectURI) {
+			return true, nil
+		}
+		if claim.MappedLocalClaimURI == "" {
+			return false, nil
+		}
+		return removeClaimAttribute(orgId, claim.MappedLocalClaimURI)
+	case constants.UpdateLocalAttributeEvent:
+		return updateLocalClaimAttribute(orgId, claim)
+	case constants.DeleteLocalClaimEvent:
+		return removeClaimAttribute(orgId, claim.ClaimURI)
+	}
+	return false, nil
+}
+
+// upsertClaimAttribute writes the attribute a SCIM claim maps to. An update of an attribute that does not exist means
+// an earlier change was missed, so it is left to the full sync.
+func upsertClaimAttribute(orgId string, claim *model.SchemaSyncClaim, added bool) (bool, error) {
+
+	attributeName := client.IdentityAttributeName(claim.MappedLocalClaimURI)
+	existing, err := psstr.GetProfileSchemaAttributeByName(orgId, attributeName)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil && !added {
+		return false, nil
+	}
+
+	// A new sub-attribute is linked to its parent. Deriving a missing parent needs every claim of the dialect.
+	var parent *model.ProfileSchemaAttribute
+	if parentName, isSub := parentAttributeName(attributeName); isSub && existing == nil {
+		parent, err = psstr.GetProfileSchemaAttributeByName(orgId, parentName)
+		if err != nil {
+			return false, err
+		}
+		if parent == nil && parentName != unsyncedParentAttribute {
+			return false, nil
+		}
+	}
+
+	attr := claimAttribute(orgId, claim, attributeName, claim.DialectURI, existing)
+	if err := psstr.UpsertIdentityAttribute(orgId, attr); err != nil {
+		return false, err
+	}
+	if parent != nil {
+		subAttributes := append(parent.SubAttributes, model.SubAttribute{
+			AttributeId:   attr.AttributeId,
+			AttributeName: attr.AttributeName,
+		})
+		if err := psstr.PatchProfileSchemaAttributeById(orgId, parent.AttributeId, map[string]interface{}{
+			"sub_attributes": subAttributeReferenceUpdate(subAttributes),
+		}); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// updateLocalClaimAttribute refreshes the attribute a local claim is synced into. A local claim no SCIM claim maps
+// to has no attribute, so there is nothing to update.
+func updateLocalClaimAttribute(orgId string, claim *model.SchemaSyncClaim) (bool, error) {
+
+	attributeName := client.IdentityAttributeName(claim.ClaimURI)
+	existing, err := psstr.GetProfileSchemaAttributeByName(orgId, attributeName)
+	if err != nil || existing == nil {
+		return err == nil, err
+	}
+	attr := claimAttribute(orgId, claim, attributeName, existing.SCIMDialect, existing)
+	if err := psstr.UpsertIdentityAttribute(orgId, attr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// removeClaimAttribute removes the attribute of a local claim along with its reference in the parent. Whether a
+// complex attribute is still needed for its sub-attributes is left to the full sync.
+func removeClaimAttribute(orgId, localClaimURI string) (bool, error) {
+
+	attributeName := client.IdentityAttributeName(localClaimURI)
+	existing, err := psstr.GetProfileSchemaAttributeByName(orgId, attributeName)
+	if err != nil || existing == nil {
+		return err == nil, err
+	}
+	if len(existing.SubAttributes) > 0 {
+		return false, nil
+	}
+	if err := psstr.DeleteProfileSchemaAttributeById(orgId, existing.AttributeId); err != nil {
+		return false, err
+	}
+
+	parentName, isSub := parentAttributeName(attributeName)
+	if !isSub {
+		return true, nil
+	}
+	parent, err := psstr.GetProfileSchemaAttributeByName(orgId, parentName)
+	if err != nil || parent == nil {
+		return err == nil, err
+	}
+	subAttributes := make([]model.SubAttribute, 0, len(parent.SubAttributes))
+	for _, subAttr := range parent.SubAttributes {
+		if subAttr.AttributeId != existing.AttributeId && subAttr.AttributeName != attributeName {
+			subAttributes = append(subAttributes, subAttr)
+		}
+	}
+	if err := psstr.PatchProfileSchemaAttributeById(orgId, parent.AttributeId, map[string]interface{}{
+		"sub_attributes": subAttributeReferenceUpdate(subAttributes),
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// claimAttribute builds the identity attribute of a claim the way the full sync does, keeping the id, merge
+// strategy and sub-attributes of the existing attribute, if any.
+func claimAttribute(orgId string, claim *model.SchemaSyncClaim, attributeName, dialectURI string,
+	existing *model.ProfileSchemaAttribute) model.ProfileSchemaAttribute {
+
+	attr := model.ProfileSchemaAttribute{
+		OrgId:           orgId,
+		AttributeId:     uuid.New().String(),
+		AttributeName:   attributeName,
+		ValueType:       constants.StringDataType,
+		MergeStrategy:   constants.MergeStrategyOverwrite,
+		Mutability:      constants.MutabilityReadWrite,
+		DisplayName:     claim.DisplayName,
+		MultiValued:     claim.MultiValued,
+		CanonicalValues: claim.CanonicalValues,
+		SCIMDialect:     dialectURI,
+	}
+	if claim.DataType != "" {
+		attr.ValueType = claim.DataType
+	}
+	if claim.ReadOnly {
+		attr.Mutability = constants.MutabilityReadOnly
+	}
+	if attr.DisplayName == "" {
+		attr.DisplayName = utils.ResolveDisplayNameFromAttribute(strings.TrimPrefix(attributeName,
+			constants.IdentityAttributes+"."))
+	}
+	if existing != nil {
+		attr.AttributeId = existing.AttributeId
+		attr.MergeStrategy = existing.MergeStrategy
+		attr.SubAttributes = existing.SubAttributes
+	}
+	if len(attr.SubAttributes) > 0 {
+		attr.ValueType = constants.ComplexDataType
+	}
+	return attr
+}
+
+// parentAttributeName returns the name of the complex attribute a synced sub-attribute belongs to.
+func parentAttributeName(attributeName string) (string, bool) {
+	key := strings.TrimPrefix(attributeName, constants.IdentityAttributes+".")
+	parentKey, _, isSub := strings.Cut(key, ".")
+	return constants.IdentityAttributes + "." + parentKey, isSub
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// expandSubAttributeDefinitions returns the given attributes together with the sub-attributes they define inline,
+// at every depth, so that each of them is validated and persisted as an attribute of its own. Inline definitions
+// inherit the organization and application of their parent.
+func expandSubAttributeDefinitions(attrs []model.ProfileSchemaAttribute) []model.ProfileSchemaAttribute {
+	prepared := make([]model.ProfileSchemaAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		prepared = append(prepared, prepareSubAttributeDefinitions(attr))
+	}
+	return model.FlattenSubAttributes(prepared)
+}
+
+func prepareSubAttributeDefinitions(attr model.ProfileSchemaAttribute) model.ProfileSchemaAttribute {
+	if len(attr.SubAttributes) == 0 {
+		return attr
+	}
+	subAttributes := make([]model.ProfileSchemaAttribute, 0, len(attr.SubAttributes))
+	for _, subAttr := range attr.SubAttributes {
+		if !subAttr.IsReference() {
+			if subAttr.AttributeId == "" {
+				subAttr.AttributeId = uuid.New().String()
+			}
+			if subAttr.Mutability == "" {
+				subAttr.Mutability = constants.MutabilityReadWrite
+			}
+			if subAttr.ApplicationIdentifier == "" {
+				subAttr.ApplicationIdentifier = attr.ApplicationIdentifier
+			}
+			subAttr.OrgId = attr.OrgId
+			subAttr = prepareSubAttributeDefinitions(subAttr)
+		}
+		subAttributes = append(subAttributes, subAttr)
+	}
+	attr.SubAttributes = subAttributes
+	return attr
+}
+
+// resolveAttributeSubAttributes replaces the sub-attribute references of attr with their full definitions.
+func resolveAttributeSubAttributes(orgId string, attr model.ProfileSchemaAttribute) (model.ProfileSchemaAttribute, error) {
+	if len(attr.SubAttributes) == 0 {
+		return attr, nil
+	}
+	scopeAttrs, err := psstr.GetProfileSchemaAttributesByScope(orgId, strings.SplitN(attr.AttributeName, ".", 2)[0])
+	if err != nil {
+		return attr, err
+	}
+	return withSubAttributesOf(attr, scopeAttrs), nil
+}
+
+// resolveAttributeSubAttributesTx replaces the sub-attribute references of attr with their full definitions, as
+// seen within the given transaction.
+func resolveAttributeSubAttributesTx(tx *sql.Tx, orgId string,
+	attr model.ProfileSchemaAttribute) (model.ProfileSchemaAttribute, error) {
+	if len(attr.SubAttributes) == 0 {
+		return attr, nil
+	}
+	scopeAttrs, err := psstr.GetProfileSchemaAttributesByScopeTx(tx, orgId, strings.SplitN(attr.AttributeName, ".", 2)[0])
+	if err != nil {
+		return attr, err
+	}
+	return withSubAttributesOf(attr, scopeAttrs), nil
+}
+
+// withSubAttributesOf resolves the sub-attribute references of attr against the attributes of its scope.
+func withSubAttributesOf(attr model.ProfileSchemaAttribute, scopeAttrs []model.ProfileSchemaAttribute) model.ProfileSchemaAttribute {
+	for _, resolved := range model.ResolveSubAttributes(append(scopeAttrs, attr)) {
+		if resolved.AttributeId == attr.AttributeId {
+			return resolved
+		}
+	}
+	return attr
+}
+
+// subAttributeReferenceUpdate returns the sub-attribute references to persist for a patched attribute.
+func subAttributeReferenceUpdate(subAttributes []model.ProfileSchemaAttribute) []interface{} {
+	references := make([]interface{}, 0, len(subAttributes))
+	for _, reference := range model.SubAttributeReferences(subAttributes) {
+		references = append(references, map[string]interface{}{
+			"attribute_id":   reference.AttributeId,
+			"attribute_name": reference.AttributeName,
+		})
+	}
+	return references
+}
@@ -1,5 +1,7 @@
 package service
 
+import "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+
 func OverrideValidateApplicationIdentifierForTest(
 	fn func(string, string) (error, bool),
 ) (restore func()) {
@@ -11,3 +13,15 @@ func OverrideValidateApplicationIdentifierForTest(
 		validateApplicationIdentifierFn = prev
 	}
 }
+
+func OverrideFetchIdentitySchemaForTest(
+	fn func(string) ([]model.ProfileSchemaAttribute, error),
+) (restore func()) {
+
+	prev := fetchIdentitySchemaFn
+	fetchIdentitySchemaFn = fn
+
+	return func() {
+		fetchIdentitySchemaFn = prev
+	}
+}
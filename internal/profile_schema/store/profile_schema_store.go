@@ -19,6 +19,7 @@
 package store
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -27,12 +28,24 @@ import (
 
 	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
 	"github.com/wso2/identity-customer-data-service/internal/system/errors"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
 )
 
+// queryExecutor runs queries either on a database client of their own or inside an open transaction.
+type queryExecutor interface {
+	ExecuteQuery(query string, args ...interface{}) ([]map[string]interface{}, error)
+}
+
+// WithTransaction runs fn in a single database transaction, so that the schema changes made through it are
+// persisted together or not at all.
+func WithTransaction(fn func(tx *sql.Tx) error) error {
+	return provider.WithTransaction(errors.UPDATE_PROFILE_SCHEMA, fn)
+}
+
 // AddProfileSchemaAttributesForScope adds multiple profile schema attributes.
 func AddProfileSchemaAttributesForScope(attrs []model.ProfileSchemaAttribute, scope, orgId string) error {
 
@@ -49,17 +62,30 @@ func AddProfileSchemaAttributesForScope(attrs []model.ProfileSchemaAttribute, sc
 	}
 	defer dbClient.Close()
 
+	return addProfileSchemaAttributesForScope(dbClient, attrs, scope, orgId)
+}
+
+// AddProfileSchemaAttributesForScopeTx adds multiple profile schema attributes within the given transaction.
+func AddProfileSchemaAttributesForScopeTx(tx *sql.Tx, attrs []model.ProfileSchemaAttribute, scope, orgId string) error {
+
+	return addProfileSchemaAttributesForScope(client.NewTxClient(tx), attrs, scope, orgId)
+}
+
+func addProfileSchemaAttributesForScope(dbClient queryExecutor, attrs []model.ProfileSchemaAttribute, scope, orgId string) error {
+
+	logger := log.GetLogger()
+
 	if len(attrs) == 0 {
 		return nil // nothing to insert
 	}
 
 	baseQuery := scripts.InsertProfileSchemaAttributesForScope[provider.NewDBProvider().GetDBType()]
 	valueStrings := make([]string, 0, len(attrs))
-	valueArgs := make([]interface{}, 0, len(attrs)*12)
+	valueArgs := make([]interface{}, 0, len(attrs)*14)
 
 	for i, attr := range attrs {
-		idx := i * 12
-		subAttrsJSON, err := json.Marshal(attr.SubAttributes)
+		idx := i * 14
+		subAttrsJSON, err := json.Marshal(model.SubAttributeReferences(attr.SubAttributes))
 		if err != nil {
 			errorMsg := fmt.Sprintf("Failed to marshal sub attributes for attribute %s", attr.AttributeId)
 			logger.Debug(errorMsg, log.Error(err))
@@ -79,16 +105,16 @@ func AddProfileSchemaAttributesForScope(attrs []model.ProfileSchemaAttribute, sc
 			}, err)
 		}
 
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d,  $%d, $%d,  $%d, $%d, $%d, $%d) ",
-			idx+1, idx+2, idx+3, idx+4, idx+5, idx+6, idx+7, idx+8, idx+9, idx+10, idx+11, idx+12))
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d,  $%d, $%d,  $%d, $%d, $%d, $%d, $%d, $%d) ",
+			idx+1, idx+2, idx+3, idx+4, idx+5, idx+6, idx+7, idx+8, idx+9, idx+10, idx+11, idx+12, idx+13, idx+14))
 		valueArgs = append(valueArgs, orgId, attr.AttributeId, attr.AttributeName, attr.ValueType,
 			attr.MergeStrategy, attr.ApplicationIdentifier, attr.Mutability, attr.MultiValued, subAttrsJSON,
-			canonicalJSON, scope, attr.DisplayName)
+			canonicalJSON, scope, attr.DisplayName, attr.Expression, marshalConstraints(attr.Constraints))
 
 	}
 
 	query := baseQuery + strings.Join(valueStrings, ", ")
-	_, err = dbClient.ExecuteQuery(query, valueArgs...)
+	_, err := dbClient.ExecuteQuery(query, valueArgs...)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to insert profile schema attributes for org: %s", attrs[0].OrgId)
 		logger.Debug(errorMsg, log.Error(err))
@@ -121,6 +147,19 @@ func GetProfileSchemaAttributeById(orgId, attributeId string) (model.ProfileSche
 	}
 	defer dbClient.Close()
 
+	return getProfileSchemaAttributeById(dbClient, orgId, attributeId)
+}
+
+// GetProfileSchemaAttributeByIdTx retrieves a profile schema attribute by its ID within the given transaction.
+func GetProfileSchemaAttributeByIdTx(tx *sql.Tx, orgId, attributeId string) (model.ProfileSchemaAttribute, error) {
+
+	return getProfileSchemaAttributeById(client.NewTxClient(tx), orgId, attributeId)
+}
+
+func getProfileSchemaAttributeById(dbClient queryExecutor, orgId, attributeId string) (model.ProfileSchemaAttribute, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetProfileSchemaAttributeById[provider.NewDBProvider().GetDBType()]
 
 	results, err := dbClient.ExecuteQuery(query, orgId, attributeId)
@@ -162,6 +201,20 @@ func GetProfileSchemaAttributesByScope(orgId, scope string) ([]model.ProfileSche
 	}
 	defer dbClient.Close()
 
+	return getProfileSchemaAttributesByScope(dbClient, orgId, scope)
+}
+
+// GetProfileSchemaAttributesByScopeTx retrieves the profile schema attributes of a scope within the given
+// transaction.
+func GetProfileSchemaAttributesByScopeTx(tx *sql.Tx, orgId, scope string) ([]model.ProfileSchemaAttribute, error) {
+
+	return getProfileSchemaAttributesByScope(client.NewTxClient(tx), orgId, scope)
+}
+
+func getProfileSchemaAttributesByScope(dbClient queryExecutor, orgId, scope string) ([]model.ProfileSchemaAttribute, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetProfileSchemaAttributeByScope[provider.NewDBProvider().GetDBType()]
 
 	results, err := dbClient.ExecuteQuery(query, orgId, scope)
@@ -206,6 +259,19 @@ func GetProfileSchemaAttributeByName(orgId, attributeName string) (*model.Profil
 	}
 	defer dbClient.Close()
 
+	return getProfileSchemaAttributeByName(dbClient, orgId, attributeName)
+}
+
+// GetProfileSchemaAttributeByNameTx retrieves a profile schema attribute by its name within the given transaction.
+func GetProfileSchemaAttributeByNameTx(tx *sql.Tx, orgId, attributeName string) (*model.ProfileSchemaAttribute, error) {
+
+	return getProfileSchemaAttributeByName(client.NewTxClient(tx), orgId, attributeName)
+}
+
+func getProfileSchemaAttributeByName(dbClient queryExecutor, orgId, attributeName string) (*model.ProfileSchemaAttribute, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetProfileSchemaAttributeByName[provider.NewDBProvider().GetDBType()]
 
 	results, err := dbClient.ExecuteQuery(query, orgId, attributeName)
@@ -227,7 +293,7 @@ func GetProfileSchemaAttributeByName(orgId, attributeName string) (*model.Profil
 	}
 
 	row := results[0]
-	var subAttrs []model.SubAttribute
+	var subAttrs []model.ProfileSchemaAttribute
 	if raw, ok := row["sub_attributes"].(string); ok && raw != "" {
 		if err := json.Unmarshal([]byte(raw), &subAttrs); err != nil {
 			errorMsg := fmt.Sprintf("Failed to unmarshal sub_attributes for attribute '%s' in org '%s'",
@@ -269,6 +335,10 @@ func GetProfileSchemaAttributeByName(orgId, attributeName string) (*model.Profil
 		MultiValued:           row["multi_valued"].(bool),
 		SubAttributes:         subAttrs,
 		CanonicalValues:       canonicalValues,
+		Scope:                 rowString(row, "scope"),
+		SCIMDialect:           rowString(row, "scim_dialect"),
+		Expression:            rowString(row, "expression"),
+		Constraints:           parseConstraints(row["constraints"]),
 	}
 
 	logger.Info(fmt.Sprintf("Successfully fetched profile schema attribute '%s' for organizaton '%s'",
@@ -293,6 +363,20 @@ func GetProfileSchemaAttributesForOrg(orgId string) ([]model.ProfileSchemaAttrib
 	}
 	defer dbClient.Close()
 
+	return getProfileSchemaAttributesForOrg(dbClient, orgId)
+}
+
+// GetProfileSchemaAttributesForOrgTx retrieves all profile schema attributes of an organization within the given
+// transaction.
+func GetProfileSchemaAttributesForOrgTx(tx *sql.Tx, orgId string) ([]model.ProfileSchemaAttribute, error) {
+
+	return getProfileSchemaAttributesForOrg(client.NewTxClient(tx), orgId)
+}
+
+func getProfileSchemaAttributesForOrg(dbClient queryExecutor, orgId string) ([]model.ProfileSchemaAttribute, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetProfileSchemaByOrg[provider.NewDBProvider().GetDBType()]
 
 	results, err := dbClient.ExecuteQuery(query, orgId)
@@ -331,6 +415,19 @@ func PatchProfileSchemaAttributeById(orgId, attributeId string, updates map[stri
 	}
 	defer dbClient.Close()
 
+	return patchProfileSchemaAttributeById(dbClient, orgId, attributeId, updates)
+}
+
+// PatchProfileSchemaAttributeByIdTx updates a profile schema attribute within the given transaction.
+func PatchProfileSchemaAttributeByIdTx(tx *sql.Tx, orgId, attributeId string, updates map[string]interface{}) error {
+
+	return patchProfileSchemaAttributeById(client.NewTxClient(tx), orgId, attributeId, updates)
+}
+
+func patchProfileSchemaAttributeById(dbClient queryExecutor, orgId, attributeId string, updates map[string]interface{}) error {
+
+	logger := log.GetLogger()
+
 	setClauses := []string{}
 	args := []interface{}{}
 	argIndex := 1
@@ -369,7 +466,7 @@ func PatchProfileSchemaAttributeById(orgId, attributeId string, updates map[stri
 	query := `UPDATE profile_schema SET ` + strings.Join(setClauses, ", ") +
 		` WHERE org_handle = $` + strconv.Itoa(argIndex) + ` AND attribute_id = $` + strconv.Itoa(argIndex+1)
 
-	_, err = dbClient.ExecuteQuery(query, args...)
+	_, err := dbClient.ExecuteQuery(query, args...)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Error occurred while executing update for org: %s", orgId)
 		logger.Debug(errorMsg, log.Error(err))
@@ -478,7 +575,7 @@ func PatchProfileSchemaAttributesForScope(orgId string, scope string, updates []
 	stmt := scripts.UpdateProfileSchemaAttributesForSchema[provider.NewDBProvider().GetDBType()]
 
 	for _, attr := range updates {
-		subAttrsJSON, err := json.Marshal(attr.SubAttributes)
+		subAttrsJSON, err := json.Marshal(model.SubAttributeReferences(attr.SubAttributes))
 		if err != nil {
 			errorMsg := fmt.Sprintf("Failed to marshal sub attributes for attribute %s", attr.AttributeId)
 			logger.Debug(errorMsg, log.Error(err))
@@ -509,6 +606,8 @@ func PatchProfileSchemaAttributesForScope(orgId string, scope string, updates []
 			canonicalJSON,
 			subAttrsJSON,
 			attr.DisplayName,
+			attr.Expression,
+			marshalConstraints(attr.Constraints),
 			orgId,
 			attr.AttributeId,
 			scope,
@@ -585,7 +684,7 @@ func DeleteProfileSchema(orgId string) error {
 // mapRowToProfileAttribute converts database row to a ProfileSchemaAttribute model.
 func mapRowToProfileAttribute(row map[string]interface{}) model.ProfileSchemaAttribute {
 
-	var subAttrs []model.SubAttribute
+	var subAttrs []model.ProfileSchemaAttribute
 	if raw, ok := row["sub_attributes"].(string); ok && raw != "" {
 		if err := json.Unmarshal([]byte(raw), &subAttrs); err != nil {
 			log.GetLogger().Debug("Failed to unmarshal sub_attributes", log.Error(err))
@@ -616,9 +715,49 @@ func mapRowToProfileAttribute(row map[string]interface{}) model.ProfileSchemaAtt
 		MultiValued:           row["multi_valued"].(bool),
 		SubAttributes:         subAttrs,
 		CanonicalValues:       canonicalValues,
+		Expression:            rowString(row, "expression"),
+		Constraints:           parseConstraints(row["constraints"]),
 	}
 }
 
+// marshalConstraints serializes attribute constraints for the constraints JSONB column.
+func marshalConstraints(constraints *model.AttributeConstraints) string {
+	if constraints == nil {
+		return "{}"
+	}
+	raw, err := json.Marshal(constraints)
+	if err != nil {
+		log.GetLogger().Debug("Failed to marshal attribute constraints", log.Error(err))
+		return "{}"
+	}
+	return string(raw)
+}
+
+// parseConstraints reads the constraints column, returning nil when no constraint is set.
+func parseConstraints(raw interface{}) *model.AttributeConstraints {
+	s, ok := raw.(string)
+	if !ok || s == "" {
+		return nil
+	}
+	var constraints model.AttributeConstraints
+	if err := json.Unmarshal([]byte(s), &constraints); err != nil {
+		log.GetLogger().Debug("Failed to unmarshal attribute constraints", log.Error(err))
+		return nil
+	}
+	if constraints.IsEmpty() {
+		return nil
+	}
+	return &constraints
+}
+
+// rowString returns the string value of a nullable column, or an empty string.
+func rowString(row map[string]interface{}, column string) string {
+	if s, ok := row[column].(string); ok {
+		return s
+	}
+	return ""
+}
+
 func UpsertIdentityAttributes(orgID string, attrs []model.ProfileSchemaAttribute) error {
 
 	dbClient, err := provider.NewDBProvider().GetDBClient()
@@ -662,31 +801,11 @@ func UpsertIdentityAttributes(orgID string, attrs []model.ProfileSchemaAttribute
 	incomingIDs := make([]string, 0, len(attrs))
 
 	for _, attr := range attrs {
-		canonicalJSON, _ := json.Marshal(attr.CanonicalValues)
-		subAttrJSON, _ := json.Marshal(attr.SubAttributes)
-		attrKey := extractClaimKeyFromURI(attr.AttributeName)
-		attr.AttributeName = attrKey
 		incomingIDs = append(incomingIDs, attr.AttributeId)
-
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
-			argIndex, argIndex+1, argIndex+2, argIndex+3, argIndex+4, argIndex+5, argIndex+6,
-			argIndex+7, argIndex+8, argIndex+9, argIndex+10, argIndex+11, argIndex+12))
-		valueArgs = append(valueArgs,
-			orgID,
-			attr.AttributeId,
-			attr.AttributeName,
-			attr.ValueType,
-			attr.MergeStrategy,
-			attr.Mutability,
-			attr.ApplicationIdentifier,
-			attr.MultiValued,
-			string(canonicalJSON),
-			string(subAttrJSON),
-			attr.SCIMDialect,
-			constants.IdentityAttributes,
-			attr.DisplayName,
-		)
-		argIndex += 13
+		valueString, args := identityAttributeRow(orgID, attr, argIndex)
+		valueStrings = append(valueStrings, valueString)
+		valueArgs = append(valueArgs, args...)
+		argIndex += len(args)
 	}
 
 	upsertQuery := fmt.Sprintf(upsertTemplate, strings.Join(valueStrings, ","))
@@ -738,6 +857,64 @@ func UpsertIdentityAttributes(orgID string, attrs []model.ProfileSchemaAttribute
 	return nil
 }
 
+// UpsertIdentityAttribute inserts or updates a single identity attribute, leaving the other identity attributes of
+// the organization as they are.
+func UpsertIdentityAttribute(orgID string, attr model.ProfileSchemaAttribute) error {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	logger := log.GetLogger()
+	if err != nil {
+		errorMsg := fmt.Sprintf("Error initializing DB client for organization: %s ", orgID)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors.NewServerError(errors.ErrorMessage{
+			Code:        errors.SYNC_PROFILE_SCHEMA.Code,
+			Message:     errors.SYNC_PROFILE_SCHEMA.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	defer dbClient.Close()
+
+	valueString, args := identityAttributeRow(orgID, attr, 1)
+	query := fmt.Sprintf(scripts.UpsertIdentityClaimsForProfileSchema[provider.NewDBProvider().GetDBType()], valueString)
+	if _, err = dbClient.ExecuteQuery(query, args...); err != nil {
+		errorMsg := fmt.Sprintf("Failed to upsert identity attribute: %s for organization: %s", attr.AttributeName, orgID)
+		logger.Debug(errorMsg, log.Error(err))
+		return errors.NewServerError(errors.ErrorMessage{
+			Code:        errors.SYNC_PROFILE_SCHEMA.Code,
+			Message:     errors.SYNC_PROFILE_SCHEMA.Message,
+			Description: errorMsg,
+		}, err)
+	}
+	return nil
+}
+
+// identityAttributeRow returns the VALUES tuple of an identity attribute upsert, with placeholders numbered from
+// argIndex, and its arguments.
+func identityAttributeRow(orgID string, attr model.ProfileSchemaAttribute, argIndex int) (string, []interface{}) {
+	canonicalJSON, _ := json.Marshal(attr.CanonicalValues)
+	subAttrJSON, _ := json.Marshal(model.SubAttributeReferences(attr.SubAttributes))
+	args := []interface{}{
+		orgID,
+		attr.AttributeId,
+		extractClaimKeyFromURI(attr.AttributeName),
+		attr.ValueType,
+		attr.MergeStrategy,
+		attr.Mutability,
+		attr.ApplicationIdentifier,
+		attr.MultiValued,
+		string(canonicalJSON),
+		string(subAttrJSON),
+		attr.SCIMDialect,
+		constants.IdentityAttributes,
+		attr.DisplayName,
+	}
+	placeholders := make([]string, len(args))
+	for i := range args {
+		placeholders[i] = fmt.Sprintf("$%d", argIndex+i)
+	}
+	return "(" + strings.Join(placeholders, ",") + ")", args
+}
+
 func extractClaimKeyFromURI(uri string) string {
 	parts := strings.Split(strings.TrimRight(uri, "/"), "/")
 	if len(parts) == 0 {
@@ -0,0 +1,228 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+func schemaMigrationError(errorMsg string, err error) error {
+	log.GetLogger().Debug(errorMsg, log.Error(err))
+	return errors.NewServerError(errors.ErrorMessage{
+		Code:        errors.MIGRATE_PROFILE_SCHEMA.Code,
+		Message:     errors.MIGRATE_PROFILE_SCHEMA.Message,
+		Description: errorMsg,
+	}, err)
+}
+
+// InsertSchemaMigration persists a new schema migration.
+func InsertSchemaMigration(migration model.SchemaMigration) error {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return schemaMigrationError("Failed to get database client for adding a schema migration", err)
+	}
+	defer dbClient.Close()
+
+	return insertSchemaMigration(dbClient, migration)
+}
+
+// InsertSchemaMigrationTx persists a new schema migration within the given transaction.
+func InsertSchemaMigrationTx(tx *sql.Tx, migration model.SchemaMigration) error {
+	return insertSchemaMigration(client.NewTxClient(tx), migration)
+}
+
+func insertSchemaMigration(dbClient queryExecutor, migration model.SchemaMigration) error {
+
+	from, err := json.Marshal(migration.From)
+	if err != nil {
+		return schemaMigrationError("Failed to marshal the source definition of a schema migration", err)
+	}
+	to, err := json.Marshal(migration.To)
+	if err != nil {
+		return schemaMigrationError("Failed to marshal the target definition of a schema migration", err)
+	}
+
+	query := scripts.InsertSchemaMigration[provider.NewDBProvider().GetDBType()]
+	_, err = dbClient.ExecuteQuery(query, migration.MigrationId, migration.OrgHandle, migration.AttributeId,
+		migration.AttributeName, migration.ApplicationIdentifier, migration.Operation, from, to, migration.Status,
+		migration.Owner, migration.CreatedAt)
+	if err != nil {
+		return schemaMigrationError(fmt.Sprintf("Failed to add schema migration: %s", migration.MigrationId), err)
+	}
+	return nil
+}
+
+// UpdateSchemaMigrationProgress persists the status, counters and failures of a schema migration. It reports false,
+// without writing, once the migration has been superseded or claimed by an instance other than its owner.
+func UpdateSchemaMigrationProgress(migration model.SchemaMigration) (bool, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return false, schemaMigrationError("Failed to get database client for updating a schema migration", err)
+	}
+	defer dbClient.Close()
+
+	failures, err := json.Marshal(migration.Failures)
+	if err != nil {
+		return false, schemaMigrationError("Failed to marshal the failures of a schema migration", err)
+	}
+
+	query := scripts.UpdateSchemaMigrationProgress[provider.NewDBProvider().GetDBType()]
+	results, err := dbClient.ExecuteQuery(query, migration.MigrationId, migration.Status, migration.TotalProfiles,
+		migration.ProcessedProfiles, migration.ConvertedValues, migration.FailedValues, failures, migration.Error,
+		migration.UpdatedAt, migration.Owner)
+	if err != nil {
+		return false, schemaMigrationError(fmt.Sprintf("Failed to update schema migration: %s", migration.MigrationId), err)
+	}
+	return len(results) > 0, nil
+}
+
+// TouchSchemaMigration records a heartbeat of a migration on behalf of its owner.
+func TouchSchemaMigration(migrationId, owner string) error {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return schemaMigrationError("Failed to get database client for touching a schema migration", err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.TouchSchemaMigration[provider.NewDBProvider().GetDBType()]
+	if _, err = dbClient.ExecuteQuery(query, migrationId, owner, time.Now().UTC()); err != nil {
+		return schemaMigrationError(fmt.Sprintf("Failed to touch schema migration: %s", migrationId), err)
+	}
+	return nil
+}
+
+// GetSchemaMigration fetches a schema migration of an org, or nil if there is none with the given id.
+func GetSchemaMigration(orgHandle, migrationId string) (*model.SchemaMigration, error) {
+
+	migrations, err := querySchemaMigrations(scripts.GetSchemaMigration, orgHandle, migrationId)
+	if err != nil || len(migrations) == 0 {
+		return nil, err
+	}
+	return &migrations[0], nil
+}
+
+// GetSchemaMigrationsByAttribute fetches the migrations of an attribute, newest first.
+func GetSchemaMigrationsByAttribute(orgHandle, attributeId string) ([]model.SchemaMigration, error) {
+	return querySchemaMigrations(scripts.GetSchemaMigrationsByAttribute, orgHandle, attributeId)
+}
+
+// ClaimStaleSchemaMigration hands the oldest unfinished migration without a heartbeat since staleBefore to owner, or
+// returns nil if there is none. Each migration is claimed by a single caller.
+func ClaimStaleSchemaMigration(owner string, staleBefore time.Time) (*model.SchemaMigration, error) {
+
+	migrations, err := querySchemaMigrations(scripts.ClaimStaleSchemaMigration, owner, time.Now().UTC(), staleBefore)
+	if err != nil || len(migrations) == 0 {
+		return nil, err
+	}
+	migrations[0].Owner = owner
+	return &migrations[0], nil
+}
+
+// GetUnfinishedSchemaPurges fetches the pending and running purges of an org.
+func GetUnfinishedSchemaPurges(orgHandle string) ([]model.SchemaMigration, error) {
+	return querySchemaMigrations(scripts.GetUnfinishedSchemaPurges, orgHandle)
+}
+
+// SupersedeSchemaMigrations marks the unfinished migrations of an attribute as superseded so that they stop.
+func SupersedeSchemaMigrations(orgHandle, attributeId string) error {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return schemaMigrationError("Failed to get database client for superseding schema migrations", err)
+	}
+	defer dbClient.Close()
+
+	return supersedeSchemaMigrations(dbClient, orgHandle, attributeId)
+}
+
+// SupersedeSchemaMigrationsTx marks the unfinished migrations of an attribute as superseded within the given
+// transaction.
+func SupersedeSchemaMigrationsTx(tx *sql.Tx, orgHandle, attributeId string) error {
+	return supersedeSchemaMigrations(client.NewTxClient(tx), orgHandle, attributeId)
+}
+
+func supersedeSchemaMigrations(dbClient queryExecutor, orgHandle, attributeId string) error {
+
+	query := scripts.SupersedeSchemaMigrations[provider.NewDBProvider().GetDBType()]
+	if _, err := dbClient.ExecuteQuery(query, orgHandle, attributeId, time.Now().UTC()); err != nil {
+		return schemaMigrationError(fmt.Sprintf("Failed to supersede the migrations of attribute: %s", attributeId), err)
+	}
+	return nil
+}
+
+func querySchemaMigrations(queries map[string]string, args ...interface{}) ([]model.SchemaMigration, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return nil, schemaMigrationError("Failed to get database client for fetching schema migrations", err)
+	}
+	defer dbClient.Close()
+
+	results, err := dbClient.ExecuteQuery(queries[provider.NewDBProvider().GetDBType()], args...)
+	if err != nil {
+		return nil, schemaMigrationError("Failed to fetch schema migrations", err)
+	}
+
+	migrations := make([]model.SchemaMigration, 0, len(results))
+	for _, row := range results {
+		migration := model.SchemaMigration{
+			MigrationId:           row["migration_id"].(string),
+			OrgHandle:             row["org_handle"].(string),
+			AttributeId:           row["attribute_id"].(string),
+			AttributeName:         row["attribute_name"].(string),
+			ApplicationIdentifier: row["application_identifier"].(string),
+			Operation:             row["operation"].(string),
+			Status:                row["status"].(string),
+			TotalProfiles:         int(row["total_profiles"].(int64)),
+			ProcessedProfiles:     int(row["processed_profiles"].(int64)),
+			ConvertedValues:       int(row["converted_values"].(int64)),
+			FailedValues:          int(row["failed_values"].(int64)),
+			Error:                 row["error"].(string),
+			CreatedAt:             row["created_at"].(time.Time),
+			UpdatedAt:             row["updated_at"].(time.Time),
+		}
+		for column, target := range map[string]interface{}{
+			"source_definition": &migration.From,
+			"target_definition": &migration.To,
+			"failures":          &migration.Failures,
+		} {
+			if raw, ok := row[column].(string); ok && raw != "" {
+				if err := json.Unmarshal([]byte(raw), target); err != nil {
+					return nil, schemaMigrationError(fmt.Sprintf("Failed to parse %s of schema migration: %s",
+						column, migration.MigrationId), err)
+				}
+			}
+		}
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+func schemaSyncBacklogError(errorMsg string, err error) error {
+	log.GetLogger().Debug(errorMsg, log.Error(err))
+	return errors.NewServerError(errors.ErrorMessage{
+		Code:        errors.PERSIST_PENDING_QUEUE_ITEMS.Code,
+		Message:     errors.PERSIST_PENDING_QUEUE_ITEMS.Message,
+		Description: errorMsg,
+	}, err)
+}
+
+// AddToSchemaSyncBacklog persists schema sync jobs that could not be processed before shutdown, so that the next
+// start enqueues them again.
+func AddToSchemaSyncBacklog(syncs []model.ProfileSchemaSync) error {
+
+	if len(syncs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(syncs)
+	if err != nil {
+		return schemaSyncBacklogError("Failed to encode the schema sync jobs for the backlog", err)
+	}
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return schemaSyncBacklogError("Failed to get database client for adding to the schema sync backlog", err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.InsertSchemaSyncBacklog[provider.NewDBProvider().GetDBType()]
+	if _, err := dbClient.ExecuteQuery(query, string(payload)); err != nil {
+		return schemaSyncBacklogError(fmt.Sprintf("Failed to add %d schema sync jobs to the backlog", len(syncs)), err)
+	}
+	return nil
+}
+
+// TakeSchemaSyncBacklog removes the persisted schema sync jobs and returns them, oldest first.
+func TakeSchemaSyncBacklog() ([]model.ProfileSchemaSync, error) {
+
+	dbClient, err := provider.NewDBProvider().GetDBClient()
+	if err != nil {
+		return nil, schemaSyncBacklogError("Failed to get database client for taking the schema sync backlog", err)
+	}
+	defer dbClient.Close()
+
+	query := scripts.TakeSchemaSyncBacklog[provider.NewDBProvider().GetDBType()]
+	results, err := dbClient.ExecuteQuery(query)
+	if err != nil {
+		return nil, schemaSyncBacklogError("Failed to take the schema sync backlog", err)
+	}
+	syncs := make([]model.ProfileSchemaSync, 0, len(results))
+	for _, row := range results {
+		var sync model.ProfileSchemaSync
+		if err := json.Unmarshal([]byte(row["payload"].(string)), &sync); err != nil {
+			// The jobs are already deleted; an undecodable one cannot be retried.
+			log.GetLogger().Warn("Dropping an undecodable job of the schema sync backlog", log.Error(err))
+			continue
+		}
+		syncs = append(syncs, sync)
+	}
+	return syncs, nil
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+
+// ImportSummary lists the changes an import made, or would make on a dry run, per kind of bundle entry.
+type ImportSummary struct {
+	DryRun            bool           `json:"dry_run"`
+	Attributes        ChangeSummary  `json:"attributes"`
+	UnificationRules  ChangeSummary  `json:"unification_rules"`
+	ConsentCategories ChangeSummary  `json:"consent_categories"`
+	Failure           *ImportFailure `json:"failure,omitempty"` // Set when the import failed and was rolled back
+}
+
+// ImportFailure describes the error an import failed with. The summary it belongs to lists no created or updated
+// entries, as a failed import changes nothing.
+type ImportFailure struct {
+	Code        string                `json:"code"`
+	Message     string                `json:"message"`
+	Description string                `json:"description,omitempty"`
+	Details     []errors2.ErrorDetail `json:"details,omitempty"`
+}
+
+// ChangeSummary lists the created and updated entries of one kind and counts the ones left as they were.
+type ChangeSummary struct {
+	Created   []Change `json:"created"`
+	Updated   []Change `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Change identifies a bundle entry and, for updates, the fields that differ from the organization's current schema.
+type Change struct {
+	Name                  string   `json:"name"`
+	ApplicationIdentifier string   `json:"application_identifier,omitempty"`
+	Fields                []string `json:"fields,omitempty"`
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package model
+
+import (
+	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	unificationModel "github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
+)
+
+// SchemaBundle is the portable form of an organization's profile schema. It carries no identifiers so that it can
+// be imported into any organization.
+type SchemaBundle struct {
+	Version           int                                          `json:"version"`
+	ExportedAt        string                                       `json:"exported_at,omitempty"`
+	Traits            []BundleAttribute                            `json:"traits"`
+	ApplicationData   []BundleAttribute                            `json:"application_data"`
+	UnificationRules  []unificationModel.UnificationRuleAPIRequest `json:"unification_rules"`
+	ConsentCategories []BundleConsentCategory                      `json:"consent_categories"`
+}
+
+// BundleAttribute is a profile schema attribute in a bundle. Sub-attributes are nested in their parent.
+type BundleAttribute struct {
+	AttributeName         string                            `json:"attribute_name"`
+	DisplayName           string                            `json:"display_name,omitempty"`
+	ValueType             string                            `json:"value_type"`
+	MergeStrategy         string                            `json:"merge_strategy"`
+	Mutability            string                            `json:"mutability"`
+	ApplicationIdentifier string                            `json:"application_identifier,omitempty"`
+	MultiValued           bool                              `json:"multi_valued,omitempty"`
+	CanonicalValues       []schemaModel.CanonicalValue      `json:"canonical_values,omitempty"`
+	SubAttributes         []BundleAttribute                 `json:"sub_attributes,omitempty"`
+	Expression            string                            `json:"expression,omitempty"`
+	Constraints           *schemaModel.AttributeConstraints `json:"constraints,omitempty"`
+}
+
+// BundleConsentCategory is a consent category in a bundle, bound to attributes by name.
+type BundleConsentCategory struct {
+	CategoryName  string                                              `json:"category_name"`
+	Purpose       string                                              `json:"purpose"`
+	Destinations  []string                                            `json:"destinations,omitempty"`
+	Attributes    []BundleConsentAttribute                            `json:"attributes,omitempty"`
+	Localizations map[string]consentModel.ConsentCategoryLocalization `json:"localizations,omitempty"`
+}
+
+// BundleConsentAttribute binds a consent category to a profile schema attribute.
+type BundleConsentAttribute struct {
+	AttributeName         string `json:"attribute_name"`
+	ApplicationIdentifier string `json:"application_identifier,omitempty"`
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package provider
+
+import (
+	"github.com/wso2/identity-customer-data-service/internal/schema_bundle/service"
+)
+
+// SchemaBundleProviderInterface defines the interface for the schema bundle provider.
+type SchemaBundleProviderInterface interface {
+	GetSchemaBundleService() service.SchemaBundleServiceInterface
+}
+
+// SchemaBundleProvider is the default implementation of the SchemaBundleProviderInterface.
+type SchemaBundleProvider struct{}
+
+// NewSchemaBundleProvider creates a new instance of SchemaBundleProvider.
+func NewSchemaBundleProvider() SchemaBundleProviderInterface {
+	return &SchemaBundleProvider{}
+}
+
+// GetSchemaBundleService returns the schema bundle service instance.
+func (sp *SchemaBundleProvider) GetSchemaBundleService() service.SchemaBundleServiceInterface {
+	return service.GetSchemaBundleService()
+}
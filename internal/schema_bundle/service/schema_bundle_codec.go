@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wso2/identity-customer-data-service/internal/schema_bundle/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"gopkg.in/yaml.v2"
+)
+
+// EncodeSchemaBundle serializes the bundle in the given format. YAML keeps the field order of the JSON form.
+func EncodeSchemaBundle(bundle model.SchemaBundle, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil || format != constants.SchemaBundleFormatYAML {
+		return data, err
+	}
+	value, err := orderedYAMLValue(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
+
+// DecodeSchemaBundle parses a bundle in the given format. Unknown fields are rejected so that typos do not go
+// unnoticed.
+func DecodeSchemaBundle(data []byte, format string) (model.SchemaBundle, error) {
+	var bundle model.SchemaBundle
+	if format == constants.SchemaBundleFormatYAML {
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return bundle, err
+		}
+		converted, err := jsonCompatible(raw)
+		if err != nil {
+			return bundle, err
+		}
+		if data, err = json.Marshal(converted); err != nil {
+			return bundle, err
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&bundle)
+	return bundle, err
+}
+
+// orderedYAMLValue reads the next JSON value from the decoder, keeping the order of object keys.
+func orderedYAMLValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := yaml.MapSlice{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := orderedYAMLValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case '[':
+		array := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := orderedYAMLValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %v", delim)
+	}
+}
+
+// jsonCompatible converts the generic maps produced by the YAML decoder into string keyed maps.
+func jsonCompatible(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported key %v", key)
+			}
+			converted, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = converted
+		}
+		return object, nil
+	case []interface{}:
+		array := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := jsonCompatible(item)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, converted)
+		}
+		return array, nil
+	default:
+		return v, nil
+	}
+}
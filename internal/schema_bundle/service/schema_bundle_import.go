@@ -0,0 +1,484 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
+	consentService "github.com/wso2/identity-customer-data-service/internal/consent/service"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/schema_bundle/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	unificationModel "github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
+	unificationService "github.com/wso2/identity-customer-data-service/internal/unification_rules/service"
+)
+
+// importPlan holds the changes an import makes, in the form the owning services accept them.
+type importPlan struct {
+	summary           model.ImportSummary
+	createdAttributes []schemaModel.ProfileSchemaAttribute
+	updatedAttributes []attributeUpdate
+	createdRules      []unificationModel.UnificationRule
+	updatedRules      []unificationModel.UnificationRule
+	createdCategories []consentModel.ConsentCategory
+	updatedCategories []consentModel.ConsentCategory
+}
+
+type attributeUpdate struct {
+	attributeId string
+	scope       string
+	updates     map[string]interface{}
+}
+
+// planImport compares the bundle with the organization's current schema.
+func planImport(orgHandle string, bundle model.SchemaBundle, current *orgSchema) (*importPlan, error) {
+
+	plan := &importPlan{summary: model.ImportSummary{
+		Attributes:        newChangeSummary(),
+		UnificationRules:  newChangeSummary(),
+		ConsentCategories: newChangeSummary(),
+	}}
+
+	attributeNames := plan.planAttributes(orgHandle, bundle, current)
+	if err := plan.planRules(orgHandle, bundle, current, attributeNames); err != nil {
+		return nil, err
+	}
+	if err := plan.planCategories(orgHandle, bundle, current, attributeNames); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func newChangeSummary() model.ChangeSummary {
+	return model.ChangeSummary{Created: []model.Change{}, Updated: []model.Change{}}
+}
+
+// planAttributes plans the attribute changes and returns the keys of the attributes the organization has once
+// they are applied.
+func (p *importPlan) planAttributes(orgHandle string, bundle model.SchemaBundle, current *orgSchema) map[string]bool {
+
+	existing := make(map[string]schemaModel.ProfileSchemaAttribute, len(current.attributes))
+	attributeNames := make(map[string]bool, len(current.attributes))
+	for _, attr := range current.attributes {
+		key := attributeKey(attr.AttributeName, attr.ApplicationIdentifier)
+		existing[key] = attr
+		attributeNames[key] = true
+	}
+
+	topLevel := make([]schemaModel.ProfileSchemaAttribute, 0, len(bundle.Traits)+len(bundle.ApplicationData))
+	for _, bundleAttr := range append(append([]model.BundleAttribute{}, bundle.Traits...), bundle.ApplicationData...) {
+		topLevel = append(topLevel, fromBundleAttribute(bundleAttr, orgHandle, ""))
+	}
+	definitions := schemaModel.FlattenSubAttributes(topLevel)
+
+	// New attributes get their Ids up front so that their parents can refer to them.
+	ids := make(map[string]string, len(definitions))
+	for _, def := range definitions {
+		key := attributeKey(def.AttributeName, def.ApplicationIdentifier)
+		if attr, ok := existing[key]; ok {
+			ids[key] = attr.AttributeId
+		} else {
+			ids[key] = uuid.New().String()
+		}
+		attributeNames[key] = true
+	}
+
+	for _, def := range definitions {
+		key := attributeKey(def.AttributeName, def.ApplicationIdentifier)
+		scope := strings.SplitN(def.AttributeName, ".", 2)[0]
+		change := model.Change{Name: def.AttributeName, ApplicationIdentifier: def.ApplicationIdentifier}
+
+		references := make([]schemaModel.ProfileSchemaAttribute, 0, len(def.SubAttributes))
+		for _, subAttr := range def.SubAttributes {
+			references = append(references, schemaModel.ProfileSchemaAttribute{
+				AttributeId:   ids[attributeKey(subAttr.AttributeName, subAttr.ApplicationIdentifier)],
+				AttributeName: subAttr.AttributeName,
+			})
+		}
+
+		attr, found := existing[key]
+		if !found {
+			created := def
+			created.AttributeId = ids[key]
+			created.SubAttributes = references
+			p.createdAttributes = append(p.createdAttributes, created)
+			p.summary.Attributes.Created = append(p.summary.Attributes.Created, change)
+			continue
+		}
+
+		fields := attributeDifferences(attr, def)
+		if len(fields) == 0 {
+			p.summary.Attributes.Unchanged++
+			continue
+		}
+		if def.DisplayName == "" {
+			def.DisplayName = attr.DisplayName
+		}
+		p.updatedAttributes = append(p.updatedAttributes, attributeUpdate{
+			attributeId: attr.AttributeId,
+			scope:       scope,
+			updates:     attributeUpdates(def, references, scope),
+		})
+		change.Fields = fields
+		p.summary.Attributes.Updated = append(p.summary.Attributes.Updated, change)
+	}
+	return attributeNames
+}
+
+func (p *importPlan) planRules(orgHandle string, bundle model.SchemaBundle, current *orgSchema,
+	attributeNames map[string]bool) error {
+
+	existing := make(map[string]unificationModel.UnificationRule, len(current.rules))
+	priorities := make(map[string]int, len(current.rules))
+	for _, rule := range current.rules {
+		existing[rule.PropertyName] = rule
+		priorities[rule.PropertyName] = rule.Priority
+	}
+
+	now := time.Now().UTC()
+	for _, bundleRule := range bundle.UnificationRules {
+		if !attributeNames[attributeKey(bundleRule.PropertyName, "")] {
+			return invalidBundle(fmt.Sprintf("Unification rule '%s' refers to the unknown attribute '%s'.",
+				bundleRule.RuleName, bundleRule.PropertyName))
+		}
+		priorities[bundleRule.PropertyName] = bundleRule.Priority
+		change := model.Change{Name: bundleRule.PropertyName}
+
+		rule, found := existing[bundleRule.PropertyName]
+		if !found {
+			p.createdRules = append(p.createdRules, unificationModel.UnificationRule{
+				RuleId:       uuid.New().String(),
+				OrgHandle:    orgHandle,
+				RuleName:     bundleRule.RuleName,
+				PropertyName: bundleRule.PropertyName,
+				Priority:     bundleRule.Priority,
+				IsActive:     bundleRule.IsActive,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			})
+			p.summary.UnificationRules.Created = append(p.summary.UnificationRules.Created, change)
+			continue
+		}
+
+		var fields []string
+		if rule.RuleName != bundleRule.RuleName {
+			fields = append(fields, "rule_name")
+		}
+		if rule.Priority != bundleRule.Priority {
+			fields = append(fields, "priority")
+		}
+		if rule.IsActive != bundleRule.IsActive {
+			fields = append(fields, "is_active")
+		}
+		if len(fields) == 0 {
+			p.summary.UnificationRules.Unchanged++
+			continue
+		}
+		rule.RuleName = bundleRule.RuleName
+		rule.Priority = bundleRule.Priority
+		rule.IsActive = bundleRule.IsActive
+		p.updatedRules = append(p.updatedRules, rule)
+		change.Fields = fields
+		p.summary.UnificationRules.Updated = append(p.summary.UnificationRules.Updated, change)
+	}
+
+	// Priorities must stay unique across the rules the organization ends up with.
+	usedBy := make(map[int]string, len(priorities))
+	for property, priority := range priorities {
+		if other, taken := usedBy[priority]; taken {
+			return invalidBundle(fmt.Sprintf("Unification rules on '%s' and '%s' would share priority %d.",
+				other, property, priority))
+		}
+		usedBy[priority] = property
+	}
+	return nil
+}
+
+func (p *importPlan) planCategories(orgHandle string, bundle model.SchemaBundle, current *orgSchema,
+	attributeNames map[string]bool) error {
+
+	existing := make(map[string]consentModel.ConsentCategory, len(current.categories))
+	for _, category := range current.categories {
+		existing[category.CategoryName] = category
+	}
+
+	for _, bundleCategory := range bundle.ConsentCategories {
+		category := consentModel.ConsentCategory{
+			CategoryName:  bundleCategory.CategoryName,
+			OrgHandle:     orgHandle,
+			Purpose:       bundleCategory.Purpose,
+			Destinations:  bundleCategory.Destinations,
+			Localizations: bundleCategory.Localizations,
+		}
+		for _, attr := range bundleCategory.Attributes {
+			if !attributeNames[attributeKey(attr.AttributeName, attr.ApplicationIdentifier)] {
+				return invalidBundle(fmt.Sprintf("Consent category '%s' refers to the unknown attribute '%s'.",
+					bundleCategory.CategoryName, attr.AttributeName))
+			}
+			category.Attributes = append(category.Attributes, consentModel.ConsentAttribute{
+				AttributeName:         attr.AttributeName,
+				ApplicationIdentifier: attr.ApplicationIdentifier,
+			})
+		}
+		change := model.Change{Name: bundleCategory.CategoryName}
+
+		existingCategory, found := existing[bundleCategory.CategoryName]
+		if !found {
+			p.createdCategories = append(p.createdCategories, category)
+			p.summary.ConsentCategories.Created = append(p.summary.ConsentCategories.Created, change)
+			continue
+		}
+		if existingCategory.IsMandatory {
+			return invalidBundle(fmt.Sprintf("Consent category '%s' is mandatory and cannot be imported.",
+				bundleCategory.CategoryName))
+		}
+
+		fields := categoryDifferences(existingCategory, category)
+		if len(fields) == 0 {
+			p.summary.ConsentCategories.Unchanged++
+			continue
+		}
+		category.CategoryIdentifier = existingCategory.CategoryIdentifier
+		p.updatedCategories = append(p.updatedCategories, category)
+		change.Fields = fields
+		p.summary.ConsentCategories.Updated = append(p.summary.ConsentCategories.Updated, change)
+	}
+	return nil
+}
+
+// apply makes the planned changes in a single transaction. Attributes that do not depend on others go first, so
+// that computed attributes and parents find what they refer to. A failure rolls the transaction back, leaving the
+// organization's schema as it was. Migrations of profile values for updated attributes start once the changes are
+// committed.
+func (p *importPlan) apply(orgHandle string) error {
+
+	var startMigrations []func()
+	err := provider.WithTransaction(errors2.IMPORT_PROFILE_SCHEMA, func(tx *sql.Tx) error {
+		schemaSvc := schemaService.GetProfileSchemaService()
+		for _, computed := range []bool{false, true} {
+			for _, scope := range []string{constants.Traits, constants.ApplicationData} {
+				batch := make([]schemaModel.ProfileSchemaAttribute, 0)
+				for _, attr := range p.createdAttributes {
+					if attr.IsComputed() == computed && strings.HasPrefix(attr.AttributeName, scope+".") {
+						batch = append(batch, attr)
+					}
+				}
+				if len(batch) == 0 {
+					continue
+				}
+				if _, err := schemaSvc.AddProfileSchemaAttributesForScopeTx(tx, batch, scope, orgHandle); err != nil {
+					return err
+				}
+			}
+		}
+		for _, update := range p.updatedAttributes {
+			startMigration, err := schemaSvc.UpdateProfileSchemaAttributeByIdTx(tx, orgHandle, update.attributeId,
+				update.updates, update.scope)
+			if err != nil {
+				return err
+			}
+			startMigrations = append(startMigrations, startMigration)
+		}
+
+		ruleSvc := unificationService.GetUnificationRuleService()
+		for _, rule := range p.updatedRules {
+			if err := ruleSvc.PatchUnificationRuleTx(tx, rule.RuleId, orgHandle, rule); err != nil {
+				return err
+			}
+		}
+		for _, rule := range p.createdRules {
+			if err := ruleSvc.AddUnificationRuleTx(tx, rule, orgHandle); err != nil {
+				return err
+			}
+		}
+
+		categorySvc := consentService.GetConsentCategoryService()
+		for _, category := range p.updatedCategories {
+			if err := categorySvc.UpdateConsentCategoryTx(tx, category); err != nil {
+				return err
+			}
+		}
+		for _, category := range p.createdCategories {
+			if _, err := categorySvc.AddConsentCategoryTx(tx, category); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, startMigration := range startMigrations {
+		startMigration()
+	}
+	return nil
+}
+
+// failedSummary is the summary of an import that failed: nothing was created or updated, and the entries the plan
+// left unchanged are still counted.
+func (p *importPlan) failedSummary(err error) model.ImportSummary {
+
+	summary := model.ImportSummary{
+		Attributes:        newChangeSummary(),
+		UnificationRules:  newChangeSummary(),
+		ConsentCategories: newChangeSummary(),
+		Failure:           importFailure(err),
+	}
+	summary.Attributes.Unchanged = p.summary.Attributes.Unchanged
+	summary.UnificationRules.Unchanged = p.summary.UnificationRules.Unchanged
+	summary.ConsentCategories.Unchanged = p.summary.ConsentCategories.Unchanged
+	return summary
+}
+
+// attributeDifferences returns the fields in which the bundle definition differs from the existing attribute. An
+// empty display name in the bundle keeps the existing one.
+func attributeDifferences(existing, incoming schemaModel.ProfileSchemaAttribute) []string {
+	var fields []string
+	if incoming.DisplayName != "" && incoming.DisplayName != existing.DisplayName {
+		fields = append(fields, "display_name")
+	}
+	if incoming.ValueType != existing.ValueType {
+		fields = append(fields, "value_type")
+	}
+	if incoming.MergeStrategy != existing.MergeStrategy {
+		fields = append(fields, "merge_strategy")
+	}
+	if incoming.Mutability != existing.Mutability {
+		fields = append(fields, "mutability")
+	}
+	if incoming.MultiValued != existing.MultiValued {
+		fields = append(fields, "multi_valued")
+	}
+	if !(len(incoming.CanonicalValues) == 0 && len(existing.CanonicalValues) == 0) &&
+		!reflect.DeepEqual(incoming.CanonicalValues, existing.CanonicalValues) {
+		fields = append(fields, "canonical_values")
+	}
+	if !reflect.DeepEqual(subAttributeNames(incoming), subAttributeNames(existing)) {
+		fields = append(fields, "sub_attributes")
+	}
+	if incoming.Expression != existing.Expression {
+		fields = append(fields, "expression")
+	}
+	if constraintsJSON(incoming.Constraints) != constraintsJSON(existing.Constraints) {
+		fields = append(fields, "constraints")
+	}
+	return fields
+}
+
+func subAttributeNames(attr schemaModel.ProfileSchemaAttribute) []string {
+	names := make([]string, 0, len(attr.SubAttributes))
+	for _, subAttr := range attr.SubAttributes {
+		names = append(names, subAttr.AttributeName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func constraintsJSON(constraints *schemaModel.AttributeConstraints) string {
+	if constraints == nil || constraints.IsEmpty() {
+		return ""
+	}
+	data, _ := json.Marshal(constraints)
+	return string(data)
+}
+
+// attributeUpdates builds the update of an existing attribute in the form the profile schema service accepts
+// from the API.
+func attributeUpdates(def schemaModel.ProfileSchemaAttribute, references []schemaModel.ProfileSchemaAttribute,
+	scope string) map[string]interface{} {
+
+	canonicalValues := make([]interface{}, 0, len(def.CanonicalValues))
+	for _, cv := range def.CanonicalValues {
+		canonicalValues = append(canonicalValues, map[string]interface{}{"value": cv.Value, "label": cv.Label})
+	}
+	subAttributes := make([]interface{}, 0, len(references))
+	for _, reference := range references {
+		subAttributes = append(subAttributes, map[string]interface{}{
+			"attribute_id":   reference.AttributeId,
+			"attribute_name": reference.AttributeName,
+		})
+	}
+	constraints := map[string]interface{}{}
+	if raw := constraintsJSON(def.Constraints); raw != "" {
+		_ = json.Unmarshal([]byte(raw), &constraints)
+	}
+
+	updates := map[string]interface{}{
+		"attribute_name":   def.AttributeName,
+		"display_name":     def.DisplayName,
+		"value_type":       def.ValueType,
+		"merge_strategy":   def.MergeStrategy,
+		"mutability":       def.Mutability,
+		"multi_valued":     def.MultiValued,
+		"canonical_values": canonicalValues,
+		"sub_attributes":   subAttributes,
+		"expression":       def.Expression,
+		"constraints":      constraints,
+	}
+	if scope == constants.ApplicationData {
+		updates["application_identifier"] = def.ApplicationIdentifier
+	}
+	return updates
+}
+
+// categoryDifferences returns the fields in which the bundle category differs from the existing one.
+func categoryDifferences(existing, incoming consentModel.ConsentCategory) []string {
+	var fields []string
+	if incoming.Purpose != existing.Purpose {
+		fields = append(fields, "purpose")
+	}
+	if !reflect.DeepEqual(sortedStrings(incoming.Destinations), sortedStrings(existing.Destinations)) {
+		fields = append(fields, "destinations")
+	}
+	if !reflect.DeepEqual(consentBindings(incoming), consentBindings(existing)) {
+		fields = append(fields, "attributes")
+	}
+	if !(len(incoming.Localizations) == 0 && len(existing.Localizations) == 0) &&
+		!reflect.DeepEqual(incoming.Localizations, existing.Localizations) {
+		fields = append(fields, "localizations")
+	}
+	return fields
+}
+
+func consentBindings(category consentModel.ConsentCategory) []string {
+	bindings := make([]string, 0, len(category.Attributes))
+	for _, attr := range category.Attributes {
+		bindings = append(bindings, attributeKey(attr.AttributeName, attr.ApplicationIdentifier))
+	}
+	sort.Strings(bindings)
+	return bindings
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
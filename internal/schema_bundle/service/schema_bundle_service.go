@@ -0,0 +1,354 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
+	consentService "github.com/wso2/identity-customer-data-service/internal/consent/service"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/schema_bundle/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	unificationModel "github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
+	unificationService "github.com/wso2/identity-customer-data-service/internal/unification_rules/service"
+)
+
+type SchemaBundleServiceInterface interface {
+	ExportSchemaBundle(orgHandle string) (*model.SchemaBundle, error)
+	ImportSchemaBundle(orgHandle string, bundle model.SchemaBundle, dryRun bool) (*model.ImportSummary, error)
+}
+
+// SchemaBundleService is the default implementation of the SchemaBundleServiceInterface.
+type SchemaBundleService struct{}
+
+// GetSchemaBundleService creates a new instance of SchemaBundleService.
+func GetSchemaBundleService() SchemaBundleServiceInterface {
+
+	return &SchemaBundleService{}
+}
+
+// ExportSchemaBundle serializes the trait and application data attributes, unification rules and consent
+// categories of the organization into a bundle. Identity attributes are left out as they are synced from the
+// identity server, and so are the mandatory consent categories every organization is seeded with.
+func (s *SchemaBundleService) ExportSchemaBundle(orgHandle string) (*model.SchemaBundle, error) {
+
+	current, err := loadOrgSchema(orgHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &model.SchemaBundle{
+		Version:           constants.SchemaBundleVersion,
+		ExportedAt:        time.Now().UTC().Format(time.RFC3339),
+		Traits:            []model.BundleAttribute{},
+		ApplicationData:   []model.BundleAttribute{},
+		UnificationRules:  []unificationModel.UnificationRuleAPIRequest{},
+		ConsentCategories: []model.BundleConsentCategory{},
+	}
+
+	for _, attr := range current.attributes {
+		// Sub-attributes are exported within their parent.
+		if strings.Count(attr.AttributeName, ".") != 1 {
+			continue
+		}
+		switch strings.SplitN(attr.AttributeName, ".", 2)[0] {
+		case constants.Traits:
+			bundle.Traits = append(bundle.Traits, toBundleAttribute(attr))
+		case constants.ApplicationData:
+			bundle.ApplicationData = append(bundle.ApplicationData, toBundleAttribute(attr))
+		}
+	}
+	sortBundleAttributes(bundle.Traits)
+	sortBundleAttributes(bundle.ApplicationData)
+
+	for _, rule := range current.rules {
+		bundle.UnificationRules = append(bundle.UnificationRules, unificationModel.UnificationRuleAPIRequest{
+			RuleName:     rule.RuleName,
+			PropertyName: rule.PropertyName,
+			Priority:     rule.Priority,
+			IsActive:     rule.IsActive,
+		})
+	}
+	sort.Slice(bundle.UnificationRules, func(i, j int) bool {
+		return bundle.UnificationRules[i].Priority < bundle.UnificationRules[j].Priority
+	})
+
+	for _, category := range current.categories {
+		if category.IsMandatory {
+			continue
+		}
+		bundle.ConsentCategories = append(bundle.ConsentCategories, toBundleConsentCategory(category))
+	}
+	sort.Slice(bundle.ConsentCategories, func(i, j int) bool {
+		return bundle.ConsentCategories[i].CategoryName < bundle.ConsentCategories[j].CategoryName
+	})
+
+	return bundle, nil
+}
+
+// ImportSchemaBundle brings the organization's schema in line with the bundle. Entries missing from the
+// organization are created and differing ones are updated; nothing is deleted. Attributes are imported first so
+// that unification rules and consent categories can refer to them. The changes are made in a single transaction, so
+// a failed import changes nothing. On a dry run the changes are only computed.
+func (s *SchemaBundleService) ImportSchemaBundle(orgHandle string, bundle model.SchemaBundle,
+	dryRun bool) (*model.ImportSummary, error) {
+
+	if err := validateSchemaBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	current, err := loadOrgSchema(orgHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planImport(orgHandle, bundle, current)
+	if err != nil {
+		return nil, err
+	}
+	plan.summary.DryRun = dryRun
+	if dryRun {
+		return &plan.summary, nil
+	}
+
+	logger := log.GetLogger()
+	if err := plan.apply(orgHandle); err != nil {
+		logger.Debug(fmt.Sprintf("Profile schema import failed for organization: %s", orgHandle), log.Error(err))
+		failed := plan.failedSummary(err)
+		return &failed, err
+	}
+	logger.Info(fmt.Sprintf("Profile schema bundle imported for organization: %s", orgHandle))
+	return &plan.summary, nil
+}
+
+// importFailure describes the error an import failed with. Server errors keep their details to the logs.
+func importFailure(err error) *model.ImportFailure {
+
+	var clientError *errors2.ClientError
+	if errors.As(err, &clientError) {
+		return &model.ImportFailure{
+			Code:        clientError.Code,
+			Message:     clientError.Message,
+			Description: clientError.Description,
+			Details:     clientError.Details,
+		}
+	}
+	var serverError *errors2.ServerError
+	if errors.As(err, &serverError) {
+		return &model.ImportFailure{Code: serverError.Code, Message: serverError.Message}
+	}
+	return &model.ImportFailure{Message: "Internal server error"}
+}
+
+// orgSchema is the part of an organization's configuration a bundle covers.
+type orgSchema struct {
+	attributes []schemaModel.ProfileSchemaAttribute
+	rules      []unificationModel.UnificationRule
+	categories []consentModel.ConsentCategory
+}
+
+func loadOrgSchema(orgHandle string) (*orgSchema, error) {
+
+	attributes, err := schemaService.GetProfileSchemaService().GetProfileSchemaAttributesForOrg(orgHandle)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := unificationService.GetUnificationRuleService().GetUnificationRules(orgHandle)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := consentService.GetConsentCategoryService().GetAllConsentCategories(orgHandle)
+	if err != nil {
+		return nil, err
+	}
+	return &orgSchema{attributes: attributes, rules: rules, categories: categories}, nil
+}
+
+func toBundleAttribute(attr schemaModel.ProfileSchemaAttribute) model.BundleAttribute {
+	bundleAttr := model.BundleAttribute{
+		AttributeName:         attr.AttributeName,
+		DisplayName:           attr.DisplayName,
+		ValueType:             attr.ValueType,
+		MergeStrategy:         attr.MergeStrategy,
+		Mutability:            attr.Mutability,
+		ApplicationIdentifier: attr.ApplicationIdentifier,
+		MultiValued:           attr.MultiValued,
+		CanonicalValues:       attr.CanonicalValues,
+		Expression:            attr.Expression,
+		Constraints:           attr.Constraints,
+	}
+	for _, subAttr := range attr.SubAttributes {
+		bundleSubAttr := toBundleAttribute(subAttr)
+		// Sub-attributes always share the application of their parent.
+		bundleSubAttr.ApplicationIdentifier = ""
+		bundleAttr.SubAttributes = append(bundleAttr.SubAttributes, bundleSubAttr)
+	}
+	return bundleAttr
+}
+
+// fromBundleAttribute converts a bundle attribute into a schema attribute with nested sub-attribute definitions.
+func fromBundleAttribute(bundleAttr model.BundleAttribute, orgHandle, appId string) schemaModel.ProfileSchemaAttribute {
+	if bundleAttr.ApplicationIdentifier != "" {
+		appId = bundleAttr.ApplicationIdentifier
+	}
+	mutability := bundleAttr.Mutability
+	if mutability == "" {
+		mutability = constants.MutabilityReadWrite
+	}
+	attr := schemaModel.ProfileSchemaAttribute{
+		OrgId:                 orgHandle,
+		AttributeName:         bundleAttr.AttributeName,
+		DisplayName:           bundleAttr.DisplayName,
+		ValueType:             bundleAttr.ValueType,
+		MergeStrategy:         bundleAttr.MergeStrategy,
+		Mutability:            mutability,
+		ApplicationIdentifier: appId,
+		MultiValued:           bundleAttr.MultiValued,
+		CanonicalValues:       bundleAttr.CanonicalValues,
+		Expression:            bundleAttr.Expression,
+		Constraints:           bundleAttr.Constraints,
+	}
+	for _, bundleSubAttr := range bundleAttr.SubAttributes {
+		attr.SubAttributes = append(attr.SubAttributes, fromBundleAttribute(bundleSubAttr, orgHandle, appId))
+	}
+	return attr
+}
+
+func sortBundleAttributes(attrs []model.BundleAttribute) {
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].ApplicationIdentifier != attrs[j].ApplicationIdentifier {
+			return attrs[i].ApplicationIdentifier < attrs[j].ApplicationIdentifier
+		}
+		return attrs[i].AttributeName < attrs[j].AttributeName
+	})
+}
+
+func toBundleConsentCategory(category consentModel.ConsentCategory) model.BundleConsentCategory {
+	bundleCategory := model.BundleConsentCategory{
+		CategoryName:  category.CategoryName,
+		Purpose:       category.Purpose,
+		Destinations:  category.Destinations,
+		Localizations: category.Localizations,
+	}
+	for _, attr := range category.Attributes {
+		bundleCategory.Attributes = append(bundleCategory.Attributes, model.BundleConsentAttribute{
+			AttributeName:         attr.AttributeName,
+			ApplicationIdentifier: attr.ApplicationIdentifier,
+		})
+	}
+	return bundleCategory
+}
+
+// validateSchemaBundle checks the structure of the bundle. The entries themselves are validated by the services
+// that own them when the import is applied.
+func validateSchemaBundle(bundle model.SchemaBundle) error {
+
+	if bundle.Version != constants.SchemaBundleVersion {
+		return invalidBundle(fmt.Sprintf("Unsupported bundle version: %d. Supported version is %d.",
+			bundle.Version, constants.SchemaBundleVersion))
+	}
+
+	seen := make(map[string]bool)
+	sections := map[string][]model.BundleAttribute{
+		constants.Traits:          bundle.Traits,
+		constants.ApplicationData: bundle.ApplicationData,
+	}
+	for scope, attrs := range sections {
+		for _, attr := range attrs {
+			parts := strings.Split(attr.AttributeName, ".")
+			if len(parts) != 2 || parts[0] != scope || parts[1] == "" {
+				return invalidBundle(fmt.Sprintf("Attribute '%s' must be a top-level attribute of the '%s' scope.",
+					attr.AttributeName, scope))
+			}
+			if scope == constants.ApplicationData && attr.ApplicationIdentifier == "" {
+				return invalidBundle(fmt.Sprintf("Attribute '%s' requires an application_identifier.", attr.AttributeName))
+			}
+			if err := validateBundleSubAttributes(attr); err != nil {
+				return err
+			}
+			key := attributeKey(attr.AttributeName, attr.ApplicationIdentifier)
+			if seen[key] {
+				return invalidBundle(fmt.Sprintf("Attribute '%s' is listed more than once.", attr.AttributeName))
+			}
+			seen[key] = true
+		}
+	}
+
+	seen = make(map[string]bool)
+	for _, rule := range bundle.UnificationRules {
+		if rule.PropertyName == "" || rule.RuleName == "" {
+			return invalidBundle("rule_name and property_name are required for each unification rule.")
+		}
+		if seen[rule.PropertyName] {
+			return invalidBundle(fmt.Sprintf("More than one unification rule uses property '%s'.", rule.PropertyName))
+		}
+		seen[rule.PropertyName] = true
+	}
+
+	seen = make(map[string]bool)
+	for _, category := range bundle.ConsentCategories {
+		if category.CategoryName == "" {
+			return invalidBundle("category_name is required for each consent category.")
+		}
+		if seen[category.CategoryName] {
+			return invalidBundle(fmt.Sprintf("Consent category '%s' is listed more than once.", category.CategoryName))
+		}
+		seen[category.CategoryName] = true
+	}
+	return nil
+}
+
+func validateBundleSubAttributes(attr model.BundleAttribute) error {
+	for _, subAttr := range attr.SubAttributes {
+		if !strings.HasPrefix(subAttr.AttributeName, attr.AttributeName+".") ||
+			strings.Count(subAttr.AttributeName, ".") != strings.Count(attr.AttributeName, ".")+1 {
+			return invalidBundle(fmt.Sprintf("Sub-attribute '%s' must be exactly one level below '%s'.",
+				subAttr.AttributeName, attr.AttributeName))
+		}
+		if subAttr.ApplicationIdentifier != "" && subAttr.ApplicationIdentifier != attr.ApplicationIdentifier {
+			return invalidBundle(fmt.Sprintf("Sub-attribute '%s' must belong to the application of '%s'.",
+				subAttr.AttributeName, attr.AttributeName))
+		}
+		if err := validateBundleSubAttributes(subAttr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func attributeKey(attributeName, appId string) string {
+	return appId + "/" + attributeName
+}
+
+func invalidBundle(description string) error {
+	return errors2.NewClientError(errors2.ErrorMessage{
+		Code:        errors2.INVALID_SCHEMA_BUNDLE.Code,
+		Message:     errors2.INVALID_SCHEMA_BUNDLE.Message,
+		Description: description,
+	}, http.StatusBadRequest)
+}
@@ -486,12 +486,7 @@ func (c *IdentityClient) GetProfileSchema(orgHandle string) ([]model.ProfileSche
 		dialectURI := fmt.Sprintf("%v", dialect["dialectURI"])
 		dialectID := fmt.Sprintf("%v", dialect["id"])
 
-		// Only consider SCIM-based dialects
-		if !(strings.HasPrefix(dialectURI, "urn:scim:") || strings.HasPrefix(dialectURI, "urn:ietf:")) {
-			continue
-		}
-		// Skip SCIM core v1 dialect explicitly
-		if dialectURI == "urn:scim:schemas:core:1.0" {
+		if !IsSyncedDialect(dialectURI) {
 			continue
 		}
 
@@ -707,6 +702,20 @@ func (c *IdentityClient) GetLocalClaimsMap(orgId string) (map[string]map[string]
 	return claimMap, nil
 }
 
+// IsSyncedDialect reports whether the claims of the given dialect are synced into the profile schema. Only SCIM
+// dialects are, except the SCIM core v1 dialect.
+func IsSyncedDialect(dialectURI string) bool {
+	if !(strings.HasPrefix(dialectURI, "urn:scim:") || strings.HasPrefix(dialectURI, "urn:ietf:")) {
+		return false
+	}
+	return dialectURI != "urn:scim:schemas:core:1.0"
+}
+
+// IdentityAttributeName returns the name of the identity attribute a local claim is synced into.
+func IdentityAttributeName(localURI string) string {
+	return "identity_attributes." + extractClaimKeyFromLocalURI(localURI)
+}
+
 func extractClaimKeyFromLocalURI(localURI string) string {
 	parts := strings.Split(localURI, "/")
 	return parts[len(parts)-1]
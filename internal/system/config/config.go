@@ -78,6 +78,15 @@ type ExternalBrokerConfig struct {
 	// SchemaSyncQueueName is the destination used for schema sync
 	// messages (e.g. "/queue/cds-schema-sync").
 	SchemaSyncQueueName string `yaml:"schema_sync_queue_name"`
+	// ProfileDeadLetterQueueName is the destination profiles are moved to
+	// when their unification keeps failing. Defaults to ProfileQueueName
+	// with a ".dlq" suffix.
+	ProfileDeadLetterQueueName string `yaml:"profile_dead_letter_queue_name"`
+	// ManagementURL is the base URL of the broker's web console (e.g.
+	// "http://localhost:8161"). When set, the activemq provider reads the
+	// depth of its queues from the console's Jolokia API, authenticating
+	// with Username and Password.
+	ManagementURL string `yaml:"management_url"`
 }
 
 // MessageQueueConfig selects the queue provider and its settings. When Type
@@ -89,6 +98,64 @@ type MessageQueueConfig struct {
 	// any registered external provider (e.g. "activemq").
 	Type   string               `yaml:"type"`
 	Broker ExternalBrokerConfig `yaml:"broker"`
+	// Kafka holds the settings of the "kafka" provider, which does not use
+	// Broker.
+	Kafka KafkaConfig `yaml:"kafka"`
+	// Redelivery bounds how often a failed item is retried before it is
+	// dead-lettered.
+	Redelivery RedeliveryConfig `yaml:"redelivery"`
+	// Workers is the number of profiles each instance unifies concurrently.
+	// Defaults to the number of CPUs.
+	Workers int `yaml:"workers"`
+	// UnificationLock serialises the unifications of an organization:
+	// "memory" (default) within this instance, "postgres" across all
+	// instances sharing the database.
+	UnificationLock string `yaml:"unification_lock"`
+	// Outbox configures the relay publishing the profiles written to the
+	// unification outbox to the queue.
+	Outbox OutboxConfig `yaml:"outbox"`
+}
+
+// OutboxConfig configures the unification outbox relay.
+type OutboxConfig struct {
+	PollInterval int `yaml:"poll_interval"` // in milliseconds
+	BatchSize    int `yaml:"batch_size"`
+	Debounce     int `yaml:"debounce"` // in milliseconds
+	// MaxWait bounds the debounce: a profile whose oldest entry is older is
+	// published even if it is still being written.
+	MaxWait int `yaml:"max_wait"` // in milliseconds
+}
+
+// KafkaConfig holds the settings of the Kafka queue provider.
+type KafkaConfig struct {
+	// Brokers are the bootstrap servers (host:port).
+	Brokers  []string `yaml:"brokers"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	// Each topic is consumed by a consumer group of its own, shared by all
+	// CDS replicas so that each partition is consumed by one replica at a
+	// time. Separate groups keep a member joining or leaving the consumers of
+	// one topic from rebalancing the other.
+	ProfileConsumerGroup    string `yaml:"profile_consumer_group"`
+	SchemaSyncConsumerGroup string `yaml:"schema_sync_consumer_group"`
+	ProfileTopic            string `yaml:"profile_topic"`
+	SchemaSyncTopic         string `yaml:"schema_sync_topic"`
+	// DeadLetterTopic should be log-compacted: replaying a dead letter
+	// writes a tombstone for it. Defaults to ProfileTopic with a ".dlq"
+	// suffix.
+	DeadLetterTopic string `yaml:"dead_letter_topic"`
+	// PartitionKey selects what profile messages are partitioned by: "org"
+	// (default) serialises all unifications of an organization, "profile"
+	// only those of the same profile.
+	PartitionKey string `yaml:"partition_key"`
+}
+
+// RedeliveryConfig configures the retries of queue items whose processing
+// fails. Backoff doubles after every attempt, up to MaxBackoff.
+type RedeliveryConfig struct {
+	MaxAttempts    int `yaml:"max_attempts"`
+	InitialBackoff int `yaml:"initial_backoff"` // in milliseconds
+	MaxBackoff     int `yaml:"max_backoff"`     // in milliseconds
 }
 
 // Application identifier types.
@@ -106,9 +173,26 @@ type Config struct {
 	DataSource   DataSourceConfig   `yaml:"datasource"`
 	TLS          TLSConfig          `yaml:"tls"`
 	Cleanup      CleanupConfig      `yaml:"cleanup"`
+	Sync         SyncConfig         `yaml:"sync"`
 	MessageQueue MessageQueueConfig `yaml:"message_queue"`
+	// ConsentReceipt describes the PII controller named on the Kantara consent receipts issued for consent changes.
+	ConsentReceipt ConsentReceiptConfig `yaml:"consent_receipt"`
+	// Consent holds the consent category settings, such as the locale localizations fall back to.
+	Consent ConsentConfig `yaml:"consent"`
 	// ApplicationIdentifierType selects how applications are identified: "client_id" (default) or "app_id".
 	ApplicationIdentifierType string `yaml:"application_identifier_type"`
+	// Shutdown bounds the drain that precedes the exit of the server.
+	Shutdown ShutdownConfig `yaml:"shutdown"`
+}
+
+// ShutdownConfig configures the drain that runs when the server is asked to stop. Pending queue items that are not
+// processed within the drain timeout are persisted and resumed by the next start.
+type ShutdownConfig struct {
+	// PreStopDelay is how long the server keeps serving while reporting not
+	// ready before it stops listening; 0 uses the default, and a negative
+	// value skips the wait.
+	PreStopDelay int `yaml:"pre_stop_delay"` // in milliseconds
+	DrainTimeout int `yaml:"drain_timeout"`  // in milliseconds
 }
 
 // UsesAppIDIdentifier reports whether applications are identified by the app ID.
@@ -125,10 +209,65 @@ type TLSConfig struct {
 	TrustStore              string `yaml:"trust_store"`
 }
 
+type ConsentConfig struct {
+	DefaultLocale string `yaml:"default_locale"`
+}
+
+type ConsentReceiptConfig struct {
+	Jurisdiction string                   `yaml:"jurisdiction"`
+	Language     string                   `yaml:"language"`
+	PolicyURL    string                   `yaml:"policy_url"`
+	Controller   ConsentReceiptController `yaml:"controller"`
+}
+
+type ConsentReceiptController struct {
+	Name    string `yaml:"name"`
+	Contact string `yaml:"contact"`
+	Address string `yaml:"address"`
+	Email   string `yaml:"email"`
+	Phone   string `yaml:"phone"`
+	URL     string `yaml:"url"`
+}
+
+// MissingFields lists the settings a consent receipt requires that are not configured, by their key in the
+// consent_receipt block. The receipts issued meanwhile carry empty values for them.
+func (c ConsentReceiptConfig) MissingFields() []string {
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"jurisdiction", c.Jurisdiction},
+		{"language", c.Language},
+		{"policy_url", c.PolicyURL},
+		{"controller.name", c.Controller.Name},
+		{"controller.contact", c.Controller.Contact},
+		{"controller.address", c.Controller.Address},
+		{"controller.email", c.Controller.Email},
+		{"controller.phone", c.Controller.Phone},
+	}
+	var missing []string
+	for _, field := range required {
+		if field.value == "" {
+			missing = append(missing, field.key)
+		}
+	}
+	return missing
+}
+
 type CleanupConfig struct {
 	Cookie CookieCleanupConfig `yaml:"cookie"`
 }
 
+type SyncConfig struct {
+	Schema SchemaSyncConfig `yaml:"schema"`
+}
+
+// SchemaSyncConfig configures the periodic check of the schema for drift from the identity server claims.
+type SchemaSyncConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	Interval int  `yaml:"interval"` // in seconds
+}
+
 type CookieCleanupConfig struct {
 	Enabled   bool `yaml:"enabled"`
 	Interval  int  `yaml:"interval"` // in seconds
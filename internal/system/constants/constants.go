@@ -33,6 +33,29 @@ const DefaultTenant = "carbon.super"
 const SpaceSeparator = " "
 const SystemAppHeader = "SystemApp"
 const DefaultQueueSize = 1000
+
+// Redelivery of failed queue items
+const (
+	DefaultRedeliveryMaxAttempts    = 5
+	DefaultRedeliveryInitialBackoff = 1000  // in milliseconds
+	DefaultRedeliveryMaxBackoff     = 30000 // in milliseconds
+	DeadLetterQueueSuffix           = ".dlq"
+)
+
+// Unification outbox relay
+const (
+	DefaultOutboxPollInterval = 1000 // in milliseconds
+	DefaultOutboxBatchSize    = 100
+	DefaultOutboxDebounce     = 500  // in milliseconds
+	DefaultOutboxMaxWait      = 5000 // in milliseconds
+)
+
+// Shutdown drain; the pre-stop delay and the drain together should end well within the termination grace period of
+// the platform (30s on Kubernetes).
+const (
+	DefaultShutdownPreStopDelay = 5000  // in milliseconds
+	DefaultShutdownDrainTimeout = 20000 // in milliseconds
+)
 const DefaultLimit = 50
 const CONSOLE_APP = "CONSOLE"
 const AZPClaim = "azp"
@@ -56,6 +79,12 @@ const (
 	UnificationRuleResource = "unification rule"
 	SchemaAttribute         = "schema attribute"
 	AdminConfigResource     = "admin config"
+	ApplicationResource     = "application"
+	SchemaBundleResource    = "schema bundle"
+	SchemaMigrationResource = "schema migration"
+	SchemaDriftResource     = "schema drift"
+	DeadLetterResource      = "dead letter"
+	JobResource             = "job"
 )
 
 const (
@@ -105,12 +134,29 @@ const (
 )
 
 const (
-	MergeStrategyOverwrite = "overwrite" // Overwrite the existing value with the new one.
-	MergeStrategyLatest    = "latest"
-	MergeStrategyCombine   = "combine" // Combine values from both profiles (e.g., arrays).
-	MergeStrategyOldest    = "oldest"  // Use the oldest value from the profiles being merged.
+	MergeStrategyOverwrite      = "overwrite"       // Overwrite the existing value with the new one.
+	MergeStrategyLatest         = "latest"          // Use the most recently written value from the profiles being merged.
+	MergeStrategyCombine        = "combine"         // Combine values from both profiles (e.g., arrays).
+	MergeStrategyOldest         = "oldest"          // Use the oldest value from the profiles being merged.
+	MergeStrategySourcePriority = "source_priority" // Use the value written by the most trusted application.
 )
 
+// Built-in formats a string attribute can be constrained to.
+const (
+	ConstraintFormatEmail      = "email"       // RFC 5322 address, e.g. ada@example.com
+	ConstraintFormatE164       = "e164"        // E.164 phone number, e.g. +94771234567
+	ConstraintFormatURI        = "uri"         // Absolute URI with a scheme
+	ConstraintFormatISOCountry = "iso_country" // ISO 3166-1 alpha-2 country code, e.g. LK
+)
+
+// AllowedConstraintFormats defines the valid set of attribute constraint formats.
+var AllowedConstraintFormats = map[string]bool{
+	ConstraintFormatEmail:      true,
+	ConstraintFormatE164:       true,
+	ConstraintFormatURI:        true,
+	ConstraintFormatISOCountry: true,
+}
+
 // AllowedMutabilityValues defines the valid set of mutability types.
 var AllowedMutabilityValues = map[string]bool{
 	MutabilityReadWrite: true, // Can be both read and updated freely.
@@ -118,6 +164,7 @@ var AllowedMutabilityValues = map[string]bool{
 	MutabilityWriteOnly: true, // Can be written but not read back (e.g., passwords).
 	MutabilityImmutable: true, // Must be set at creation and cannot be changed later (created time)
 	MutabilityWriteOnce: true, // Can be empty initially, but once set, cannot be updated. (userId)
+	MutabilityComputed:  true, // Derived from an expression over other attributes; read-only on input.
 }
 
 var AllowedAttributesScope = map[string]bool{
@@ -138,8 +185,11 @@ const (
 )
 
 var AllowedMergeStrategies = map[string]bool{
-	"combine":   true, // Combine values from both profiles (the value type has to be arrayOfString or arrayOfInt)
-	"overwrite": true, // todo: Remove later.
+	"combine":                   true, // Combine values from both profiles (the value type has to be arrayOfString or arrayOfInt)
+	"overwrite":                 true, // todo: Remove later.
+	MergeStrategyLatest:         true, // Keep the value with the most recent write timestamp.
+	MergeStrategyOldest:         true, // Keep the value with the oldest write timestamp.
+	MergeStrategySourcePriority: true, // Keep the value from the application ranked highest in the org's source_priority.
 }
 
 var AllowedConsentPurposes = map[string]bool{
@@ -153,6 +203,15 @@ const (
 	DefaultIdentityDataCategoryPurpose = "profiling"
 )
 
+// DefaultConsentLocale is the locale consent category localizations fall back to when none is configured.
+const DefaultConsentLocale = "en"
+
+// MaxPurposeDescriptionLength caps the long-form purpose description of a consent category localization.
+const MaxPurposeDescriptionLength = 2000
+
+// BCP47LocaleRegex matches a BCP-47 language tag such as "en", "fr-CA" or "zh-Hant-TW".
+var BCP47LocaleRegex = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
 const (
 	ScopeIdentityAttributes = "identityAttributes"
 	ScopeTraits             = "traits"
@@ -202,6 +261,7 @@ const (
 	ConfigCDSEnabled            = "cds_enabled"
 	ConfigInitialSchemaSyncDone = "initial_schema_sync_done"
 	ConfigSystemApplications    = "system_applications"
+	ConfigSourcePriority        = "source_priority"
 )
 
 const (
@@ -209,5 +269,52 @@ const (
 )
 
 const (
-	DefaultCookieCleanupTime = 24 * 60 * 60 // 24 hours in seconds
+	DefaultCookieCleanupTime    = 24 * 60 * 60 // 24 hours in seconds
+	DefaultSchemaDriftCheckTime = 60 * 60      // 1 hour in seconds
+)
+
+// Profile schema bundles
+const (
+	SchemaBundleVersion    = 1 // Version of the bundle layout written by the export
+	SchemaBundleFormatJSON = "json"
+	SchemaBundleFormatYAML = "yaml"
+)
+
+// Profile schema representations
+const (
+	ProfileSchemaFormatJSONSchema = "jsonschema"
+	JSONSchemaDialect             = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// Schema migration statuses and limits
+const (
+	SchemaMigrationPending    = "pending"
+	SchemaMigrationRunning    = "running"
+	SchemaMigrationCompleted  = "completed"
+	SchemaMigrationFailed     = "failed"
+	SchemaMigrationSuperseded = "superseded" // Stopped in favour of a later change to the same attribute
+
+	SchemaMigrationConvert = "convert" // Converts values to the attribute's new definition
+	SchemaMigrationPurge   = "purge"   // Removes the values of a deleted attribute
+
+	SchemaMigrationBatchSize         = 200 // Profiles converted between progress updates
+	SchemaMigrationFailureSampleSize = 100 // Failures kept on a migration or preview
+
+	SchemaMigrationHeartbeatInterval = 30000  // in milliseconds
+	SchemaMigrationStaleTimeout      = 120000 // in milliseconds; an unfinished migration not reported for this long is resumed elsewhere
+)
+
+// Background job statuses and types
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+
+	JobTypeSchemaSync       = "schema_sync"
+	JobTypeCookieCleanup    = "cookie_cleanup"
+	JobTypeSchemaDriftCheck = "schema_drift_check"
+
+	JobHeartbeatInterval = 30000  // in milliseconds
+	JobStaleTimeout      = 120000 // in milliseconds; a running job that has not reported for this long is failed
 )
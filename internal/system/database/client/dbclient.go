@@ -53,6 +53,50 @@ func (client *DBClient) ExecuteQuery(query string, args ...interface{}) ([]map[s
 	if err != nil {
 		return nil, err
 	}
+	return scanRows(rows)
+}
+
+// BeginTx starts a new database transaction.
+func (client *DBClient) BeginTx() (*sql.Tx, error) {
+
+	return client.db.Begin()
+}
+
+// Close closes the database connection.
+func (c *DBClient) Close() error {
+	if os.Getenv("TEST_MODE") == "true" {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// TxClient executes queries inside an open transaction, so that the writes of several store calls
+// are committed or rolled back together.
+type TxClient struct {
+	tx *sql.Tx
+}
+
+// NewTxClient creates a TxClient running its queries in the given transaction.
+func NewTxClient(tx *sql.Tx) *TxClient {
+
+	return &TxClient{
+		tx: tx,
+	}
+}
+
+// ExecuteQuery executes a query in the transaction and returns the result as a slice of maps.
+func (client *TxClient) ExecuteQuery(query string, args ...interface{}) ([]map[string]interface{}, error) {
+
+	rows, err := client.tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanRows(rows)
+}
+
+// scanRows reads all rows into maps keyed by the lowercased column names, and closes the rows.
+func scanRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+
 	defer rows.Close()
 
 	columns, err := rows.Columns()
@@ -80,19 +124,5 @@ func (client *DBClient) ExecuteQuery(query string, args ...interface{}) ([]map[s
 		results = append(results, result)
 	}
 
-	return results, nil
-}
-
-// BeginTx starts a new database transaction.
-func (client *DBClient) BeginTx() (*sql.Tx, error) {
-
-	return client.db.Begin()
-}
-
-// Close closes the database connection.
-func (c *DBClient) Close() error {
-	if os.Getenv("TEST_MODE") == "true" {
-		return nil
-	}
-	return c.db.Close()
+	return results, rows.Err()
 }
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package provider
+
+import (
+	"database/sql"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// WithTransaction runs fn in a single database transaction. The transaction is committed when fn succeeds and
+// rolled back when it fails, so that none of the writes made through it are persisted. Failures to begin or commit
+// the transaction are reported as server errors with the given error message.
+func WithTransaction(errorMessage errors.ErrorMessage, fn func(tx *sql.Tx) error) error {
+
+	logger := log.GetLogger()
+	serverError := func(description string, err error) error {
+		logger.Debug(description, log.Error(err))
+		return errors.NewServerError(errors.ErrorMessage{
+			Code:        errorMessage.Code,
+			Message:     errorMessage.Message,
+			Description: description,
+		}, err)
+	}
+
+	dbClient, err := NewDBProvider().GetDBClient()
+	if err != nil {
+		return serverError("Failed to get database client for starting a transaction", err)
+	}
+	defer dbClient.Close()
+
+	tx, err := dbClient.BeginTx()
+	if err != nil {
+		return serverError("Failed to begin transaction", err)
+	}
+	if err := fn(tx); err != nil {
+		if errRoll := tx.Rollback(); errRoll != nil {
+			logger.Debug("Failed to rollback transaction", log.Error(errRoll))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return serverError("Failed to commit transaction", err)
+	}
+	return nil
+}
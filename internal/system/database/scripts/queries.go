@@ -34,6 +34,21 @@ var GetAppIdentifierByClientID = map[string]string{
 		WHERE org_handle = $1 AND client_id = $2 LIMIT 1`,
 }
 
+// UpsertApplicationPurposes inserts or replaces the processing purposes declared for an application.
+var UpsertApplicationPurposes = map[string]string{
+	"postgres": `INSERT INTO application_purposes (org_handle, app_identifier, purposes, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (org_handle, app_identifier) DO UPDATE SET
+			purposes   = EXCLUDED.purposes,
+			updated_at = now()`,
+}
+
+// GetApplicationPurposes fetches the processing purposes declared for an application.
+var GetApplicationPurposes = map[string]string{
+	"postgres": `SELECT array_to_json(purposes)::text AS purposes FROM application_purposes
+		WHERE org_handle = $1 AND app_identifier = $2`,
+}
+
 var DeleteProfileSchemaForOrg = map[string]string{
 	"postgres": `
         DELETE FROM profile_schema WHERE org_handle = $1 AND scope != 'identity_attributes' `,
@@ -41,7 +56,7 @@ var DeleteProfileSchemaForOrg = map[string]string{
 
 var GetProfileSchemaByOrg = map[string]string{
 	"postgres": `SELECT attribute_id, attribute_name, display_name, value_type, merge_strategy , application_identifier, mutability, 
-       multi_valued, sub_attributes::text, canonical_values::text FROM profile_schema WHERE org_handle = $1`,
+       multi_valued, sub_attributes::text, canonical_values::text, expression, constraints::text FROM profile_schema WHERE org_handle = $1`,
 }
 
 var DeleteIdentityClaimsOfProfileSchema = map[string]string{
@@ -76,17 +91,17 @@ var UpsertIdentityClaimsForProfileSchema = map[string]string{
 
 var GetProfileSchemaAttributeByName = map[string]string{
 	"postgres": `SELECT attribute_id, attribute_name, display_name, value_type, merge_strategy, mutability, application_identifier,
-       multi_valued, sub_attributes::text, canonical_values::text, scope FROM profile_schema WHERE org_handle = $1
-       AND attribute_name = $2 LIMIT 1`,
+       multi_valued, sub_attributes::text, canonical_values::text, scope, scim_dialect, expression, constraints::text FROM profile_schema
+       WHERE org_handle = $1 AND attribute_name = $2 LIMIT 1`,
 }
 
 var InsertProfileSchemaAttributesForScope = map[string]string{
 	"postgres": `INSERT INTO profile_schema (org_handle, attribute_id, attribute_name, value_type, merge_strategy, 
-                            application_identifier, mutability, multi_valued, sub_attributes, canonical_values, scope, display_name) VALUES `,
+                            application_identifier, mutability, multi_valued, sub_attributes, canonical_values, scope, display_name, expression, constraints) VALUES `,
 }
 var GetProfileSchemaAttributeByScope = map[string]string{
 	"postgres": `SELECT attribute_id, org_handle, attribute_name, display_name, value_type, merge_strategy, mutability, application_identifier, multi_valued,   sub_attributes::text,
-  canonical_values::text FROM profile_schema WHERE org_handle = $1 AND scope = $2`,
+  canonical_values::text, expression, constraints::text FROM profile_schema WHERE org_handle = $1 AND scope = $2`,
 }
 
 var UpdateProfileSchemaAttributesForSchema = map[string]string{
@@ -100,8 +115,10 @@ var UpdateProfileSchemaAttributesForSchema = map[string]string{
 			multi_valued = $6,
 			canonical_values = $7,
 			sub_attributes = $8,
-			display_name = $9
-		WHERE org_handle = $10 AND attribute_id = $11 AND scope = $12
+			display_name = $9,
+			expression = $10,
+			constraints = $11
+		WHERE org_handle = $12 AND attribute_id = $13 AND scope = $14
 	`,
 }
 
@@ -111,13 +128,13 @@ var DeleteProfileSchemaAttributeForScope = map[string]string{
 
 var GetProfileSchemaAttributeById = map[string]string{
 	"postgres": `SELECT attribute_id, attribute_name, display_name, value_type, merge_strategy, mutability, application_identifier, multi_valued, sub_attributes::text,
-  canonical_values::text, scope
+  canonical_values::text, scope, expression, constraints::text
 	          FROM profile_schema WHERE org_handle = $1 AND attribute_id = $2`,
 }
 
 var FilterProfileSchemaAttributes = map[string]string{
 	"postgres": `SELECT attribute_id, org_handle, attribute_name, display_name, value_type, merge_strategy, mutability, application_identifier, multi_valued, sub_attributes::text,
-  canonical_values::text FROM profile_schema WHERE org_handle = $1`,
+  canonical_values::text, expression, constraints::text FROM profile_schema WHERE org_handle = $1`,
 }
 
 var DeleteProfileSchemaAttributeById = map[string]string{
@@ -149,8 +166,8 @@ var UpdateUnificationRule = map[string]string{
 var InsertProfile = map[string]string{
 	"postgres": `
 		INSERT INTO profiles (
-		profile_id, user_id, org_handle, created_at, updated_at, location, list_profile, delete_profile, traits, identity_attributes
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		profile_id, user_id, org_handle, created_at, updated_at, location, list_profile, delete_profile, traits, identity_attributes, attribute_metadata
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	ON CONFLICT (profile_id) DO NOTHING;`,
 }
 
@@ -164,7 +181,7 @@ var InsertProfileReference = map[string]string{
 var GetProfileById = map[string]string{
 	"postgres": `
 		SELECT p.profile_id, p.user_id, p.created_at, p.updated_at,p.location, p.org_handle, p.list_profile, p.delete_profile, 
-		       p.traits, p.identity_attributes, r.profile_status, r.reference_profile_id, r.reference_reason
+		       p.traits, p.identity_attributes, p.attribute_metadata, r.profile_status, r.reference_profile_id, r.reference_reason
 		FROM 
 			profiles p
 		LEFT JOIN 
@@ -174,15 +191,25 @@ var GetProfileById = map[string]string{
 }
 
 var GetProfileConsentsByProfileId = map[string]string{
-	"postgres": `SELECT profile_id, category_id, consent_status, consented_at FROM profile_consents WHERE profile_id = $1;`,
+	"postgres": `SELECT profile_id, category_id, consent_status, consented_at, receipt_id FROM profile_consents WHERE profile_id = $1 AND org_handle = $2;`,
 }
 
 var DeleteProfileConsentsByProfileId = map[string]string{
-	"postgres": `DELETE FROM profile_consents WHERE profile_id = $1;`,
+	"postgres": `DELETE FROM profile_consents WHERE profile_id = $1 AND org_handle = $2;`,
 }
 
 var InsertProfileConsentsByProfileId = map[string]string{
-	"postgres": `INSERT INTO profile_consents (profile_id, category_id, consent_status, consented_at) VALUES ($1, $2, $3, $4)`,
+	"postgres": `INSERT INTO profile_consents (profile_id, org_handle, category_id, consent_status, consented_at, receipt_id) VALUES ($1, $2, $3, $4, $5, $6)`,
+}
+
+// InsertConsentReceipt persists the consent receipt issued for a profile's consent change.
+var InsertConsentReceipt = map[string]string{
+	"postgres": `INSERT INTO profile_consent_receipts (receipt_id, profile_id, org_handle, receipt, created_at) VALUES ($1, $2, $3, $4, $5)`,
+}
+
+// GetConsentReceipt fetches a consent receipt issued for a profile.
+var GetConsentReceipt = map[string]string{
+	"postgres": `SELECT receipt_id, profile_id, org_handle, receipt::text AS receipt, created_at FROM profile_consent_receipts WHERE profile_id = $1 AND org_handle = $2 AND receipt_id = $3`,
 }
 
 var GetAppDataByProfileId = map[string]string{
@@ -205,8 +232,9 @@ var UpdateProfile = map[string]string{
 			delete_profile = $3,
 			traits = $4,
 			identity_attributes = $5,
-			updated_at = $6
-		 WHERE profile_id = $7;`,
+			updated_at = $6,
+			attribute_metadata = $7
+		 WHERE profile_id = $8;`,
 }
 
 var UpsertProfileReference = map[string]string{
@@ -310,7 +338,8 @@ var GetAllReferenceProfileExceptCurrent = map[string]string{
 		p.delete_profile,
 		p.list_profile, 
 		p.traits, 
-		p.identity_attributes
+		p.identity_attributes,
+		p.attribute_metadata
 	FROM 
 		profiles p
 	JOIN 
@@ -321,6 +350,11 @@ var GetAllReferenceProfileExceptCurrent = map[string]string{
 		AND p.org_handle = $2;`,
 }
 
+// UpdateProfileAttributeMetadata replaces the per-attribute write metadata of a profile.
+var UpdateProfileAttributeMetadata = map[string]string{
+	"postgres": `UPDATE profiles SET attribute_metadata = $1 WHERE profile_id = $2;`,
+}
+
 var FetchReferencedProfiles = map[string]string{
 	"postgres": `
 		SELECT profile_id, reference_reason, profile_status 
@@ -331,7 +365,7 @@ var FetchReferencedProfiles = map[string]string{
 var GetProfileByUserId = map[string]string{
 	"postgres": `
 		SELECT p.profile_id, p.user_id, p.created_at, p.updated_at,p.location, p.org_handle, p.list_profile, p.delete_profile, 
-		       p.traits, p.identity_attributes, r.profile_status, r.reference_profile_id, r.reference_reason
+		       p.traits, p.identity_attributes, p.attribute_metadata, r.profile_status, r.reference_profile_id, r.reference_reason
 		FROM 
 			profiles p
 		LEFT JOIN 
@@ -342,8 +376,8 @@ var GetProfileByUserId = map[string]string{
 }
 
 var InsertConsentCategory = map[string]string{
-	"postgres": `INSERT INTO consent_categories (category_name, category_identifier, org_handle, purpose, destinations, is_mandatory)
-				VALUES ($1, $2, $3, $4, $5, $6)`,
+	"postgres": `INSERT INTO consent_categories (category_name, category_identifier, org_handle, purpose, destinations, is_mandatory, localizations)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`,
 }
 
 var UpsertDefaultIdentityDataCategory = map[string]string{
@@ -355,41 +389,46 @@ var UpsertDefaultIdentityDataCategory = map[string]string{
 }
 
 var GetAllConsentCategories = map[string]string{
-	"postgres": `SELECT category_name, category_identifier, org_handle, purpose, destinations, is_mandatory FROM consent_categories`,
+	"postgres": `SELECT category_name, category_identifier, org_handle, purpose, destinations, is_mandatory, localizations::text AS localizations FROM consent_categories WHERE org_handle = $1`,
 }
 
 var GetConsentCategoryById = map[string]string{
-	"postgres": `SELECT category_name, category_identifier, org_handle, purpose, destinations, is_mandatory FROM consent_categories WHERE category_identifier = $1`,
+	"postgres": `SELECT category_name, category_identifier, org_handle, purpose, destinations, is_mandatory, localizations::text AS localizations FROM consent_categories WHERE category_identifier = $1 AND org_handle = $2`,
 }
 
 var GetConsentCategoryByName = map[string]string{
-	"postgres": `SELECT category_name, category_identifier, org_handle, purpose, destinations, is_mandatory FROM consent_categories WHERE category_name = $1 AND org_handle = $2`,
+	"postgres": `SELECT category_name, category_identifier, org_handle, purpose, destinations, is_mandatory, localizations::text AS localizations FROM consent_categories WHERE category_name = $1 AND org_handle = $2`,
 }
 
 var GetMandatoryConsentCategoryIdsByOrg = map[string]string{
 	"postgres": `SELECT category_identifier FROM consent_categories WHERE org_handle = $1 AND is_mandatory = TRUE`,
 }
 
+// GetConsentCategoryIdsByPurposes fetches the identifiers of the org's categories that serve any of the given purposes.
+var GetConsentCategoryIdsByPurposes = map[string]string{
+	"postgres": `SELECT category_identifier FROM consent_categories WHERE org_handle = $1 AND purpose = ANY($2)`,
+}
+
 var UpdateConsentCategory = map[string]string{
-	"postgres": `UPDATE consent_categories SET category_name=$1, purpose=$2, destinations=$3 WHERE category_identifier=$4`,
+	"postgres": `UPDATE consent_categories SET category_name=$1, purpose=$2, destinations=$3, localizations=$4 WHERE category_identifier=$5 AND org_handle=$6`,
 }
 
 var DeleteConsentCategory = map[string]string{
-	"postgres": `DELETE FROM consent_categories WHERE category_identifier=$1`,
+	"postgres": `DELETE FROM consent_categories WHERE category_identifier=$1 AND org_handle=$2`,
 }
 
 var InsertConsentCategoryAttribute = map[string]string{
-	"postgres": `INSERT INTO consent_category_attributes (category_id, scope, attribute_name, attribute_id, application_identifier)
-				VALUES ($1, $2, $3, $4, $5)
-				ON CONFLICT (category_id, scope, attribute_name, application_identifier) DO NOTHING`,
+	"postgres": `INSERT INTO consent_category_attributes (org_handle, category_id, scope, attribute_name, attribute_id, application_identifier)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (org_handle, category_id, scope, attribute_name, application_identifier) DO NOTHING`,
 }
 
 var GetConsentCategoryAttributesByCategoryId = map[string]string{
-	"postgres": `SELECT scope, attribute_name, attribute_id, application_identifier FROM consent_category_attributes WHERE category_id = $1`,
+	"postgres": `SELECT scope, attribute_name, attribute_id, application_identifier FROM consent_category_attributes WHERE category_id = $1 AND org_handle = $2`,
 }
 
 var DeleteConsentCategoryAttributesByCategoryId = map[string]string{
-	"postgres": `DELETE FROM consent_category_attributes WHERE category_id = $1`,
+	"postgres": `DELETE FROM consent_category_attributes WHERE category_id = $1 AND org_handle = $2`,
 }
 
 var InsertCookie = map[string]string{
@@ -429,6 +468,10 @@ var GetOrgConfigurations = map[string]string{
 	"postgres": `SELECT config, value FROM cds_config WHERE org_handle = $1`,
 }
 
+var GetOrgsWithConfigValue = map[string]string{
+	"postgres": `SELECT org_handle FROM cds_config WHERE config = $1 AND value = $2 ORDER BY org_handle`,
+}
+
 var UpdateOrgConfiguration = map[string]string{
 	"postgres": `INSERT INTO cds_config (org_handle, config, value) 
                  VALUES ($1, $2, $3) 
@@ -446,3 +489,271 @@ var UpdateInitialSchemaSyncDoneConfig = map[string]string{
                  ON CONFLICT (org_handle, config) 
                  DO UPDATE SET value = EXCLUDED.value`,
 }
+
+// Profile attribute values. %[1]s is the profiles JSONB column holding the attribute's scope; $path is the JSON
+// path of the attribute inside it.
+
+// CountProfileAttributeValues counts the profiles of an org holding a value for an attribute.
+var CountProfileAttributeValues = map[string]string{
+	"postgres": `SELECT COUNT(*) AS count FROM profiles
+		WHERE org_handle = $1 AND %[1]s #> $2::text[] IS NOT NULL`,
+}
+
+// GetProfileAttributeValues pages through the stored values of an attribute, ordered by profile id.
+var GetProfileAttributeValues = map[string]string{
+	"postgres": `SELECT profile_id, (%[1]s #> $2::text[])::text AS value FROM profiles
+		WHERE org_handle = $1 AND %[1]s #> $2::text[] IS NOT NULL AND profile_id > $3
+		ORDER BY profile_id LIMIT $4`,
+}
+
+// ReplaceProfileAttributeValue sets the value of an attribute, provided it still holds the expected value.
+var ReplaceProfileAttributeValue = map[string]string{
+	"postgres": `UPDATE profiles SET %[1]s = jsonb_set(%[1]s, $2::text[], $3::jsonb)
+		WHERE profile_id = $4 AND %[1]s #> $2::text[] = $5::jsonb RETURNING profile_id`,
+}
+
+// RemoveProfileAttributeValue removes the value of an attribute, provided it still holds the expected value.
+var RemoveProfileAttributeValue = map[string]string{
+	"postgres": `UPDATE profiles SET %[1]s = %[1]s #- $2::text[]
+		WHERE profile_id = $3 AND %[1]s #> $2::text[] = $4::jsonb RETURNING profile_id`,
+}
+
+// CountApplicationAttributeValues counts the profiles of an org holding a value for an application data attribute.
+var CountApplicationAttributeValues = map[string]string{
+	"postgres": `SELECT COUNT(*) AS count FROM application_data a JOIN profiles p ON p.profile_id = a.profile_id
+		WHERE p.org_handle = $1 AND a.app_id = $2 AND a.application_data #> $3::text[] IS NOT NULL`,
+}
+
+// GetApplicationAttributeValues pages through the stored values of an application data attribute.
+var GetApplicationAttributeValues = map[string]string{
+	"postgres": `SELECT a.profile_id, (a.application_data #> $3::text[])::text AS value
+		FROM application_data a JOIN profiles p ON p.profile_id = a.profile_id
+		WHERE p.org_handle = $1 AND a.app_id = $2 AND a.application_data #> $3::text[] IS NOT NULL
+			AND a.profile_id > $4
+		ORDER BY a.profile_id LIMIT $5`,
+}
+
+// ReplaceApplicationAttributeValue sets the value of an application data attribute, provided it still holds the
+// expected value.
+var ReplaceApplicationAttributeValue = map[string]string{
+	"postgres": `UPDATE application_data SET application_data = jsonb_set(application_data, $3::text[], $4::jsonb)
+		WHERE profile_id = $1 AND app_id = $2 AND application_data #> $3::text[] = $5::jsonb RETURNING profile_id`,
+}
+
+// RemoveApplicationAttributeValue removes the value of an application data attribute, provided it still holds the
+// expected value.
+var RemoveApplicationAttributeValue = map[string]string{
+	"postgres": `UPDATE application_data SET application_data = application_data #- $3::text[]
+		WHERE profile_id = $1 AND app_id = $2 AND application_data #> $3::text[] = $4::jsonb RETURNING profile_id`,
+}
+
+var InsertSchemaMigration = map[string]string{
+	"postgres": `INSERT INTO profile_schema_migrations (migration_id, org_handle, attribute_id, attribute_name,
+		application_identifier, operation, source_definition, target_definition, status, owner, created_at,
+		updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)`,
+}
+
+// UpdateSchemaMigrationProgress records the progress of a migration unless it has been superseded or claimed by
+// another instance.
+var UpdateSchemaMigrationProgress = map[string]string{
+	"postgres": `UPDATE profile_schema_migrations SET status = $2, total_profiles = $3, processed_profiles = $4,
+		converted_values = $5, failed_values = $6, failures = $7, error = $8, updated_at = $9
+		WHERE migration_id = $1 AND owner = $10 AND status <> 'superseded' RETURNING migration_id`,
+}
+
+// TouchSchemaMigration records a heartbeat of an unfinished migration still owned by the instance running it.
+var TouchSchemaMigration = map[string]string{
+	"postgres": `UPDATE profile_schema_migrations SET updated_at = $3
+		WHERE migration_id = $1 AND owner = $2 AND status IN ('pending', 'running')`,
+}
+
+var GetSchemaMigration = map[string]string{
+	"postgres": `SELECT migration_id, org_handle, attribute_id, attribute_name, application_identifier, operation,
+		source_definition::text, target_definition::text, status, total_profiles, processed_profiles,
+		converted_values, failed_values, failures::text, error, created_at, updated_at
+		FROM profile_schema_migrations WHERE org_handle = $1 AND migration_id = $2`,
+}
+
+var GetSchemaMigrationsByAttribute = map[string]string{
+	"postgres": `SELECT migration_id, org_handle, attribute_id, attribute_name, application_identifier, operation,
+		source_definition::text, target_definition::text, status, total_profiles, processed_profiles,
+		converted_values, failed_values, failures::text, error, created_at, updated_at
+		FROM profile_schema_migrations WHERE org_handle = $1 AND attribute_id = $2 ORDER BY created_at DESC`,
+}
+
+// ClaimStaleSchemaMigration hands the oldest unfinished migration without a heartbeat since $3 to the owner $1.
+// Migrations being claimed by other instances are skipped rather than waited for.
+var ClaimStaleSchemaMigration = map[string]string{
+	"postgres": `UPDATE profile_schema_migrations SET owner = $1, updated_at = $2
+		WHERE migration_id = (SELECT migration_id FROM profile_schema_migrations
+			WHERE status IN ('pending', 'running') AND updated_at < $3
+			ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING migration_id, org_handle, attribute_id, attribute_name, application_identifier, operation,
+		source_definition::text, target_definition::text, status, total_profiles, processed_profiles,
+		converted_values, failed_values, failures::text, error, created_at, updated_at`,
+}
+
+// GetUnfinishedSchemaPurges fetches the pending and running purges of an org.
+var GetUnfinishedSchemaPurges = map[string]string{
+	"postgres": `SELECT migration_id, org_handle, attribute_id, attribute_name, application_identifier, operation,
+		source_definition::text, target_definition::text, status, total_profiles, processed_profiles,
+		converted_values, failed_values, failures::text, error, created_at, updated_at
+		FROM profile_schema_migrations
+		WHERE org_handle = $1 AND operation = 'purge' AND status IN ('pending', 'running')`,
+}
+
+// SupersedeSchemaMigrations stops the unfinished migrations of an attribute in favour of a newer one.
+var SupersedeSchemaMigrations = map[string]string{
+	"postgres": `UPDATE profile_schema_migrations SET status = 'superseded', updated_at = $3
+		WHERE org_handle = $1 AND attribute_id = $2 AND status IN ('pending', 'running')`,
+}
+
+var InsertQueueJob = map[string]string{
+	"postgres": `INSERT INTO queue_jobs (queue_name, payload) VALUES ($1, $2::jsonb)`,
+}
+
+// ClaimQueueJob claims the oldest available job of a queue for the lease duration ($2, in milliseconds). Jobs
+// claimed by other consumers are skipped rather than waited for.
+var ClaimQueueJob = map[string]string{
+	"postgres": `UPDATE queue_jobs SET attempts = attempts + 1, available_at = now() + $2 * interval '1 millisecond'
+		WHERE job_id = (SELECT job_id FROM queue_jobs
+			WHERE queue_name = $1 AND dead_lettered_at IS NULL AND available_at <= now()
+			ORDER BY job_id LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING job_id, payload::text, attempts`,
+}
+
+var DeleteQueueJob = map[string]string{
+	"postgres": `DELETE FROM queue_jobs WHERE job_id = $1`,
+}
+
+// RetryQueueJob makes a failed job available again after the backoff ($3, in milliseconds).
+var RetryQueueJob = map[string]string{
+	"postgres": `UPDATE queue_jobs SET last_error = $2, available_at = now() + $3 * interval '1 millisecond'
+		WHERE job_id = $1`,
+}
+
+var DeadLetterQueueJob = map[string]string{
+	"postgres": `UPDATE queue_jobs SET last_error = $2, dead_lettered_at = now() WHERE job_id = $1`,
+}
+
+var GetDeadLetteredQueueJobs = map[string]string{
+	"postgres": `SELECT job_id, payload::text, attempts, last_error, dead_lettered_at FROM queue_jobs
+		WHERE queue_name = $1 AND dead_lettered_at IS NOT NULL ORDER BY dead_lettered_at, job_id`,
+}
+
+// ReplayQueueJob makes a dead-lettered job available again with a fresh set of attempts.
+var ReplayQueueJob = map[string]string{
+	"postgres": `UPDATE queue_jobs SET attempts = 0, last_error = '', available_at = now(), dead_lettered_at = NULL
+		WHERE queue_name = $1 AND job_id = $2 AND dead_lettered_at IS NOT NULL RETURNING job_id`,
+}
+
+// AcquireUnificationLock takes a transaction-scoped advisory lock on the key; it is released when the transaction
+// ends.
+var AcquireUnificationLock = map[string]string{
+	"postgres": `SELECT pg_advisory_xact_lock(hashtext($1))`,
+}
+
+var InsertUnificationOutboxEntry = map[string]string{
+	"postgres": `INSERT INTO unification_outbox (profile_id, org_handle) VALUES ($1, $2)`,
+}
+
+// LockUnificationOutboxEntries locks the oldest outbox entries for the relay's transaction, leaving out the profiles
+// with an entry younger than the debounce window ($2, in milliseconds) unless they also have an entry older than the
+// maximum wait ($3, in milliseconds). Entries locked by the relays of other instances are skipped rather than waited
+// for.
+var LockUnificationOutboxEntries = map[string]string{
+	"postgres": `SELECT outbox_id, profile_id, org_handle FROM unification_outbox o
+		WHERE NOT EXISTS (SELECT 1 FROM unification_outbox n WHERE n.profile_id = o.profile_id
+				AND n.created_at > now() - $2 * INTERVAL '1 millisecond')
+			OR EXISTS (SELECT 1 FROM unification_outbox w WHERE w.profile_id = o.profile_id
+				AND w.created_at <= now() - $3 * INTERVAL '1 millisecond')
+		ORDER BY outbox_id LIMIT $1
+		FOR UPDATE SKIP LOCKED`,
+}
+
+var DeleteUnificationOutboxEntry = map[string]string{
+	"postgres": `DELETE FROM unification_outbox WHERE outbox_id = $1`,
+}
+
+// RequeueUnificationMessages adds the unification messages of a JSON array ($1) back to the outbox, in order.
+var RequeueUnificationMessages = map[string]string{
+	"postgres": `INSERT INTO unification_outbox (profile_id, org_handle)
+		SELECT message->>'profile_id', message->>'org_handle'
+		FROM jsonb_array_elements($1::jsonb) WITH ORDINALITY AS messages(message, position) ORDER BY position`,
+}
+
+// InsertSchemaSyncBacklog adds the jobs of a JSON array ($1) to the backlog, in order.
+var InsertSchemaSyncBacklog = map[string]string{
+	"postgres": `INSERT INTO schema_sync_backlog (payload)
+		SELECT job FROM jsonb_array_elements($1::jsonb) WITH ORDINALITY AS jobs(job, position) ORDER BY position`,
+}
+
+// TakeSchemaSyncBacklog removes the whole backlog and returns it, oldest first.
+var TakeSchemaSyncBacklog = map[string]string{
+	"postgres": `WITH taken AS (DELETE FROM schema_sync_backlog RETURNING backlog_id, payload)
+		SELECT payload::text FROM taken ORDER BY backlog_id`,
+}
+
+var InsertJob = map[string]string{
+	"postgres": `INSERT INTO jobs (job_id, job_type, org_handle, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)`,
+}
+
+var StartJob = map[string]string{
+	"postgres": `UPDATE jobs SET status = 'running', progress = 0, error = '', started_at = $2, updated_at = $2
+		WHERE job_id = $1`,
+}
+
+var UpdateJobProgress = map[string]string{
+	"postgres": `UPDATE jobs SET progress = $2, updated_at = $3 WHERE job_id = $1`,
+}
+
+// TouchJob records that a running job is still running.
+var TouchJob = map[string]string{
+	"postgres": `UPDATE jobs SET updated_at = $2 WHERE job_id = $1 AND status = 'running'`,
+}
+
+var FinishJob = map[string]string{
+	"postgres": `UPDATE jobs SET status = $2, progress = $3, result = $4::jsonb, error = $5, finished_at = $6,
+		updated_at = $6 WHERE job_id = $1`,
+}
+
+// FailStaleJobs fails the running jobs of a type that have not reported since $2, as their instance stopped.
+var FailStaleJobs = map[string]string{
+	"postgres": `UPDATE jobs SET status = 'failed', error = $3, finished_at = $4, updated_at = $4
+		WHERE job_type = $1 AND status = 'running' AND updated_at < $2`,
+}
+
+var GetJob = map[string]string{
+	"postgres": `SELECT job_id, job_type, org_handle, status, progress, COALESCE(result::text, '') AS result, error,
+		created_at, started_at, finished_at, updated_at
+		FROM jobs WHERE org_handle = $1 AND job_id = $2`,
+}
+
+// ListJobs lists the jobs of an org, newest first, optionally of one type ($2) and status ($3).
+var ListJobs = map[string]string{
+	"postgres": `SELECT job_id, job_type, org_handle, status, progress, COALESCE(result::text, '') AS result, error,
+		created_at, started_at, finished_at, updated_at
+		FROM jobs WHERE org_handle = $1 AND ($2 = '' OR job_type = $2) AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC, job_id LIMIT $4`,
+}
+
+// ClaimPeriodicJob moves the next run of a periodic job $2 seconds ahead if it is due, returning a row only to the
+// instance that moved it.
+var ClaimPeriodicJob = map[string]string{
+	"postgres": `INSERT INTO job_schedules (job_type, next_run_at) VALUES ($1, now() + $2::bigint * INTERVAL '1 second')
+		ON CONFLICT (job_type) DO UPDATE SET next_run_at = EXCLUDED.next_run_at
+		WHERE job_schedules.next_run_at <= now()
+		RETURNING job_type`,
+}
+
+// GetConsentedCategoryIdsByProfileIds fetches the categories each of the org's profiles in $2 has consented to.
+var GetConsentedCategoryIdsByProfileIds = map[string]string{
+	"postgres": `SELECT profile_id, category_id FROM profile_consents
+		WHERE org_handle = $1 AND profile_id = ANY($2) AND consent_status = TRUE`,
+}
+
+// CountQueueJobs counts the jobs of a queue waiting to be processed, including those being processed.
+var CountQueueJobs = map[string]string{
+	"postgres": `SELECT COUNT(*) AS depth FROM queue_jobs WHERE queue_name = $1 AND dead_lettered_at IS NULL`,
+}
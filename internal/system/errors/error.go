@@ -26,9 +26,17 @@ type ErrorMessage struct {
 	Description string `json:"error_description"`
 }
 
+// ErrorDetail describes one problem with one attribute of a request.
+type ErrorDetail struct {
+	Attribute  string `json:"attribute"`
+	Constraint string `json:"constraint"`
+	Message    string `json:"message"`
+}
+
 type ClientError struct {
 	ErrorMessage
 	StatusCode int
+	Details    []ErrorDetail
 }
 
 type ServerError struct {
@@ -58,6 +66,15 @@ func NewClientError(msg ErrorMessage, code int) *ClientError {
 	}
 }
 
+// NewClientErrorWithDetails creates a client error that reports a problem per attribute.
+func NewClientErrorWithDetails(msg ErrorMessage, code int, details []ErrorDetail) *ClientError {
+	return &ClientError{
+		ErrorMessage: msg,
+		StatusCode:   code,
+		Details:      details,
+	}
+}
+
 func NewClientErrorWithoutCode(msg ErrorMessage) *ClientError {
 	return &ClientError{
 		ErrorMessage: msg,
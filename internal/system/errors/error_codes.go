@@ -42,6 +42,18 @@ var (
 		Code:    errorPrefix + "15003",
 		Message: "Error while encoding data.",
 	}
+	PERSIST_PENDING_QUEUE_ITEMS = ErrorMessage{
+		Code:    errorPrefix + "15004",
+		Message: "Error while persisting the pending queue items on shutdown.",
+	}
+	GET_JOB = ErrorMessage{
+		Code:    errorPrefix + "15005",
+		Message: "Error while fetching background jobs.",
+	}
+	UPDATE_JOB = ErrorMessage{
+		Code:    errorPrefix + "15006",
+		Message: "Error while recording a background job.",
+	}
 
 	ADD_PROFILE_SCHEMA = ErrorMessage{
 		Code:    errorPrefix + "15101",
@@ -108,6 +120,36 @@ var (
 		Message: "Error while resolving application information.",
 	}
 
+	UPDATE_APPLICATION_PURPOSES_FAILED = ErrorMessage{
+		Code:    errorPrefix + "15114",
+		Message: "Error while persisting application purposes.",
+	}
+
+	GET_APPLICATION_PURPOSES_FAILED = ErrorMessage{
+		Code:    errorPrefix + "15115",
+		Message: "Error while fetching application purposes.",
+	}
+
+	EXPORT_PROFILE_SCHEMA = ErrorMessage{
+		Code:    errorPrefix + "15116",
+		Message: "Error while exporting profile schema.",
+	}
+
+	IMPORT_PROFILE_SCHEMA = ErrorMessage{
+		Code:    errorPrefix + "15117",
+		Message: "Error while importing profile schema.",
+	}
+
+	MIGRATE_PROFILE_SCHEMA = ErrorMessage{
+		Code:    errorPrefix + "15118",
+		Message: "Error while migrating profile values to the updated schema.",
+	}
+
+	DETECT_SCHEMA_DRIFT = ErrorMessage{
+		Code:    errorPrefix + "15119",
+		Message: "Error while checking the profile schema for drift from the identity server.",
+	}
+
 	ADD_UNIFICATION_RULE = ErrorMessage{
 		Code:    errorPrefix + "15201",
 		Message: "Error while adding unification rules.",
@@ -128,6 +170,21 @@ var (
 		Message: "Error while deleting unification rule(s).",
 	}
 
+	GET_DEAD_LETTERS = ErrorMessage{
+		Code:    errorPrefix + "15205",
+		Message: "Error while fetching dead-lettered unification messages.",
+	}
+
+	REPLAY_DEAD_LETTER = ErrorMessage{
+		Code:    errorPrefix + "15206",
+		Message: "Error while replaying a dead-lettered unification message.",
+	}
+
+	RELAY_UNIFICATION_OUTBOX = ErrorMessage{
+		Code:    errorPrefix + "15207",
+		Message: "Error while relaying profiles from the unification outbox.",
+	}
+
 	ADD_CONSENT_CATEGORY = ErrorMessage{
 		Code:    errorPrefix + "15301",
 		Message: "Adding consent category failed.",
@@ -168,6 +225,11 @@ var (
 		Message: "Fetching profile(s) failed.",
 	}
 
+	GET_CONSENT_RECEIPT = ErrorMessage{
+		Code:    errorPrefix + "15405",
+		Message: "Fetching consent receipt failed.",
+	}
+
 	PARSING_ERROR = ErrorMessage{
 		Code:    errorPrefix + "15901",
 		Message: "Parsing token failed.",
@@ -278,6 +340,18 @@ var (
 		Description: "Multiple user profiles record found for the given user_id",
 	}
 
+	CONSENT_RECEIPT_NOT_FOUND = ErrorMessage{
+		Code:        errorPrefix + "11017",
+		Message:     "Consent receipt not found.",
+		Description: "No consent receipt exists with the given id for the profile.",
+	}
+
+	PROFILE_CONSTRAINT_VIOLATION = ErrorMessage{
+		Code:        errorPrefix + "11018",
+		Message:     "Profile attributes violate schema constraints.",
+		Description: "One or more attribute values do not satisfy the constraints of the profile schema.",
+	}
+
 	UNIFICATION_RULE_NOT_FOUND = ErrorMessage{
 		Code:    errorPrefix + "12001",
 		Message: "No unification rule found.",
@@ -302,6 +376,12 @@ var (
 		Message: "Unification rule Id is required.",
 	}
 
+	DEAD_LETTER_NOT_FOUND = ErrorMessage{
+		Code:        errorPrefix + "12006",
+		Message:     "Dead letter not found.",
+		Description: "No dead-lettered unification message exists with the given id.",
+	}
+
 	PROFILE_SCHEMA_ADD_BAD_REQUEST = ErrorMessage{
 		Code:    errorPrefix + "13001",
 		Message: "Invalid request payload.",
@@ -332,6 +412,26 @@ var (
 		Message: "Attribute add/update not supported.",
 	}
 
+	INVALID_SCHEMA_BUNDLE = ErrorMessage{
+		Code:    errorPrefix + "13007",
+		Message: "Invalid profile schema bundle.",
+	}
+
+	INVALID_PROFILE_SCHEMA_FORMAT = ErrorMessage{
+		Code:    errorPrefix + "13008",
+		Message: "Unsupported profile schema format.",
+	}
+
+	SCHEMA_MIGRATION_NOT_FOUND = ErrorMessage{
+		Code:    errorPrefix + "13009",
+		Message: "Schema migration not found.",
+	}
+
+	ATTRIBUTE_DATA_BEING_PURGED = ErrorMessage{
+		Code:    errorPrefix + "13010",
+		Message: "Attribute data is being purged.",
+	}
+
 	CONSENT_CAT_VALIDATION = ErrorMessage{
 		Code:    errorPrefix + "14001",
 		Message: "Consent category validation failed",
@@ -373,8 +473,34 @@ var (
 		Description: "Customer data service is not enabled for the organization",
 	}
 
+	UPDATE_APPLICATION_PURPOSES_BAD_REQUEST = ErrorMessage{
+		Code:    errorPrefix + "16003",
+		Message: "Invalid request payload for updating application purposes.",
+	}
+
+	APPLICATION_NOT_FOUND = ErrorMessage{
+		Code:    errorPrefix + "16004",
+		Message: "Application not found.",
+	}
+
 	INVALID_FILTER_FORMAT = ErrorMessage{
 		Code:    errorPrefix + "19001",
 		Message: "Invalid filter format.",
 	}
+
+	JOB_NOT_FOUND = ErrorMessage{
+		Code:    errorPrefix + "19003",
+		Message: "Job not found.",
+	}
+
+	INVALID_JOB_FILTER = ErrorMessage{
+		Code:    errorPrefix + "19004",
+		Message: "Invalid job filter.",
+	}
+
+	SERVER_SHUTTING_DOWN = ErrorMessage{
+		Code:        errorPrefix + "19002",
+		Message:     "Service unavailable.",
+		Description: "The server is shutting down and no longer accepts changes. Retry the request.",
+	}
 )
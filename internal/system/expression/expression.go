@@ -0,0 +1,541 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package expression implements the small expression language used by computed profile attributes.
+//
+// An expression combines attribute references, literals, arithmetic and function calls, for example:
+//
+//	identity_attributes.given_name + ' ' + identity_attributes.family_name
+//	age(identity_attributes.birthdate)
+//	sum(application_data.*.order_total)
+//
+// Attribute references are fully qualified paths. The `*` segment matches every key at that level and yields a list
+// of the values found, which is how application data of all applications is aggregated.
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Wildcard is the path segment that matches every key at its level.
+const Wildcard = "*"
+
+// Expression is a parsed expression that can be evaluated against a profile document.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses the source of an expression. It fails on syntax errors and on calls to unknown functions.
+func Parse(source string) (*Expression, error) {
+	p := &parser{lexer: lexer{input: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.current.kind == tokenEOF {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.current.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", p.current.text, p.current.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the source the expression was parsed from.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// References returns the attribute paths the expression reads, in order of first use.
+func (e *Expression) References() []string {
+	seen := map[string]bool{}
+	refs := make([]string, 0)
+	e.root.collect(func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			refs = append(refs, path)
+		}
+	})
+	return refs
+}
+
+// Evaluate evaluates the expression against a document shaped like a profile, i.e. a map keyed by scope. now is
+// the reference time for date functions. A nil result means the value could not be derived from the document.
+func (e *Expression) Evaluate(document map[string]interface{}, now time.Time) (interface{}, error) {
+	return e.root.eval(&evalContext{document: document, now: now})
+}
+
+type evalContext struct {
+	document map[string]interface{}
+	now      time.Time
+}
+
+type node interface {
+	eval(ctx *evalContext) (interface{}, error)
+	collect(visit func(path string))
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(*evalContext) (interface{}, error) { return n.value, nil }
+func (n literalNode) collect(func(string))                   {}
+
+type pathNode struct {
+	path     string
+	segments []string
+}
+
+func (n pathNode) eval(ctx *evalContext) (interface{}, error) {
+	if strings.Contains(n.path, Wildcard) {
+		return resolveWildcard(ctx.document, n.segments), nil
+	}
+	var current interface{} = ctx.document
+	for _, segment := range n.segments {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		current = m[segment]
+	}
+	return current, nil
+}
+
+func (n pathNode) collect(visit func(string)) { visit(n.path) }
+
+// resolveWildcard walks the path and returns every non-nil value it reaches.
+func resolveWildcard(current interface{}, segments []string) []interface{} {
+	if len(segments) == 0 {
+		if current == nil {
+			return nil
+		}
+		return []interface{}{current}
+	}
+	m, ok := current.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if segments[0] != Wildcard {
+		return resolveWildcard(m[segments[0]], segments[1:])
+	}
+	values := make([]interface{}, 0)
+	for _, child := range m {
+		values = append(values, resolveWildcard(child, segments[1:])...)
+	}
+	return values
+}
+
+type unaryNode struct {
+	operand node
+}
+
+func (n unaryNode) eval(ctx *evalContext) (interface{}, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	f, ok := toNumber(v)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate non-numeric value %v", v)
+	}
+	return -f, nil
+}
+
+func (n unaryNode) collect(visit func(string)) { n.operand.collect(visit) }
+
+type binaryNode struct {
+	operator    string
+	left, right node
+}
+
+func (n binaryNode) eval(ctx *evalContext) (interface{}, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.operator == "+" {
+		// '+' concatenates as soon as either side is text; a missing value concatenates as an empty string, unless
+		// every attribute concatenated is missing, as the text would then hold nothing but the literals.
+		_, leftIsText := left.(string)
+		_, rightIsText := right.(string)
+		if leftIsText || rightIsText {
+			if readsOnlyMissingValues(n, ctx) {
+				return nil, nil
+			}
+			return toText(left) + toText(right), nil
+		}
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator '%s' needs numeric operands, got %v and %v", n.operator, left, right)
+	}
+	switch n.operator {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	default:
+		if r == 0 {
+			return nil, nil
+		}
+		return l / r, nil
+	}
+}
+
+// readsOnlyMissingValues reports whether an expression reads attributes, and none of them has a value.
+func readsOnlyMissingValues(n node, ctx *evalContext) bool {
+	reads, present := false, false
+	var walk func(node)
+	walk = func(n node) {
+		switch n := n.(type) {
+		case pathNode:
+			reads = true
+			if v, _ := n.eval(ctx); v != nil {
+				if values, ok := v.([]interface{}); !ok || len(values) > 0 {
+					present = true
+				}
+			}
+		case unaryNode:
+			walk(n.operand)
+		case binaryNode:
+			walk(n.left)
+			walk(n.right)
+		case callNode:
+			for _, arg := range n.args {
+				walk(arg)
+			}
+		}
+	}
+	walk(n)
+	return reads && !present
+}
+
+func (n binaryNode) collect(visit func(string)) {
+	n.left.collect(visit)
+	n.right.collect(visit)
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n callNode) eval(ctx *evalContext) (interface{}, error) {
+	if n.fn.missingWithInputs && readsOnlyMissingValues(n, ctx) {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(n.args))
+	for _, arg := range n.args {
+		v, err := arg.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	result, err := n.fn.call(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return result, nil
+}
+
+func (n callNode) collect(visit func(string)) {
+	for _, arg := range n.args {
+		arg.collect(visit)
+	}
+}
+
+// ---- parsing ----
+
+type parser struct {
+	lexer   lexer
+	current token
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.current = t
+	return nil
+}
+
+// parseExpression parses additive expressions: term (('+' | '-') term)*.
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.kind == tokenOperator && (p.current.text == "+" || p.current.text == "-") {
+		operator := p.current.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseTerm parses multiplicative expressions: unary (('*' | '/') unary)*.
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.kind == tokenOperator && (p.current.text == "*" || p.current.text == "/") {
+		operator := p.current.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.current.kind == tokenOperator && p.current.text == "-" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.current
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' at position %d", t.text, t.pos)
+		}
+		return literalNode{value: f}, p.advance()
+	case tokenString:
+		return literalNode{value: t.text}, p.advance()
+	case tokenLeftParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.current.kind != tokenRightParen {
+			return nil, fmt.Errorf("expected ')' at position %d", p.current.pos)
+		}
+		return inner, p.advance()
+	case tokenIdentifier:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.current.kind == tokenLeftParen {
+			return p.parseCall(t)
+		}
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+		segments := strings.Split(t.text, ".")
+		if len(segments) < 2 {
+			return nil, fmt.Errorf("attribute reference '%s' at position %d must be qualified with its scope, "+
+				"e.g. traits.%s", t.text, t.pos, t.text)
+		}
+		for _, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("invalid attribute reference '%s' at position %d", t.text, t.pos)
+			}
+		}
+		return pathNode{path: t.text, segments: segments}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected '%s' at position %d", t.text, t.pos)
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s' at position %d", name.text, name.pos)
+	}
+	if err := p.advance(); err != nil { // consume '('
+		return nil, err
+	}
+	args := make([]node, 0)
+	if p.current.kind != tokenRightParen {
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.current.kind != tokenComma {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p.current.kind != tokenRightParen {
+		return nil, fmt.Errorf("expected ')' to close call to '%s' at position %d", name.text, p.current.pos)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("function '%s' called with %d argument(s)", name.text, len(args))
+	}
+	return callNode{name: name.text, fn: fn, args: args}, p.advance()
+}
+
+// ---- lexing ----
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdentifier
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.input[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokenLeftParen, text: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokenRightParen, text: ")", pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokenComma, text: ",", pos: start}, nil
+	case strings.IndexByte("+-*/", c) >= 0:
+		l.pos++
+		return token{kind: tokenOperator, text: string(c), pos: start}, nil
+	case c == '\'' || c == '"':
+		return l.lexString(c)
+	case c >= '0' && c <= '9':
+		for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+			l.pos++
+		}
+		return token{kind: tokenNumber, text: l.input[start:l.pos], pos: start}, nil
+	case isIdentifierStart(c):
+		for l.pos < len(l.input) && isIdentifierPart(l.input[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokenIdentifier, text: l.input[start:l.pos], pos: start}, nil
+	default:
+		return token{}, fmt.Errorf("unexpected character '%c' at position %d", c, start)
+	}
+}
+
+func (l *lexer) lexString(quote byte) (token, error) {
+	start := l.pos
+	l.pos++ // opening quote
+	var sb strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		if c == '\\' && l.pos+1 < len(l.input) {
+			sb.WriteByte(l.input[l.pos+1])
+			l.pos += 2
+			continue
+		}
+		if c == quote {
+			l.pos++
+			return token{kind: tokenString, text: sb.String(), pos: start}, nil
+		}
+		sb.WriteByte(c)
+		l.pos++
+	}
+	return token{}, fmt.Errorf("unterminated string starting at position %d", start)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdentifierPart also accepts '.' and '*' so that a whole attribute path lexes as one identifier.
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c) || c == '.' || c == '*'
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// function describes a built-in function. maxArgs is -1 for variadic functions. A function that is missingWithInputs
+// returns no value when every attribute its arguments read is missing, as '+' does.
+type function struct {
+	minArgs, maxArgs  int
+	missingWithInputs bool
+	call              func(ctx *evalContext, args []interface{}) (interface{}, error)
+}
+
+var functions = map[string]function{
+	// concat joins all present values, e.g. concat(traits.first, ' ', traits.last).
+	"concat": {minArgs: 1, maxArgs: -1, missingWithInputs: true,
+		call: func(_ *evalContext, args []interface{}) (interface{}, error) {
+			var sb strings.Builder
+			for _, v := range flatten(args) {
+				sb.WriteString(toText(v))
+			}
+			return sb.String(), nil
+		}},
+	// join joins the non-empty values with the separator given as the first argument.
+	"join": {minArgs: 2, maxArgs: -1, call: func(_ *evalContext, args []interface{}) (interface{}, error) {
+		parts := make([]string, 0, len(args)-1)
+		for _, v := range flatten(args[1:]) {
+			if s := toText(v); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 0 {
+			return nil, nil
+		}
+		return strings.Join(parts, toText(args[0])), nil
+	}},
+	// coalesce returns the first non-empty value.
+	"coalesce": {minArgs: 1, maxArgs: -1, call: func(_ *evalContext, args []interface{}) (interface{}, error) {
+		for _, v := range args {
+			if v == nil {
+				continue
+			}
+			if s, ok := v.(string); ok && s == "" {
+				continue
+			}
+			return v, nil
+		}
+		return nil, nil
+	}},
+	// sum adds up every numeric value, flattening lists such as wildcard references.
+	"sum": {minArgs: 1, maxArgs: -1, call: func(_ *evalContext, args []interface{}) (interface{}, error) {
+		values := flatten(args)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		total := 0.0
+		for _, v := range values {
+			f, ok := toNumber(v)
+			if !ok {
+				return nil, fmt.Errorf("cannot sum non-numeric value %v", v)
+			}
+			total += f
+		}
+		return total, nil
+	}},
+	// count returns the number of present values, flattening lists.
+	"count": {minArgs: 1, maxArgs: -1, call: func(_ *evalContext, args []interface{}) (interface{}, error) {
+		return float64(len(flatten(args))), nil
+	}},
+	// age returns the number of whole years elapsed since the given date.
+	"age": {minArgs: 1, maxArgs: 1, call: func(ctx *evalContext, args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		born, err := toTime(args[0])
+		if err != nil {
+			return nil, err
+		}
+		years := ctx.now.Year() - born.Year()
+		if ctx.now.Month() < born.Month() || (ctx.now.Month() == born.Month() && ctx.now.Day() < born.Day()) {
+			years--
+		}
+		return float64(years), nil
+	}},
+	"lower": stringFunction(strings.ToLower),
+	"upper": stringFunction(strings.ToUpper),
+	"trim":  stringFunction(strings.TrimSpace),
+}
+
+func stringFunction(transform func(string) string) function {
+	return function{minArgs: 1, maxArgs: 1, call: func(_ *evalContext, args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return transform(toText(args[0])), nil
+	}}
+}
+
+// flatten expands list arguments and drops missing values.
+func flatten(args []interface{}) []interface{} {
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+		case []interface{}:
+			values = append(values, flatten(v)...)
+		default:
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func toText(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1e15 {
+			return strconv.FormatInt(int64(t), 10)
+		}
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toTime accepts dates (YYYY-MM-DD), RFC 3339 timestamps and epoch seconds.
+func toTime(v interface{}) (time.Time, error) {
+	if s, ok := v.(string); ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse(time.DateOnly, s); err == nil {
+			return t, nil
+		}
+	}
+	if f, ok := toNumber(v); ok {
+		return time.Unix(int64(f), 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("'%v' is not a date", v)
+}
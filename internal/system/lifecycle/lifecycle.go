@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package lifecycle tracks whether the server is draining before it shuts
+// down. While draining, the server finishes in-flight work but accepts no new
+// changes, so that a rolling restart does not lose writes or unification work.
+package lifecycle
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+var draining atomic.Bool
+
+// BeginDrain marks the server as draining. It cannot be undone: the process
+// is expected to exit once the drain completes.
+func BeginDrain() {
+	draining.Store(true)
+}
+
+// IsDraining reports whether the server has started draining.
+func IsDraining() bool {
+	return draining.Load()
+}
+
+// RejectWritesWhileDraining rejects requests that change data with
+// 503 Service Unavailable once the server is draining, so that clients retry
+// them against another replica. Reads are still served.
+func RejectWritesWhileDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsDraining() && isWrite(r.Method) {
+			w.Header().Set("Retry-After", "1")
+			utils.HandleError(w, errors.NewClientError(errors.SERVER_SHUTTING_DOWN, http.StatusServiceUnavailable))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
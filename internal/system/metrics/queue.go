@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// Queues, as the value of the "queue" label.
+const (
+	QueueProfileUnification = "profile_unification"
+	QueueSchemaSync         = "schema_sync"
+)
+
+// Processing outcomes, as the value of the "outcome" label.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// depthReadTimeout bounds reading the depth of a queue during a scrape.
+const depthReadTimeout = 5 * time.Second
+
+// Queue metrics, labelled by queue and by the provider ("memory", "activemq", ...) holding it.
+var (
+	QueueEnqueued = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cds_queue_enqueued_total",
+		Help: "Items accepted by the queue.",
+	}, []string{"queue", "provider"})
+	QueueEnqueueFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cds_queue_enqueue_failures_total",
+		Help: "Items the queue failed to accept.",
+	}, []string{"queue", "provider"})
+	QueueDepth    = newQueueDepth()
+	QueueInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cds_queue_in_flight",
+		Help: "Items received by this instance whose processing has not finished.",
+	}, []string{"queue", "provider"})
+	QueueLag = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cds_queue_lag_seconds",
+		Help:    "Time from enqueueing an item to the start of its processing.",
+		Buckets: DefaultBuckets,
+	}, []string{"queue", "provider"})
+	QueueDeadLettered = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cds_queue_dead_lettered_total",
+		Help: "Items moved to the dead-letter destination after failing every attempt.",
+	}, []string{"queue", "provider"})
+)
+
+// Worker metrics.
+var (
+	WorkerProcessingDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cds_worker_processing_duration_seconds",
+		Help:    "Time a worker took to process one item, by queue and outcome.",
+		Buckets: DefaultBuckets,
+	}, []string{"queue", "outcome"})
+	UnificationMerges = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cds_unification_merges_total",
+		Help: "Profiles merged by unification, by the kinds of the merged profiles (TEMP_TEMP, TEMP_PERM, PERM_PERM) " +
+			"and the rule that matched them.",
+	}, []string{"type", "rule"})
+)
+
+// DepthReader returns the number of items waiting in a queue.
+type DepthReader func(ctx context.Context) (int64, error)
+
+// queueDepth is the cds_queue_depth gauge. In-memory queues count their items
+// in it; queues kept by a broker or the database register a DepthReader,
+// which is called at every scrape.
+type queueDepth struct {
+	*prometheus.GaugeVec
+	desc *prometheus.Desc
+
+	mu      sync.Mutex
+	readers map[[2]string]DepthReader
+}
+
+func newQueueDepth() *queueDepth {
+	const name = "cds_queue_depth"
+	const help = "Items waiting in the queue. In-memory queues report the items of this instance; queues kept by a " +
+		"broker or the database report the items waiting for all instances."
+	labels := []string{"queue", "provider"}
+	d := &queueDepth{
+		GaugeVec: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels),
+		desc:     prometheus.NewDesc(name, help, labels, nil),
+		readers:  map[[2]string]DepthReader{},
+	}
+	Registry.MustRegister(d)
+	return d
+}
+
+// Collect reports the counted depths, then reads the depth of every queue
+// with a reader. A queue whose depth cannot be read is left out.
+func (d *queueDepth) Collect(ch chan<- prometheus.Metric) {
+	d.GaugeVec.Collect(ch)
+
+	d.mu.Lock()
+	readers := make(map[[2]string]DepthReader, len(d.readers))
+	for labels, read := range d.readers {
+		readers[labels] = read
+	}
+	d.mu.Unlock()
+
+	for labels, read := range readers {
+		ctx, cancel := context.WithTimeout(context.Background(), depthReadTimeout)
+		depth, err := read(ctx)
+		cancel()
+		if err != nil {
+			log.GetLogger().Warn(fmt.Sprintf("metrics: failed to read the depth of queue %s of provider %s: %v",
+				labels[0], labels[1], err))
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(d.desc, prometheus.GaugeValue, float64(depth), labels[0], labels[1])
+	}
+}
+
+// RegisterQueueDepth reports the depth of the queue by calling read at every
+// scrape, replacing an earlier reader of the same queue and provider.
+func RegisterQueueDepth(queueName, provider string, read DepthReader) {
+	QueueDepth.mu.Lock()
+	defer QueueDepth.mu.Unlock()
+	QueueDepth.readers[[2]string{queueName, provider}] = read
+}
+
+// UnregisterQueueDepth stops reporting the depth of the queue, once it was closed.
+func UnregisterQueueDepth(queueName, provider string) {
+	QueueDepth.mu.Lock()
+	defer QueueDepth.mu.Unlock()
+	delete(QueueDepth.readers, [2]string{queueName, provider})
+}
@@ -22,20 +22,26 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-stomp/stomp/v3"
+	"github.com/go-stomp/stomp/v3/frame"
 	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
 	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
 	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
 	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
 	"github.com/wso2/identity-customer-data-service/internal/system/utils"
 )
 
@@ -48,13 +54,36 @@ const (
 
 func init() {
 	queue.RegisterProfileQueueProvider(queue.TypeActiveMQ,
-		func(cfg config.ExternalBrokerConfig, tlsCfg config.TLSConfig) (queue.ProfileUnificationQueue, error) {
-			return NewProfileQueue(cfg.Addr, cfg.Username, cfg.Password, cfg.ProfileQueueName, tlsCfg)
+		func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.ProfileUnificationQueue, error) {
+			broker := cfg.Broker
+			deadLetterDestination := broker.ProfileDeadLetterQueueName
+			if deadLetterDestination == "" {
+				deadLetterDestination = broker.ProfileQueueName + constants.DeadLetterQueueSuffix
+			}
+			q, err := NewProfileQueue(broker.Addr, broker.Username, broker.Password, broker.ProfileQueueName,
+				deadLetterDestination, delivery.NewPolicy(cfg.Redelivery), delivery.Workers(cfg.Workers), tlsCfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := reportDepth(broker, metrics.QueueProfileUnification, broker.ProfileQueueName, tlsCfg); err != nil {
+				_ = q.Close()
+				return nil, err
+			}
+			return q, nil
 		},
 	)
 	queue.RegisterSchemaSyncQueueProvider(queue.TypeActiveMQ,
-		func(cfg config.ExternalBrokerConfig, tlsCfg config.TLSConfig) (queue.SchemaSyncQueue, error) {
-			return NewSchemaSyncQueue(cfg.Addr, cfg.Username, cfg.Password, cfg.SchemaSyncQueueName, tlsCfg)
+		func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.SchemaSyncQueue, error) {
+			broker := cfg.Broker
+			q, err := NewSchemaSyncQueue(broker.Addr, broker.Username, broker.Password, broker.SchemaSyncQueueName, tlsCfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := reportDepth(broker, metrics.QueueSchemaSync, broker.SchemaSyncQueueName, tlsCfg); err != nil {
+				_ = q.Close()
+				return nil, err
+			}
+			return q, nil
 		},
 	)
 }
@@ -112,6 +141,37 @@ func parseAddr(addr string) (hostPort string, useTLS bool) {
 	return addr, false
 }
 
+// trustedRoots builds the CA pool from the system roots, then appends the
+// trust store if configured — same pattern as identity_client.go. This allows
+// the broker's internal CA cert to be added to the existing trust_store
+// without any new config fields.
+func trustedRoots(tlsCfg config.TLSConfig) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if tlsCfg.TrustStore == "" {
+		return rootCAs, nil
+	}
+	certDir := tlsCfg.CertDir
+	if certDir == "" {
+		certDir = filepath.Join(utils.GetCDSHome(), "etc", "certs")
+	}
+	if !filepath.IsAbs(certDir) {
+		if abs, absErr := filepath.Abs(certDir); absErr == nil {
+			certDir = abs
+		}
+	}
+	trustPEM, err := os.ReadFile(filepath.Join(certDir, tlsCfg.TrustStore))
+	if err != nil {
+		return nil, fmt.Errorf("activemq: failed to read trust_store: %w", err)
+	}
+	if ok := rootCAs.AppendCertsFromPEM(trustPEM); !ok {
+		return nil, fmt.Errorf("activemq: failed to append certs from trust_store")
+	}
+	return rootCAs, nil
+}
+
 func (mc *managedConn) dial() error {
 	hostPort, useTLS := parseAddr(mc.addr)
 
@@ -136,31 +196,9 @@ func (mc *managedConn) dial() error {
 	var netConn net.Conn
 	var err error
 	if useTLS {
-		// Build CA pool from system roots, then append the trust store if
-		// configured — same pattern as identity_client.go. This allows the
-		// broker's internal CA cert to be added to the existing trust_store
-		// without any new config fields.
-		rootCAs, sysErr := x509.SystemCertPool()
-		if sysErr != nil || rootCAs == nil {
-			rootCAs = x509.NewCertPool()
-		}
-		if mc.tlsCfg.TrustStore != "" {
-			certDir := mc.tlsCfg.CertDir
-			if certDir == "" {
-				certDir = filepath.Join(utils.GetCDSHome(), "etc", "certs")
-			}
-			if !filepath.IsAbs(certDir) {
-				if abs, absErr := filepath.Abs(certDir); absErr == nil {
-					certDir = abs
-				}
-			}
-			trustPEM, readErr := os.ReadFile(filepath.Join(certDir, mc.tlsCfg.TrustStore))
-			if readErr != nil {
-				return fmt.Errorf("activemq: failed to read trust_store: %w", readErr)
-			}
-			if ok := rootCAs.AppendCertsFromPEM(trustPEM); !ok {
-				return fmt.Errorf("activemq: failed to append certs from trust_store")
-			}
+		rootCAs, caErr := trustedRoots(mc.tlsCfg)
+		if caErr != nil {
+			return caErr
 		}
 		netConn, err = tls.DialWithDialer(dialer, "tcp", hostPort, &tls.Config{
 			MinVersion: tls.VersionTLS12,
@@ -268,13 +306,14 @@ func (mc *managedConn) reconnectWithBackoff(context string, maxAttempts int) err
 
 // subscribeCurrent subscribes on the current live connection and returns the
 // subscription together with the generation the subscription belongs to.
-func (mc *managedConn) subscribeCurrent(destination string) (*stomp.Subscription, uint64, error) {
+func (mc *managedConn) subscribeCurrent(destination string, ack stomp.AckMode,
+	opts ...func(*frame.Frame) error) (*stomp.Subscription, uint64, error) {
 	conn, generation := mc.getConnAndGeneration()
 	if conn == nil {
 		return nil, generation, fmt.Errorf("activemq: no active connection available for subscription")
 	}
 
-	sub, err := conn.Subscribe(destination, stomp.AckAuto)
+	sub, err := conn.Subscribe(destination, ack, opts...)
 	if err != nil {
 		return nil, generation, err
 	}
@@ -286,18 +325,31 @@ func (mc *managedConn) subscribeCurrent(destination string) (*stomp.Subscription
 // ProfileQueue
 // -----------------------------------------------------------------------
 
-// ProfileQueue is the ActiveMQ-backed ProfileUnificationQueue.
+// ProfileQueue is the ActiveMQ-backed ProfileUnificationQueue. Messages are
+// consumed with client-individual acknowledgements, so a message whose
+// handler has not succeeded is redelivered by the broker after a crash or a
+// lost connection.
 type ProfileQueue struct {
-	mc          *managedConn
-	destination string
+	mc                    *managedConn
+	destination           string
+	deadLetterDestination string
+	policy                delivery.Policy
+	workers               int
 }
 
-func NewProfileQueue(addr, username, password, destination string, tlsCfg config.TLSConfig) (*ProfileQueue, error) {
+func NewProfileQueue(addr, username, password, destination, deadLetterDestination string, policy delivery.Policy,
+	workers int, tlsCfg config.TLSConfig) (*ProfileQueue, error) {
 	mc, err := newManagedConn(addr, username, password, tlsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("activemq: failed to connect for profile queue: %w", err)
 	}
-	return &ProfileQueue{mc: mc, destination: destination}, nil
+	return &ProfileQueue{
+		mc:                    mc,
+		destination:           destination,
+		deadLetterDestination: deadLetterDestination,
+		policy:                policy,
+		workers:               max(workers, 1),
+	}, nil
 }
 
 // Enqueue marshals the profile to JSON and sends it to ActiveMQ.
@@ -308,13 +360,14 @@ func NewProfileQueue(addr, username, password, destination string, tlsCfg config
 // called in the request path where blocking indefinitely is unacceptable.
 // Callers that need stronger delivery guarantees should persist the item and
 // retry externally.
-func (q *ProfileQueue) Enqueue(profile profileModel.Profile) error {
+func (q *ProfileQueue) Enqueue(profile profileModel.UnificationMessage) (err error) {
+	defer func() { recordEnqueue(metrics.QueueProfileUnification, err) }()
 	data, err := json.Marshal(profile)
 	if err != nil {
 		return fmt.Errorf("activemq: failed to marshal profile %s: %w", profile.ProfileId, err)
 	}
 
-	if err := q.mc.getConn().Send(q.destination, contentTypeJSON, data); err != nil {
+	if err := q.mc.getConn().Send(q.destination, contentTypeJSON, data, enqueuedAtHeader()); err != nil {
 		log.GetLogger().Error(fmt.Sprintf(
 			"activemq: send failed for profile %s, will reconnect and retry: %v",
 			profile.ProfileId, err))
@@ -324,7 +377,7 @@ func (q *ProfileQueue) Enqueue(profile profileModel.Profile) error {
 		}
 
 		// Single retry after reconnect.
-		if retryErr := q.mc.getConn().Send(q.destination, contentTypeJSON, data); retryErr != nil {
+		if retryErr := q.mc.getConn().Send(q.destination, contentTypeJSON, data, enqueuedAtHeader()); retryErr != nil {
 			return fmt.Errorf("activemq: retry send failed for profile %s: %w", profile.ProfileId, retryErr)
 		}
 	}
@@ -341,12 +394,20 @@ func (q *ProfileQueue) Enqueue(profile profileModel.Profile) error {
 // underlying connection was intentionally retired during a managed reconnect,
 // the consumer simply re-subscribes on the current connection instead of
 // reconnecting again.
-func (q *ProfileQueue) Start(handler func(profileModel.Profile)) error {
-	sub, subGen, err := q.mc.subscribeCurrent(q.destination)
+//
+// Up to workers messages are handled concurrently; the broker's prefetch is
+// set to match, so it holds back further messages until one is acknowledged.
+// A message is acknowledged once handler succeeds. While handler fails the
+// message is retried per the redelivery policy, and then moved to the
+// dead-letter destination. A message left unacknowledged by a shutdown is
+// redelivered by the broker.
+func (q *ProfileQueue) Start(handler func(profileModel.UnificationMessage) error) error {
+	sub, subGen, err := q.subscribe()
 	if err != nil {
 		return fmt.Errorf("activemq: failed to subscribe to profile queue %s: %w", q.destination, err)
 	}
 
+	workers := make(chan struct{}, q.workers)
 	go func() {
 		for {
 			msg, ok := <-sub.C
@@ -365,7 +426,7 @@ func (q *ProfileQueue) Start(handler func(profileModel.Profile)) error {
 					log.GetLogger().Info(
 						"activemq: profile queue subscription closed on retired connection, re-subscribing on current connection",
 					)
-					newSub, newGen, err := q.mc.subscribeCurrent(q.destination)
+					newSub, newGen, err := q.subscribe()
 					if err != nil {
 						log.GetLogger().Error(fmt.Sprintf(
 							"activemq: failed to re-subscribe to profile queue on current connection: %v", err,
@@ -385,7 +446,7 @@ func (q *ProfileQueue) Start(handler func(profileModel.Profile)) error {
 					return
 				}
 
-				newSub, newGen, err := q.mc.subscribeCurrent(q.destination)
+				newSub, newGen, err := q.subscribe()
 				if err != nil {
 					log.GetLogger().Error(fmt.Sprintf(
 						"activemq: failed to re-subscribe to profile queue: %v", err,
@@ -404,24 +465,61 @@ func (q *ProfileQueue) Start(handler func(profileModel.Profile)) error {
 				continue
 			}
 
-			var profile profileModel.Profile
-			if err := json.Unmarshal(msg.Body, &profile); err != nil {
-				log.GetLogger().Error(fmt.Sprintf(
-					"activemq: failed to unmarshal profile message: %v", err,
-				))
-				continue
-			}
-
-			handler(profile)
+			workers <- struct{}{}
+			go func() {
+				defer func() { <-workers }()
+				q.process(msg, handler)
+			}()
 		}
 	}()
 
 	return nil
 }
 
+// subscribe subscribes to the profile queue on the current connection.
+func (q *ProfileQueue) subscribe() (*stomp.Subscription, uint64, error) {
+	return q.mc.subscribeCurrent(q.destination, stomp.AckClientIndividual,
+		stomp.SubscribeOpt.Header("activemq.prefetchSize", strconv.Itoa(q.workers)))
+}
+
+// process hands a message to handler and acknowledges or dead-letters it.
+func (q *ProfileQueue) process(msg *stomp.Message, handler func(profileModel.UnificationMessage) error) {
+	observeLag(metrics.QueueProfileUnification, msg)
+	metrics.QueueInFlight.WithLabelValues(metrics.QueueProfileUnification, queue.TypeActiveMQ).Inc()
+	defer metrics.QueueInFlight.WithLabelValues(metrics.QueueProfileUnification, queue.TypeActiveMQ).Dec()
+
+	var profile profileModel.UnificationMessage
+	if err := json.Unmarshal(msg.Body, &profile); err != nil {
+		log.GetLogger().Error(fmt.Sprintf(
+			"activemq: failed to unmarshal profile message: %v", err,
+		))
+		q.deadLetter(msg, 1, err)
+		return
+	}
+
+	attempts, err := q.policy.Deliver(func() error { return handler(profile) }, q.mc.done)
+	switch {
+	case err == nil:
+		if ackErr := msg.Conn.Ack(msg); ackErr != nil {
+			log.GetLogger().Error(fmt.Sprintf(
+				"activemq: failed to acknowledge profile %s: %v", profile.ProfileId, ackErr))
+		}
+	case errors.Is(err, delivery.ErrStopped):
+		// Left unacknowledged, so the broker redelivers it.
+		log.GetLogger().Info(fmt.Sprintf(
+			"activemq: profile queue stopped while retrying profile %s", profile.ProfileId))
+	default:
+		log.GetLogger().Error(fmt.Sprintf(
+			"activemq: profile %s failed after %d attempts, moving it to %s: %v",
+			profile.ProfileId, attempts, q.deadLetterDestination, err))
+		q.deadLetter(msg, attempts, err)
+	}
+}
+
 // Close signals the consumer goroutine to stop and gracefully disconnects
 // from ActiveMQ. Safe to call more than once.
 func (q *ProfileQueue) Close() error {
+	metrics.UnregisterQueueDepth(metrics.QueueProfileUnification, queue.TypeActiveMQ)
 	q.mc.shutdown()
 	return q.mc.getConn().Disconnect()
 }
@@ -446,13 +544,14 @@ func NewSchemaSyncQueue(addr, username, password, destination string, tlsCfg con
 
 // Enqueue marshals the schema sync to JSON and sends it to ActiveMQ.
 // See ProfileQueue.Enqueue for retry-policy rationale.
-func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) error {
+func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) (err error) {
+	defer func() { recordEnqueue(metrics.QueueSchemaSync, err) }()
 	data, err := json.Marshal(sync)
 	if err != nil {
 		return fmt.Errorf("activemq: failed to marshal schema sync for tenant %s: %w", sync.OrgId, err)
 	}
 
-	if err := q.mc.getConn().Send(q.destination, contentTypeJSON, data); err != nil {
+	if err := q.mc.getConn().Send(q.destination, contentTypeJSON, data, enqueuedAtHeader()); err != nil {
 		log.GetLogger().Error(fmt.Sprintf(
 			"activemq: send failed for schema sync tenant %s, will reconnect and retry: %v",
 			sync.OrgId, err))
@@ -461,7 +560,7 @@ func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) error {
 			return fmt.Errorf("activemq: send failed for schema sync tenant %s: %w", sync.OrgId, reconnErr)
 		}
 
-		if retryErr := q.mc.getConn().Send(q.destination, contentTypeJSON, data); retryErr != nil {
+		if retryErr := q.mc.getConn().Send(q.destination, contentTypeJSON, data, enqueuedAtHeader()); retryErr != nil {
 			return fmt.Errorf("activemq: retry send failed for schema sync tenant %s: %w", sync.OrgId, retryErr)
 		}
 	}
@@ -471,7 +570,7 @@ func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) error {
 // Start subscribes to the destination and launches a consumer goroutine.
 // See ProfileQueue.Start for retry-policy rationale.
 func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) error {
-	sub, subGen, err := q.mc.subscribeCurrent(q.destination)
+	sub, subGen, err := q.mc.subscribeCurrent(q.destination, stomp.AckAuto)
 	if err != nil {
 		return fmt.Errorf("activemq: failed to subscribe to schema sync queue %s: %w", q.destination, err)
 	}
@@ -491,7 +590,7 @@ func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) err
 					log.GetLogger().Info(
 						"activemq: schema sync subscription closed on retired connection, re-subscribing on current connection",
 					)
-					newSub, newGen, err := q.mc.subscribeCurrent(q.destination)
+					newSub, newGen, err := q.mc.subscribeCurrent(q.destination, stomp.AckAuto)
 					if err != nil {
 						log.GetLogger().Error(fmt.Sprintf(
 							"activemq: failed to re-subscribe to schema sync queue on current connection: %v", err,
@@ -511,7 +610,7 @@ func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) err
 					return
 				}
 
-				newSub, newGen, err := q.mc.subscribeCurrent(q.destination)
+				newSub, newGen, err := q.mc.subscribeCurrent(q.destination, stomp.AckAuto)
 				if err != nil {
 					log.GetLogger().Error(fmt.Sprintf(
 						"activemq: failed to re-subscribe to schema sync queue: %v", err,
@@ -535,7 +634,10 @@ func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) err
 					"activemq: failed to unmarshal schema sync message: %v", err))
 				continue
 			}
+			observeLag(metrics.QueueSchemaSync, msg)
+			metrics.QueueInFlight.WithLabelValues(metrics.QueueSchemaSync, queue.TypeActiveMQ).Inc()
 			handler(sync)
+			metrics.QueueInFlight.WithLabelValues(metrics.QueueSchemaSync, queue.TypeActiveMQ).Dec()
 		}
 	}()
 	return nil
@@ -544,6 +646,7 @@ func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) err
 // Close signals the consumer goroutine to stop and gracefully disconnects
 // from ActiveMQ. Safe to call more than once.
 func (q *SchemaSyncQueue) Close() error {
+	metrics.UnregisterQueueDepth(metrics.QueueSchemaSync, queue.TypeActiveMQ)
 	q.mc.shutdown()
 	return q.mc.getConn().Disconnect()
 }
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package activemq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-stomp/stomp/v3"
+	"github.com/google/uuid"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+)
+
+// Dead-letter message headers. ActiveMQ exposes STOMP headers as JMS
+// properties, so the names must be valid selector identifiers.
+const (
+	headerDeadLetterId = "cdsDeadLetterId"
+	headerAttempts     = "cdsAttempts"
+	headerError        = "cdsError"
+	headerFailedAt     = "cdsFailedAt"
+
+	// maxErrorHeaderLength caps the failure reason kept on a dead letter.
+	maxErrorHeaderLength = 1000
+	// deadLetterWaitTimeout bounds how long browsing or replaying the
+	// dead-letter destination waits for the broker.
+	deadLetterWaitTimeout = 5 * time.Second
+)
+
+// deadLetter sends a message whose handler failed to the dead-letter
+// destination and acknowledges the original. When the dead letter cannot be
+// sent the original is negatively acknowledged so that the broker redelivers
+// it rather than losing it.
+func (q *ProfileQueue) deadLetter(msg *stomp.Message, attempts int, cause error) {
+	reason := cause.Error()
+	if len(reason) > maxErrorHeaderLength {
+		reason = reason[:maxErrorHeaderLength]
+	}
+	err := q.mc.getConn().Send(q.deadLetterDestination, contentTypeJSON, msg.Body,
+		stomp.SendOpt.Header("persistent", "true"),
+		stomp.SendOpt.Header(headerDeadLetterId, uuid.New().String()),
+		stomp.SendOpt.Header(headerAttempts, strconv.Itoa(attempts)),
+		stomp.SendOpt.Header(headerError, reason),
+		stomp.SendOpt.Header(headerFailedAt, time.Now().UTC().Format(time.RFC3339Nano)),
+	)
+	if err != nil {
+		log.GetLogger().Error(fmt.Sprintf(
+			"activemq: failed to send message to dead-letter destination %s, leaving it for redelivery: %v",
+			q.deadLetterDestination, err))
+		if nackErr := msg.Conn.Nack(msg); nackErr != nil {
+			log.GetLogger().Error(fmt.Sprintf("activemq: failed to nack profile message: %v", nackErr))
+		}
+		return
+	}
+	metrics.QueueDeadLettered.WithLabelValues(metrics.QueueProfileUnification, queue.TypeActiveMQ).Inc()
+	if ackErr := msg.Conn.Ack(msg); ackErr != nil {
+		log.GetLogger().Error(fmt.Sprintf("activemq: failed to acknowledge dead-lettered message: %v", ackErr))
+	}
+}
+
+// DeadLetters browses the dead-letter destination without consuming it.
+func (q *ProfileQueue) DeadLetters() ([]delivery.DeadLetter, error) {
+	sub, err := q.mc.getConn().Subscribe(q.deadLetterDestination, stomp.AckAuto,
+		stomp.SubscribeOpt.Header("browser", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("activemq: failed to browse %s: %w", q.deadLetterDestination, err)
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	deadLetters := []delivery.DeadLetter{}
+	timeout := time.After(deadLetterWaitTimeout)
+	for {
+		select {
+		case msg, ok := <-sub.C:
+			if !ok {
+				return deadLetters, nil
+			}
+			if msg.Err != nil {
+				return nil, fmt.Errorf("activemq: failed to browse %s: %w", q.deadLetterDestination, msg.Err)
+			}
+			// The broker marks the end of a browse with an empty message.
+			if msg.Header.Get("browser") == "end" {
+				return deadLetters, nil
+			}
+			deadLetters = append(deadLetters, deadLetterOf(msg))
+		case <-timeout:
+			return deadLetters, nil
+		}
+	}
+}
+
+// ReplayDeadLetter consumes the dead letter with the given id and sends it
+// back to the profile destination.
+func (q *ProfileQueue) ReplayDeadLetter(id string) error {
+	// The id goes into a selector, so anything but a generated id is rejected.
+	if _, err := uuid.Parse(id); err != nil {
+		return delivery.ErrDeadLetterNotFound
+	}
+	sub, err := q.mc.getConn().Subscribe(q.deadLetterDestination, stomp.AckClientIndividual,
+		stomp.SubscribeOpt.Header("selector", fmt.Sprintf("%s = '%s'", headerDeadLetterId, id)))
+	if err != nil {
+		return fmt.Errorf("activemq: failed to read %s: %w", q.deadLetterDestination, err)
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	select {
+	case msg, ok := <-sub.C:
+		if !ok {
+			return fmt.Errorf("activemq: subscription to %s closed during replay", q.deadLetterDestination)
+		}
+		if msg.Err != nil {
+			return fmt.Errorf("activemq: failed to read %s: %w", q.deadLetterDestination, msg.Err)
+		}
+		if err := q.mc.getConn().Send(q.destination, contentTypeJSON, msg.Body, enqueuedAtHeader()); err != nil {
+			_ = msg.Conn.Nack(msg)
+			return fmt.Errorf("activemq: failed to replay dead letter %s: %w", id, err)
+		}
+		return msg.Conn.Ack(msg)
+	case <-time.After(deadLetterWaitTimeout):
+		return delivery.ErrDeadLetterNotFound
+	}
+}
+
+// deadLetterOf reads a dead letter from a message of the dead-letter destination.
+func deadLetterOf(msg *stomp.Message) delivery.DeadLetter {
+	deadLetter := delivery.DeadLetter{
+		Id:    msg.Header.Get(headerDeadLetterId),
+		Error: msg.Header.Get(headerError),
+	}
+	deadLetter.Attempts, _ = strconv.Atoi(msg.Header.Get(headerAttempts))
+	deadLetter.FailedAt, _ = time.Parse(time.RFC3339Nano, msg.Header.Get(headerFailedAt))
+	var profile profileModel.UnificationMessage
+	if err := json.Unmarshal(msg.Body, &profile); err == nil {
+		deadLetter.Profile = profile
+	}
+	return deadLetter
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package activemq
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+)
+
+// jolokiaNotFound is the status Jolokia reports when no queue matches the
+// read, which is the case until the broker created the queue.
+const jolokiaNotFound = 404
+
+// jolokiaResponse is the answer of Jolokia to a read of the QueueSize of the
+// queues matching a pattern, keyed by MBean name.
+type jolokiaResponse struct {
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+	Value  map[string]struct {
+		QueueSize int64 `json:"QueueSize"`
+	} `json:"value"`
+}
+
+// reportDepth reports the depth of the destination in the metrics, read from
+// the broker's Jolokia API at every scrape. Nothing is reported when the
+// broker config has no management URL.
+func reportDepth(broker config.ExternalBrokerConfig, queueName, destination string,
+	tlsCfg config.TLSConfig) error {
+
+	if broker.ManagementURL == "" {
+		return nil
+	}
+	client := &http.Client{}
+	if strings.HasPrefix(broker.ManagementURL, "https://") {
+		rootCAs, err := trustedRoots(tlsCfg)
+		if err != nil {
+			return err
+		}
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: rootCAs}}
+	}
+	// ActiveMQ names STOMP destinations without their "/queue/" prefix.
+	mbean := "org.apache.activemq:type=Broker,brokerName=*,destinationType=Queue,destinationName=" +
+		strings.TrimPrefix(destination, "/queue/")
+	readURL := strings.TrimSuffix(broker.ManagementURL, "/") + "/api/jolokia/read/" + mbean + "/QueueSize"
+
+	metrics.RegisterQueueDepth(queueName, queue.TypeActiveMQ, func(ctx context.Context) (int64, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, readURL, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.SetBasicAuth(broker.Username, broker.Password)
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("activemq: management API answered %s", resp.Status)
+		}
+		var answer jolokiaResponse
+		if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+			return 0, fmt.Errorf("activemq: failed to read the management API answer: %w", err)
+		}
+		if answer.Status == jolokiaNotFound {
+			return 0, nil
+		}
+		if answer.Status != http.StatusOK {
+			return 0, fmt.Errorf("activemq: management API answered %d: %s", answer.Status, answer.Error)
+		}
+		var depth int64
+		for _, destination := range answer.Value {
+			depth += destination.QueueSize
+		}
+		return depth, nil
+	})
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package activemq
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-stomp/stomp/v3"
+	"github.com/go-stomp/stomp/v3/frame"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+)
+
+// headerEnqueuedAt carries the time a message was sent, in Unix milliseconds,
+// from which its consumer measures the queue lag.
+const headerEnqueuedAt = "cdsEnqueuedAt"
+
+// enqueuedAtHeader stamps a message with the current time.
+func enqueuedAtHeader() func(*frame.Frame) error {
+	return stomp.SendOpt.Header(headerEnqueuedAt, strconv.FormatInt(time.Now().UnixMilli(), 10))
+}
+
+// recordEnqueue counts an enqueue to the named queue as accepted or failed.
+func recordEnqueue(queueName string, err error) {
+	if err != nil {
+		metrics.QueueEnqueueFailures.WithLabelValues(queueName, queue.TypeActiveMQ).Inc()
+		return
+	}
+	metrics.QueueEnqueued.WithLabelValues(queueName, queue.TypeActiveMQ).Inc()
+}
+
+// observeLag records how long a received message waited since it was sent.
+// Messages sent without the header are not observed.
+func observeLag(queueName string, msg *stomp.Message) {
+	enqueuedAt, err := strconv.ParseInt(msg.Header.Get(headerEnqueuedAt), 10, 64)
+	if err != nil {
+		return
+	}
+	metrics.QueueLag.WithLabelValues(queueName, queue.TypeActiveMQ).
+		Observe(time.Since(time.UnixMilli(enqueuedAt)).Seconds())
+}
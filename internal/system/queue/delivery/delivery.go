@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package delivery holds the redelivery policy and the dead-letter model shared
+// by the queue providers. Providers acknowledge an item only after its handler
+// succeeds; an item whose handler keeps failing is retried with backoff and
+// then moved to a dead-letter destination instead of being dropped.
+package delivery
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// ErrStopped is returned by Deliver when the queue shuts down before the item
+// was processed. The item is left unacknowledged so that it can be delivered
+// again.
+var ErrStopped = errors.New("delivery: queue stopped before the item was processed")
+
+// ErrDeadLetterNotFound is returned when a dead letter to replay does not exist.
+var ErrDeadLetterNotFound = errors.New("delivery: dead letter not found")
+
+// Policy bounds the delivery attempts of a queue item.
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// NewPolicy builds the redelivery policy from config, using the defaults for
+// unset values.
+func NewPolicy(cfg config.RedeliveryConfig) Policy {
+	policy := Policy{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: time.Duration(cfg.InitialBackoff) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.MaxBackoff) * time.Millisecond,
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = constants.DefaultRedeliveryMaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = constants.DefaultRedeliveryInitialBackoff * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = constants.DefaultRedeliveryMaxBackoff * time.Millisecond
+	}
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = policy.InitialBackoff
+	}
+	return policy
+}
+
+// Workers returns the number of items a consumer should process concurrently
+// for the configured worker count: the count itself, or the number of CPUs
+// when it is not set.
+func Workers(configured int) int {
+	if configured > 0 {
+		return configured
+	}
+	return runtime.NumCPU()
+}
+
+// Deliver calls handle until it succeeds or the attempts run out, doubling the
+// backoff between attempts. It returns the number of attempts made and the
+// last error, or ErrStopped when stop is closed while backing off.
+func (p Policy) Deliver(handle func() error, stop <-chan struct{}) (int, error) {
+	attempt := 0
+	for {
+		attempt++
+		err := handle()
+		if err == nil || attempt >= p.MaxAttempts {
+			return attempt, err
+		}
+		select {
+		case <-stop:
+			return attempt, ErrStopped
+		case <-time.After(p.Backoff(attempt)):
+		}
+	}
+}
+
+// Backoff returns the delay before retrying an item that failed on the given (1-based) attempt. It starts at
+// InitialBackoff and doubles per attempt, up to MaxBackoff.
+func (p Policy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// DeadLetter is a profile whose unification failed on every delivery attempt.
+type DeadLetter struct {
+	Id       string                          `json:"id"`
+	Profile  profileModel.UnificationMessage `json:"profile"`
+	Attempts int                             `json:"attempts"`
+	Error    string                          `json:"error"`
+	FailedAt time.Time                       `json:"failed_at"`
+}
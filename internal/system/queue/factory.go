@@ -24,18 +24,20 @@ import (
 
 	"github.com/wso2/identity-customer-data-service/internal/system/config"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
 	"github.com/wso2/identity-customer-data-service/internal/system/queue/inmemory"
 )
 
 // ProfileQueueProvider is the constructor signature for a
-// ProfileUnificationQueue provider. It receives the broker config and the
-// system TLS config (used to build the CA pool for SSL connections).
-type ProfileQueueProvider func(cfg config.ExternalBrokerConfig, tlsCfg config.TLSConfig) (ProfileUnificationQueue, error)
+// ProfileUnificationQueue provider. It receives the message queue config
+// (broker settings and the redelivery policy) and the system TLS config (used
+// to build the CA pool for SSL connections).
+type ProfileQueueProvider func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (ProfileUnificationQueue, error)
 
 // SchemaSyncQueueProvider is the constructor signature for a SchemaSyncQueue
-// provider. It receives the broker config and the system TLS config (used to
-// build the CA pool for SSL connections).
-type SchemaSyncQueueProvider func(cfg config.ExternalBrokerConfig, tlsCfg config.TLSConfig) (SchemaSyncQueue, error)
+// provider. It receives the message queue config and the system TLS config
+// (used to build the CA pool for SSL connections).
+type SchemaSyncQueueProvider func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (SchemaSyncQueue, error)
 
 var (
 	mu                       sync.RWMutex
@@ -50,7 +52,7 @@ var (
 // Example:
 //
 //	func init() {
-//	    queue.RegisterProfileQueueProvider("myprovider", func(cfg config.ExternalBrokerConfig) (queue.ProfileUnificationQueue, error) {
+//	    queue.RegisterProfileQueueProvider("myprovider", func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.ProfileUnificationQueue, error) {
 //	        return newMyProviderQueue(cfg)
 //	    })
 //	}
@@ -76,7 +78,8 @@ func RegisterSchemaSyncQueueProvider(name string, p SchemaSyncQueueProvider) {
 // returned if no matching provider is found.
 func NewProfileUnificationQueue(cfg config.Config) (ProfileUnificationQueue, error) {
 	if cfg.MessageQueue.Type == TypeMemory || cfg.MessageQueue.Type == "" {
-		return inmemory.NewProfileQueue(constants.DefaultQueueSize), nil
+		return inmemory.NewProfileQueue(constants.DefaultQueueSize, delivery.NewPolicy(cfg.MessageQueue.Redelivery),
+			delivery.Workers(cfg.MessageQueue.Workers)), nil
 	}
 	mu.RLock()
 	p, ok := profileQueueProviders[cfg.MessageQueue.Type]
@@ -85,7 +88,7 @@ func NewProfileUnificationQueue(cfg config.Config) (ProfileUnificationQueue, err
 		return nil, fmt.Errorf("queue: unknown profile queue provider %q; "+
 			"register it by importing its package (see docs/extending-queue-providers.md)", cfg.MessageQueue.Type)
 	}
-	return p(cfg.MessageQueue.Broker, cfg.TLS)
+	return p(cfg.MessageQueue, cfg.TLS)
 }
 
 // NewSchemaSyncQueue returns the SchemaSyncQueue for the provider named in
@@ -104,5 +107,5 @@ func NewSchemaSyncQueue(cfg config.Config) (SchemaSyncQueue, error) {
 		return nil, fmt.Errorf("queue: unknown schema sync queue provider %q; "+
 			"register it by importing its package (see docs/extending-queue-providers.md)", cfg.MessageQueue.Type)
 	}
-	return p(cfg.MessageQueue.Broker, cfg.TLS)
+	return p(cfg.MessageQueue, cfg.TLS)
 }
@@ -23,13 +23,23 @@
 package inmemory
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
 	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
 )
 
+// providerName labels the metrics of the in-memory queues.
+const providerName = "memory"
+
 // -----------------------------------------------------------------------
 // ProfileQueue
 // -----------------------------------------------------------------------
@@ -37,46 +47,201 @@ import (
 // ProfileQueue is the in-memory implementation of queue.ProfileUnificationQueue.
 // It uses a buffered Go channel as the underlying queue. The mu/closed fields
 // synchronize Enqueue and Close to prevent sending on a closed channel.
+// Profiles whose handler keeps failing are kept in a bounded dead-letter list
+// (oldest dropped first), which does not survive a restart. Profiles that are
+// still pending on shutdown are handed back by Drain for the caller to persist.
 type ProfileQueue struct {
-	ch        chan profileModel.Profile
+	ch        chan queuedProfile
+	policy    delivery.Policy
+	workers   int
+	done      chan struct{}
+	stopOnce  sync.Once
 	closeOnce sync.Once
 	mu        sync.RWMutex
 	closed    bool
+
+	// abandon is closed when a drain runs out of time; the workers then
+	// stop processing and set the pending profiles aside in unprocessed.
+	// inProgress holds the profile each worker took from the channel, so that
+	// an abandoned drain can hand back those whose handler has not returned.
+	// receiveMu makes taking a profile and recording it one step.
+	abandon       chan struct{}
+	abandonOnce   sync.Once
+	running       sync.WaitGroup
+	receiveMu     sync.Mutex
+	unprocessedMu sync.Mutex
+	unprocessed   []profileModel.UnificationMessage
+	inProgress    map[int]profileModel.UnificationMessage
+
+	deadLettersMu  sync.Mutex
+	deadLetters    []delivery.DeadLetter
+	maxDeadLetters int
 }
 
-// NewProfileQueue creates a new ProfileQueue with the given buffer size.
-func NewProfileQueue(size int) *ProfileQueue {
-	return &ProfileQueue{ch: make(chan profileModel.Profile, size)}
+// queuedProfile is a profile in the channel, with the time it was enqueued.
+type queuedProfile struct {
+	profile    profileModel.UnificationMessage
+	enqueuedAt time.Time
+}
+
+// NewProfileQueue creates a new ProfileQueue with the given buffer size, whose
+// consumer runs workers handlers concurrently. The dead-letter list holds up
+// to size profiles.
+func NewProfileQueue(size int, policy delivery.Policy, workers int) *ProfileQueue {
+	return &ProfileQueue{
+		ch:             make(chan queuedProfile, size),
+		policy:         policy,
+		workers:        workers,
+		done:           make(chan struct{}),
+		abandon:        make(chan struct{}),
+		inProgress:     map[int]profileModel.UnificationMessage{},
+		maxDeadLetters: size,
+	}
 }
 
 // Enqueue adds a profile to the in-memory channel. It is non-blocking: if
 // the channel is full or closed the item is dropped and an error is returned.
-func (q *ProfileQueue) Enqueue(profile profileModel.Profile) error {
+func (q *ProfileQueue) Enqueue(profile profileModel.UnificationMessage) error {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 	if q.closed {
+		metrics.QueueEnqueueFailures.WithLabelValues(metrics.QueueProfileUnification, providerName).Inc()
 		return fmt.Errorf("inmemory: profile queue closed, dropping profile %s", profile.ProfileId)
 	}
+	metrics.QueueDepth.WithLabelValues(metrics.QueueProfileUnification, providerName).Inc()
 	select {
-	case q.ch <- profile:
+	case q.ch <- queuedProfile{profile: profile, enqueuedAt: time.Now()}:
+		metrics.QueueEnqueued.WithLabelValues(metrics.QueueProfileUnification, providerName).Inc()
 		return nil
 	default:
+		metrics.QueueDepth.WithLabelValues(metrics.QueueProfileUnification, providerName).Dec()
+		metrics.QueueEnqueueFailures.WithLabelValues(metrics.QueueProfileUnification, providerName).Inc()
 		return fmt.Errorf("inmemory: profile queue full, dropping profile %s", profile.ProfileId)
 	}
 }
 
-// Start launches a goroutine that reads profiles from the channel and
-// forwards each one to handler. The goroutine runs until the channel is
-// closed. Always returns nil.
-func (q *ProfileQueue) Start(handler func(profileModel.Profile)) error {
-	go func() {
-		for profile := range q.ch {
-			handler(profile)
-		}
-	}()
+// Start launches the worker goroutines, which read profiles from the channel
+// and forward each one to handler, redelivering it per the redelivery policy
+// while handler fails. A profile that fails every attempt is dead-lettered.
+// The goroutines run until the channel is closed. Always returns nil.
+func (q *ProfileQueue) Start(handler func(profileModel.UnificationMessage) error) error {
+	for i := 0; i < max(q.workers, 1); i++ {
+		q.running.Add(1)
+		go func() {
+			defer q.running.Done()
+			for {
+				item, ok := q.take(i)
+				if !ok {
+					return
+				}
+				select {
+				case <-q.abandon:
+					q.setAside(item)
+				default:
+					q.process(item, handler)
+				}
+				q.unprocessedMu.Lock()
+				delete(q.inProgress, i)
+				q.unprocessedMu.Unlock()
+			}
+		}()
+	}
 	return nil
 }
 
+// take receives the next profile for worker i and records it as in progress.
+// It returns false once the channel is closed and empty.
+func (q *ProfileQueue) take(i int) (queuedProfile, bool) {
+	q.receiveMu.Lock()
+	defer q.receiveMu.Unlock()
+	item, ok := <-q.ch
+	if ok {
+		q.unprocessedMu.Lock()
+		q.inProgress[i] = item.profile
+		q.unprocessedMu.Unlock()
+	}
+	return item, ok
+}
+
+// process hands a profile to handler, redelivering it while handler fails and
+// dead-lettering it once every attempt failed.
+func (q *ProfileQueue) process(item queuedProfile, handler func(profileModel.UnificationMessage) error) {
+	metrics.QueueDepth.WithLabelValues(metrics.QueueProfileUnification, providerName).Dec()
+	metrics.QueueLag.WithLabelValues(metrics.QueueProfileUnification, providerName).
+		Observe(time.Since(item.enqueuedAt).Seconds())
+	metrics.QueueInFlight.WithLabelValues(metrics.QueueProfileUnification, providerName).Inc()
+	defer metrics.QueueInFlight.WithLabelValues(metrics.QueueProfileUnification, providerName).Dec()
+
+	profile := item.profile
+	attempts, err := q.policy.Deliver(func() error { return handler(profile) }, q.done)
+	if errors.Is(err, delivery.ErrStopped) {
+		log.GetLogger().Warn(fmt.Sprintf(
+			"inmemory: profile queue closed while retrying profile %s", profile.ProfileId))
+		q.unprocessedMu.Lock()
+		q.unprocessed = append(q.unprocessed, profile)
+		q.unprocessedMu.Unlock()
+		return
+	}
+	if err != nil {
+		q.deadLetter(profile, attempts, err)
+	}
+}
+
+// setAside keeps a profile that was not processed for Drain to return.
+func (q *ProfileQueue) setAside(item queuedProfile) {
+	metrics.QueueDepth.WithLabelValues(metrics.QueueProfileUnification, providerName).Dec()
+	q.unprocessedMu.Lock()
+	defer q.unprocessedMu.Unlock()
+	q.unprocessed = append(q.unprocessed, item.profile)
+}
+
+// deadLetter records a profile whose handler failed on every attempt.
+func (q *ProfileQueue) deadLetter(profile profileModel.UnificationMessage, attempts int, cause error) {
+	log.GetLogger().Error(fmt.Sprintf(
+		"inmemory: profile %s failed after %d attempts, moving it to the dead-letter list: %v",
+		profile.ProfileId, attempts, cause))
+
+	metrics.QueueDeadLettered.WithLabelValues(metrics.QueueProfileUnification, providerName).Inc()
+
+	q.deadLettersMu.Lock()
+	defer q.deadLettersMu.Unlock()
+	if len(q.deadLetters) >= q.maxDeadLetters {
+		q.deadLetters = q.deadLetters[1:]
+	}
+	q.deadLetters = append(q.deadLetters, delivery.DeadLetter{
+		Id:       uuid.New().String(),
+		Profile:  profile,
+		Attempts: attempts,
+		Error:    cause.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+}
+
+// DeadLetters returns a copy of the dead-letter list, oldest first.
+func (q *ProfileQueue) DeadLetters() ([]delivery.DeadLetter, error) {
+	q.deadLettersMu.Lock()
+	defer q.deadLettersMu.Unlock()
+	return append([]delivery.DeadLetter{}, q.deadLetters...), nil
+}
+
+// ReplayDeadLetter enqueues the dead-lettered profile again and removes it
+// from the dead-letter list. The dead letter is kept when the enqueue fails.
+func (q *ProfileQueue) ReplayDeadLetter(id string) error {
+	q.deadLettersMu.Lock()
+	defer q.deadLettersMu.Unlock()
+	for i, deadLetter := range q.deadLetters {
+		if deadLetter.Id != id {
+			continue
+		}
+		if err := q.Enqueue(deadLetter.Profile); err != nil {
+			return err
+		}
+		q.deadLetters = append(q.deadLetters[:i], q.deadLetters[i+1:]...)
+		return nil
+	}
+	return delivery.ErrDeadLetterNotFound
+}
+
 // Close marks the queue as closed and closes the underlying channel, which
 // causes the consumer goroutine started by Start to exit. It is safe to call
 // Close more than once.
@@ -84,10 +249,59 @@ func (q *ProfileQueue) Close() error {
 	q.mu.Lock()
 	q.closed = true
 	q.mu.Unlock()
+	q.stopOnce.Do(func() { close(q.done) })
 	q.closeOnce.Do(func() { close(q.ch) })
 	return nil
 }
 
+// Drain closes the queue for new profiles and lets the workers process the
+// buffered ones until the buffer is empty or ctx is done. When ctx is done
+// first, the workers stop retrying and taking profiles, and Drain returns
+// without waiting for the handler calls in progress: their profiles are
+// returned along with the buffered ones, and are unified again if the call
+// completes after all. It returns the profiles that were not processed,
+// which are lost unless the caller persists them.
+func (q *ProfileQueue) Drain(ctx context.Context) []profileModel.UnificationMessage {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.closeOnce.Do(func() { close(q.ch) })
+
+	finished := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		q.abandonOnce.Do(func() { close(q.abandon) })
+	}
+	q.stopOnce.Do(func() { close(q.done) })
+
+	// Without workers (Start was never called) the buffer is still full.
+	q.receiveMu.Lock()
+	for item := range q.ch {
+		q.setAside(item)
+	}
+	q.receiveMu.Unlock()
+
+	q.unprocessedMu.Lock()
+	defer q.unprocessedMu.Unlock()
+	unprocessed := q.unprocessed
+	returned := map[profileModel.UnificationMessage]bool{}
+	for _, profile := range unprocessed {
+		returned[profile] = true
+	}
+	for _, profile := range q.inProgress {
+		if !returned[profile] {
+			unprocessed = append(unprocessed, profile)
+		}
+	}
+	q.unprocessed = nil
+	return unprocessed
+}
+
 // -----------------------------------------------------------------------
 // SchemaSyncQueue
 // -----------------------------------------------------------------------
@@ -96,15 +310,33 @@ func (q *ProfileQueue) Close() error {
 // It uses a buffered Go channel as the underlying queue. The mu/closed fields
 // synchronize Enqueue and Close to prevent sending on a closed channel.
 type SchemaSyncQueue struct {
-	ch        chan schemaModel.ProfileSchemaSync
+	ch        chan queuedSchemaSync
 	closeOnce sync.Once
 	mu        sync.RWMutex
 	closed    bool
+
+	// abandon is closed when a drain runs out of time; the consumer then
+	// sets the pending jobs aside in unprocessed. inProgress is the job the
+	// consumer took from the channel, which an abandoned drain hands back if
+	// the handler has not returned.
+	abandon       chan struct{}
+	abandonOnce   sync.Once
+	running       sync.WaitGroup
+	receiveMu     sync.Mutex
+	unprocessedMu sync.Mutex
+	unprocessed   []schemaModel.ProfileSchemaSync
+	inProgress    *schemaModel.ProfileSchemaSync
+}
+
+// queuedSchemaSync is a schema sync job in the channel, with the time it was enqueued.
+type queuedSchemaSync struct {
+	sync       schemaModel.ProfileSchemaSync
+	enqueuedAt time.Time
 }
 
 // NewSchemaSyncQueue creates a new SchemaSyncQueue with the given buffer size.
 func NewSchemaSyncQueue(size int) *SchemaSyncQueue {
-	return &SchemaSyncQueue{ch: make(chan schemaModel.ProfileSchemaSync, size)}
+	return &SchemaSyncQueue{ch: make(chan queuedSchemaSync, size), abandon: make(chan struct{})}
 }
 
 // Enqueue adds a schema sync job to the in-memory channel. It is non-blocking:
@@ -113,12 +345,17 @@ func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) error {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 	if q.closed {
+		metrics.QueueEnqueueFailures.WithLabelValues(metrics.QueueSchemaSync, providerName).Inc()
 		return fmt.Errorf("inmemory: schema sync queue closed, dropping job for tenant %s", sync.OrgId)
 	}
+	metrics.QueueDepth.WithLabelValues(metrics.QueueSchemaSync, providerName).Inc()
 	select {
-	case q.ch <- sync:
+	case q.ch <- queuedSchemaSync{sync: sync, enqueuedAt: time.Now()}:
+		metrics.QueueEnqueued.WithLabelValues(metrics.QueueSchemaSync, providerName).Inc()
 		return nil
 	default:
+		metrics.QueueDepth.WithLabelValues(metrics.QueueSchemaSync, providerName).Dec()
+		metrics.QueueEnqueueFailures.WithLabelValues(metrics.QueueSchemaSync, providerName).Inc()
 		return fmt.Errorf("inmemory: schema sync queue full, dropping job for tenant %s", sync.OrgId)
 	}
 }
@@ -127,14 +364,59 @@ func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) error {
 // forwards each one to handler. The goroutine runs until the channel is
 // closed. Always returns nil.
 func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) error {
+	q.running.Add(1)
 	go func() {
-		for sync := range q.ch {
-			handler(sync)
+		defer q.running.Done()
+		for {
+			item, ok := q.take()
+			if !ok {
+				return
+			}
+			metrics.QueueDepth.WithLabelValues(metrics.QueueSchemaSync, providerName).Dec()
+			select {
+			case <-q.abandon:
+				q.unprocessedMu.Lock()
+				q.unprocessed = append(q.unprocessed, item.sync)
+				q.inProgress = nil
+				q.unprocessedMu.Unlock()
+				continue
+			default:
+			}
+			metrics.QueueLag.WithLabelValues(metrics.QueueSchemaSync, providerName).
+				Observe(time.Since(item.enqueuedAt).Seconds())
+			metrics.QueueInFlight.WithLabelValues(metrics.QueueSchemaSync, providerName).Inc()
+			handler(item.sync)
+			metrics.QueueInFlight.WithLabelValues(metrics.QueueSchemaSync, providerName).Dec()
+			q.unprocessedMu.Lock()
+			q.inProgress = nil
+			q.unprocessedMu.Unlock()
 		}
 	}()
 	return nil
 }
 
+// take receives the next job and records it as in progress. It returns
+// false once the channel is closed and empty.
+func (q *SchemaSyncQueue) take() (queuedSchemaSync, bool) {
+	q.receiveMu.Lock()
+	defer q.receiveMu.Unlock()
+	item, ok := <-q.ch
+	if ok {
+		q.unprocessedMu.Lock()
+		q.inProgress = &item.sync
+		q.unprocessedMu.Unlock()
+	}
+	return item, ok
+}
+
+// setAside keeps a job that was not processed for Drain to return.
+func (q *SchemaSyncQueue) setAside(item queuedSchemaSync) {
+	metrics.QueueDepth.WithLabelValues(metrics.QueueSchemaSync, providerName).Dec()
+	q.unprocessedMu.Lock()
+	defer q.unprocessedMu.Unlock()
+	q.unprocessed = append(q.unprocessed, item.sync)
+}
+
 // Close marks the queue as closed and closes the underlying channel, which
 // causes the consumer goroutine started by Start to exit. It is safe to call
 // Close more than once.
@@ -145,3 +427,39 @@ func (q *SchemaSyncQueue) Close() error {
 	q.closeOnce.Do(func() { close(q.ch) })
 	return nil
 }
+
+// Drain closes the queue for new jobs and lets the consumer process the
+// buffered ones until the buffer is empty or ctx is done. When ctx is done
+// first, the consumer stops taking jobs, and Drain returns without waiting
+// for the job in progress, which is returned along with the buffered ones and
+// runs again on the next start. It returns the jobs that were not processed.
+func (q *SchemaSyncQueue) Drain(ctx context.Context) []schemaModel.ProfileSchemaSync {
+	_ = q.Close()
+
+	finished := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		q.abandonOnce.Do(func() { close(q.abandon) })
+	}
+
+	// Without a consumer (Start was never called) the buffer is still full.
+	q.receiveMu.Lock()
+	for item := range q.ch {
+		q.setAside(item)
+	}
+	q.receiveMu.Unlock()
+
+	q.unprocessedMu.Lock()
+	defer q.unprocessedMu.Unlock()
+	unprocessed := q.unprocessed
+	if q.inProgress != nil {
+		unprocessed = append(unprocessed, *q.inProgress)
+	}
+	q.unprocessed = nil
+	return unprocessed
+}
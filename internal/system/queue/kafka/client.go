@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+)
+
+// Record is a message to produce. Records with the same Key are written to
+// the same partition, and a nil Value is a tombstone.
+type Record struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Message is a record read from a partition.
+type Message struct {
+	Record
+	Partition int32
+	Offset    int64
+	Timestamp time.Time
+}
+
+// Client is the subset of a Kafka client the queue provider needs. It is
+// implemented by the franz adapter around the franz-go client library, and by
+// kafkatest.Broker for tests.
+type Client interface {
+	// Produce writes the record and returns once the broker acknowledged it.
+	Produce(ctx context.Context, record Record) error
+
+	// JoinGroup joins the consumer group of the topic. The partitions of the
+	// topic are balanced across the members of the group.
+	JoinGroup(group, topic string) (GroupConsumer, error)
+
+	// ReadTopic returns all messages of the topic, from the earliest retained
+	// offset of every partition to its current end.
+	ReadTopic(ctx context.Context, topic string) ([]Message, error)
+
+	// Lag returns the number of messages of the topic after the offsets the
+	// group committed. A topic that does not exist has no lag.
+	Lag(ctx context.Context, group, topic string) (int64, error)
+
+	// Close releases the connections of the client.
+	Close() error
+}
+
+// GroupConsumer is a member of a consumer group.
+type GroupConsumer interface {
+	// Poll blocks until a message of an assigned partition is available, or
+	// ctx is done. After a rebalance, a partition is read again from its
+	// committed offset.
+	Poll(ctx context.Context) (Message, error)
+
+	// Commit marks the message, and all earlier messages of its partition, as
+	// processed by the group.
+	Commit(ctx context.Context, msg Message) error
+
+	// Close leaves the group.
+	Close() error
+}
+
+// ClientFactory creates a Client for the given config.
+type ClientFactory func(cfg config.KafkaConfig, tlsCfg config.TLSConfig) (Client, error)
+
+// ErrNoClient is returned when the kafka provider is used before a client
+// factory was registered.
+var ErrNoClient = errors.New("kafka: no client registered; import a Kafka client adapter " +
+	"that calls kafka.RegisterClientFactory")
+
+var (
+	clientMu      sync.RWMutex
+	clientFactory ClientFactory
+)
+
+// RegisterClientFactory sets the factory the kafka provider creates its
+// clients with. Call it in the init() function of a client adapter package.
+func RegisterClientFactory(f ClientFactory) {
+	clientMu.Lock()
+	defer clientMu.Unlock()
+	clientFactory = f
+}
+
+func newClient(cfg config.KafkaConfig, tlsCfg config.TLSConfig) (Client, error) {
+	clientMu.RLock()
+	f := clientFactory
+	clientMu.RUnlock()
+	if f == nil {
+		return nil, ErrNoClient
+	}
+	return f(cfg, tlsCfg)
+}
@@ -0,0 +1,339 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package franz is the Kafka client adapter of the kafka queue provider. It
+// implements kafka.Client with github.com/twmb/franz-go and registers itself
+// with kafka.RegisterClientFactory when imported.
+//
+// Brokers given with an "ssl://" prefix are connected to over TLS, trusting
+// the system roots and the configured trust_store. A username enables
+// SASL/PLAIN authentication.
+package franz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/kafka"
+	"github.com/wso2/identity-customer-data-service/internal/system/utils"
+)
+
+const sslPrefix = "ssl://"
+
+// errClientClosed is returned by Poll once the consumer was closed.
+var errClientClosed = errors.New("kafka: client closed")
+
+func init() {
+	kafka.RegisterClientFactory(NewClient)
+}
+
+// Client implements kafka.Client. It produces and reads topics with one
+// franz-go client, and joins consumer groups with a client per member.
+type Client struct {
+	opts     []kgo.Opt
+	producer *kgo.Client
+}
+
+// NewClient connects to the brokers of cfg.
+func NewClient(cfg config.KafkaConfig, tlsCfg config.TLSConfig) (kafka.Client, error) {
+	opts, err := clientOpts(cfg, tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := kgo.NewClient(append(opts, kgo.AllowAutoTopicCreation())...)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: failed to create client: %w", err)
+	}
+	return &Client{opts: opts, producer: producer}, nil
+}
+
+// clientOpts returns the connection options shared by all clients.
+func clientOpts(cfg config.KafkaConfig, tlsCfg config.TLSConfig) ([]kgo.Opt, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka: no brokers configured")
+	}
+	brokers := make([]string, 0, len(cfg.Brokers))
+	useTLS := false
+	for _, broker := range cfg.Brokers {
+		if strings.HasPrefix(broker, sslPrefix) {
+			useTLS = true
+		}
+		brokers = append(brokers, strings.TrimPrefix(broker, sslPrefix))
+	}
+	opts := []kgo.Opt{kgo.SeedBrokers(brokers...)}
+	if useTLS {
+		rootCAs, err := trustedRoots(tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.DialTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12, RootCAs: rootCAs}))
+	}
+	if cfg.Username != "" {
+		opts = append(opts, kgo.SASL(plain.Auth{User: cfg.Username, Pass: cfg.Password}.AsMechanism()))
+	}
+	return opts, nil
+}
+
+// trustedRoots returns the system roots together with the certificates of the
+// configured trust store.
+func trustedRoots(tlsCfg config.TLSConfig) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if tlsCfg.TrustStore == "" {
+		return rootCAs, nil
+	}
+	certDir := tlsCfg.CertDir
+	if certDir == "" {
+		certDir = filepath.Join(utils.GetCDSHome(), "etc", "certs")
+	}
+	trustPEM, err := os.ReadFile(filepath.Join(certDir, tlsCfg.TrustStore))
+	if err != nil {
+		return nil, fmt.Errorf("kafka: failed to read trust_store: %w", err)
+	}
+	if !rootCAs.AppendCertsFromPEM(trustPEM) {
+		return nil, errors.New("kafka: failed to append certs from trust_store")
+	}
+	return rootCAs, nil
+}
+
+// Produce writes the record, partitioned by its key, and waits for the
+// acknowledgement of all in-sync replicas.
+func (c *Client) Produce(ctx context.Context, record kafka.Record) error {
+	r := &kgo.Record{Topic: record.Topic, Key: record.Key, Value: record.Value}
+	for key, value := range record.Headers {
+		r.Headers = append(r.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+	}
+	return c.producer.ProduceSync(ctx, r).FirstErr()
+}
+
+// JoinGroup creates a member of the consumer group. Offsets are committed only
+// by Commit; a group without committed offsets starts at the earliest offset.
+func (c *Client) JoinGroup(group, topic string) (kafka.GroupConsumer, error) {
+	opts := append(append([]kgo.Opt{}, c.opts...),
+		kgo.ConsumerGroup(group),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.DisableAutoCommit(),
+		kgo.AllowAutoTopicCreation(),
+	)
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: failed to create consumer: %w", err)
+	}
+	return &groupConsumer{client: client}, nil
+}
+
+// ReadTopic reads every partition of the topic from its log start offset to
+// its high watermark. A topic that does not exist has no messages.
+func (c *Client) ReadTopic(ctx context.Context, topic string) ([]kafka.Message, error) {
+	admin := kadm.NewClient(c.producer)
+	starts, err := admin.ListStartOffsets(ctx, topic)
+	if err == nil {
+		err = starts.Error()
+	}
+	if errors.Is(err, kerr.UnknownTopicOrPartition) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ends, err := admin.ListEndOffsets(ctx, topic)
+	if err == nil {
+		err = ends.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from := map[int32]kgo.Offset{}
+	remaining := map[int32]int64{}
+	starts.Each(func(start kadm.ListedOffset) {
+		end, ok := ends.Lookup(topic, start.Partition)
+		if ok && end.Offset > start.Offset {
+			from[start.Partition] = kgo.NewOffset().At(start.Offset)
+			remaining[start.Partition] = end.Offset
+		}
+	})
+	if len(from) == 0 {
+		return nil, nil
+	}
+
+	reader, err := kgo.NewClient(append(append([]kgo.Opt{}, c.opts...),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: from}))...)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: failed to create reader: %w", err)
+	}
+	defer reader.Close()
+
+	var messages []kafka.Message
+	for len(remaining) > 0 {
+		fetches := reader.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var fetchErr error
+		fetches.EachError(func(_ string, _ int32, err error) {
+			if fetchErr == nil {
+				fetchErr = err
+			}
+		})
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		fetches.EachRecord(func(r *kgo.Record) {
+			end, ok := remaining[r.Partition]
+			if !ok || r.Offset >= end {
+				return
+			}
+			messages = append(messages, toMessage(r))
+			if r.Offset+1 >= end {
+				delete(remaining, r.Partition)
+			}
+		})
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].Timestamp.Before(messages[j].Timestamp) })
+	return messages, nil
+}
+
+// Lag sums, over the partitions of the topic, the messages after the offset
+// the group committed, or after the log start offset when it committed none.
+func (c *Client) Lag(ctx context.Context, group, topic string) (int64, error) {
+	admin := kadm.NewClient(c.producer)
+	starts, err := admin.ListStartOffsets(ctx, topic)
+	if err == nil {
+		err = starts.Error()
+	}
+	if errors.Is(err, kerr.UnknownTopicOrPartition) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	ends, err := admin.ListEndOffsets(ctx, topic)
+	if err == nil {
+		err = ends.Error()
+	}
+	if err != nil {
+		return 0, err
+	}
+	committed, err := admin.FetchOffsets(ctx, group)
+	if err != nil {
+		return 0, err
+	}
+
+	var lag int64
+	ends.Each(func(end kadm.ListedOffset) {
+		from := int64(0)
+		if start, ok := starts.Lookup(topic, end.Partition); ok {
+			from = start.Offset
+		}
+		if offset, ok := committed.Lookup(topic, end.Partition); ok && offset.Err == nil && offset.At > from {
+			from = offset.At
+		}
+		if end.Offset > from {
+			lag += end.Offset - from
+		}
+	})
+	return lag, nil
+}
+
+// Close closes the client used to produce and read topics.
+func (c *Client) Close() error {
+	c.producer.Close()
+	return nil
+}
+
+// groupConsumer is a member of a consumer group.
+type groupConsumer struct {
+	client *kgo.Client
+}
+
+// Poll returns the next message of the partitions assigned to the member. It
+// takes one record at a time from the client, so that no records of a
+// partition revoked by a rebalance are held here.
+func (g *groupConsumer) Poll(ctx context.Context) (kafka.Message, error) {
+	for {
+		fetches := g.client.PollRecords(ctx, 1)
+		if fetches.IsClientClosed() {
+			return kafka.Message{}, errClientClosed
+		}
+		if records := fetches.Records(); len(records) > 0 {
+			return toMessage(records[0]), nil
+		}
+		if err := ctx.Err(); err != nil {
+			return kafka.Message{}, err
+		}
+		var fetchErr error
+		fetches.EachError(func(topic string, partition int32, err error) {
+			if fetchErr == nil {
+				fetchErr = fmt.Errorf("fetching %s/%d: %w", topic, partition, err)
+			}
+		})
+		if fetchErr != nil {
+			return kafka.Message{}, fetchErr
+		}
+	}
+}
+
+// Commit commits the offset after msg for the group.
+func (g *groupConsumer) Commit(ctx context.Context, msg kafka.Message) error {
+	return g.client.CommitRecords(ctx, &kgo.Record{
+		Topic:       msg.Topic,
+		Partition:   msg.Partition,
+		Offset:      msg.Offset,
+		LeaderEpoch: -1,
+	})
+}
+
+// Close leaves the group and closes the client of the member.
+func (g *groupConsumer) Close() error {
+	g.client.Close()
+	return nil
+}
+
+func toMessage(r *kgo.Record) kafka.Message {
+	headers := make(map[string]string, len(r.Headers))
+	for _, header := range r.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	return kafka.Message{
+		Record: kafka.Record{
+			Topic:   r.Topic,
+			Key:     r.Key,
+			Value:   r.Value,
+			Headers: headers,
+		},
+		Partition: r.Partition,
+		Offset:    r.Offset,
+		Timestamp: r.Timestamp,
+	}
+}
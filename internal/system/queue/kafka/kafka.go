@@ -0,0 +1,459 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package kafka provides Kafka implementations of the
+// queue.ProfileUnificationQueue and queue.SchemaSyncQueue interfaces. All CDS
+// replicas join one consumer group, so each partition is consumed by a single
+// replica, and messages are keyed by organization (or profile) so that the
+// unifications of a customer are processed in order. Offsets are committed
+// only after the handler succeeded.
+//
+// The provider talks to Kafka through the Client interface. The franz
+// subpackage is the client adapter, and registers itself with
+// RegisterClientFactory when imported; kafkatest.Broker implements Client in
+// memory for tests.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+)
+
+const (
+	// PartitionByOrg keys profile messages by organization handle.
+	PartitionByOrg = "org"
+	// PartitionByProfile keys profile messages by profile id.
+	PartitionByProfile = "profile"
+
+	defaultProfileConsumerGroup    = "cds-profile-unification"
+	defaultSchemaSyncConsumerGroup = "cds-schema-sync"
+	defaultProfileTopic            = "cds-profile-unification"
+	defaultSchemaSyncTopic         = "cds-schema-sync"
+
+	// pollRetryInterval is how long the consumer waits after a failed poll.
+	pollRetryInterval = 2 * time.Second
+	// readTopicTimeout bounds reading the dead-letter topic.
+	readTopicTimeout = 10 * time.Second
+	// commitTimeout bounds committing the offset of a processed message.
+	commitTimeout = 10 * time.Second
+)
+
+func init() {
+	queue.RegisterProfileQueueProvider(queue.TypeKafka,
+		func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.ProfileUnificationQueue, error) {
+			client, err := newClient(cfg.Kafka, tlsCfg)
+			if err != nil {
+				return nil, err
+			}
+			return NewProfileQueue(client, cfg.Kafka, delivery.NewPolicy(cfg.Redelivery),
+				delivery.Workers(cfg.Workers)), nil
+		},
+	)
+	queue.RegisterSchemaSyncQueueProvider(queue.TypeKafka,
+		func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.SchemaSyncQueue, error) {
+			client, err := newClient(cfg.Kafka, tlsCfg)
+			if err != nil {
+				return nil, err
+			}
+			return NewSchemaSyncQueue(client, cfg.Kafka), nil
+		},
+	)
+}
+
+// consumerLoop runs members of a consumer group until the queue is closed.
+// Every member consumes its own partitions one message at a time, so running
+// several members in one instance processes partitions concurrently while
+// keeping the order within each partition. process returns false when the
+// queue closed before msg was committed. The depth of the queue reported in
+// the metrics is the lag of the group on the topic.
+type consumerLoop struct {
+	client    Client
+	group     string
+	topic     string
+	metric    string // the queue label of the metrics
+	members   int
+	consumers []GroupConsumer
+	ctx       context.Context
+	cancel    context.CancelFunc
+	running   sync.WaitGroup
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+func newConsumerLoop(client Client, group, topic, metric string, members int) *consumerLoop {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &consumerLoop{
+		client:  client,
+		group:   group,
+		topic:   topic,
+		metric:  metric,
+		members: max(members, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	metrics.RegisterQueueDepth(metric, queue.TypeKafka, func(ctx context.Context) (int64, error) {
+		return client.Lag(ctx, group, topic)
+	})
+	return l
+}
+
+// recordEnqueue counts a message produced to the topic as accepted or failed.
+func (l *consumerLoop) recordEnqueue(err error) {
+	if err != nil {
+		metrics.QueueEnqueueFailures.WithLabelValues(l.metric, queue.TypeKafka).Inc()
+		return
+	}
+	metrics.QueueEnqueued.WithLabelValues(l.metric, queue.TypeKafka).Inc()
+}
+
+func (l *consumerLoop) start(process func(GroupConsumer, Message) bool) error {
+	var err error
+	started := false
+	l.startOnce.Do(func() {
+		started = true
+		for i := 0; i < l.members; i++ {
+			consumer, joinErr := l.client.JoinGroup(l.group, l.topic)
+			if joinErr != nil {
+				err = fmt.Errorf("kafka: failed to join consumer group %s of topic %s: %w", l.group, l.topic, joinErr)
+				l.cancel()
+				break
+			}
+			l.consumers = append(l.consumers, consumer)
+			l.running.Add(1)
+			go l.consume(consumer, process)
+		}
+	})
+	if !started {
+		return fmt.Errorf("kafka: consumer of topic %s already started", l.topic)
+	}
+	return err
+}
+
+func (l *consumerLoop) consume(consumer GroupConsumer, process func(GroupConsumer, Message) bool) {
+	defer l.running.Done()
+	for {
+		msg, err := consumer.Poll(l.ctx)
+		if l.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.GetLogger().Error(fmt.Sprintf("kafka: failed to poll topic %s: %v", l.topic, err))
+			if !l.sleep(pollRetryInterval) {
+				return
+			}
+			continue
+		}
+		if !process(consumer, msg) {
+			return
+		}
+	}
+}
+
+// commit marks msg as processed. It does not use the context of the loop, so
+// that the commit of a message processed while the queue closes still
+// completes. A failed commit (for example after a rebalance) only means msg is
+// delivered again.
+func (l *consumerLoop) commit(consumer GroupConsumer, msg Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), commitTimeout)
+	defer cancel()
+	if err := consumer.Commit(ctx, msg); err != nil {
+		log.GetLogger().Warn(fmt.Sprintf("kafka: failed to commit offset %d of %s/%d, it will be redelivered: %v",
+			msg.Offset, msg.Topic, msg.Partition, err))
+	}
+}
+
+// sleep waits for d, returning false when the queue is closed meanwhile.
+func (l *consumerLoop) sleep(d time.Duration) bool {
+	select {
+	case <-l.ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// close stops the members, waiting for the messages in progress, leaves the
+// group and closes the client. Uncommitted messages are redelivered to the
+// remaining members of the group.
+func (l *consumerLoop) close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		l.cancel()
+		l.startOnce.Do(func() {})
+		l.running.Wait()
+		metrics.UnregisterQueueDepth(l.metric, queue.TypeKafka)
+		for _, consumer := range l.consumers {
+			if closeErr := consumer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if closeErr := l.client.Close(); err == nil {
+			err = closeErr
+		}
+	})
+	return err
+}
+
+// -----------------------------------------------------------------------
+// ProfileQueue
+// -----------------------------------------------------------------------
+
+// ProfileQueue is the Kafka implementation of queue.ProfileUnificationQueue.
+// A profile whose handler fails on every attempt is written to the
+// dead-letter topic, keyed by dead-letter id, before its offset is committed;
+// replaying it writes a tombstone for that id.
+type ProfileQueue struct {
+	loop            *consumerLoop
+	topic           string
+	deadLetterTopic string
+	partitionKey    string
+	policy          delivery.Policy
+}
+
+// NewProfileQueue creates a ProfileQueue on the given client, running workers
+// members of the consumer group. Unset settings of cfg take their defaults.
+func NewProfileQueue(client Client, cfg config.KafkaConfig, policy delivery.Policy, workers int) *ProfileQueue {
+	topic := orDefault(cfg.ProfileTopic, defaultProfileTopic)
+	return &ProfileQueue{
+		loop: newConsumerLoop(client, orDefault(cfg.ProfileConsumerGroup, defaultProfileConsumerGroup), topic,
+			metrics.QueueProfileUnification, workers),
+		topic:           topic,
+		deadLetterTopic: orDefault(cfg.DeadLetterTopic, topic+constants.DeadLetterQueueSuffix),
+		partitionKey:    orDefault(cfg.PartitionKey, PartitionByOrg),
+		policy:          policy,
+	}
+}
+
+// Enqueue writes the profile to the profile topic.
+func (q *ProfileQueue) Enqueue(profile profileModel.UnificationMessage) (err error) {
+	defer func() { q.loop.recordEnqueue(err) }()
+	value, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("kafka: failed to serialise profile %s: %w", profile.ProfileId, err)
+	}
+	key := profile.OrgHandle
+	if q.partitionKey == PartitionByProfile {
+		key = profile.ProfileId
+	}
+	if err := q.loop.client.Produce(q.loop.ctx, Record{Topic: q.topic, Key: []byte(key), Value: value}); err != nil {
+		return fmt.Errorf("kafka: failed to produce profile %s: %w", profile.ProfileId, err)
+	}
+	return nil
+}
+
+// Start joins the consumer group and forwards the profiles of the assigned
+// partitions to handler, one at a time per member. A profile is committed once
+// handler returns nil; otherwise it is retried per the redelivery policy and
+// then dead-lettered.
+func (q *ProfileQueue) Start(handler func(profileModel.UnificationMessage) error) error {
+	return q.loop.start(func(consumer GroupConsumer, msg Message) bool {
+		var profile profileModel.UnificationMessage
+		attempts, cause := 1, json.Unmarshal(msg.Value, &profile)
+		if cause == nil {
+			attempts, cause = q.policy.Deliver(func() error { return handler(profile) }, q.loop.ctx.Done())
+		}
+		if errors.Is(cause, delivery.ErrStopped) {
+			return false
+		}
+		if cause != nil && !q.deadLetter(profile, attempts, cause) {
+			return false
+		}
+		q.loop.commit(consumer, msg)
+		return true
+	})
+}
+
+// deadLetter writes a dead letter for the profile. Committing the offset past
+// a profile that is not dead-lettered would lose it, so writing is retried
+// until it succeeds; false is returned when the queue is closed meanwhile.
+func (q *ProfileQueue) deadLetter(profile profileModel.UnificationMessage, attempts int, cause error) bool {
+	log.GetLogger().Error(fmt.Sprintf("kafka: profile %s failed after %d attempts, dead-lettering it: %v",
+		profile.ProfileId, attempts, cause))
+	deadLetter := delivery.DeadLetter{
+		Id:       uuid.New().String(),
+		Profile:  profile,
+		Attempts: attempts,
+		Error:    cause.Error(),
+		FailedAt: time.Now().UTC(),
+	}
+	value, err := json.Marshal(deadLetter)
+	if err != nil {
+		log.GetLogger().Error(fmt.Sprintf("kafka: failed to serialise dead letter of profile %s: %v",
+			profile.ProfileId, err))
+		return true
+	}
+	record := Record{Topic: q.deadLetterTopic, Key: []byte(deadLetter.Id), Value: value}
+	for attempt := 1; ; attempt++ {
+		err := q.loop.client.Produce(q.loop.ctx, record)
+		if err == nil {
+			metrics.QueueDeadLettered.WithLabelValues(metrics.QueueProfileUnification, queue.TypeKafka).Inc()
+			return true
+		}
+		log.GetLogger().Error(fmt.Sprintf("kafka: failed to write dead letter of profile %s to %s: %v",
+			profile.ProfileId, q.deadLetterTopic, err))
+		if !q.loop.sleep(q.policy.Backoff(attempt)) {
+			return false
+		}
+	}
+}
+
+// DeadLetters returns the dead letters that have not been replayed, oldest
+// first.
+func (q *ProfileQueue) DeadLetters() ([]delivery.DeadLetter, error) {
+	deadLetters, err := q.readDeadLetters()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]delivery.DeadLetter, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		result = append(result, deadLetter)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].FailedAt.Before(result[j].FailedAt) })
+	return result, nil
+}
+
+// ReplayDeadLetter writes the dead-lettered profile back to the profile topic
+// and a tombstone for the dead letter.
+func (q *ProfileQueue) ReplayDeadLetter(id string) error {
+	deadLetters, err := q.readDeadLetters()
+	if err != nil {
+		return err
+	}
+	deadLetter, ok := deadLetters[id]
+	if !ok {
+		return delivery.ErrDeadLetterNotFound
+	}
+	if err := q.Enqueue(deadLetter.Profile); err != nil {
+		return err
+	}
+	if err := q.loop.client.Produce(q.loop.ctx, Record{Topic: q.deadLetterTopic, Key: []byte(id)}); err != nil {
+		return fmt.Errorf("kafka: failed to write tombstone of dead letter %s: %w", id, err)
+	}
+	return nil
+}
+
+// readDeadLetters reads the dead-letter topic, dropping the dead letters that
+// have a later tombstone.
+func (q *ProfileQueue) readDeadLetters() (map[string]delivery.DeadLetter, error) {
+	ctx, cancel := context.WithTimeout(q.loop.ctx, readTopicTimeout)
+	defer cancel()
+	messages, err := q.loop.client.ReadTopic(ctx, q.deadLetterTopic)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: failed to read dead-letter topic %s: %w", q.deadLetterTopic, err)
+	}
+	deadLetters := map[string]delivery.DeadLetter{}
+	for _, msg := range messages {
+		id := string(msg.Key)
+		if msg.Value == nil {
+			delete(deadLetters, id)
+			continue
+		}
+		var deadLetter delivery.DeadLetter
+		if err := json.Unmarshal(msg.Value, &deadLetter); err != nil {
+			log.GetLogger().Warn(fmt.Sprintf("kafka: skipping unreadable dead letter %s: %v", id, err))
+			continue
+		}
+		deadLetters[id] = deadLetter
+	}
+	return deadLetters, nil
+}
+
+// Close leaves the consumer group and closes the client. It is safe to call
+// Close more than once.
+func (q *ProfileQueue) Close() error {
+	return q.loop.close()
+}
+
+// -----------------------------------------------------------------------
+// SchemaSyncQueue
+// -----------------------------------------------------------------------
+
+// SchemaSyncQueue is the Kafka implementation of queue.SchemaSyncQueue.
+// Messages are keyed by organization.
+type SchemaSyncQueue struct {
+	loop  *consumerLoop
+	topic string
+}
+
+// NewSchemaSyncQueue creates a SchemaSyncQueue on the given client. Unset
+// settings of cfg take their defaults.
+func NewSchemaSyncQueue(client Client, cfg config.KafkaConfig) *SchemaSyncQueue {
+	topic := orDefault(cfg.SchemaSyncTopic, defaultSchemaSyncTopic)
+	return &SchemaSyncQueue{
+		loop: newConsumerLoop(client, orDefault(cfg.SchemaSyncConsumerGroup, defaultSchemaSyncConsumerGroup), topic,
+			metrics.QueueSchemaSync, 1),
+		topic: topic,
+	}
+}
+
+// Enqueue writes the schema sync job to the schema sync topic.
+func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) (err error) {
+	defer func() { q.loop.recordEnqueue(err) }()
+	value, err := json.Marshal(sync)
+	if err != nil {
+		return fmt.Errorf("kafka: failed to serialise schema sync job for tenant %s: %w", sync.OrgId, err)
+	}
+	if err := q.loop.client.Produce(q.loop.ctx, Record{Topic: q.topic, Key: []byte(sync.OrgId), Value: value}); err != nil {
+		return fmt.Errorf("kafka: failed to produce schema sync job for tenant %s: %w", sync.OrgId, err)
+	}
+	return nil
+}
+
+// Start joins the consumer group and forwards the jobs of the assigned
+// partitions to handler, committing each once handler returns.
+func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) error {
+	return q.loop.start(func(consumer GroupConsumer, msg Message) bool {
+		var sync schemaModel.ProfileSchemaSync
+		if err := json.Unmarshal(msg.Value, &sync); err != nil {
+			log.GetLogger().Error(fmt.Sprintf("kafka: skipping unreadable schema sync job at %s/%d offset %d: %v",
+				msg.Topic, msg.Partition, msg.Offset, err))
+		} else {
+			handler(sync)
+		}
+		q.loop.commit(consumer, msg)
+		return true
+	})
+}
+
+// Close leaves the consumer group and closes the client. It is safe to call
+// Close more than once.
+func (q *SchemaSyncQueue) Close() error {
+	return q.loop.close()
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
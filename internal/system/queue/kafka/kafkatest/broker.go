@@ -0,0 +1,276 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package kafkatest provides an in-memory Kafka broker implementing
+// kafka.Client, for testing the kafka queue provider without a cluster. It
+// models the behaviour the provider relies on: keyed partitioning, consumer
+// groups whose members share the partitions of a topic, committed offsets, and
+// redelivery of uncommitted messages after a rebalance.
+package kafkatest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/kafka"
+)
+
+// Broker is an in-memory Kafka broker. All clients of a test share one
+// Broker; closing it as a client keeps its data.
+type Broker struct {
+	partitions int
+
+	mu      sync.Mutex
+	changed chan struct{} // closed and replaced whenever a log or an assignment changes
+	topics  map[string][][]kafka.Message
+	groups  map[string]*group
+	failing int // number of upcoming Produce calls that fail
+}
+
+// group is a consumer group of one topic.
+type group struct {
+	members   []*Consumer
+	committed map[int32]int64
+}
+
+// NewBroker creates a broker whose topics have the given number of
+// partitions.
+func NewBroker(partitions int) *Broker {
+	return &Broker{
+		partitions: partitions,
+		changed:    make(chan struct{}),
+		topics:     map[string][][]kafka.Message{},
+		groups:     map[string]*group{},
+	}
+}
+
+// FailProduce makes the next n Produce calls fail.
+func (b *Broker) FailProduce(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failing = n
+}
+
+// Produce appends the record to the partition its key hashes to.
+func (b *Broker) Produce(ctx context.Context, record kafka.Record) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failing > 0 {
+		b.failing--
+		return errors.New("kafkatest: produce failed")
+	}
+	logs := b.topic(record.Topic)
+	partition := b.partitionOf(record.Key)
+	logs[partition] = append(logs[partition], kafka.Message{
+		Record:    record,
+		Partition: partition,
+		Offset:    int64(len(logs[partition])),
+		Timestamp: time.Now(),
+	})
+	b.notify()
+	return nil
+}
+
+// Messages returns the messages of a partition of the topic.
+func (b *Broker) Messages(topic string, partition int32) []kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]kafka.Message{}, b.topic(topic)[partition]...)
+}
+
+// PartitionOf returns the partition records with the given key are written to.
+func (b *Broker) PartitionOf(key string) int32 {
+	return b.partitionOf([]byte(key))
+}
+
+// Committed returns the committed offset of a partition for the group, or 0.
+func (b *Broker) Committed(groupName, topic string, partition int32) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if g, ok := b.groups[groupName+"/"+topic]; ok {
+		return g.committed[partition]
+	}
+	return 0
+}
+
+// Lag returns the number of messages of the topic after the offsets the group
+// committed.
+func (b *Broker) Lag(ctx context.Context, groupName, topic string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var committed map[int32]int64
+	if g, ok := b.groups[groupName+"/"+topic]; ok {
+		committed = g.committed
+	}
+	var lag int64
+	for partition, partitionLog := range b.topic(topic) {
+		lag += int64(len(partitionLog)) - committed[int32(partition)]
+	}
+	return lag, nil
+}
+
+// ReadTopic returns all messages of the topic, ordered by time.
+func (b *Broker) ReadTopic(ctx context.Context, topic string) ([]kafka.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var messages []kafka.Message
+	for _, partitionLog := range b.topic(topic) {
+		messages = append(messages, partitionLog...)
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].Timestamp.Before(messages[j].Timestamp) })
+	return messages, nil
+}
+
+// JoinGroup adds a consumer to the group, rebalancing its partitions.
+func (b *Broker) JoinGroup(groupName, topic string) (kafka.GroupConsumer, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.topic(topic)
+	key := groupName + "/" + topic
+	g, ok := b.groups[key]
+	if !ok {
+		g = &group{committed: map[int32]int64{}}
+		b.groups[key] = g
+	}
+	consumer := &Consumer{broker: b, group: g, topic: topic}
+	g.members = append(g.members, consumer)
+	b.rebalance(g)
+	return consumer, nil
+}
+
+// Close does nothing: the broker outlives its clients.
+func (b *Broker) Close() error {
+	return nil
+}
+
+func (b *Broker) topic(name string) [][]kafka.Message {
+	logs, ok := b.topics[name]
+	if !ok {
+		logs = make([][]kafka.Message, b.partitions)
+		b.topics[name] = logs
+	}
+	return logs
+}
+
+func (b *Broker) partitionOf(key []byte) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int32(h.Sum32() % uint32(b.partitions))
+}
+
+// rebalance assigns the partitions round-robin to the members of the group,
+// which then read them from the committed offsets.
+func (b *Broker) rebalance(g *group) {
+	for _, member := range g.members {
+		member.assigned = nil
+		member.position = map[int32]int64{}
+	}
+	if len(g.members) > 0 {
+		for p := 0; p < b.partitions; p++ {
+			member := g.members[p%len(g.members)]
+			member.assigned = append(member.assigned, int32(p))
+			member.position[int32(p)] = g.committed[int32(p)]
+		}
+	}
+	b.notify()
+}
+
+func (b *Broker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Consumer is a member of a consumer group of a Broker.
+type Consumer struct {
+	broker   *Broker
+	group    *group
+	topic    string
+	assigned []int32
+	position map[int32]int64
+	next     int
+	closed   bool
+}
+
+// Poll returns the next message of the assigned partitions, rotating between
+// partitions.
+func (c *Consumer) Poll(ctx context.Context) (kafka.Message, error) {
+	for {
+		c.broker.mu.Lock()
+		if c.closed {
+			c.broker.mu.Unlock()
+			return kafka.Message{}, errors.New("kafkatest: consumer closed")
+		}
+		logs := c.broker.topic(c.topic)
+		for i := range c.assigned {
+			partition := c.assigned[(c.next+i)%len(c.assigned)]
+			if position := c.position[partition]; position < int64(len(logs[partition])) {
+				c.position[partition] = position + 1
+				c.next = (c.next + i + 1) % len(c.assigned)
+				msg := logs[partition][position]
+				c.broker.mu.Unlock()
+				return msg, nil
+			}
+		}
+		changed := c.broker.changed
+		c.broker.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Commit records the offset after msg as the group's committed offset. It
+// fails when the partition is no longer assigned to the consumer.
+func (c *Consumer) Commit(ctx context.Context, msg kafka.Message) error {
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+	for _, partition := range c.assigned {
+		if partition == msg.Partition {
+			if msg.Offset+1 > c.group.committed[partition] {
+				c.group.committed[partition] = msg.Offset + 1
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("kafkatest: partition %d is not assigned to the consumer", msg.Partition)
+}
+
+// Close leaves the group, rebalancing its partitions to the other members.
+func (c *Consumer) Close() error {
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	for i, member := range c.group.members {
+		if member == c {
+			c.group.members = append(c.group.members[:i], c.group.members[i+1:]...)
+			break
+		}
+	}
+	c.broker.rebalance(c.group)
+	return nil
+}
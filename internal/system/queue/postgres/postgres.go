@@ -0,0 +1,423 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package postgres provides implementations of the queue.ProfileUnificationQueue
+// and queue.SchemaSyncQueue interfaces that keep their items in the
+// queue_jobs table of the CDS database. Items survive restarts, and consumers
+// on several CDS replicas share the work by claiming jobs with
+// SELECT ... FOR UPDATE SKIP LOCKED. No broker is needed.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+)
+
+const (
+	// pollInterval is how long an idle consumer waits before looking for
+	// new jobs. Jobs enqueued by the same instance wake it immediately.
+	pollInterval = time.Second
+	// leaseDuration is how long a claimed job stays invisible to other
+	// consumers. A job whose consumer crashed is claimed again after it.
+	leaseDuration = 5 * time.Minute
+
+	defaultProfileQueueName    = "cds-profile-unification"
+	defaultSchemaSyncQueueName = "cds-schema-sync"
+)
+
+func init() {
+	queue.RegisterProfileQueueProvider(queue.TypePostgres,
+		func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.ProfileUnificationQueue, error) {
+			name := cfg.Broker.ProfileQueueName
+			if name == "" {
+				name = defaultProfileQueueName
+			}
+			return NewProfileQueue(name, delivery.NewPolicy(cfg.Redelivery), delivery.Workers(cfg.Workers))
+		},
+	)
+	queue.RegisterSchemaSyncQueueProvider(queue.TypePostgres,
+		func(cfg config.MessageQueueConfig, tlsCfg config.TLSConfig) (queue.SchemaSyncQueue, error) {
+			name := cfg.Broker.SchemaSyncQueueName
+			if name == "" {
+				name = defaultSchemaSyncQueueName
+			}
+			return NewSchemaSyncQueue(name)
+		},
+	)
+}
+
+// -----------------------------------------------------------------------
+// jobQueue
+// -----------------------------------------------------------------------
+
+// job is a claimed row of the queue_jobs table.
+type job struct {
+	id       int64
+	payload  string
+	attempts int
+}
+
+// jobQueue holds the jobs of one named queue. A job is claimed by pushing its
+// available_at past the lease, and deleted once it has been processed. A
+// failed job is made available again after the policy's backoff, and kept as
+// a dead letter once its attempts are exhausted. A job claimed more often than
+// the policy allows (its consumer kept crashing) is dead-lettered unprocessed.
+type jobQueue struct {
+	name     string
+	metric   string // the queue label of the metrics
+	policy   delivery.Policy
+	workers  int
+	dbClient client.DBClientInterface
+	dbType   string
+
+	wake      chan struct{}
+	done      chan struct{}
+	running   sync.WaitGroup
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+func newJobQueue(name, metric string, policy delivery.Policy, workers int) (*jobQueue, error) {
+	dbProvider := provider.NewDBProvider()
+	dbClient, err := dbProvider.GetDBClient()
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to connect to the database for queue %s: %w", name, err)
+	}
+	q := &jobQueue{
+		name:     name,
+		metric:   metric,
+		policy:   policy,
+		workers:  max(workers, 1),
+		dbClient: dbClient,
+		dbType:   dbProvider.GetDBType(),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	metrics.RegisterQueueDepth(metric, queue.TypePostgres, q.depth)
+	return q, nil
+}
+
+// enqueue stores a job with the JSON encoding of item.
+func (q *jobQueue) enqueue(item interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			metrics.QueueEnqueueFailures.WithLabelValues(q.metric, queue.TypePostgres).Inc()
+			return
+		}
+		metrics.QueueEnqueued.WithLabelValues(q.metric, queue.TypePostgres).Inc()
+	}()
+	select {
+	case <-q.done:
+		return fmt.Errorf("postgres: queue %s closed", q.name)
+	default:
+	}
+	payload, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to serialise job for queue %s: %w", q.name, err)
+	}
+	if _, err := q.dbClient.ExecuteQuery(scripts.InsertQueueJob[q.dbType], q.name, string(payload)); err != nil {
+		return fmt.Errorf("postgres: failed to store job for queue %s: %w", q.name, err)
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// start launches the worker goroutines, each of which claims jobs one at a
+// time and passes them to process until the queue is closed.
+func (q *jobQueue) start(process func(job)) {
+	q.startOnce.Do(func() {
+		q.running.Add(q.workers)
+		for i := 0; i < q.workers; i++ {
+			go q.work(process)
+		}
+	})
+}
+
+func (q *jobQueue) work(process func(job)) {
+	defer q.running.Done()
+	for {
+		claimed, err := q.claim()
+		if err != nil {
+			log.GetLogger().Error(fmt.Sprintf("postgres: failed to claim a job from queue %s: %v", q.name, err))
+		}
+		if err != nil || claimed == nil {
+			select {
+			case <-q.done:
+				return
+			case <-q.wake:
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		if claimed.attempts > q.policy.MaxAttempts {
+			q.deadLetter(claimed, fmt.Errorf("job was claimed %d times without completing", claimed.attempts))
+		} else {
+			process(*claimed)
+		}
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+	}
+}
+
+// claim returns the oldest available job, or nil when there is none.
+func (q *jobQueue) claim() (*job, error) {
+	rows, err := q.dbClient.ExecuteQuery(scripts.ClaimQueueJob[q.dbType], q.name, leaseDuration.Milliseconds())
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &job{
+		id:       rows[0]["job_id"].(int64),
+		payload:  rows[0]["payload"].(string),
+		attempts: int(rows[0]["attempts"].(int64)),
+	}, nil
+}
+
+// complete deletes a processed job.
+func (q *jobQueue) complete(claimed job) {
+	if _, err := q.dbClient.ExecuteQuery(scripts.DeleteQueueJob[q.dbType], claimed.id); err != nil {
+		// The lease expires and the job is processed again.
+		log.GetLogger().Error(fmt.Sprintf("postgres: failed to delete processed job %d of queue %s: %v",
+			claimed.id, q.name, err))
+	}
+}
+
+// fail schedules a failed job for another attempt, or dead-letters it when its
+// attempts are exhausted.
+func (q *jobQueue) fail(claimed job, cause error) {
+	if claimed.attempts >= q.policy.MaxAttempts {
+		q.deadLetter(&claimed, cause)
+		return
+	}
+	backoff := q.policy.Backoff(claimed.attempts)
+	if _, err := q.dbClient.ExecuteQuery(scripts.RetryQueueJob[q.dbType], claimed.id, cause.Error(),
+		backoff.Milliseconds()); err != nil {
+		// The lease expires and the job is retried then.
+		log.GetLogger().Error(fmt.Sprintf("postgres: failed to schedule retry of job %d of queue %s: %v",
+			claimed.id, q.name, err))
+	}
+}
+
+func (q *jobQueue) deadLetter(claimed *job, cause error) {
+	log.GetLogger().Error(fmt.Sprintf("postgres: job %d of queue %s failed after %d attempts, dead-lettering it: %v",
+		claimed.id, q.name, claimed.attempts, cause))
+	if _, err := q.dbClient.ExecuteQuery(scripts.DeadLetterQueueJob[q.dbType], claimed.id, cause.Error()); err != nil {
+		log.GetLogger().Error(fmt.Sprintf("postgres: failed to dead-letter job %d of queue %s: %v",
+			claimed.id, q.name, err))
+		return
+	}
+	metrics.QueueDeadLettered.WithLabelValues(q.metric, queue.TypePostgres).Inc()
+}
+
+// depth counts the jobs of the queue that are not dead letters. The jobs are
+// shared by all instances, so every instance reports the same depth.
+func (q *jobQueue) depth(context.Context) (int64, error) {
+	rows, err := q.dbClient.ExecuteQuery(scripts.CountQueueJobs[q.dbType], q.name)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	depth, _ := rows[0]["depth"].(int64)
+	return depth, nil
+}
+
+// deadLetters returns the dead-lettered jobs, oldest first.
+func (q *jobQueue) deadLetters() ([]map[string]interface{}, error) {
+	rows, err := q.dbClient.ExecuteQuery(scripts.GetDeadLetteredQueueJobs[q.dbType], q.name)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to fetch dead letters of queue %s: %w", q.name, err)
+	}
+	return rows, nil
+}
+
+// replay makes a dead-lettered job available again.
+func (q *jobQueue) replay(id string) error {
+	jobId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return delivery.ErrDeadLetterNotFound
+	}
+	rows, err := q.dbClient.ExecuteQuery(scripts.ReplayQueueJob[q.dbType], q.name, jobId)
+	if err != nil {
+		return fmt.Errorf("postgres: failed to replay dead letter %s of queue %s: %w", id, q.name, err)
+	}
+	if len(rows) == 0 {
+		return delivery.ErrDeadLetterNotFound
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// close stops the worker goroutines, waiting for the jobs in progress, and
+// releases the database connection. Unprocessed jobs stay in the table.
+func (q *jobQueue) close() error {
+	var err error
+	q.closeOnce.Do(func() {
+		close(q.done)
+		q.startOnce.Do(func() {})
+		q.running.Wait()
+		metrics.UnregisterQueueDepth(q.metric, queue.TypePostgres)
+		err = q.dbClient.Close()
+	})
+	return err
+}
+
+// -----------------------------------------------------------------------
+// ProfileQueue
+// -----------------------------------------------------------------------
+
+// ProfileQueue is the PostgreSQL implementation of queue.ProfileUnificationQueue.
+type ProfileQueue struct {
+	jobs *jobQueue
+}
+
+// NewProfileQueue creates a ProfileQueue that keeps its jobs under the given
+// queue name and processes up to workers of them concurrently.
+func NewProfileQueue(name string, policy delivery.Policy, workers int) (*ProfileQueue, error) {
+	jobs, err := newJobQueue(name, metrics.QueueProfileUnification, policy, workers)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileQueue{jobs: jobs}, nil
+}
+
+// Enqueue stores the profile as a new job.
+func (q *ProfileQueue) Enqueue(profile profileModel.UnificationMessage) error {
+	return q.jobs.enqueue(profile)
+}
+
+// Start launches the worker goroutines. A job is deleted once handler
+// returns nil; otherwise it is retried per the redelivery policy and then
+// dead-lettered. Always returns nil.
+func (q *ProfileQueue) Start(handler func(profileModel.UnificationMessage) error) error {
+	q.jobs.start(func(claimed job) {
+		var profile profileModel.UnificationMessage
+		if err := json.Unmarshal([]byte(claimed.payload), &profile); err != nil {
+			q.jobs.deadLetter(&claimed, fmt.Errorf("failed to deserialise profile: %w", err))
+			return
+		}
+		if err := handler(profile); err != nil {
+			q.jobs.fail(claimed, err)
+			return
+		}
+		q.jobs.complete(claimed)
+	})
+	return nil
+}
+
+// DeadLetters returns the dead-lettered profiles, oldest first.
+func (q *ProfileQueue) DeadLetters() ([]delivery.DeadLetter, error) {
+	rows, err := q.jobs.deadLetters()
+	if err != nil {
+		return nil, err
+	}
+	deadLetters := make([]delivery.DeadLetter, 0, len(rows))
+	for _, row := range rows {
+		deadLetter := delivery.DeadLetter{
+			Id:       strconv.FormatInt(row["job_id"].(int64), 10),
+			Attempts: int(row["attempts"].(int64)),
+			Error:    row["last_error"].(string),
+			FailedAt: row["dead_lettered_at"].(time.Time).UTC(),
+		}
+		// An undecodable payload is still listed, without the profile.
+		_ = json.Unmarshal([]byte(row["payload"].(string)), &deadLetter.Profile)
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+// ReplayDeadLetter makes the dead-lettered profile available to consumers
+// again, with a fresh set of attempts.
+func (q *ProfileQueue) ReplayDeadLetter(id string) error {
+	return q.jobs.replay(id)
+}
+
+// Close stops consuming and releases the database connection. It is safe to
+// call Close more than once.
+func (q *ProfileQueue) Close() error {
+	return q.jobs.close()
+}
+
+// -----------------------------------------------------------------------
+// SchemaSyncQueue
+// -----------------------------------------------------------------------
+
+// SchemaSyncQueue is the PostgreSQL implementation of queue.SchemaSyncQueue.
+type SchemaSyncQueue struct {
+	jobs *jobQueue
+}
+
+// NewSchemaSyncQueue creates a SchemaSyncQueue that keeps its jobs under the
+// given queue name.
+func NewSchemaSyncQueue(name string) (*SchemaSyncQueue, error) {
+	jobs, err := newJobQueue(name, metrics.QueueSchemaSync, delivery.NewPolicy(config.RedeliveryConfig{}), 1)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaSyncQueue{jobs: jobs}, nil
+}
+
+// Enqueue stores the schema sync job.
+func (q *SchemaSyncQueue) Enqueue(sync schemaModel.ProfileSchemaSync) error {
+	return q.jobs.enqueue(sync)
+}
+
+// Start launches the worker goroutines. A job is deleted once handler
+// returns. Always returns nil.
+func (q *SchemaSyncQueue) Start(handler func(schemaModel.ProfileSchemaSync)) error {
+	q.jobs.start(func(claimed job) {
+		var sync schemaModel.ProfileSchemaSync
+		if err := json.Unmarshal([]byte(claimed.payload), &sync); err != nil {
+			q.jobs.deadLetter(&claimed, fmt.Errorf("failed to deserialise schema sync job: %w", err))
+			return
+		}
+		handler(sync)
+		q.jobs.complete(claimed)
+	})
+	return nil
+}
+
+// Close stops consuming and releases the database connection. It is safe to
+// call Close more than once.
+func (q *SchemaSyncQueue) Close() error {
+	return q.jobs.close()
+}
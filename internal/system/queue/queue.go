@@ -19,29 +19,53 @@
 package queue
 
 import (
+	"context"
+
 	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
 	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
 )
 
 // Provider type constants used in configuration.
 const (
 	TypeMemory   = "memory"
 	TypeActiveMQ = "activemq"
+	TypePostgres = "postgres"
+	TypeKafka    = "kafka"
 )
 
+// DeadLetter is a profile whose unification failed on every delivery attempt.
+type DeadLetter = delivery.DeadLetter
+
+// ErrDeadLetterNotFound is returned by ReplayDeadLetter for an unknown id.
+var ErrDeadLetterNotFound = delivery.ErrDeadLetterNotFound
+
 // ProfileUnificationQueue defines the contract for enqueuing profiles for
-// asynchronous unification processing.
+// asynchronous unification processing. Delivery is at-least-once: an item is
+// acknowledged only after handler returns nil. A failing item is retried with
+// backoff (see delivery.Policy) and then moved to a dead-letter destination,
+// from where it can be inspected and replayed.
 type ProfileUnificationQueue interface {
-	// Enqueue adds a profile to the queue for unification. It returns nil
-	// on success or a descriptive error when the item cannot be accepted
-	// (e.g. queue full, serialization failure, broker unreachable).
-	Enqueue(profile profileModel.Profile) error
+	// Enqueue adds a profile to the queue for unification. Only the
+	// profile's identity is queued; the handler reads the profile. It
+	// returns nil on success or a descriptive error when the item cannot be
+	// accepted (e.g. queue full, serialization failure, broker unreachable).
+	Enqueue(message profileModel.UnificationMessage) error
 
 	// Start begins consuming queue items and invokes handler for each one.
 	// Implementations must start the consumer loop in a separate goroutine
 	// so that Start returns immediately. An error is returned when the queue
-	// cannot be started (e.g. broker subscription failure).
-	Start(handler func(profileModel.Profile)) error
+	// cannot be started (e.g. broker subscription failure). A non-nil error
+	// from handler means the item was not processed and must be redelivered.
+	Start(handler func(profileModel.UnificationMessage) error) error
+
+	// DeadLetters returns the dead-lettered profiles, oldest first.
+	DeadLetters() ([]DeadLetter, error)
+
+	// ReplayDeadLetter moves the dead-lettered profile with the given id
+	// back onto the queue. It returns ErrDeadLetterNotFound when there is
+	// no such dead letter.
+	ReplayDeadLetter(id string) error
 
 	// Close performs a graceful shutdown of the queue, flushing any
 	// in-flight items and releasing underlying resources (connections,
@@ -68,3 +92,24 @@ type SchemaSyncQueue interface {
 	// channels, goroutines). It is safe to call Close more than once.
 	Close() error
 }
+
+// ProfileQueueDrainer is implemented by profile queues that hold their
+// pending items in process memory, which Close would lose. Brokers and the
+// database keep unacknowledged items themselves and need no drain.
+type ProfileQueueDrainer interface {
+	// Drain stops accepting items and keeps processing the pending ones
+	// until none are left or ctx is done. It then stops the consumer and
+	// returns the items that were not processed, so that the caller can
+	// persist them. The queue is closed afterwards.
+	Drain(ctx context.Context) []profileModel.UnificationMessage
+}
+
+// SchemaSyncQueueDrainer is the SchemaSyncQueue counterpart of
+// ProfileQueueDrainer.
+type SchemaSyncQueueDrainer interface {
+	// Drain stops accepting jobs and keeps processing the pending ones
+	// until none are left or ctx is done. It then stops the consumer and
+	// returns the jobs that were not processed. The queue is closed
+	// afterwards.
+	Drain(ctx context.Context) []schemaModel.ProfileSchemaSync
+}
@@ -26,7 +26,7 @@ import (
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
 )
 
-// HealthService handles routing for health and readiness endpoints.
+// HealthService handles routing for health, readiness and metrics endpoints.
 type HealthService struct {
 	handler *handler.HealthHandler
 	mux     *http.ServeMux
@@ -43,6 +43,7 @@ func NewHealthService(mux *http.ServeMux) *HealthService {
 	const base = constants.ApiBasePath + "/v1"
 	s.mux.HandleFunc("GET "+base+"/health", s.handler.HandleHealth)
 	s.mux.HandleFunc("GET "+base+"/ready", s.handler.HandleReadiness)
+	s.mux.HandleFunc("GET "+base+"/metrics", s.handler.HandleMetrics)
 
 	return s
 }
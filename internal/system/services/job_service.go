@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/wso2/identity-customer-data-service/internal/job/handler"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+type JobService struct {
+	handler *handler.JobHandler
+	mux     *http.ServeMux
+}
+
+func NewJobService(mux *http.ServeMux) *JobService {
+	s := &JobService{
+		handler: handler.NewJobHandler(),
+		mux:     mux,
+	}
+
+	const base = constants.ApiBasePath + "/v1"
+	s.mux.HandleFunc("GET "+base+"/jobs", s.handler.GetJobs)
+	s.mux.HandleFunc("GET "+base+"/jobs/{jobID}", s.handler.GetJob)
+
+	return s
+}
+
+// Route handles tenant-aware routing for background jobs
+func (s *JobService) Route(w http.ResponseWriter, r *http.Request) {
+	// Normalize trailing slashes for consistent matching
+	if trimmed := strings.TrimSuffix(r.URL.Path, "/"); trimmed != "" {
+		r.URL.Path = trimmed
+	}
+	s.mux.ServeHTTP(w, r)
+}
@@ -42,6 +42,11 @@ func NewProfileSchemaService(mux *http.ServeMux) *ProfileSchemaService {
 	s.mux.HandleFunc("GET "+base+"/profile-schema", s.handler.GetProfileSchema)
 	s.mux.HandleFunc("DELETE "+base+"/profile-schema", s.handler.DeleteProfileSchema)
 	s.mux.HandleFunc("POST "+base+"/profile-schema/sync", s.handler.SyncProfileSchema)
+	s.mux.HandleFunc("GET "+base+"/profile-schema/export", s.handler.ExportProfileSchema)
+	s.mux.HandleFunc("POST "+base+"/profile-schema/import", s.handler.ImportProfileSchema)
+	s.mux.HandleFunc("GET "+base+"/profile-schema/migrations/{migrationID}", s.handler.GetSchemaMigration)
+	s.mux.HandleFunc("GET "+base+"/profile-schema/drift", s.handler.GetSchemaDriftReport)
+	s.mux.HandleFunc("POST "+base+"/profile-schema/drift/reconcile", s.handler.ReconcileSchemaDrift)
 
 	// Scope-level
 	s.mux.HandleFunc("POST "+base+"/profile-schema/{scope}", s.handler.AddProfileSchemaAttributesForScope)
@@ -52,6 +57,8 @@ func NewProfileSchemaService(mux *http.ServeMux) *ProfileSchemaService {
 	s.mux.HandleFunc("GET "+base+"/profile-schema/{scope}/{attrID}", s.handler.GetProfileSchemaAttributeById)
 	s.mux.HandleFunc("PUT "+base+"/profile-schema/{scope}/{attrID}", s.handler.PatchProfileSchemaAttributeById)
 	s.mux.HandleFunc("DELETE "+base+"/profile-schema/{scope}/{attrID}", s.handler.DeleteProfileSchemaAttributeById)
+	s.mux.HandleFunc("GET "+base+"/profile-schema/{scope}/{attrID}/migrations", s.handler.GetSchemaMigrationsForAttribute)
+	s.mux.HandleFunc("POST "+base+"/profile-schema/{scope}/{attrID}/migration-preview", s.handler.PreviewSchemaMigration)
 
 	return s
 }
@@ -51,6 +51,7 @@ func NewProfileService(mux *http.ServeMux) *ProfileService {
 	ps.mux.HandleFunc("DELETE "+base+"/profiles/{profileId}", ps.profileHandler.DeleteProfile)
 	ps.mux.HandleFunc("GET "+base+"/profiles/{profileId}/consents", ps.profileHandler.GetProfileConsents)
 	ps.mux.HandleFunc("PUT "+base+"/profiles/{profileId}/consents", ps.profileHandler.UpdateProfileConsents)
+	ps.mux.HandleFunc("GET "+base+"/profiles/{profileId}/consents/receipts/{receiptId}", ps.profileHandler.GetConsentReceipt)
 
 	return ps
 }
@@ -45,6 +45,8 @@ func NewUnificationRulesService(mux *http.ServeMux) *UnificationRulesService {
 	s.mux.HandleFunc("GET "+base+"/unification-rules/{ruleId}", s.unificationRulesHandler.GetUnificationRule)
 	s.mux.HandleFunc("PATCH "+base+"/unification-rules/{ruleId}", s.unificationRulesHandler.PatchUnificationRule)
 	s.mux.HandleFunc("DELETE "+base+"/unification-rules/{ruleId}", s.unificationRulesHandler.DeleteUnificationRule)
+	s.mux.HandleFunc("GET "+base+"/unification/dead-letters", s.unificationRulesHandler.GetDeadLetters)
+	s.mux.HandleFunc("POST "+base+"/unification/dead-letters/{id}/replay", s.unificationRulesHandler.ReplayDeadLetter)
 
 	return s
 }
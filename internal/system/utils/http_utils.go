@@ -40,13 +40,15 @@ func HandleError(w http.ResponseWriter, err error) {
 	if ok := errors.As(err, &clientError); ok {
 		w.WriteHeader(clientError.StatusCode)
 		_ = json.NewEncoder(w).Encode(struct {
-			Code        string `json:"code"`
-			Message     string `json:"message"`
-			Description string `json:"description"`
+			Code        string                     `json:"code"`
+			Message     string                     `json:"message"`
+			Description string                     `json:"description"`
+			Details     []customerrors.ErrorDetail `json:"details,omitempty"`
 		}{
 			Code:        clientError.ErrorMessage.Code,
 			Message:     clientError.ErrorMessage.Message,
 			Description: clientError.ErrorMessage.Description,
+			Details:     clientError.Details,
 		})
 		return
 	}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// ApplyComputedAttributes recomputes the computed attributes of the profile from its current data. A value that
+// cannot be derived, e.g. because an input is missing, removes the attribute from the profile. Expressions that
+// fail to evaluate are logged and leave the attribute unset rather than failing the write.
+func ApplyComputedAttributes(profile *profileModel.Profile, schemaAttrs []schemaModel.ProfileSchemaAttribute, now time.Time) {
+	logger := log.GetLogger()
+	computed, err := schemaModel.OrderComputedAttributes(schemaAttrs)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Skipping computed attributes of profile %s: %v", profile.ProfileId, err))
+		return
+	}
+	if len(computed) == 0 {
+		return
+	}
+
+	if profile.Traits == nil {
+		profile.Traits = map[string]interface{}{}
+	}
+	if profile.IdentityAttributes == nil {
+		profile.IdentityAttributes = map[string]interface{}{}
+	}
+	appData := make(map[string]interface{}, len(profile.ApplicationData))
+	for i := range profile.ApplicationData {
+		if profile.ApplicationData[i].AppSpecificData == nil {
+			profile.ApplicationData[i].AppSpecificData = map[string]interface{}{}
+		}
+		appData[profile.ApplicationData[i].AppId] = profile.ApplicationData[i].AppSpecificData
+	}
+	// The document aliases the profile's maps, so each computed value is visible to the attributes evaluated after it.
+	document := map[string]interface{}{
+		constants.IdentityAttributes: profile.IdentityAttributes,
+		constants.Traits:             profile.Traits,
+		constants.ApplicationData:    appData,
+	}
+
+	for _, c := range computed {
+		attr := c.Attribute
+		value, err := c.Expression.Evaluate(document, now)
+		if err == nil && value != nil {
+			value, err = coerceComputedValue(value, attr.ValueType)
+		}
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to compute attribute '%s' of profile %s", attr.ValuePath(), profile.ProfileId),
+				log.Error(err))
+			value = nil
+		}
+
+		key := attr.AttributeName[strings.Index(attr.AttributeName, ".")+1:]
+		target := profile.Traits
+		if strings.HasPrefix(attr.AttributeName, constants.ApplicationData+".") {
+			data, ok := appData[attr.ApplicationIdentifier].(map[string]interface{})
+			if !ok {
+				if value == nil {
+					continue
+				}
+				data = map[string]interface{}{}
+				appData[attr.ApplicationIdentifier] = data
+				profile.ApplicationData = append(profile.ApplicationData, profileModel.ApplicationData{
+					AppId:           attr.ApplicationIdentifier,
+					AppSpecificData: data,
+				})
+			}
+			target = data
+		}
+
+		if value == nil {
+			delete(target, key)
+		} else {
+			target[key] = value
+		}
+	}
+}
+
+// coerceComputedValue converts the result of an expression to the value type of the computed attribute.
+func coerceComputedValue(value interface{}, valueType string) (interface{}, error) {
+	switch valueType {
+	case constants.StringDataType, constants.DateDataType, constants.DateTimeDataType:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case constants.IntegerDataType, constants.EpochDataType:
+		f, err := computedNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return int64(math.Trunc(f)), nil
+	case constants.DecimalDataType:
+		return computedNumber(value)
+	case constants.BooleanDataType:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+		return nil, fmt.Errorf("value %v is not a boolean", value)
+	default:
+		return nil, fmt.Errorf("value type '%s' cannot be computed", valueType)
+	}
+}
+
+func computedNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	return 0, fmt.Errorf("value %v is not a number", value)
+}
@@ -45,34 +45,24 @@ func StartCookieCleanupWorker(cfg config.CookieCleanupConfig) {
 		batchSize = 500
 	}
 
-	cookieCleanupDone = make(chan struct{})
-
 	logger.Info(fmt.Sprintf("Cookie cleanup worker started. Interval: %s, Batch size: %d",
 		interval, batchSize))
 
-	ticker := time.NewTicker(interval)
-
-	go func() {
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				runCookieCleanup(batchSize)
-			case <-cookieCleanupDone:
-				logger.Info("Cookie cleanup worker stopped")
-				return
-			}
-		}
-	}()
+	cookieCleanupDone = startPeriodicJob(constants.JobTypeCookieCleanup, interval,
+		func(progress func(percent int)) (interface{}, error) {
+			return runCookieCleanup(batchSize)
+		})
 }
 
 func StopCookieCleanupWorker() {
 	if cookieCleanupDone != nil {
 		close(cookieCleanupDone)
+		log.GetLogger().Info("Cookie cleanup worker stopped")
 	}
 }
 
-func runCookieCleanup(batchSize int) {
+// runCookieCleanup purges the inactive cookie profiles batch by batch, and returns how many were purged.
+func runCookieCleanup(batchSize int) (map[string]int, error) {
 
 	logger := log.GetLogger()
 	total := 0
@@ -81,7 +71,7 @@ func runCookieCleanup(batchSize int) {
 		deleted, err := store.DeleteInactiveCookieProfiles(batchSize)
 		if err != nil {
 			logger.Debug("Cookie cleanup batch error", log.Error(err))
-			break
+			return map[string]int{"purged": total}, err
 		}
 		total += deleted
 		if deleted < batchSize {
@@ -92,4 +82,5 @@ func runCookieCleanup(batchSize int) {
 	if total > 0 {
 		logger.Info(fmt.Sprintf("Cookie cleanup: purged %d inactive records", total))
 	}
+	return map[string]int{"purged": total}, nil
 }
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+)
+
+// GetDeadLetteredProfiles returns the unification messages of the given organization that exhausted their
+// delivery attempts and were moved to the dead letter queue.
+func GetDeadLetteredProfiles(orgHandle string) ([]queue.DeadLetter, error) {
+	q, err := currentProfileQueue()
+	if err != nil {
+		return nil, err
+	}
+	deadLetters, err := q.DeadLetters()
+	if err != nil {
+		log.GetLogger().Debug("Failed to fetch dead-lettered unification messages", log.Error(err))
+		return nil, errors2.NewServerError(errors2.GET_DEAD_LETTERS, err)
+	}
+	result := make([]queue.DeadLetter, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		if deadLetter.Profile.OrgHandle == orgHandle {
+			result = append(result, deadLetter)
+		}
+	}
+	return result, nil
+}
+
+// ReplayDeadLetteredProfile moves a dead-lettered unification message of the given organization back to the
+// profile unification queue so that it is processed again.
+func ReplayDeadLetteredProfile(orgHandle, id string) error {
+	q, err := currentProfileQueue()
+	if err != nil {
+		return err
+	}
+	deadLetters, err := q.DeadLetters()
+	if err != nil {
+		return errors2.NewServerError(errors2.REPLAY_DEAD_LETTER, err)
+	}
+	owned := false
+	for _, deadLetter := range deadLetters {
+		if deadLetter.Id == id && deadLetter.Profile.OrgHandle == orgHandle {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return deadLetterNotFound()
+	}
+	if err := q.ReplayDeadLetter(id); err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			// Replayed concurrently by another request.
+			return deadLetterNotFound()
+		}
+		return errors2.NewServerError(errors2.REPLAY_DEAD_LETTER, err)
+	}
+	log.GetLogger().Info(fmt.Sprintf("Replayed dead-lettered unification message %s of organization %s",
+		id, orgHandle))
+	return nil
+}
+
+func currentProfileQueue() (queue.ProfileUnificationQueue, error) {
+	profileQueueMu.RLock()
+	q := activeProfileQueue
+	profileQueueMu.RUnlock()
+	if q == nil {
+		return nil, errors2.NewServerError(errors2.GET_DEAD_LETTERS,
+			errors.New("profile unification worker is not running"))
+	}
+	return q, nil
+}
+
+func deadLetterNotFound() error {
+	return errors2.NewClientError(errors2.ErrorMessage{
+		Code:        errors2.DEAD_LETTER_NOT_FOUND.Code,
+		Message:     errors2.DEAD_LETTER_NOT_FOUND.Message,
+		Description: errors2.DEAD_LETTER_NOT_FOUND.Description,
+	}, http.StatusNotFound)
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"strings"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// mergeProvenance carries the per-attribute write metadata of the two profiles being merged, and collects the
+// metadata of the merged profile as attributes are resolved.
+type mergeProvenance struct {
+	existing       map[string]profileModel.AttributeMetadata
+	incoming       map[string]profileModel.AttributeMetadata
+	sourcePriority map[string]int
+	merged         map[string]profileModel.AttributeMetadata
+}
+
+// attributeProvenance is the write metadata of one top-level attribute on both sides of a merge.
+type attributeProvenance struct {
+	key            string
+	existing       profileModel.AttributeMetadata
+	incoming       profileModel.AttributeMetadata
+	sourcePriority map[string]int
+	// chosen is set when a timestamp-aware strategy picked one side's value.
+	chosen *profileModel.AttributeMetadata
+}
+
+// newMergeProvenance prepares the provenance of a merge. sourcePriority lists applications from most to least
+// trusted; the merged metadata starts as a copy of the existing profile's.
+func newMergeProvenance(existing, incoming profileModel.Profile, sourcePriority []string) *mergeProvenance {
+	ranks := make(map[string]int, len(sourcePriority))
+	for i, app := range sourcePriority {
+		if _, seen := ranks[app]; !seen {
+			ranks[app] = i
+		}
+	}
+	merged := make(map[string]profileModel.AttributeMetadata, len(existing.AttributeMetadata))
+	for k, v := range existing.AttributeMetadata {
+		merged[k] = v
+	}
+	return &mergeProvenance{
+		existing:       existing.AttributeMetadata,
+		incoming:       incoming.AttributeMetadata,
+		sourcePriority: ranks,
+		merged:         merged,
+	}
+}
+
+func (p *mergeProvenance) attribute(key string) *attributeProvenance {
+	return &attributeProvenance{
+		key:            key,
+		existing:       p.existing[key],
+		incoming:       p.incoming[key],
+		sourcePriority: p.sourcePriority,
+	}
+}
+
+// record stores the merged metadata of an attribute: the side a timestamp-aware strategy picked, otherwise the
+// most recent write of the two.
+func (p *mergeProvenance) record(attr *attributeProvenance) {
+	if attr.chosen != nil {
+		if !attr.chosen.UpdatedAt.IsZero() {
+			p.merged[attr.key] = *attr.chosen
+		}
+		return
+	}
+	latest := attr.existing
+	if attr.incoming.UpdatedAt.After(latest.UpdatedAt) {
+		latest = attr.incoming
+	}
+	if !latest.UpdatedAt.IsZero() {
+		p.merged[attr.key] = latest
+	}
+}
+
+// adoptIncoming copies the incoming metadata of every attribute under prefix, for data taken over wholesale.
+func (p *mergeProvenance) adoptIncoming(prefix string) {
+	for k, v := range p.incoming {
+		if strings.HasPrefix(k, prefix) {
+			p.merged[k] = v
+		}
+	}
+}
+
+// isProvenanceStrategy reports whether the merge strategy picks a value by its write metadata.
+func isProvenanceStrategy(strategy string) bool {
+	switch strings.ToLower(strategy) {
+	case constants.MergeStrategyLatest, constants.MergeStrategyOldest, constants.MergeStrategySourcePriority:
+		return true
+	}
+	return false
+}
+
+// pick resolves a conflict with a timestamp-aware strategy. An empty value never wins over a non-empty one.
+// Attributes written before metadata was tracked have no timestamp and lose to timestamped ones; when neither side
+// can be ranked the incoming value wins, as with overwrite.
+func (a *attributeProvenance) pick(existing, incoming interface{}, strategy string) interface{} {
+	if isEmptyValue(incoming) {
+		a.chosen = &a.existing
+		return existing
+	}
+	if isEmptyValue(existing) {
+		a.chosen = &a.incoming
+		return incoming
+	}
+
+	var takeIncoming bool
+	switch strings.ToLower(strategy) {
+	case constants.MergeStrategyLatest:
+		takeIncoming = a.isIncomingNewer()
+	case constants.MergeStrategyOldest:
+		takeIncoming = !a.incoming.UpdatedAt.IsZero() &&
+			(a.existing.UpdatedAt.IsZero() || a.incoming.UpdatedAt.Before(a.existing.UpdatedAt))
+	case constants.MergeStrategySourcePriority:
+		existingRank, incomingRank := a.rank(a.existing.SourceApplication), a.rank(a.incoming.SourceApplication)
+		if existingRank == incomingRank {
+			takeIncoming = a.isIncomingNewer()
+		} else {
+			takeIncoming = incomingRank < existingRank
+		}
+	default:
+		takeIncoming = true
+	}
+
+	if takeIncoming {
+		a.chosen = &a.incoming
+		return incoming
+	}
+	a.chosen = &a.existing
+	return existing
+}
+
+func (a *attributeProvenance) isIncomingNewer() bool {
+	if a.existing.UpdatedAt.IsZero() {
+		return true
+	}
+	return !a.incoming.UpdatedAt.IsZero() && !a.incoming.UpdatedAt.Before(a.existing.UpdatedAt)
+}
+
+// rank returns the trust rank of an application; unranked and unknown sources rank last.
+func (a *attributeProvenance) rank(app string) int {
+	if rank, ok := a.sourcePriority[app]; ok && app != "" {
+		return rank
+	}
+	return len(a.sourcePriority)
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	jobProvider "github.com/wso2/identity-customer-data-service/internal/job/provider"
+	jobService "github.com/wso2/identity-customer-data-service/internal/job/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// periodicJobPollInterval bounds how long a due periodic job waits for an instance to claim it.
+const periodicJobPollInterval = time.Minute
+
+// startPeriodicJob runs a job of the whole deployment once every interval, recording each run as a job. Every
+// instance polls for the run being due, and the instance that claims it runs it, so instances sharing the database
+// do not repeat each other's work. The job stops when the returned channel is closed.
+func startPeriodicJob(jobType string, interval time.Duration, run jobService.RunFunc) chan struct{} {
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(min(interval, periodicJobPollInterval))
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				err := jobProvider.NewJobProvider().GetJobService().RunPeriodicJob(jobType, interval, run)
+				if err != nil {
+					log.GetLogger().Debug(fmt.Sprintf("Periodic job: %s failed", jobType), log.Error(err))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return done
+}
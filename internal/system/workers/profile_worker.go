@@ -19,6 +19,8 @@
 package workers
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -27,6 +29,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	adminConfigStore "github.com/wso2/identity-customer-data-service/internal/admin_config/store"
 	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
 	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
 	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
@@ -34,6 +37,7 @@ import (
 	"github.com/wso2/identity-customer-data-service/internal/system/config"
 	"github.com/wso2/identity-customer-data-service/internal/system/constants"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
 	"github.com/wso2/identity-customer-data-service/internal/system/queue"
 	"github.com/wso2/identity-customer-data-service/internal/system/utils"
 	"github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
@@ -48,6 +52,8 @@ import (
 var (
 	profileQueueMu     sync.RWMutex
 	activeProfileQueue queue.ProfileUnificationQueue
+	// unificationLock serialises the unifications of an organization across the queue's workers.
+	unificationLock unificationLocker
 )
 
 // StartProfileWorker initialises the profile unification queue (using the
@@ -56,67 +62,88 @@ var (
 // started; the caller should treat this as a fatal startup failure.
 func StartProfileWorker() error {
 	cfg := config.GetCDSRuntime().Config
+	locker, err := newUnificationLocker(cfg.MessageQueue.UnificationLock)
+	if err != nil {
+		return fmt.Errorf("workers: %w", err)
+	}
+	unificationLock = locker
 	q, err := queue.NewProfileUnificationQueue(cfg)
 	if err != nil {
 		return fmt.Errorf("workers: failed to create profile unification queue: %w", err)
 	}
-	if err := q.Start(func(profile profileModel.Profile) {
-		p, err := profileStore.GetProfile(profile.ProfileId)
-		if err == nil && p != nil {
-			unifyProfiles(*p)
-		}
-	}); err != nil {
+	if err := q.Start(processProfileUnification); err != nil {
 		_ = q.Close()
 		return fmt.Errorf("workers: failed to start profile unification queue: %w", err)
 	}
 	profileQueueMu.Lock()
 	activeProfileQueue = q
 	profileQueueMu.Unlock()
+	startUnificationOutboxRelay(cfg.MessageQueue.Outbox)
 	return nil
 }
 
-// EnqueueProfileForProcessing adds a profile to the active queue for
-// asynchronous unification. It is a no-op when the worker has not been
-// started or has been stopped.
-func EnqueueProfileForProcessing(profile profileModel.Profile) {
-	profileQueueMu.RLock()
-	q := activeProfileQueue
-	profileQueueMu.RUnlock()
-	if q != nil {
-		if err := q.Enqueue(profile); err != nil {
-			log.GetLogger().Error(fmt.Sprintf(
-				"workers: failed to enqueue profile %s for unification: %v",
-				profile.ProfileId, err))
-		}
-	}
-}
-
-// ProfileWorkerQueue is a thin adapter that allows service-layer code to
-// enqueue profiles without taking a direct dependency on the queue package.
-type ProfileWorkerQueue struct{}
-
-// Enqueue forwards the profile to the active queue via EnqueueProfileForProcessing.
-func (q *ProfileWorkerQueue) Enqueue(profile profileModel.Profile) {
-	EnqueueProfileForProcessing(profile)
+// StopProfileWorker shuts down the outbox relay and the profile unification
+// queue without waiting for the pending profiles, which are persisted instead.
+func StopProfileWorker() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return DrainProfileWorker(ctx)
 }
 
-// StopProfileWorker gracefully shuts down the profile unification queue.
-// It nils out the global reference under a write lock before calling Close,
-// ensuring no concurrent Enqueue can send on a closed queue. It should be
-// called during application shutdown.
-func StopProfileWorker() error {
+// DrainProfileWorker gracefully shuts down the outbox relay and the profile
+// unification queue. It nils out the global reference under a write lock
+// before closing the queue, ensuring no concurrent Enqueue can send on a
+// closed queue. A queue that holds its profiles in memory keeps unifying them
+// until ctx is done; the profiles left are returned to the unification outbox,
+// from which the next start publishes them. It should be called during
+// application shutdown.
+func DrainProfileWorker(ctx context.Context) error {
+	stopUnificationOutboxRelay()
 	profileQueueMu.Lock()
 	q := activeProfileQueue
 	activeProfileQueue = nil
 	profileQueueMu.Unlock()
-	if q != nil {
+	if q == nil {
+		return nil
+	}
+	drainer, ok := q.(queue.ProfileQueueDrainer)
+	if !ok {
 		return q.Close()
 	}
-	return nil
+	pending := drainer.Drain(ctx)
+	if len(pending) > 0 {
+		log.GetLogger().Info(fmt.Sprintf("Returning %d pending profiles to the unification outbox", len(pending)))
+	}
+	return profileStore.RequeueUnificationMessages(pending)
+}
+
+// processProfileUnification unifies the current state of a queued profile. Messages only reference the profile, so
+// the profile is always read afresh, under the organization's unification lock, as another worker may just have
+// merged it. An error is returned when the unification could not be completed, so that the queue redelivers the
+// message. The queue calls it from several workers; the lock keeps two of them from merging into the same master.
+func processProfileUnification(message profileModel.UnificationMessage) (err error) {
+	start := time.Now()
+	defer func() { observeProcessing(metrics.QueueProfileUnification, start, err) }()
+
+	unlock, err := unificationLock.lock(unificationLockKey(message.OrgHandle))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	p, err := profileStore.GetProfile(message.ProfileId)
+	if err != nil {
+		return fmt.Errorf("failed to fetch profile %s for unification: %w", message.ProfileId, err)
+	}
+	if p == nil {
+		// Deleted since it was queued; nothing to unify.
+		return nil
+	}
+	return unifyProfiles(*p)
 }
 
 // unifyProfiles unifies profiles based on unification rules
-func unifyProfiles(newProfile profileModel.Profile) {
+func unifyProfiles(newProfile profileModel.Profile) error {
 
 	logger := log.GetLogger()
 
@@ -127,6 +154,7 @@ func unifyProfiles(newProfile profileModel.Profile) {
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to fetch unification rules for unifying profile: %s",
 			newProfile.ProfileId), log.Error(err))
+		return err
 	}
 	if len(unificationRules) == 0 {
 		logger.Info(fmt.Sprintf("No unification rules found for tenant: %s", newProfile.OrgHandle))
@@ -139,7 +167,7 @@ func unifyProfiles(newProfile profileModel.Profile) {
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to fetch existing master profiles for unification of profile: %s",
 			newProfile.ProfileId), log.Error(err))
-		return
+		return err
 	}
 
 	// Step 3a: Direct userId match — system-level invariant, no rule needed.
@@ -152,8 +180,7 @@ func unifyProfiles(newProfile profileModel.Profile) {
 			if existingMasterProfile.UserId == newProfile.UserId {
 				logger.Info(fmt.Sprintf("Profiles %s and %s share the same userId %s. Proceeding with merge.",
 					existingMasterProfile.ProfileId, newProfile.ProfileId, newProfile.UserId))
-				mergeMatchedProfiles(existingMasterProfile, newProfile, constants.SystemUserIdMatchReason)
-				return
+				return mergeMatchedProfiles(existingMasterProfile, newProfile, constants.SystemUserIdMatchReason)
 			}
 		}
 	}
@@ -164,20 +191,20 @@ func unifyProfiles(newProfile profileModel.Profile) {
 		for _, existingMasterProfile := range existingMasterProfiles {
 			if existingMasterProfile.ProfileId == newProfile.ProfileStatus.ReferenceProfileId {
 				// Skip if the existing master profile is the parent of the new profile
-				return
+				return nil
 			}
 			if doesProfileMatch(existingMasterProfile, newProfile, rule) {
-				mergeMatchedProfiles(existingMasterProfile, newProfile, rule.RuleName)
-				return
+				return mergeMatchedProfiles(existingMasterProfile, newProfile, rule.RuleName)
 			}
 		}
 	}
+	return nil
 }
 
 // mergeMatchedProfiles handles all merge scenarios for two matched profiles.
 // It determines the master/child relationship based on permanent (has userId) vs temporary,
 // and whether the existing profile already has child references.
-func mergeMatchedProfiles(existingMasterProfile profileModel.Profile, newProfile profileModel.Profile, reason string) {
+func mergeMatchedProfiles(existingMasterProfile profileModel.Profile, newProfile profileModel.Profile, reason string) error {
 
 	logger := log.GetLogger()
 
@@ -185,6 +212,7 @@ func mergeMatchedProfiles(existingMasterProfile profileModel.Profile, newProfile
 	refs, err := profileStore.FetchReferencedProfiles(existingMasterProfile.ProfileId)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to fetch references for profile: %s during unification with profile: %s", existingMasterProfile.ProfileId, newProfile.ProfileId))
+		return err
 	}
 	existingMasterProfile.ProfileStatus.References = refs
 
@@ -192,8 +220,17 @@ func mergeMatchedProfiles(existingMasterProfile profileModel.Profile, newProfile
 	schemaRules, err := schemaStore.GetProfileSchemaAttributesForOrg(newProfile.OrgHandle)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to fetch profile schema attributes for org %s during unification of profile %s", newProfile.OrgHandle, newProfile.ProfileId))
+		return err
+	}
+	var sourcePriority []string
+	adminConfig, err := adminConfigStore.GetAdminConfig(newProfile.OrgHandle)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to fetch source priority for org %s during unification of profile %s", newProfile.OrgHandle, newProfile.ProfileId))
+		return err
+	} else if adminConfig != nil {
+		sourcePriority = adminConfig.SourcePriority
 	}
-	newMasterProfile := MergeProfiles(existingMasterProfile, newProfile, schemaRules)
+	newMasterProfile := MergeProfiles(existingMasterProfile, newProfile, schemaRules, sourcePriority)
 
 	hasUserIDExisting := existingMasterProfile.UserId != ""
 	hasUserIDNew := newProfile.UserId != ""
@@ -205,28 +242,37 @@ func mergeMatchedProfiles(existingMasterProfile profileModel.Profile, newProfile
 		logger.Info(fmt.Sprintf("Not merging profiles %s and %s — different userIds (%s vs %s)",
 			existingMasterProfile.ProfileId, newProfile.ProfileId,
 			existingMasterProfile.UserId, newProfile.UserId))
-		return
+		return nil
 	}
 
-	// ── Case: perm-temp or temp-perm ──
-	if hasUserIDExisting != hasUserIDNew {
-		mergePermanentAndTemporary(existingMasterProfile, newProfile, newMasterProfile, reason, hasExistingChildren)
-		return
-	}
+	// The whole merge is written in one transaction, so that a failure at any step leaves both profiles as
+	// they were and the redelivered message can merge them from scratch.
+	err = profileStore.WithTransaction(func(tx *sql.Tx) error {
+		// ── Case: perm-temp or temp-perm ──
+		if hasUserIDExisting != hasUserIDNew {
+			return mergePermanentAndTemporary(tx, existingMasterProfile, newProfile, newMasterProfile, reason, hasExistingChildren)
+		}
 
-	// ── Case: Both permanent with same userId OR both temporary ──
-	mergeSameKindProfiles(existingMasterProfile, newProfile, newMasterProfile, reason, hasUserIDExisting, hasExistingChildren)
+		// ── Case: Both permanent with same userId OR both temporary ──
+		return mergeSameKindProfiles(tx, existingMasterProfile, newProfile, newMasterProfile, reason, hasUserIDExisting, hasExistingChildren)
+	})
+	if err != nil {
+		return err
+	}
+	metrics.UnificationMerges.WithLabelValues(mergeType(hasUserIDExisting, hasUserIDNew), reason).Inc()
+	return nil
 }
 
 // mergePermanentAndTemporary merges a permanent profile (has userId) with a temporary one.
 // The permanent profile always becomes the master.
 func mergePermanentAndTemporary(
+	tx *sql.Tx,
 	existingMasterProfile profileModel.Profile,
 	newProfile profileModel.Profile,
 	newMasterProfile profileModel.Profile,
 	reason string,
 	hasExistingChildren bool,
-) {
+) error {
 	logger := log.GetLogger()
 
 	hasUserIDExisting := existingMasterProfile.UserId != ""
@@ -245,10 +291,10 @@ func mergePermanentAndTemporary(
 		}
 		children := []profileModel.Reference{newChild}
 
-		if err := profileStore.UpdateProfileReferences(newMasterProfile, children); err != nil {
+		if err := mergeStep(MergeStepReferences, profileStore.UpdateProfileReferences(tx, newMasterProfile, children)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to add child profile %s to master %s",
 				newProfile.ProfileId, newMasterProfile.ProfileId), log.Error(err))
-			return
+			return err
 		}
 	} else {
 		// New is permanent — it becomes master, existing becomes child
@@ -260,10 +306,10 @@ func mergePermanentAndTemporary(
 
 		// If the existing master had children, re-parent them to the new master
 		if hasExistingChildren {
-			if err := profileStore.UpdateProfileReferences(newMasterProfile, existingMasterProfile.ProfileStatus.References); err != nil {
+			if err := mergeStep(MergeStepReferences, profileStore.UpdateProfileReferences(tx, newMasterProfile, existingMasterProfile.ProfileStatus.References)); err != nil {
 				logger.Error(fmt.Sprintf("Failed to re-parent references from %s to %s",
 					existingMasterProfile.ProfileId, newMasterProfile.ProfileId), log.Error(err))
-				return
+				return err
 			}
 		}
 
@@ -273,27 +319,28 @@ func mergePermanentAndTemporary(
 		}
 		children := []profileModel.Reference{newChild}
 
-		if err := profileStore.UpdateProfileReferences(newMasterProfile, children); err != nil {
+		if err := mergeStep(MergeStepReferences, profileStore.UpdateProfileReferences(tx, newMasterProfile, children)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to add child profile %s to master %s",
 				existingMasterProfile.ProfileId, newMasterProfile.ProfileId), log.Error(err))
-			return
+			return err
 		}
 	}
 
 	// Write merged data to the master profile
-	persistMergedProfileData(newMasterProfile, newProfile.ProfileId)
+	return persistMergedProfileData(tx, newMasterProfile, newProfile.ProfileId)
 }
 
 // mergeSameKindProfiles merges two profiles of the same kind:
 // both permanent (same userId) or both temporary.
 func mergeSameKindProfiles(
+	tx *sql.Tx,
 	existingMasterProfile profileModel.Profile,
 	newProfile profileModel.Profile,
 	newMasterProfile profileModel.Profile,
 	reason string,
 	bothPermanent bool,
 	hasExistingChildren bool,
-) {
+) error {
 	logger := log.GetLogger()
 
 	if hasExistingChildren {
@@ -315,10 +362,10 @@ func mergeSameKindProfiles(
 		}
 		children := []profileModel.Reference{newChild}
 
-		if err := profileStore.UpdateProfileReferences(newMasterProfile, children); err != nil {
+		if err := mergeStep(MergeStepReferences, profileStore.UpdateProfileReferences(tx, newMasterProfile, children)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to add child profile %s to master %s",
 				newProfile.ProfileId, newMasterProfile.ProfileId), log.Error(err))
-			return
+			return err
 		}
 	} else if bothPermanent {
 		// Both permanent, same userId, no children — promote existing as master.
@@ -334,10 +381,10 @@ func mergeSameKindProfiles(
 		}
 		children := []profileModel.Reference{newChild}
 
-		if err := profileStore.UpdateProfileReferences(newMasterProfile, children); err != nil {
+		if err := mergeStep(MergeStepReferences, profileStore.UpdateProfileReferences(tx, newMasterProfile, children)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to add child profile %s to master %s",
 				newProfile.ProfileId, newMasterProfile.ProfileId), log.Error(err))
-			return
+			return err
 		}
 	} else {
 		// Both temporary, no children — create a new neutral master referencing both.
@@ -363,57 +410,64 @@ func mergeSameKindProfiles(
 			References:         []profileModel.Reference{childProfile1, childProfile2},
 		}
 
-		if err := profileStore.InsertProfile(newMasterProfile); err != nil {
-			_ = profileStore.DeleteProfile(newMasterProfile.ProfileId) // cleanup
+		if err := mergeStep(MergeStepInsertMaster, profileStore.InsertProfileTx(tx, newMasterProfile)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to insert new master profile while unifying %s and %s",
 				newProfile.ProfileId, existingMasterProfile.ProfileId), log.Error(err))
-			return
+			return err
 		}
 
 		children := []profileModel.Reference{childProfile1, childProfile2}
-		if err := profileStore.UpdateProfileReferences(newMasterProfile, children); err != nil {
+		if err := mergeStep(MergeStepReferences, profileStore.UpdateProfileReferences(tx, newMasterProfile, children)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to add child profiles to new master %s",
 				newMasterProfile.ProfileId), log.Error(err))
-			return
+			return err
 		}
 	}
 
 	// Write merged data to the master profile
-	persistMergedProfileData(newMasterProfile, newProfile.ProfileId)
+	return persistMergedProfileData(tx, newMasterProfile, newProfile.ProfileId)
 }
 
 // persistMergedProfileData writes the merged application data, traits, and identity attributes
-// to the master profile in the store.
-func persistMergedProfileData(masterProfile profileModel.Profile, triggerProfileId string) {
+// to the master profile in the store, within the merge's transaction.
+func persistMergedProfileData(tx *sql.Tx, masterProfile profileModel.Profile, triggerProfileId string) error {
 
 	logger := log.GetLogger()
 
 	// Update ApplicationData
 	for _, appCtx := range masterProfile.ApplicationData {
-		if err := profileStore.InsertMergedMasterProfileAppData(masterProfile.ProfileId, appCtx); err != nil {
+		if err := mergeStep(MergeStepAppData, profileStore.InsertMergedMasterProfileAppData(tx, masterProfile.ProfileId, appCtx)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to update app data for master profile %s while unifying profile %s",
 				masterProfile.ProfileId, triggerProfileId), log.Error(err))
-			return
+			return err
 		}
 	}
 
 	// Update Traits
 	if masterProfile.Traits != nil {
-		if err := profileStore.InsertMergedMasterProfileTraitData(masterProfile.ProfileId, masterProfile.Traits); err != nil {
+		if err := mergeStep(MergeStepTraits, profileStore.InsertMergedMasterProfileTraitData(tx, masterProfile.ProfileId, masterProfile.Traits)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to update traits for master profile %s while unifying profile %s",
 				masterProfile.ProfileId, triggerProfileId), log.Error(err))
-			return
+			return err
 		}
 	}
 
 	// Update Identity Attributes
 	if masterProfile.IdentityAttributes != nil {
-		if err := profileStore.MergeIdentityDataOfProfiles(masterProfile.ProfileId, masterProfile.IdentityAttributes); err != nil {
+		if err := mergeStep(MergeStepIdentityData, profileStore.MergeIdentityDataOfProfiles(tx, masterProfile.ProfileId, masterProfile.IdentityAttributes)); err != nil {
 			logger.Error(fmt.Sprintf("Failed to update identity data for master profile %s while unifying profile %s",
 				masterProfile.ProfileId, triggerProfileId), log.Error(err))
-			return
+			return err
 		}
 	}
+
+	// Update attribute metadata
+	if err := mergeStep(MergeStepAttributeMetadata, profileStore.UpdateProfileAttributeMetadata(tx, masterProfile.ProfileId, masterProfile.AttributeMetadata)); err != nil {
+		logger.Error(fmt.Sprintf("Failed to update attribute metadata for master profile %s while unifying profile %s",
+			masterProfile.ProfileId, triggerProfileId), log.Error(err))
+		return err
+	}
+	return nil
 }
 
 func filterActiveRulesAndSortByPriority(rules []model.UnificationRule) []model.UnificationRule {
@@ -429,8 +483,11 @@ func filterActiveRulesAndSortByPriority(rules []model.UnificationRule) []model.U
 	return activeRules
 }
 
-// MergeProfiles merges two profiles based on schema rules and returns the merged profile.
-func MergeProfiles(existingProfile profileModel.Profile, incomingProfile profileModel.Profile, schemaRules []schemaModel.ProfileSchemaAttribute) profileModel.Profile {
+// MergeProfiles merges two profiles based on schema rules and returns the merged profile. sourcePriority ranks
+// applications from most to least trusted for attributes using the source_priority strategy. Computed attributes
+// are recomputed from the merged data.
+func MergeProfiles(existingProfile profileModel.Profile, incomingProfile profileModel.Profile,
+	schemaRules []schemaModel.ProfileSchemaAttribute, sourcePriority []string) profileModel.Profile {
 	logger := log.GetLogger()
 	logger.Info("Merging profiles, " + existingProfile.ProfileId + " and " + incomingProfile.ProfileId)
 
@@ -438,9 +495,12 @@ func MergeProfiles(existingProfile profileModel.Profile, incomingProfile profile
 	ruleMap := buildSchemaRuleMap(schemaRules)
 	appRuleMap := buildApplicationSchemaRuleMap(schemaRules)
 
-	merged.Traits = mergeNamespaceMap(existingProfile.Traits, incomingProfile.Traits, "traits", ruleMap)
-	merged.IdentityAttributes = mergeNamespaceMap(existingProfile.IdentityAttributes, incomingProfile.IdentityAttributes, "identity_attributes", ruleMap)
-	merged.ApplicationData = mergeAppData(existingProfile.ApplicationData, incomingProfile.ApplicationData, appRuleMap)
+	provenance := newMergeProvenance(existingProfile, incomingProfile, sourcePriority)
+
+	merged.Traits = mergeNamespaceMap(existingProfile.Traits, incomingProfile.Traits, "traits", "traits", ruleMap, provenance)
+	merged.IdentityAttributes = mergeNamespaceMap(existingProfile.IdentityAttributes, incomingProfile.IdentityAttributes, "identity_attributes", "identity_attributes", ruleMap, provenance)
+	merged.ApplicationData = mergeAppData(existingProfile.ApplicationData, incomingProfile.ApplicationData, appRuleMap, provenance)
+	merged.AttributeMetadata = provenance.merged
 
 	if incomingProfile.UserId != "" {
 		merged.UserId = incomingProfile.UserId
@@ -453,19 +513,26 @@ func MergeProfiles(existingProfile profileModel.Profile, incomingProfile profile
 	merged.CreatedAt = existingProfile.CreatedAt
 	merged.UpdatedAt = time.Now().UTC()
 
+	// Computed attributes are derived from the merged data rather than merged themselves.
+	ApplyComputedAttributes(&merged, schemaRules, merged.UpdatedAt)
+
 	return merged
 }
 
+// buildSchemaRuleMap indexes the schema rules by attribute path. Nested sub-attribute definitions get a rule of
+// their own, so mergeByPath applies each sub-attribute's merge strategy at every depth.
 func buildSchemaRuleMap(rules []schemaModel.ProfileSchemaAttribute) map[string]schemaModel.ProfileSchemaAttribute {
 	ruleMap := make(map[string]schemaModel.ProfileSchemaAttribute, len(rules))
-	for _, rule := range rules {
+	for _, rule := range schemaModel.FlattenSubAttributes(rules) {
 		ruleMap[rule.AttributeName] = rule
 	}
 	return ruleMap
 }
 
-func mergeNamespaceMap(existingMap, incomingMap map[string]interface{}, namespace string,
-	ruleMap map[string]schemaModel.ProfileSchemaAttribute) map[string]interface{} {
+// mergeNamespaceMap merges the top-level attributes of one namespace. metadataScope prefixes the attribute metadata
+// keys of the namespace ("traits", or "application_data.<appId>" for application data).
+func mergeNamespaceMap(existingMap, incomingMap map[string]interface{}, namespace, metadataScope string,
+	ruleMap map[string]schemaModel.ProfileSchemaAttribute, provenance *mergeProvenance) map[string]interface{} {
 
 	if existingMap == nil && incomingMap == nil {
 		return nil
@@ -480,14 +547,18 @@ func mergeNamespaceMap(existingMap, incomingMap map[string]interface{}, namespac
 	for k, incomingVal := range incomingMap {
 		path := namespace + "." + k
 		existingVal := result[k]
-		result[k] = mergeByPath(existingVal, incomingVal, path, ruleMap)
+		attr := provenance.attribute(metadataScope + "." + k)
+		result[k] = mergeByPath(existingVal, incomingVal, path, ruleMap, attr)
+		provenance.record(attr)
 	}
 
 	return result
 }
 
+// mergeByPath merges one attribute value, recursing into complex values. attr is the write metadata of the
+// top-level attribute the value belongs to.
 func mergeByPath(existing, incoming interface{}, currentPath string,
-	ruleMap map[string]schemaModel.ProfileSchemaAttribute) interface{} {
+	ruleMap map[string]schemaModel.ProfileSchemaAttribute, attr *attributeProvenance) interface{} {
 
 	if rule, ok := ruleMap[currentPath]; ok {
 		if strings.EqualFold(rule.ValueType, constants.ComplexDataType) && !rule.MultiValued {
@@ -504,13 +575,16 @@ func mergeByPath(existing, incoming interface{}, currentPath string,
 				for k, incomingChild := range incomingMap {
 					childPath := currentPath + "." + k
 					existingChild := result[k]
-					result[k] = mergeByPath(existingChild, incomingChild, childPath, ruleMap)
+					result[k] = mergeByPath(existingChild, incomingChild, childPath, ruleMap, attr)
 				}
 
 				return result
 			}
 		}
 
+		if isProvenanceStrategy(rule.MergeStrategy) {
+			return attr.pick(existing, incoming, rule.MergeStrategy)
+		}
 		return MergeAttributeValue(existing, incoming, rule.MergeStrategy, rule.ValueType, rule.MultiValued)
 	}
 
@@ -527,7 +601,7 @@ func mergeByPath(existing, incoming interface{}, currentPath string,
 		for k, incomingChild := range incomingMap {
 			childPath := currentPath + "." + k
 			existingChild := result[k]
-			result[k] = mergeByPath(existingChild, incomingChild, childPath, ruleMap)
+			result[k] = mergeByPath(existingChild, incomingChild, childPath, ruleMap, attr)
 		}
 
 		return result
@@ -922,7 +996,7 @@ func combineUniqueBools(a, b []bool) []bool {
 }
 
 func mergeAppData(existingAppData, incomingAppData []profileModel.ApplicationData,
-	appRuleMap map[string]map[string]schemaModel.ProfileSchemaAttribute) []profileModel.ApplicationData {
+	appRuleMap map[string]map[string]schemaModel.ProfileSchemaAttribute, provenance *mergeProvenance) []profileModel.ApplicationData {
 
 	logger := log.GetLogger()
 	mergedMap := make(map[string]profileModel.ApplicationData)
@@ -935,8 +1009,10 @@ func mergeAppData(existingAppData, incomingAppData []profileModel.ApplicationDat
 	for _, newApp := range incomingAppData {
 		existingApp, found := mergedMap[newApp.AppId]
 		logger.Info("Merging existing application data for application: " + newApp.AppId)
+		metadataScope := constants.ApplicationData + "." + newApp.AppId
 		if !found {
 			mergedMap[newApp.AppId] = newApp
+			provenance.adoptIncoming(metadataScope + ".")
 			continue
 		}
 
@@ -945,7 +1021,9 @@ func mergeAppData(existingAppData, incomingAppData []profileModel.ApplicationDat
 			existingApp.AppSpecificData,
 			newApp.AppSpecificData,
 			"application_data",
+			metadataScope,
 			appRules,
+			provenance,
 		)
 
 		mergedMap[newApp.AppId] = existingApp
@@ -964,7 +1042,7 @@ func mergeAppData(existingAppData, incomingAppData []profileModel.ApplicationDat
 func buildApplicationSchemaRuleMap(rules []schemaModel.ProfileSchemaAttribute) map[string]map[string]schemaModel.ProfileSchemaAttribute {
 	result := make(map[string]map[string]schemaModel.ProfileSchemaAttribute)
 
-	for _, rule := range rules {
+	for _, rule := range schemaModel.FlattenSubAttributes(rules) {
 		if !strings.HasPrefix(rule.AttributeName, "application_data.") {
 			continue
 		}
@@ -980,3 +1058,25 @@ func buildApplicationSchemaRuleMap(rules []schemaModel.ProfileSchemaAttribute) m
 
 	return result
 }
+
+// Steps of a merge, each writing to the merge's transaction.
+const (
+	MergeStepInsertMaster      = "insert_master"
+	MergeStepReferences        = "references"
+	MergeStepAppData           = "app_data"
+	MergeStepTraits            = "traits"
+	MergeStepIdentityData      = "identity_data"
+	MergeStepAttributeMetadata = "attribute_metadata"
+)
+
+// failMergeStepFn, when set, is consulted after every successful merge step and fails the merge when it
+// returns an error. Tests use it to interrupt merges half way.
+var failMergeStepFn func(step string) error
+
+// mergeStep returns the error of a merge step, or the injected failure of a step that succeeded.
+func mergeStep(step string, err error) error {
+	if err != nil || failMergeStepFn == nil {
+		return err
+	}
+	return failMergeStepFn(step)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	adminConfigStore "github.com/wso2/identity-customer-data-service/internal/admin_config/store"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+var schemaDriftDone chan struct{}
+
+// StartSchemaDriftWorker periodically checks the schema of every CDS enabled organization for drift from the
+// identity server claims, and logs a warning for each organization that drifted.
+func StartSchemaDriftWorker(cfg config.SchemaSyncConfig) {
+
+	logger := log.GetLogger()
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = constants.DefaultSchemaDriftCheckTime
+		logger.Info("Schema drift check interval not set or invalid. Defaulting to 1 hour.")
+	}
+	interval := time.Duration(cfg.Interval) * time.Second
+
+	logger.Info(fmt.Sprintf("Schema drift worker started. Interval: %s", interval))
+
+	schemaDriftDone = startPeriodicJob(constants.JobTypeSchemaDriftCheck, interval, runSchemaDriftCheck)
+}
+
+func StopSchemaDriftWorker() {
+	if schemaDriftDone != nil {
+		close(schemaDriftDone)
+		log.GetLogger().Info("Schema drift worker stopped")
+	}
+}
+
+// runSchemaDriftCheck checks every CDS enabled organization for drift, and returns the organizations that drifted.
+func runSchemaDriftCheck(progress func(percent int)) (interface{}, error) {
+
+	logger := log.GetLogger()
+	orgHandles, err := adminConfigStore.GetCDSEnabledOrgs()
+	if err != nil {
+		logger.Debug("Schema drift check could not list organizations", log.Error(err))
+		return nil, err
+	}
+
+	drifted := []string{}
+	schemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
+	for i, orgHandle := range orgHandles {
+		report, err := schemaService.GetSchemaDriftReport(orgHandle)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Schema drift check failed for organization: %s", orgHandle), log.Error(err))
+		} else if !report.InSync {
+			drifted = append(drifted, orgHandle)
+			logger.Warn(fmt.Sprintf("Profile schema of organization: %s has drifted from the identity server. "+
+				"Missing: %d, extra: %d, mismatched: %d", orgHandle, len(report.Missing), len(report.Extra),
+				len(report.Mismatched)))
+		}
+		progress((i + 1) * 100 / len(orgHandles))
+	}
+	return map[string]interface{}{"checked": len(orgHandles), "drifted": drifted}, nil
+}
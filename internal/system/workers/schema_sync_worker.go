@@ -19,13 +19,18 @@
 package workers
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	jobProvider "github.com/wso2/identity-customer-data-service/internal/job/provider"
 	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
 	"github.com/wso2/identity-customer-data-service/internal/profile_schema/provider"
+	schemaStore "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
 	"github.com/wso2/identity-customer-data-service/internal/system/config"
 	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
 	"github.com/wso2/identity-customer-data-service/internal/system/queue"
 )
 
@@ -55,9 +60,34 @@ func StartSchemaSyncWorker() error {
 	schemaSyncQueueMu.Lock()
 	activeSchemaSyncQueue = q
 	schemaSyncQueueMu.Unlock()
+	resumeSchemaSyncBacklog(q)
 	return nil
 }
 
+// resumeSchemaSyncBacklog enqueues the schema sync jobs persisted by the last
+// shutdown. Jobs that cannot be enqueued are persisted again for the next start.
+func resumeSchemaSyncBacklog(q queue.SchemaSyncQueue) {
+	logger := log.GetLogger()
+	backlog, err := schemaStore.TakeSchemaSyncBacklog()
+	if err != nil {
+		logger.Error("Failed to load the schema sync jobs left by the last shutdown", log.Error(err))
+		return
+	}
+	if len(backlog) == 0 {
+		return
+	}
+	logger.Info(fmt.Sprintf("Resuming %d schema sync jobs left by the last shutdown", len(backlog)))
+	for i, schemaSync := range backlog {
+		if err := q.Enqueue(schemaSync); err != nil {
+			logger.Error("Failed to enqueue the schema sync jobs left by the last shutdown", log.Error(err))
+			if err := schemaStore.AddToSchemaSyncBacklog(backlog[i:]); err != nil {
+				logger.Error("Failed to persist the schema sync jobs left by the last shutdown", log.Error(err))
+			}
+			return
+		}
+	}
+}
+
 // EnqueueSchemaSyncJob adds a schema sync job to the active queue. It is a
 // no-op when the worker has not been started or has been stopped. Enqueue
 // errors are logged but not propagated, because schema sync is a best-effort
@@ -72,35 +102,68 @@ func EnqueueSchemaSyncJob(schemaSync model.ProfileSchemaSync) error {
 	return q.Enqueue(schemaSync)
 }
 
-// StopSchemaSyncWorker gracefully shuts down the schema sync queue. It nils
-// out the global reference under a write lock before calling Close, ensuring
-// no concurrent Enqueue can send on a closed queue. It should be called
-// during application shutdown.
+// StopSchemaSyncWorker shuts down the schema sync queue without waiting for
+// the pending jobs, which are persisted instead.
 func StopSchemaSyncWorker() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return DrainSchemaSyncWorker(ctx)
+}
+
+// DrainSchemaSyncWorker gracefully shuts down the schema sync queue. It nils
+// out the global reference under a write lock before closing the queue,
+// ensuring no concurrent Enqueue can send on a closed queue. A queue that
+// holds its jobs in memory keeps processing them until ctx is done; the jobs
+// left are persisted and enqueued again by the next start. It should be called
+// during application shutdown.
+func DrainSchemaSyncWorker(ctx context.Context) error {
 	schemaSyncQueueMu.Lock()
 	q := activeSchemaSyncQueue
 	activeSchemaSyncQueue = nil
 	schemaSyncQueueMu.Unlock()
-	if q != nil {
+	if q == nil {
+		return nil
+	}
+	drainer, ok := q.(queue.SchemaSyncQueueDrainer)
+	if !ok {
 		return q.Close()
 	}
-	return nil
+	pending := drainer.Drain(ctx)
+	if len(pending) > 0 {
+		log.GetLogger().Info(fmt.Sprintf("Persisting %d pending schema sync jobs for the next start", len(pending)))
+	}
+	return schemaStore.AddToSchemaSyncBacklog(pending)
 }
 
-// processSchemaSyncJob processes a schema sync job
+// processSchemaSyncJob processes a schema sync job, recording its outcome on the job of the sync if it has one.
 func processSchemaSyncJob(schemaSync model.ProfileSchemaSync) {
 
+	if schemaSync.JobId == "" {
+		_ = applySchemaSync(schemaSync)
+		return
+	}
+	_ = jobProvider.NewJobProvider().GetJobService().RunJob(schemaSync.JobId,
+		func(progress func(percent int)) (interface{}, error) {
+			return nil, applySchemaSync(schemaSync)
+		})
+}
+
+func applySchemaSync(schemaSync model.ProfileSchemaSync) error {
+
+	start := time.Now()
 	logger := log.GetLogger()
 	logger.Info(fmt.Sprintf("Processing schema sync job for tenant: %s, event: %s", schemaSync.OrgId, schemaSync.Event))
 
 	schemaProvider := provider.NewProfileSchemaProvider()
 	schemaService := schemaProvider.GetProfileSchemaService()
 
-	err := schemaService.SyncProfileSchema(schemaSync.OrgId)
+	err := schemaService.ApplySchemaSyncEvent(schemaSync)
+	observeProcessing(metrics.QueueSchemaSync, start, err)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to sync profile schema for tenant: %s", schemaSync.OrgId), log.Error(err))
-		return
+		return err
 	}
 
 	logger.Info(fmt.Sprintf("Profile schema sync completed successfully for tenant: %s", schemaSync.OrgId))
+	return nil
 }
@@ -0,0 +1,31 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+// OverrideFailMergeStepForTest makes every merge consult fn after each of its steps, failing the merge at
+// the step for which fn returns an error.
+func OverrideFailMergeStepForTest(fn func(step string) error) (restore func()) {
+
+	prev := failMergeStepFn
+	failMergeStepFn = fn
+
+	return func() {
+		failMergeStepFn = prev
+	}
+}
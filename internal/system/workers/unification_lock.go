@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
+	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
+)
+
+// Unification lock types.
+const (
+	UnificationLockMemory   = "memory"
+	UnificationLockPostgres = "postgres"
+)
+
+// unificationLocker serialises unifications that could pick the same master profile. Unifications of different
+// organizations never touch the same profiles, so they are locked per organization and run concurrently.
+type unificationLocker interface {
+	// lock blocks until the key is free and returns the function releasing it.
+	lock(key string) (unlock func(), err error)
+}
+
+func newUnificationLocker(lockType string) (unificationLocker, error) {
+	switch lockType {
+	case "", UnificationLockMemory:
+		return &keyedMutex{locks: map[string]*keyedLock{}}, nil
+	case UnificationLockPostgres:
+		return advisoryLocker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown unification lock %q; use %q or %q", lockType,
+			UnificationLockMemory, UnificationLockPostgres)
+	}
+}
+
+func unificationLockKey(orgHandle string) string {
+	return "unification:" + orgHandle
+}
+
+// keyedMutex is an in-process mutex per key. It only serialises the workers of this instance.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+func (m *keyedMutex) lock(key string) (func(), error) {
+	m.mu.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		m.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}, nil
+}
+
+// advisoryLocker takes PostgreSQL advisory locks, which serialise the workers of all instances sharing the
+// database. The lock is held by an open transaction, so it is released even if the instance dies.
+type advisoryLocker struct{}
+
+func (advisoryLocker) lock(key string) (func(), error) {
+	dbProvider := provider.NewDBProvider()
+	dbClient, err := dbProvider.GetDBClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database client for unification lock %s: %w", key, err)
+	}
+	tx, err := dbClient.BeginTx()
+	if err != nil {
+		_ = dbClient.Close()
+		return nil, fmt.Errorf("failed to begin transaction for unification lock %s: %w", key, err)
+	}
+	if _, err := tx.Exec(scripts.AcquireUnificationLock[dbProvider.GetDBType()], key); err != nil {
+		_ = tx.Rollback()
+		_ = dbClient.Close()
+		return nil, fmt.Errorf("failed to acquire unification lock %s: %w", key, err)
+	}
+	return func() {
+		_ = tx.Commit()
+		_ = dbClient.Close()
+	}, nil
+}
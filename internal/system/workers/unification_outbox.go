@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+)
+
+// The unification outbox holds the profiles to unify. Each entry is written in the transaction that writes its
+// profile, and the relay publishes the entries to the active queue, deleting them in the same transaction that
+// locked them. A committed profile write is therefore enqueued even if the queue is down or the instance stops
+// right after the write. An entry whose deletion fails after it was published is published again; unification
+// re-reads the profile, so the duplicate is harmless. The relays of several instances skip each other's entries.
+//
+// A profile is published once no write to it has been committed for the debounce window, and all of its pending
+// entries are coalesced into a single message: a burst of writes to a profile is unified once, on its final state.
+// A profile that is written more often than the window is published once its oldest entry has waited the maximum
+// wait, so that it is still unified while the writes go on.
+var (
+	outboxRelayMu   sync.Mutex
+	outboxRelayStop chan struct{}
+	outboxRelayDone chan struct{}
+	// outboxRelayWake lets a profile write trigger the relay without waiting for the next poll.
+	outboxRelayWake = make(chan struct{}, 1)
+)
+
+// NotifyUnificationOutbox wakes the outbox relay after a profile write committed an outbox entry.
+func NotifyUnificationOutbox() {
+	select {
+	case outboxRelayWake <- struct{}{}:
+	default:
+	}
+}
+
+// startUnificationOutboxRelay starts publishing the outbox to the active profile queue.
+func startUnificationOutboxRelay(cfg config.OutboxConfig) {
+	interval := time.Duration(cfg.PollInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = constants.DefaultOutboxPollInterval * time.Millisecond
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = constants.DefaultOutboxBatchSize
+	}
+	debounce := cfg.Debounce
+	if debounce <= 0 {
+		debounce = constants.DefaultOutboxDebounce
+	}
+	maxWait := cfg.MaxWait
+	if maxWait <= 0 {
+		maxWait = constants.DefaultOutboxMaxWait
+	}
+	// A profile is always allowed to settle for one debounce window.
+	if maxWait < debounce {
+		maxWait = debounce
+	}
+
+	outboxRelayMu.Lock()
+	defer outboxRelayMu.Unlock()
+	stop, done := make(chan struct{}), make(chan struct{})
+	outboxRelayStop, outboxRelayDone = stop, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		// settled fires once the debounce window has passed since the last wake-up, when the profiles written
+		// last are due.
+		var settled <-chan time.Time
+		drainUnificationOutbox(batchSize, debounce, maxWait)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			case <-outboxRelayWake:
+				settled = time.After(time.Duration(debounce) * time.Millisecond)
+				continue
+			case <-settled:
+				settled = nil
+			}
+			drainUnificationOutbox(batchSize, debounce, maxWait)
+		}
+	}()
+}
+
+// stopUnificationOutboxRelay stops the relay and waits for the batch it is publishing. Entries left in the
+// outbox are published once an instance starts again.
+func stopUnificationOutboxRelay() {
+	outboxRelayMu.Lock()
+	stop, done := outboxRelayStop, outboxRelayDone
+	outboxRelayStop, outboxRelayDone = nil, nil
+	outboxRelayMu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// drainUnificationOutbox publishes batches until no entry is due or publishing fails.
+func drainUnificationOutbox(batchSize, debounce, maxWait int) {
+	for {
+		published, err := relayUnificationOutbox(batchSize, debounce, maxWait)
+		if err != nil {
+			log.GetLogger().Warn("Failed to relay the unification outbox; retrying on the next poll", log.Error(err))
+			return
+		}
+		if published < batchSize {
+			return
+		}
+	}
+}
+
+// relayUnificationOutbox publishes one batch of due outbox entries to the active queue, and returns how many
+// entries were published. The entries of a profile are published as one message. Publishing stops at the first
+// message the queue rejects; its entries and the entries after them stay in the outbox.
+func relayUnificationOutbox(batchSize, debounce, maxWait int) (int, error) {
+	profileQueueMu.RLock()
+	q := activeProfileQueue
+	profileQueueMu.RUnlock()
+	if q == nil {
+		return 0, nil
+	}
+
+	published := 0
+	var enqueueErr error
+	err := profileStore.WithTransaction(func(tx *sql.Tx) error {
+		entries, err := profileStore.LockUnificationOutboxEntries(tx, batchSize, debounce, maxWait)
+		if err != nil {
+			return err
+		}
+		// Only the locked entries are deleted: an entry committed after the lock may belong to a write that the
+		// published message is processed too early to see.
+		var order []profileModel.UnificationMessage
+		entriesOf := map[profileModel.UnificationMessage][]int64{}
+		for _, entry := range entries {
+			if _, seen := entriesOf[entry.Message]; !seen {
+				order = append(order, entry.Message)
+			}
+			entriesOf[entry.Message] = append(entriesOf[entry.Message], entry.Id)
+		}
+		for _, message := range order {
+			if err := q.Enqueue(message); err != nil {
+				enqueueErr = fmt.Errorf("failed to enqueue profile %s for unification: %w", message.ProfileId, err)
+				return nil
+			}
+			for _, id := range entriesOf[message] {
+				if err := profileStore.DeleteUnificationOutboxEntry(tx, id); err != nil {
+					return err
+				}
+				published++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, enqueueErr
+}
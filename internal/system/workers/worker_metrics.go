@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package workers
+
+import (
+	"time"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+)
+
+// Kinds of the two profiles of a merge, as the value of the "type" label: temporary profiles have no userId,
+// permanent ones have.
+const (
+	mergeTypeTempTemp = "TEMP_TEMP"
+	mergeTypeTempPerm = "TEMP_PERM"
+	mergeTypePermPerm = "PERM_PERM"
+)
+
+// mergeType returns the kinds of two merged profiles, by whether each has a userId.
+func mergeType(hasUserIdExisting, hasUserIdNew bool) string {
+	switch {
+	case hasUserIdExisting && hasUserIdNew:
+		return mergeTypePermPerm
+	case hasUserIdExisting || hasUserIdNew:
+		return mergeTypeTempPerm
+	default:
+		return mergeTypeTempTemp
+	}
+}
+
+// observeProcessing records how long a worker took to process an item of the queue, and whether it succeeded.
+func observeProcessing(queueName string, start time.Time, err error) {
+	outcome := metrics.OutcomeSuccess
+	if err != nil {
+		outcome = metrics.OutcomeFailure
+	}
+	metrics.WorkerProcessingDuration.WithLabelValues(queueName, outcome).Observe(time.Since(start).Seconds())
+}
@@ -19,6 +19,7 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"strings"
@@ -33,9 +34,11 @@ import (
 
 type UnificationRuleServiceInterface interface {
 	AddUnificationRule(rule model.UnificationRule, orgHandle string) error
+	AddUnificationRuleTx(tx *sql.Tx, rule model.UnificationRule, orgHandle string) error
 	GetUnificationRules(orgHandle string) ([]model.UnificationRule, error)
 	GetUnificationRule(ruleId string) (*model.UnificationRule, error)
 	PatchUnificationRule(ruleId, orgHandle string, updatedRule model.UnificationRule) error
+	PatchUnificationRuleTx(tx *sql.Tx, ruleId, orgHandle string, updatedRule model.UnificationRule) error
 	DeleteUnificationRule(ruleId string) error
 }
 
@@ -51,6 +54,15 @@ func GetUnificationRuleService() UnificationRuleServiceInterface {
 // AddUnificationRule Adds a new unification rule.
 func (urs *UnificationRuleService) AddUnificationRule(rule model.UnificationRule, orgHandle string) error {
 
+	return store.WithTransaction(func(tx *sql.Tx) error {
+		return urs.AddUnificationRuleTx(tx, rule, orgHandle)
+	})
+}
+
+// AddUnificationRuleTx Adds a new unification rule within the given transaction, checking it against the schema and
+// the rules as seen within the transaction.
+func (urs *UnificationRuleService) AddUnificationRuleTx(tx *sql.Tx, rule model.UnificationRule, orgHandle string) error {
+
 	logger := log.GetLogger()
 	// Need to specifically prevent
 	if rule.PropertyName == "user_id" || rule.PropertyName == "identity_attributes.user_id" {
@@ -70,7 +82,7 @@ func (urs *UnificationRuleService) AddUnificationRule(rule model.UnificationRule
 	}
 
 	profileSchemaService := provider.NewProfileSchemaProvider().GetProfileSchemaService()
-	schemaAttribute, err := profileSchemaService.GetProfileSchemaAttributeByName(rule.PropertyName, rule.OrgHandle)
+	schemaAttribute, err := profileSchemaService.GetProfileSchemaAttributeByNameTx(tx, rule.PropertyName, rule.OrgHandle)
 
 	if err != nil {
 		errorMsg := fmt.Sprintf("Error occurred while checking for the property: %s", rule.PropertyName)
@@ -100,7 +112,7 @@ func (urs *UnificationRuleService) AddUnificationRule(rule model.UnificationRule
 	}
 
 	// Check if a similar unification rule already exists
-	existingRules, err := store.GetUnificationRules(orgHandle)
+	existingRules, err := store.GetUnificationRulesTx(tx, orgHandle)
 	if err != nil {
 		return err
 	}
@@ -121,7 +133,7 @@ func (urs *UnificationRuleService) AddUnificationRule(rule model.UnificationRule
 		}
 	}
 	rule.PropertyId = schemaAttribute.AttributeId
-	return store.AddUnificationRule(rule, orgHandle)
+	return store.AddUnificationRuleTx(tx, rule, orgHandle)
 }
 
 // GetUnificationRules Fetches all resolution rules.
@@ -149,6 +161,15 @@ func (urs *UnificationRuleService) GetUnificationRule(ruleId string) (*model.Uni
 // PatchUnificationRule Applies a partial update on a specific resolution rule.
 func (urs *UnificationRuleService) PatchUnificationRule(ruleId, orgHandle string, updatedRule model.UnificationRule) error {
 
+	return store.WithTransaction(func(tx *sql.Tx) error {
+		return urs.PatchUnificationRuleTx(tx, ruleId, orgHandle, updatedRule)
+	})
+}
+
+// PatchUnificationRuleTx Applies a partial update on a specific resolution rule within the given transaction.
+func (urs *UnificationRuleService) PatchUnificationRuleTx(tx *sql.Tx, ruleId, orgHandle string,
+	updatedRule model.UnificationRule) error {
+
 	if updatedRule.PropertyName == "user_id" {
 		return errors2.NewClientError(errors2.ErrorMessage{
 			Code:        errors2.UNIFICATION_RULE_ALREADY_EXISTS.Code,
@@ -158,7 +179,7 @@ func (urs *UnificationRuleService) PatchUnificationRule(ruleId, orgHandle string
 	}
 
 	// Validate that the priority is not already in use
-	existingRules, err := store.GetUnificationRules(orgHandle)
+	existingRules, err := store.GetUnificationRulesTx(tx, orgHandle)
 	if err != nil {
 		return err
 	}
@@ -171,7 +192,7 @@ func (urs *UnificationRuleService) PatchUnificationRule(ruleId, orgHandle string
 			}, http.StatusBadRequest)
 		}
 	}
-	return store.PatchUnificationRule(ruleId, updatedRule)
+	return store.PatchUnificationRuleTx(tx, ruleId, updatedRule)
 }
 
 // DeleteUnificationRule Removes a unification rule.
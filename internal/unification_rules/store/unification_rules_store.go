@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/wso2/identity-customer-data-service/internal/system/database/client"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/provider"
 	"github.com/wso2/identity-customer-data-service/internal/system/database/scripts"
 	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
@@ -30,6 +31,17 @@ import (
 	"github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
 )
 
+// queryExecutor runs queries either on a database client of their own or inside an open transaction.
+type queryExecutor interface {
+	ExecuteQuery(query string, args ...interface{}) ([]map[string]interface{}, error)
+}
+
+// WithTransaction runs fn in a single database transaction, so that the rule changes made through it are persisted
+// together or not at all.
+func WithTransaction(fn func(tx *sql.Tx) error) error {
+	return provider.WithTransaction(errors2.UPDATE_UNIFICATION_RULE, fn)
+}
+
 // AddUnificationRule adds a new unification rule to the database
 func AddUnificationRule(rule model.UnificationRule, orgId string) error {
 
@@ -47,9 +59,22 @@ func AddUnificationRule(rule model.UnificationRule, orgId string) error {
 	}
 	defer dbClient.Close()
 
+	return addUnificationRule(dbClient, rule, orgId)
+}
+
+// AddUnificationRuleTx adds a new unification rule within the given transaction.
+func AddUnificationRuleTx(tx *sql.Tx, rule model.UnificationRule, orgId string) error {
+
+	return addUnificationRule(client.NewTxClient(tx), rule, orgId)
+}
+
+func addUnificationRule(dbClient queryExecutor, rule model.UnificationRule, orgId string) error {
+
+	logger := log.GetLogger()
+
 	query := scripts.InsertUnificationRule[provider.NewDBProvider().GetDBType()]
 
-	_, err = dbClient.ExecuteQuery(query, rule.RuleId, orgId, rule.RuleName, rule.PropertyName, rule.PropertyId, rule.Priority, rule.IsActive,
+	_, err := dbClient.ExecuteQuery(query, rule.RuleId, orgId, rule.RuleName, rule.PropertyName, rule.PropertyId, rule.Priority, rule.IsActive,
 		rule.CreatedAt, rule.UpdatedAt)
 	if err != nil {
 		errorMsg := fmt.Sprintf("Error occurred while adding unification rule: %s", rule.RuleName)
@@ -83,6 +108,19 @@ func GetUnificationRules(orgHandle string) ([]model.UnificationRule, error) {
 	}
 	defer dbClient.Close()
 
+	return getUnificationRules(dbClient, orgHandle)
+}
+
+// GetUnificationRulesTx fetches the unification rules of an organization within the given transaction.
+func GetUnificationRulesTx(tx *sql.Tx, orgHandle string) ([]model.UnificationRule, error) {
+
+	return getUnificationRules(client.NewTxClient(tx), orgHandle)
+}
+
+func getUnificationRules(dbClient queryExecutor, orgHandle string) ([]model.UnificationRule, error) {
+
+	logger := log.GetLogger()
+
 	query := scripts.GetUnificationRules[provider.NewDBProvider().GetDBType()]
 	results, err := dbClient.ExecuteQuery(query, orgHandle)
 	if err != nil {
@@ -186,8 +224,21 @@ func PatchUnificationRule(ruleId string, updatedRule model.UnificationRule) erro
 	}
 	defer dbClient.Close()
 
+	return patchUnificationRule(dbClient, ruleId, updatedRule)
+}
+
+// PatchUnificationRuleTx applies partial updates to a unification rule within the given transaction.
+func PatchUnificationRuleTx(tx *sql.Tx, ruleId string, updatedRule model.UnificationRule) error {
+
+	return patchUnificationRule(client.NewTxClient(tx), ruleId, updatedRule)
+}
+
+func patchUnificationRule(dbClient queryExecutor, ruleId string, updatedRule model.UnificationRule) error {
+
+	logger := log.GetLogger()
+
 	query := scripts.UpdateUnificationRule[provider.NewDBProvider().GetDBType()]
-	_, err = dbClient.ExecuteQuery(query, updatedRule.RuleName, updatedRule.Priority, updatedRule.IsActive, time.Now().UTC(), ruleId)
+	_, err := dbClient.ExecuteQuery(query, updatedRule.RuleName, updatedRule.Priority, updatedRule.IsActive, time.Now().UTC(), ruleId)
 
 	if err != nil {
 		errorMsg := fmt.Sprintf("Error occurred while updating unification rule for rule_id: %s", ruleId)
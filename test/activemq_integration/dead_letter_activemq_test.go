@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package activemqintegration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/activemq"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+)
+
+// Test_ActiveMQ_DeadLetters verifies that a profile whose handler keeps failing is moved to the ActiveMQ
+// dead-letter queue, can be browsed there, and is processed again once replayed.
+func Test_ActiveMQ_DeadLetters(t *testing.T) {
+
+	cfg := config.GetCDSRuntime().Config
+	broker := cfg.MessageQueue.Broker
+	destination := fmt.Sprintf("/queue/cds-test-dlq-%d", time.Now().UnixNano())
+	policy := delivery.Policy{MaxAttempts: 2, InitialBackoff: 50 * time.Millisecond, MaxBackoff: 100 * time.Millisecond}
+
+	q, err := activemq.NewProfileQueue(broker.Addr, broker.Username, broker.Password, destination,
+		destination+".dlq", policy, 1, cfg.TLS)
+	require.NoError(t, err)
+	defer q.Close()
+
+	var mu sync.Mutex
+	healed := false
+	processed := make(chan string, 1)
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healed {
+			return errors.New("database unavailable")
+		}
+		processed <- profile.ProfileId
+		return nil
+	}))
+
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "activemq-dlq-org"}
+	require.NoError(t, q.Enqueue(profile))
+
+	var deadLetters []queue.DeadLetter
+	require.Eventually(t, func() bool {
+		deadLetters, err = q.DeadLetters()
+		return err == nil && len(deadLetters) == 1
+	}, 30*time.Second, 500*time.Millisecond, "profile should be dead-lettered")
+	require.Equal(t, profile.ProfileId, deadLetters[0].Profile.ProfileId)
+	require.Equal(t, profile.OrgHandle, deadLetters[0].Profile.OrgHandle)
+	require.Equal(t, 2, deadLetters[0].Attempts)
+	require.Equal(t, "database unavailable", deadLetters[0].Error)
+
+	mu.Lock()
+	healed = true
+	mu.Unlock()
+	require.NoError(t, q.ReplayDeadLetter(deadLetters[0].Id))
+
+	select {
+	case id := <-processed:
+		require.Equal(t, profile.ProfileId, id)
+	case <-time.After(30 * time.Second):
+		t.Fatal("replayed profile was not processed")
+	}
+	deadLetters, err = q.DeadLetters()
+	require.NoError(t, err)
+	require.Empty(t, deadLetters)
+
+	require.ErrorIs(t, q.ReplayDeadLetter(uuid.New().String()), queue.ErrDeadLetterNotFound)
+}
@@ -70,6 +70,7 @@ func TestMain(m *testing.M) {
 				Password:            amq.Password,
 				ProfileQueueName:    "/queue/cds-test-profile-unification",
 				SchemaSyncQueueName: "/queue/cds-test-schema-sync",
+				ManagementURL:       amq.ManagementURL,
 			},
 		},
 	}
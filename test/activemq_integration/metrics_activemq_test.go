@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package activemqintegration
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+)
+
+// Test_ActiveMQ_Queue_Depth verifies that the depth of an ActiveMQ queue is read from the broker's management API
+// when the metrics are scraped.
+func Test_ActiveMQ_Queue_Depth(t *testing.T) {
+
+	cfg := config.GetCDSRuntime().Config
+	cfg.MessageQueue.Broker.ProfileQueueName = fmt.Sprintf("/queue/cds-test-depth-%d", time.Now().UnixNano())
+	q, err := queue.NewProfileUnificationQueue(cfg)
+	require.NoError(t, err)
+	defer q.Close()
+
+	// The queue is not started, so the profiles wait in the broker.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String(),
+			OrgHandle: "depth-org"}))
+	}
+
+	series := regexp.MustCompile(`(?m)^cds_queue_depth\{provider="activemq",queue="profile_unification"\} (\S+)$`)
+	require.Eventually(t, func() bool {
+		var buf bytes.Buffer
+		if err := metrics.Write(&buf); err != nil {
+			return false
+		}
+		match := series.FindStringSubmatch(buf.String())
+		if match == nil {
+			return false
+		}
+		depth, err := strconv.ParseFloat(match[1], 64)
+		return err == nil && depth == 3
+	}, 30*time.Second, 500*time.Millisecond, "the depth should be read from the broker")
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+)
+
+func constrainedAttr(org, name, vType string, multiValued bool, c model.AttributeConstraints) model.ProfileSchemaAttribute {
+	attr := createAttr(org, name, vType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	attr.MultiValued = multiValued
+	attr.Constraints = &c
+	return attr
+}
+
+// constraintViolations returns the constraint of each reported violation, keyed by attribute.
+func constraintViolations(t *testing.T, err error) map[string]string {
+	var clientErr *errors2.ClientError
+	require.True(t, errors.As(err, &clientErr), "expected a client error, got %v", err)
+	require.Equal(t, errors2.PROFILE_CONSTRAINT_VIOLATION.Code, clientErr.Code)
+	violations := make(map[string]string, len(clientErr.Details))
+	for _, d := range clientErr.Details {
+		violations[d.Attribute] = d.Constraint
+	}
+	return violations
+}
+
+func Test_Attribute_Constraints(t *testing.T) {
+	org := fmt.Sprintf("constraint-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+	profileSvc := profileService.GetProfilesService()
+
+	zero, maxAge := 0.0, 150.0
+	three, two := 3, 2
+
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		constrainedAttr(org, "identity_attributes.email", constants.StringDataType, false,
+			model.AttributeConstraints{Required: true, Format: constants.ConstraintFormatEmail}),
+		constrainedAttr(org, "identity_attributes.phone", constants.StringDataType, false,
+			model.AttributeConstraints{Format: constants.ConstraintFormatE164}),
+	}, constants.IdentityAttributes, org)
+	require.NoError(t, err)
+
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		constrainedAttr(org, "traits.age", constants.IntegerDataType, false,
+			model.AttributeConstraints{Minimum: &zero, Maximum: &maxAge}),
+		constrainedAttr(org, "traits.nickname", constants.StringDataType, false,
+			model.AttributeConstraints{MinLength: &three, Pattern: "^[a-z]+$"}),
+		constrainedAttr(org, "traits.country", constants.StringDataType, false,
+			model.AttributeConstraints{Format: constants.ConstraintFormatISOCountry}),
+		constrainedAttr(org, "traits.website", constants.StringDataType, false,
+			model.AttributeConstraints{Format: constants.ConstraintFormatURI}),
+		constrainedAttr(org, "traits.signup_date", constants.DateDataType, false,
+			model.AttributeConstraints{MinDate: "2020-01-01"}),
+		constrainedAttr(org, "traits.tags", constants.StringDataType, true,
+			model.AttributeConstraints{MaxItems: &two}),
+	}, constants.Traits, org)
+	require.NoError(t, err)
+
+	t.Run("Valid_values_are_accepted", func(t *testing.T) {
+		_, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{
+			"identity_attributes": {"email": "ada@example.com", "phone": "+94771234567"},
+			"traits": {"age": 36, "nickname": "ada", "country": "LK", "website": "https://example.com",
+				"signup_date": "2024-05-01", "tags": ["math", "poetry"]}
+		}`), org)
+		require.NoError(t, err)
+	})
+
+	t.Run("All_violations_are_reported_per_attribute", func(t *testing.T) {
+		_, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{
+			"identity_attributes": {"phone": "0771234567"},
+			"traits": {"age": 200, "nickname": "ad", "country": "XX", "website": "example.com",
+				"signup_date": "2019-12-31", "tags": ["a", "b", "c"]}
+		}`), org)
+		require.Error(t, err)
+		assert.Equal(t, map[string]string{
+			"identity_attributes.email": "required",
+			"identity_attributes.phone": "format",
+			"traits.age":                "maximum",
+			"traits.nickname":           "min_length",
+			"traits.country":            "format",
+			"traits.website":            "format",
+			"traits.signup_date":        "min_date",
+			"traits.tags":               "max_items",
+		}, constraintViolations(t, err))
+	})
+
+	t.Run("Required_attributes_are_checked_whichever_scopes_are_written", func(t *testing.T) {
+		for _, payload := range []string{
+			`{"traits": {"age": 36}}`,
+			`{"identity_attributes": {}, "traits": {"age": 36}}`,
+			`{}`,
+		} {
+			_, err := profileSvc.CreateProfile(mustUnmarshalProfile(payload), org)
+			require.Error(t, err, payload)
+			assert.Equal(t, map[string]string{"identity_attributes.email": "required"}, constraintViolations(t, err),
+				payload)
+		}
+	})
+
+	t.Run("Patch_needs_the_required_attributes_in_the_resulting_profile", func(t *testing.T) {
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{"identity_attributes": {"email": "alan@example.com"}}`), org)
+		require.NoError(t, err)
+
+		_, err = profileSvc.PatchProfile(created.ProfileId, org, map[string]interface{}{
+			"traits": map[string]interface{}{"age": 41},
+		}, "")
+		require.NoError(t, err, "the stored email satisfies the required constraint of a patch without it")
+
+		// A full update replaces the profile, so it must carry the required email itself.
+		_, err = profileSvc.UpdateProfile(created.ProfileId, org, mustUnmarshalProfile(`{"traits": {"age": 42}}`))
+		require.Error(t, err)
+		assert.Equal(t, map[string]string{"identity_attributes.email": "required"}, constraintViolations(t, err))
+	})
+
+	t.Run("Patch_is_checked_against_the_resulting_profile", func(t *testing.T) {
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{"identity_attributes": {"email": "grace@example.com"}}`), org)
+		require.NoError(t, err)
+
+		_, err = profileSvc.PatchProfile(created.ProfileId, org, map[string]interface{}{
+			"traits": map[string]interface{}{"age": -1},
+		}, "")
+		require.Error(t, err)
+		assert.Equal(t, map[string]string{"traits.age": "minimum"}, constraintViolations(t, err))
+
+		_, err = profileSvc.PatchProfile(created.ProfileId, org, map[string]interface{}{
+			"traits": map[string]interface{}{"age": 40},
+		}, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("Inconsistent_constraints_are_rejected", func(t *testing.T) {
+		cases := map[string]model.ProfileSchemaAttribute{
+			"length on a number": constrainedAttr(org, "traits.score", constants.IntegerDataType, false,
+				model.AttributeConstraints{MinLength: &three}),
+			"minimum above maximum": constrainedAttr(org, "traits.score", constants.IntegerDataType, false,
+				model.AttributeConstraints{Minimum: &maxAge, Maximum: &zero}),
+			"invalid pattern": constrainedAttr(org, "traits.code", constants.StringDataType, false,
+				model.AttributeConstraints{Pattern: "([a-z"}),
+			"unknown format": constrainedAttr(org, "traits.code", constants.StringDataType, false,
+				model.AttributeConstraints{Format: "postcode"}),
+			"max_items on a single value": constrainedAttr(org, "traits.code", constants.StringDataType, false,
+				model.AttributeConstraints{MaxItems: &two}),
+		}
+		for name, attr := range cases {
+			_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{attr}, constants.Traits, org)
+			assert.Error(t, err, name)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+func computedAttr(org, name, vType, expr string) model.ProfileSchemaAttribute {
+	attr := createAttr(org, name, vType, constants.MergeStrategyOverwrite, constants.MutabilityComputed)
+	attr.Expression = expr
+	return attr
+}
+
+func Test_Computed_Attributes(t *testing.T) {
+	org := fmt.Sprintf("computed-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+	profileSvc := profileService.GetProfilesService()
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		createAttr(org, "identity_attributes.given_name", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite),
+		createAttr(org, "identity_attributes.family_name", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite),
+		createAttr(org, "identity_attributes.birthdate", constants.DateDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite),
+	}, constants.IdentityAttributes, org)
+	require.NoError(t, err)
+
+	for _, app := range []string{"shop_app", "pos_app"} {
+		orderTotal := createAttr(org, "application_data.order_total", constants.DecimalDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+		orderTotal.ApplicationIdentifier = app
+		_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{orderTotal}, constants.ApplicationData, org)
+		require.NoError(t, err)
+	}
+
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		computedAttr(org, "traits.full_name", constants.StringDataType,
+			"join(' ', identity_attributes.given_name, identity_attributes.family_name)"),
+		computedAttr(org, "traits.age", constants.IntegerDataType, "age(identity_attributes.birthdate)"),
+		computedAttr(org, "traits.lifetime_value", constants.DecimalDataType, "sum(application_data.*.order_total)"),
+		computedAttr(org, "traits.greeting", constants.StringDataType, "'Hello ' + traits.full_name"),
+		computedAttr(org, "traits.formal_name", constants.StringDataType,
+			"identity_attributes.family_name + ', ' + identity_attributes.given_name"),
+		computedAttr(org, "traits.sort_name", constants.StringDataType,
+			"concat(identity_attributes.family_name, ', ', identity_attributes.given_name)"),
+	}, constants.Traits, org)
+	require.NoError(t, err)
+
+	birthdate := time.Now().UTC().AddDate(-30, 0, -1).Format(time.DateOnly)
+
+	t.Run("Computed_on_create_and_recomputed_on_update", func(t *testing.T) {
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(fmt.Sprintf(`{
+			"identity_attributes": {"given_name": "Ada", "family_name": "Lovelace", "birthdate": "%s"},
+			"application_data": {"shop_app": {"order_total": 120.5}, "pos_app": {"order_total": 79.5}}
+		}`, birthdate)), org)
+		require.NoError(t, err)
+		assert.Equal(t, "Ada Lovelace", created.Traits["full_name"])
+		assert.EqualValues(t, 30, created.Traits["age"])
+		assert.EqualValues(t, 200, created.Traits["lifetime_value"])
+		assert.Equal(t, "Hello Ada Lovelace", created.Traits["greeting"], "computed attributes can read each other")
+
+		updated, err := profileSvc.PatchProfile(created.ProfileId, org, map[string]interface{}{
+			"identity_attributes": map[string]interface{}{"given_name": "Augusta"},
+		}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "Augusta Lovelace", updated.Traits["full_name"])
+		assert.Equal(t, "Hello Augusta Lovelace", updated.Traits["greeting"])
+	})
+
+	t.Run("Computed_attributes_are_read_only_on_input", func(t *testing.T) {
+		_, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{"traits": {"full_name": "Someone Else"}}`), org)
+		require.Error(t, err)
+
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{"identity_attributes": {"given_name": "Grace"}}`), org)
+		require.NoError(t, err)
+		_, err = profileSvc.PatchProfile(created.ProfileId, org, map[string]interface{}{
+			"traits": map[string]interface{}{"full_name": "Rear Admiral"},
+		}, "")
+		require.Error(t, err)
+	})
+
+	t.Run("Missing_inputs_leave_the_attribute_unset", func(t *testing.T) {
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{"identity_attributes": {"given_name": "Alan"}}`), org)
+		require.NoError(t, err)
+		assert.Equal(t, "Alan", created.Traits["full_name"])
+		assert.NotContains(t, created.Traits, "age")
+		assert.NotContains(t, created.Traits, "lifetime_value")
+		assert.Equal(t, "Alan", created.Traits["formal_name"], "literals joined only to missing values are dropped")
+	})
+
+	t.Run("Concatenation_of_only_missing_inputs_leaves_the_attribute_unset", func(t *testing.T) {
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(fmt.Sprintf(
+			`{"identity_attributes": {"birthdate": "%s"}}`, birthdate)), org)
+		require.NoError(t, err)
+		assert.EqualValues(t, 30, created.Traits["age"])
+		assert.NotContains(t, created.Traits, "formal_name", "the literals alone should not be stored")
+		assert.NotContains(t, created.Traits, "sort_name", "concat of only missing inputs should not be stored")
+		assert.NotContains(t, created.Traits, "greeting")
+	})
+
+	t.Run("Recomputed_on_merge", func(t *testing.T) {
+		rules, err := schemaSvc.GetProfileSchemaAttributesByScope(org, constants.Traits)
+		require.NoError(t, err)
+		identityRules, err := schemaSvc.GetProfileSchemaAttributesByScope(org, constants.IdentityAttributes)
+		require.NoError(t, err)
+		schemaRules := append(rules.([]model.ProfileSchemaAttribute), identityRules.([]model.ProfileSchemaAttribute)...)
+
+		existing := profileModel.Profile{
+			ProfileId:          "existing",
+			IdentityAttributes: map[string]interface{}{"given_name": "Ada"},
+			Traits:             map[string]interface{}{"full_name": "Ada"},
+		}
+		incoming := profileModel.Profile{
+			ProfileId:          "incoming",
+			OrgHandle:          org,
+			IdentityAttributes: map[string]interface{}{"family_name": "Lovelace"},
+		}
+		merged := workers.MergeProfiles(existing, incoming, schemaRules, nil)
+		assert.Equal(t, "Ada Lovelace", merged.Traits["full_name"])
+	})
+
+	t.Run("Invalid_definitions_are_rejected", func(t *testing.T) {
+		cases := map[string]model.ProfileSchemaAttribute{
+			"unknown reference":     computedAttr(org, "traits.nick", constants.StringDataType, "traits.unknown"),
+			"unqualified reference": computedAttr(org, "traits.nick", constants.StringDataType, "given_name"),
+			"syntax error":          computedAttr(org, "traits.nick", constants.StringDataType, "lower(identity_attributes.given_name"),
+			"unknown function":      computedAttr(org, "traits.nick", constants.StringDataType, "shout(identity_attributes.given_name)"),
+			"self reference":        computedAttr(org, "traits.nick", constants.StringDataType, "traits.nick + '!'"),
+			"missing expression":    computedAttr(org, "traits.nick", constants.StringDataType, ""),
+			"expression on a writable attribute": func() model.ProfileSchemaAttribute {
+				attr := createAttr(org, "traits.nick", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+				attr.Expression = "identity_attributes.given_name"
+				return attr
+			}(),
+		}
+		for name, attr := range cases {
+			_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{attr}, constants.Traits, org)
+			assert.Error(t, err, name)
+		}
+
+		_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+			computedAttr(org, "traits.ping", constants.StringDataType, "traits.pong"),
+			computedAttr(org, "traits.pong", constants.StringDataType, "traits.ping"),
+		}, constants.Traits, org)
+		assert.Error(t, err, "cyclic dependencies are rejected")
+	})
+
+	t.Run("Inputs_of_computed_attributes_cannot_be_deleted", func(t *testing.T) {
+		givenName, err := schemaSvc.GetProfileSchemaAttributeByName("identity_attributes.given_name", org)
+		require.NoError(t, err)
+		require.NotNil(t, givenName)
+		err = schemaSvc.DeleteProfileSchemaAttributeById(org, givenName.AttributeId)
+		assert.Error(t, err)
+	})
+}
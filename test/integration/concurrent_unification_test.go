@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
+	unificationService "github.com/wso2/identity-customer-data-service/internal/unification_rules/service"
+)
+
+// Test_Concurrent_Unification creates matching profiles in parallel, in two organizations, so that the unification
+// workers process them concurrently. Every profile of an organization must end up merged into one master.
+func Test_Concurrent_Unification(t *testing.T) {
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	const profilesPerOrg = 8
+	profileSvc := profileService.GetProfilesService()
+	orgs := []string{
+		fmt.Sprintf("concurrent-a-%d", time.Now().UnixNano()),
+		fmt.Sprintf("concurrent-b-%d", time.Now().UnixNano()),
+	}
+	for _, org := range orgs {
+		setupEmailUnification(t, org)
+	}
+
+	var mu sync.Mutex
+	created := map[string][]string{}
+	var wg sync.WaitGroup
+	for _, org := range orgs {
+		for i := 0; i < profilesPerOrg; i++ {
+			wg.Add(1)
+			go func(org string, i int) {
+				defer wg.Done()
+				request := mustUnmarshalProfile(fmt.Sprintf(
+					`{"identity_attributes":{"email":["shared@%s.com"]},"traits":{"interests":["interest-%d"]}}`, org, i))
+				profile, err := profileSvc.CreateProfile(request, org)
+				require.NoError(t, err)
+				mu.Lock()
+				created[org] = append(created[org], profile.ProfileId)
+				mu.Unlock()
+			}(org, i)
+		}
+	}
+	wg.Wait()
+
+	for _, org := range orgs {
+		var expectedInterests []interface{}
+		for i := 0; i < profilesPerOrg; i++ {
+			expectedInterests = append(expectedInterests, fmt.Sprintf("interest-%d", i))
+		}
+		require.Eventually(t, func() bool {
+			masters := map[string]bool{}
+			for _, profileId := range created[org] {
+				profile, err := profileSvc.GetProfile(profileId)
+				if err != nil || profile == nil || profile.MergedTo == nil {
+					return false
+				}
+				masters[profile.MergedTo.ProfileId] = true
+			}
+			return len(masters) == 1
+		}, 30*time.Second, 200*time.Millisecond, "all profiles of %s should be merged into one master", org)
+
+		merged, err := profileSvc.GetProfile(created[org][0])
+		require.NoError(t, err)
+		require.ElementsMatch(t, expectedInterests, merged.Traits["interests"].([]interface{}))
+		cleanProfiles(profileSvc, org)
+	}
+}
+
+func setupEmailUnification(t *testing.T, org string) {
+	t.Helper()
+	schemaSvc := schemaService.GetProfileSchemaService()
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]schemaModel.ProfileSchemaAttribute{
+		{OrgId: org, AttributeId: uuid.New().String(), AttributeName: "identity_attributes.email",
+			ValueType: constants.StringDataType, MergeStrategy: "combine", Mutability: constants.MutabilityReadWrite,
+			MultiValued: true},
+	}, constants.IdentityAttributes, org)
+	require.NoError(t, err)
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]schemaModel.ProfileSchemaAttribute{
+		{OrgId: org, AttributeId: uuid.New().String(), AttributeName: "traits.interests",
+			ValueType: constants.StringDataType, MergeStrategy: "combine", Mutability: constants.MutabilityReadWrite,
+			MultiValued: true},
+	}, constants.Traits, org)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	require.NoError(t, unificationService.GetUnificationRuleService().AddUnificationRule(model.UnificationRule{
+		RuleId:       uuid.New().String(),
+		RuleName:     "email_based",
+		OrgHandle:    org,
+		PropertyName: "identity_attributes.email",
+		Priority:     1,
+		IsActive:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, org))
+}
@@ -132,13 +132,13 @@ func Test_Consent(t *testing.T) {
 	})
 
 	t.Run("Get_all_consent_categories", func(t *testing.T) {
-		cats, err := svc.GetAllConsentCategories()
+		cats, err := svc.GetAllConsentCategories(org)
 		require.NoError(t, err)
 		assert.NotEmpty(t, cats)
 	})
 
 	t.Run("Get_single_category", func(t *testing.T) {
-		fetched, err := svc.GetConsentCategory(categoryId)
+		fetched, err := svc.GetConsentCategory(categoryId, org)
 		require.NoError(t, err)
 		require.NotNil(t, fetched)
 		assert.Equal(t, "Marketing", fetched.CategoryName)
@@ -187,12 +187,58 @@ func Test_Consent(t *testing.T) {
 		err := svc.UpdateConsentCategory(updated)
 		require.NoError(t, err)
 
-		fetched, err := svc.GetConsentCategory(categoryId)
+		fetched, err := svc.GetConsentCategory(categoryId, org)
 		require.NoError(t, err)
 		assert.Equal(t, "Marketing Updated", fetched.CategoryName)
 		assert.Len(t, fetched.Attributes, 1)
 	})
 
+	t.Run("Localizations_resolve_with_fallback", func(t *testing.T) {
+		localized := consentModel.ConsentCategory{
+			CategoryIdentifier: categoryId,
+			CategoryName:       "Marketing Updated",
+			OrgHandle:          org,
+			Purpose:            "personalization",
+			Attributes:         []consentModel.ConsentAttribute{{AttributeName: "traits.age"}},
+			Localizations: map[string]consentModel.ConsentCategoryLocalization{
+				"en":    {DisplayName: "Marketing", PurposeDescription: "Send you offers."},
+				"fr":    {DisplayName: "Marketing FR", PurposeDescription: "Vous envoyer des offres."},
+				"fr-CA": {DisplayName: "Publicité"},
+			},
+		}
+		require.NoError(t, svc.UpdateConsentCategory(localized))
+
+		fetched, err := svc.GetConsentCategory(categoryId, org)
+		require.NoError(t, err)
+		require.Len(t, fetched.Localizations, 3)
+
+		resp := fetched.ToLocalizedResponse("fr-ca", "en")
+		assert.Equal(t, "fr-CA", resp.Locale)
+		assert.Equal(t, "Publicité", resp.DisplayName)
+		assert.Nil(t, resp.Localizations)
+
+		resp = fetched.ToLocalizedResponse("fr-BE", "en")
+		assert.Equal(t, "fr", resp.Locale)
+		assert.Equal(t, "Vous envoyer des offres.", resp.PurposeDescription)
+
+		resp = fetched.ToLocalizedResponse("de-DE", "en")
+		assert.Equal(t, "en", resp.Locale)
+		assert.Equal(t, "Marketing", resp.DisplayName)
+	})
+
+	t.Run("Reject_invalid_localization_locale", func(t *testing.T) {
+		bad := consentModel.ConsentCategory{
+			CategoryIdentifier: categoryId,
+			CategoryName:       "Marketing Updated",
+			OrgHandle:          org,
+			Purpose:            "personalization",
+			Localizations: map[string]consentModel.ConsentCategoryLocalization{
+				"not a locale": {DisplayName: "x"},
+			},
+		}
+		assert.Error(t, svc.UpdateConsentCategory(bad))
+	})
+
 	t.Run("Reject_update_mandatory_category", func(t *testing.T) {
 		mandatoryIds, err := consentStore.GetMandatoryConsentCategoryIds(org)
 		require.NoError(t, err)
@@ -212,7 +258,7 @@ func Test_Consent(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, mandatoryIds)
 
-		err = svc.DeleteConsentCategory(mandatoryIds[0])
+		err = svc.DeleteConsentCategory(mandatoryIds[0], org)
 		assert.Error(t, err, "should reject deletion of mandatory category")
 	})
 
@@ -231,7 +277,7 @@ func Test_Consent(t *testing.T) {
 		assert.Len(t, created.Attributes, 1)
 		assert.Equal(t, testAppId, created.Attributes[0].ApplicationIdentifier)
 		// Cleanup
-		_ = svc.DeleteConsentCategory(created.CategoryIdentifier)
+		_ = svc.DeleteConsentCategory(created.CategoryIdentifier, org)
 	})
 
 	t.Run("Reject_applicationData_attribute_with_missing_application_identifier", func(t *testing.T) {
@@ -260,11 +306,38 @@ func Test_Consent(t *testing.T) {
 		assert.Error(t, err, "should reject application_identifier that does not match schema application_identifier")
 	})
 
+	t.Run("Categories_are_isolated_per_org", func(t *testing.T) {
+		otherOrg := fmt.Sprintf("consent-other-org-%d", time.Now().UnixNano())
+
+		cats, err := svc.GetAllConsentCategories(otherOrg)
+		require.NoError(t, err)
+		for _, c := range cats {
+			assert.NotEqual(t, categoryId, c.CategoryIdentifier, "another org must not list this category")
+		}
+
+		_, err = svc.GetConsentCategory(categoryId, otherOrg)
+		assert.Error(t, err, "another org must not fetch this category")
+
+		require.NoError(t, svc.DeleteConsentCategory(categoryId, otherOrg))
+		stillThere, err := svc.GetConsentCategory(categoryId, org)
+		require.NoError(t, err)
+		assert.NotNil(t, stillThere, "delete from another org must not affect this category")
+
+		// Category names are unique per org, not globally.
+		sameName, err := svc.AddConsentCategory(consentModel.ConsentCategory{
+			CategoryName: "Marketing",
+			OrgHandle:    otherOrg,
+			Purpose:      "personalization",
+		})
+		require.NoError(t, err)
+		_ = svc.DeleteConsentCategory(sameName.CategoryIdentifier, otherOrg)
+	})
+
 	t.Run("Delete_consent_category", func(t *testing.T) {
-		err := svc.DeleteConsentCategory(categoryId)
+		err := svc.DeleteConsentCategory(categoryId, org)
 		require.NoError(t, err)
 
-		deleted, _ := svc.GetConsentCategory(categoryId)
+		deleted, _ := svc.GetConsentCategory(categoryId, org)
 		assert.Nil(t, deleted, "category should be gone after deletion")
 	})
 }
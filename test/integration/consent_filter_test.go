@@ -27,6 +27,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appService "github.com/wso2/identity-customer-data-service/internal/application/service"
 	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
 	consentService "github.com/wso2/identity-customer-data-service/internal/consent/service"
 	consentStore "github.com/wso2/identity-customer-data-service/internal/consent/store"
@@ -169,6 +170,83 @@ func Test_ConsentFilter(t *testing.T) {
 		assert.Contains(t, filtered.Traits, "interests", "consented trait should appear in response")
 	})
 
+	t.Run("Consent_update_issues_retrievable_receipt", func(t *testing.T) {
+		consents := []profileModel.ConsentRecord{
+			{CategoryIdentifier: marketingCategoryId, IsConsented: true},
+		}
+		err := profileSvc.UpdateProfileConsents(profileId, org, consents)
+		require.NoError(t, err)
+		require.NotEmpty(t, consents[0].ReceiptId, "update should link the record to a receipt")
+
+		receipt, err := profileSvc.GetConsentReceipt(profileId, org, consents[0].ReceiptId)
+		require.NoError(t, err)
+		assert.Equal(t, profileModel.ConsentReceiptVersion, receipt.Version)
+		assert.Equal(t, consents[0].ReceiptId, receipt.ConsentReceiptID)
+		assert.Equal(t, "filter-user-001", receipt.PiiPrincipalID)
+		require.Len(t, receipt.Services, 1)
+		assert.Equal(t, "Marketing", receipt.Services[0].Service)
+		require.Len(t, receipt.Services[0].Purposes, 1)
+		assert.Equal(t, "personalization", receipt.Services[0].Purposes[0].Purpose)
+		assert.Contains(t, receipt.Services[0].Purposes[0].PiiCategory, "traits.interests")
+
+		stored, err := profileSvc.GetProfileConsents(profileId, org)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, consents[0].ReceiptId, stored[0].ReceiptId)
+
+		_, err = profileSvc.GetConsentReceipt(profileId, org, uuid.New().String())
+		assert.Error(t, err, "unknown receipt should not be found")
+		assert.False(t, receipt.Services[0].Purposes[0].Withdrawn)
+	})
+
+	t.Run("Consent_withdrawal_receipt_lists_the_withdrawn_purposes", func(t *testing.T) {
+		withdrawal := []profileModel.ConsentRecord{
+			{CategoryIdentifier: marketingCategoryId, IsConsented: false},
+		}
+		require.NoError(t, profileSvc.UpdateProfileConsents(profileId, org, withdrawal))
+
+		receipt, err := profileSvc.GetConsentReceipt(profileId, org, withdrawal[0].ReceiptId)
+		require.NoError(t, err)
+		require.Len(t, receipt.Services, 1)
+		assert.Equal(t, "Marketing", receipt.Services[0].Service)
+		require.Len(t, receipt.Services[0].Purposes, 1)
+		purpose := receipt.Services[0].Purposes[0]
+		assert.Equal(t, "personalization", purpose.Purpose)
+		assert.True(t, purpose.Withdrawn)
+		assert.Equal(t, "Withdrawn", purpose.Termination)
+		assert.Contains(t, purpose.PiiCategory, "traits.interests")
+
+		// Consent is given again for the tests that follow.
+		require.NoError(t, profileSvc.UpdateProfileConsents(profileId, org, []profileModel.ConsentRecord{
+			{CategoryIdentifier: marketingCategoryId, IsConsented: true},
+		}))
+	})
+
+	t.Run("Profiles_filtered_by_consent_state", func(t *testing.T) {
+		profileIds := func(filters ...string) []string {
+			profiles, _, err := profileSvc.GetAllProfilesWithFilterCursor(org, filters, 50, nil)
+			require.NoError(t, err)
+			ids := make([]string, 0, len(profiles))
+			for _, p := range profiles {
+				ids = append(ids, p.ProfileId)
+			}
+			return ids
+		}
+
+		assert.Contains(t, profileIds("consent."+marketingCategoryId+" eq true"), profileId)
+		assert.NotContains(t, profileIds("consent."+marketingCategoryId+" eq false"), profileId)
+		assert.Contains(t, profileIds("consent."+marketingCategoryId+".consented_at gt 2000-01-01"), profileId)
+		assert.NotContains(t, profileIds("consent."+marketingCategoryId+".consented_at lt 2000-01-01"), profileId)
+		assert.Contains(t, profileIds("consent."+marketingCategoryId+" eq true", "traits.interests eq reading"), profileId)
+		// Categories whose ids differ only in characters an alias cannot hold are joined separately.
+		assert.Contains(t, profileIds("consent.a-b eq false", "consent.a_b eq false"), profileId)
+
+		_, _, err := profileSvc.GetAllProfilesWithFilterCursor(org, []string{"consent." + marketingCategoryId + " eq yes"}, 50, nil)
+		assert.Error(t, err, "consent state only accepts true or false")
+		_, _, err = profileSvc.GetAllProfilesWithFilterCursor(org, []string{"consent." + marketingCategoryId + ".consented_at gt soon"}, 50, nil)
+		assert.Error(t, err, "consent date must be parseable")
+	})
+
 	t.Run("Multiple_consentCategoryIds_union_of_consented_only", func(t *testing.T) {
 		// Add a second optional category the profile has NOT consented to.
 		analyticsCat := consentModel.ConsentCategory{
@@ -197,6 +275,34 @@ func Test_ConsentFilter(t *testing.T) {
 		assert.Contains(t, filtered.Traits, "interests")
 	})
 
+	t.Run("Page_of_profiles_filtered_by_each_profile_consents", func(t *testing.T) {
+		var req profileModel.ProfileRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"user_id": "filter-user-002",
+			"identity_attributes": {"email": "john@example.com"},
+			"traits": {"interests": "cycling"}
+		}`), &req))
+		other, err := profileSvc.CreateProfile(req, org)
+		require.NoError(t, err)
+
+		consenting, err := profileSvc.GetProfile(profileId)
+		require.NoError(t, err)
+		unconsenting, err := profileSvc.GetProfile(other.ProfileId)
+		require.NoError(t, err)
+
+		filtered, err := profileService.FilterProfilesByConsent(
+			[]profileModel.ProfileResponse{*consenting, *unconsenting}, org, []string{marketingCategoryId})
+		require.NoError(t, err)
+		require.Len(t, filtered, 2)
+
+		assert.Equal(t, profileId, filtered[0].ProfileId)
+		assert.Contains(t, filtered[0].IdentityAttributes, "email")
+		assert.Contains(t, filtered[0].Traits, "interests", "the consenting profile keeps its trait")
+		assert.Equal(t, other.ProfileId, filtered[1].ProfileId)
+		assert.Contains(t, filtered[1].IdentityAttributes, "email", "mandatory fields are kept for every profile")
+		assert.Empty(t, filtered[1].Traits, "the other profile has not consented")
+	})
+
 	t.Run("Mandatory_category_cannot_be_consented_to_per_profile", func(t *testing.T) {
 		mandatoryIds, err := consentStore.GetMandatoryConsentCategoryIds(org)
 		require.NoError(t, err)
@@ -208,6 +314,53 @@ func Test_ConsentFilter(t *testing.T) {
 		assert.Error(t, err, "should not be able to modify consent for mandatory category")
 	})
 
+	t.Run("Declared_purposes_select_categories_automatically", func(t *testing.T) {
+		appSvc := appService.GetApplicationService()
+		appId := fmt.Sprintf("personalization-app-%d", time.Now().UnixNano())
+
+		purposes, err := appSvc.UpdateApplicationPurposes(appId, org, []string{"personalization"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"personalization"}, purposes)
+
+		consentIds, enforced, err := profileService.ResolvePurposeConsentIds(org, appId, nil)
+		require.NoError(t, err)
+		assert.True(t, enforced)
+		assert.Equal(t, []string{marketingCategoryId}, consentIds,
+			"only the personalization category should be selected")
+
+		profile, err := profileSvc.GetProfile(profileId)
+		require.NoError(t, err)
+		filtered, err := profileService.FilterProfileByConsent(*profile, profileId, org, consentIds)
+		require.NoError(t, err)
+		assert.Contains(t, filtered.IdentityAttributes, "email")
+		assert.Contains(t, filtered.Traits, "interests", "consented personalization trait should appear")
+	})
+
+	t.Run("Requested_category_outside_declared_purposes_is_dropped", func(t *testing.T) {
+		appSvc := appService.GetApplicationService()
+		appId := fmt.Sprintf("profiling-app-%d", time.Now().UnixNano())
+
+		_, err := appSvc.UpdateApplicationPurposes(appId, org, []string{"profiling"})
+		require.NoError(t, err)
+
+		consentIds, enforced, err := profileService.ResolvePurposeConsentIds(org, appId, []string{marketingCategoryId})
+		require.NoError(t, err)
+		assert.True(t, enforced)
+		assert.Empty(t, consentIds, "personalization category must not be reachable for a profiling app")
+	})
+
+	t.Run("Undeclared_application_keeps_requested_categories", func(t *testing.T) {
+		consentIds, enforced, err := profileService.ResolvePurposeConsentIds(org, "undeclared-app", []string{marketingCategoryId})
+		require.NoError(t, err)
+		assert.False(t, enforced)
+		assert.Equal(t, []string{marketingCategoryId}, consentIds)
+	})
+
+	t.Run("Invalid_purpose_is_rejected", func(t *testing.T) {
+		_, err := appService.GetApplicationService().UpdateApplicationPurposes("some-app", org, []string{"advertising"})
+		assert.Error(t, err)
+	})
+
 	t.Cleanup(func() {
 		_ = profileSvc.DeleteProfile(profileId)
 	})
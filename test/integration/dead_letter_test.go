@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/inmemory"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+// failingHandler fails every profile until it is healed, and records the attempts and the profiles it
+// processed successfully.
+type failingHandler struct {
+	mu        sync.Mutex
+	healed    bool
+	attempts  map[string]int
+	processed chan string
+}
+
+func newFailingHandler() *failingHandler {
+	return &failingHandler{attempts: map[string]int{}, processed: make(chan string, 10)}
+}
+
+func (h *failingHandler) handle(profile profileModel.UnificationMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.attempts[profile.ProfileId]++
+	if !h.healed {
+		return errors.New("database unavailable")
+	}
+	h.processed <- profile.ProfileId
+	return nil
+}
+
+func (h *failingHandler) heal() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.healed = true
+}
+
+func (h *failingHandler) attemptsOf(profileId string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.attempts[profileId]
+}
+
+func Test_InMemoryQueue_DeadLetters(t *testing.T) {
+
+	policy := delivery.Policy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+
+	t.Run("Failing_profile_is_retried_then_dead_lettered_and_replayed", func(t *testing.T) {
+		q := inmemory.NewProfileQueue(10, policy, 1)
+		defer q.Close()
+		handler := newFailingHandler()
+		require.NoError(t, q.Start(handler.handle))
+
+		profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "dlq-org"}
+		require.NoError(t, q.Enqueue(profile))
+
+		var deadLetters []queue.DeadLetter
+		require.Eventually(t, func() bool {
+			deadLetters, _ = q.DeadLetters()
+			return len(deadLetters) == 1
+		}, 5*time.Second, 20*time.Millisecond)
+		require.Equal(t, profile.ProfileId, deadLetters[0].Profile.ProfileId)
+		require.Equal(t, 3, deadLetters[0].Attempts)
+		require.Equal(t, "database unavailable", deadLetters[0].Error)
+		require.Equal(t, 3, handler.attemptsOf(profile.ProfileId))
+
+		handler.heal()
+		require.NoError(t, q.ReplayDeadLetter(deadLetters[0].Id))
+		select {
+		case id := <-handler.processed:
+			require.Equal(t, profile.ProfileId, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("replayed profile was not processed")
+		}
+		deadLetters, err := q.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+	})
+
+	t.Run("Profile_succeeding_on_retry_is_not_dead_lettered", func(t *testing.T) {
+		q := inmemory.NewProfileQueue(10, policy, 1)
+		defer q.Close()
+		var mu sync.Mutex
+		calls := 0
+		processed := make(chan struct{}, 1)
+		require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			if calls == 1 {
+				return errors.New("transient failure")
+			}
+			processed <- struct{}{}
+			return nil
+		}))
+
+		require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String()}))
+		select {
+		case <-processed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("profile was not redelivered")
+		}
+		deadLetters, err := q.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+	})
+
+	t.Run("Replaying_unknown_dead_letter_fails", func(t *testing.T) {
+		q := inmemory.NewProfileQueue(10, policy, 1)
+		defer q.Close()
+		require.ErrorIs(t, q.ReplayDeadLetter(uuid.New().String()), queue.ErrDeadLetterNotFound)
+	})
+
+	t.Run("Worker_replay_of_unknown_dead_letter_is_not_found", func(t *testing.T) {
+		err := workers.ReplayDeadLetteredProfile("dlq-org", uuid.New().String())
+		var clientErr *errors2.ClientError
+		require.ErrorAs(t, err, &clientErr)
+		require.Equal(t, http.StatusNotFound, clientErr.StatusCode)
+		require.Equal(t, errors2.DEAD_LETTER_NOT_FOUND.Code, clientErr.ErrorMessage.Code)
+	})
+
+	t.Run("Worker_lists_no_dead_letters_for_healthy_org", func(t *testing.T) {
+		deadLetters, err := workers.GetDeadLetteredProfiles("dlq-org")
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+	})
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	healthService "github.com/wso2/identity-customer-data-service/internal/health_check/service"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaStore "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	"github.com/wso2/identity-customer-data-service/internal/system/lifecycle"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/inmemory"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+func Test_Graceful_Drain(t *testing.T) {
+
+	t.Run("Drain_processes_the_pending_profiles_within_the_deadline", func(t *testing.T) {
+		policy := delivery.Policy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+		q := inmemory.NewProfileQueue(10, policy, 2)
+		var mu sync.Mutex
+		processed := map[string]bool{}
+		require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			processed[profile.ProfileId] = true
+			return nil
+		}))
+
+		var enqueued []string
+		for i := 0; i < 5; i++ {
+			profileId := uuid.New().String()
+			require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: profileId, OrgHandle: "drain-org"}))
+			enqueued = append(enqueued, profileId)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.Empty(t, q.Drain(ctx))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, profileId := range enqueued {
+			require.True(t, processed[profileId], "profile %s should be processed by the drain", profileId)
+		}
+		require.Error(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String()}),
+			"a drained queue should not accept profiles")
+	})
+
+	t.Run("Drain_returns_the_profiles_left_at_the_deadline", func(t *testing.T) {
+		// The first profile keeps failing with a long backoff, holding the only worker.
+		policy := delivery.Policy{MaxAttempts: 5, InitialBackoff: time.Minute, MaxBackoff: time.Minute}
+		q := inmemory.NewProfileQueue(10, policy, 1)
+		attempted := make(chan struct{}, 1)
+		require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+			select {
+			case attempted <- struct{}{}:
+			default:
+			}
+			return errors.New("database unavailable")
+		}))
+
+		var enqueued []profileModel.UnificationMessage
+		for i := 0; i < 3; i++ {
+			message := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "drain-org"}
+			require.NoError(t, q.Enqueue(message))
+			enqueued = append(enqueued, message)
+		}
+		<-attempted
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		unprocessed := q.Drain(ctx)
+		require.Less(t, time.Since(start), 5*time.Second, "the drain should end at its deadline")
+		require.ElementsMatch(t, enqueued, unprocessed)
+		deadLetters, err := q.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters, "profiles cut off by the drain are not failures")
+	})
+
+	t.Run("Drain_does_not_wait_for_a_handler_past_the_deadline", func(t *testing.T) {
+		// The handler of the first profile never returns, holding the only worker.
+		policy := delivery.Policy{MaxAttempts: 1}
+		q := inmemory.NewProfileQueue(10, policy, 1)
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{}, 1)
+		require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		}))
+
+		var enqueued []profileModel.UnificationMessage
+		for i := 0; i < 3; i++ {
+			message := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "drain-org"}
+			require.NoError(t, q.Enqueue(message))
+			enqueued = append(enqueued, message)
+		}
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		unprocessed := q.Drain(ctx)
+		require.Less(t, time.Since(start), 5*time.Second, "the drain should end at its deadline")
+		require.ElementsMatch(t, enqueued, unprocessed, "the profile in progress should be returned too")
+	})
+
+	t.Run("Drain_returns_the_schema_sync_job_in_progress_at_the_deadline", func(t *testing.T) {
+		q := inmemory.NewSchemaSyncQueue(10)
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{}, 1)
+		require.NoError(t, q.Start(func(schemaModel.ProfileSchemaSync) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+		}))
+		jobs := []schemaModel.ProfileSchemaSync{
+			{OrgId: "drain-org", Event: "POST_ADD_LOCAL_CLAIM"},
+			{OrgId: "drain-org", Event: "POST_DELETE_LOCAL_CLAIM"},
+		}
+		for _, job := range jobs {
+			require.NoError(t, q.Enqueue(job))
+		}
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		unprocessed := q.Drain(ctx)
+		require.Less(t, time.Since(start), 5*time.Second, "the drain should end at its deadline")
+		require.ElementsMatch(t, jobs, unprocessed)
+	})
+
+	t.Run("Drain_returns_the_schema_sync_jobs_left", func(t *testing.T) {
+		q := inmemory.NewSchemaSyncQueue(10)
+		jobs := []schemaModel.ProfileSchemaSync{
+			{OrgId: "drain-org", Event: "POST_ADD_LOCAL_CLAIM"},
+			{OrgId: "drain-org", Event: "POST_DELETE_LOCAL_CLAIM"},
+		}
+		for _, job := range jobs {
+			require.NoError(t, q.Enqueue(job))
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.Equal(t, jobs, q.Drain(ctx))
+	})
+
+	t.Run("Profiles_left_by_a_drain_return_to_the_outbox", func(t *testing.T) {
+		require.NoError(t, workers.StopProfileWorker())
+		running := false
+		defer func() {
+			if !running {
+				require.NoError(t, workers.StartProfileWorker())
+			}
+		}()
+
+		profileId := uuid.New().String()
+		require.NoError(t, profileStore.RequeueUnificationMessages([]profileModel.UnificationMessage{
+			{ProfileId: profileId, OrgHandle: "drain-org"},
+		}))
+		require.Equal(t, 1, outboxEntriesOf(t, profileId))
+
+		// The next start publishes it again.
+		require.NoError(t, workers.StartProfileWorker())
+		running = true
+		require.Eventually(t, func() bool {
+			return outboxEntriesOf(t, profileId) == 0
+		}, 30*time.Second, 200*time.Millisecond)
+	})
+
+	t.Run("Schema_sync_jobs_left_by_a_drain_are_persisted_in_order", func(t *testing.T) {
+		jobs := []schemaModel.ProfileSchemaSync{
+			{OrgId: "drain-org", Event: "POST_ADD_LOCAL_CLAIM", Claim: &schemaModel.SchemaSyncClaim{ClaimURI: "http://wso2.org/claims/nickname"}},
+			{OrgId: "drain-org", Event: "POST_DELETE_LOCAL_CLAIM"},
+		}
+		require.NoError(t, schemaStore.AddToSchemaSyncBacklog(jobs))
+
+		backlog, err := schemaStore.TakeSchemaSyncBacklog()
+		require.NoError(t, err)
+		require.Equal(t, jobs, backlog)
+		backlog, err = schemaStore.TakeSchemaSyncBacklog()
+		require.NoError(t, err)
+		require.Empty(t, backlog, "taking the backlog empties it")
+	})
+
+	t.Run("Draining_server_rejects_writes_and_is_not_ready", func(t *testing.T) {
+		handler := lifecycle.RejectWritesWhileDraining(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		serve := func(method string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(method, "/cds/api/v1/profiles", nil))
+			return rec
+		}
+
+		require.Equal(t, http.StatusOK, serve(http.MethodPost).Code)
+		require.NoError(t, healthService.GetHealthCheckService().CheckReadiness())
+
+		restore := lifecycle.BeginDrainForTest()
+		defer restore()
+
+		rec := serve(http.MethodPost)
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		require.NotEmpty(t, rec.Header().Get("Retry-After"))
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Equal(t, errors2.SERVER_SHUTTING_DOWN.Code, body["code"])
+		require.Equal(t, http.StatusServiceUnavailable, serve(http.MethodDelete).Code)
+		require.Equal(t, http.StatusOK, serve(http.MethodGet).Code, "reads are served while draining")
+		require.Error(t, healthService.GetHealthCheckService().CheckReadiness())
+	})
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/identity-customer-data-service/internal/job/model"
+	jobService "github.com/wso2/identity-customer-data-service/internal/job/service"
+	jobStore "github.com/wso2/identity-customer-data-service/internal/job/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+)
+
+func Test_Jobs(t *testing.T) {
+
+	svc := jobService.GetJobService()
+	orgHandle := "job-org-" + uuid.New().String()
+
+	t.Run("Completed_job_records_its_progress_and_result", func(t *testing.T) {
+		job, err := svc.CreateJob(constants.JobTypeSchemaSync, orgHandle)
+		require.NoError(t, err)
+		require.Equal(t, constants.JobPending, job.Status)
+
+		err = svc.RunJob(job.JobId, func(progress func(percent int)) (interface{}, error) {
+			progress(50)
+			running, err := svc.GetJob(orgHandle, job.JobId)
+			require.NoError(t, err)
+			require.Equal(t, constants.JobRunning, running.Status)
+			require.Equal(t, 50, running.Progress)
+			require.NotNil(t, running.StartedAt)
+			return map[string]int{"synced": 3}, nil
+		})
+		require.NoError(t, err)
+
+		done, err := svc.GetJob(orgHandle, job.JobId)
+		require.NoError(t, err)
+		require.Equal(t, constants.JobCompleted, done.Status)
+		require.Equal(t, 100, done.Progress)
+		require.JSONEq(t, `{"synced": 3}`, string(done.Result))
+		require.NotNil(t, done.FinishedAt)
+		require.Empty(t, done.Error)
+	})
+
+	t.Run("Failed_job_records_its_error", func(t *testing.T) {
+		job, err := svc.CreateJob(constants.JobTypeSchemaSync, orgHandle)
+		require.NoError(t, err)
+
+		runErr := errors.New("identity server unreachable")
+		err = svc.RunJob(job.JobId, func(progress func(percent int)) (interface{}, error) {
+			progress(30)
+			return nil, runErr
+		})
+		require.ErrorIs(t, err, runErr)
+
+		failed, err := svc.GetJob(orgHandle, job.JobId)
+		require.NoError(t, err)
+		require.Equal(t, constants.JobFailed, failed.Status)
+		require.Equal(t, 30, failed.Progress)
+		require.Equal(t, runErr.Error(), failed.Error)
+		require.Empty(t, failed.Result)
+	})
+
+	t.Run("Jobs_are_listed_newest_first_and_filtered", func(t *testing.T) {
+		jobs, err := svc.ListJobs(orgHandle, model.JobFilter{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		require.Equal(t, constants.JobFailed, jobs[0].Status)
+		require.Equal(t, constants.JobCompleted, jobs[1].Status)
+
+		failed, err := svc.ListJobs(orgHandle, model.JobFilter{Status: constants.JobFailed})
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+
+		limited, err := svc.ListJobs(orgHandle, model.JobFilter{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, limited, 1)
+
+		other, err := svc.ListJobs(orgHandle, model.JobFilter{Type: constants.JobTypeCookieCleanup})
+		require.NoError(t, err)
+		require.Empty(t, other)
+
+		_, err = svc.ListJobs(orgHandle, model.JobFilter{Status: "stuck"})
+		var clientErr *errors2.ClientError
+		require.ErrorAs(t, err, &clientErr)
+		require.Equal(t, http.StatusBadRequest, clientErr.StatusCode)
+		require.Equal(t, errors2.INVALID_JOB_FILTER.Code, clientErr.ErrorMessage.Code)
+	})
+
+	t.Run("Job_of_another_org_is_not_found", func(t *testing.T) {
+		jobs, err := svc.ListJobs(orgHandle, model.JobFilter{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+
+		for _, jobId := range []string{jobs[0].JobId, uuid.New().String()} {
+			_, err = svc.GetJob("other-"+orgHandle, jobId)
+			var clientErr *errors2.ClientError
+			require.ErrorAs(t, err, &clientErr)
+			require.Equal(t, http.StatusNotFound, clientErr.StatusCode)
+			require.Equal(t, errors2.JOB_NOT_FOUND.Code, clientErr.ErrorMessage.Code)
+		}
+	})
+
+	t.Run("Job_that_could_not_run_is_failed", func(t *testing.T) {
+		otherOrg := "job-org-" + uuid.New().String()
+		job, err := svc.CreateJob(constants.JobTypeSchemaSync, otherOrg)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.FailJob(job.JobId, "The schema sync could not be enqueued."))
+		failed, err := svc.GetJob(otherOrg, job.JobId)
+		require.NoError(t, err)
+		require.Equal(t, constants.JobFailed, failed.Status)
+		require.Equal(t, "The schema sync could not be enqueued.", failed.Error)
+		require.NotNil(t, failed.FinishedAt)
+		require.Nil(t, failed.StartedAt, "the job never ran")
+	})
+
+	t.Run("Periodic_job_running_longer_than_its_interval_is_not_failed", func(t *testing.T) {
+		restore := jobService.OverrideHeartbeatForTest(100*time.Millisecond, 500*time.Millisecond)
+		defer restore()
+		jobType := "test_periodic_" + uuid.New().String()
+
+		// The first run outlasts the interval and the stale timeout without reporting progress.
+		release := make(chan struct{})
+		finished := make(chan error, 1)
+		go func() {
+			finished <- svc.RunPeriodicJob(jobType, time.Second, func(progress func(percent int)) (interface{}, error) {
+				<-release
+				return nil, nil
+			})
+		}()
+		time.Sleep(1100 * time.Millisecond)
+		require.NoError(t, svc.RunPeriodicJob(jobType, time.Second, func(progress func(percent int)) (interface{}, error) {
+			return nil, nil
+		}))
+
+		running, err := svc.ListJobs(constants.DefaultTenant, model.JobFilter{Type: jobType, Status: constants.JobRunning})
+		require.NoError(t, err)
+		require.Len(t, running, 1, "the first run records its heartbeat, so it is not failed as stale")
+		close(release)
+		require.NoError(t, <-finished)
+		completed, err := svc.ListJobs(constants.DefaultTenant, model.JobFilter{Type: jobType, Status: constants.JobCompleted})
+		require.NoError(t, err)
+		require.Len(t, completed, 2)
+	})
+
+	t.Run("Periodic_job_left_by_a_stopped_instance_is_failed", func(t *testing.T) {
+		restore := jobService.OverrideHeartbeatForTest(100*time.Millisecond, 500*time.Millisecond)
+		defer restore()
+		jobType := "test_periodic_" + uuid.New().String()
+
+		// A run whose instance stopped records no heartbeat after it started.
+		stale, err := svc.CreateJob(jobType, constants.DefaultTenant)
+		require.NoError(t, err)
+		require.NoError(t, jobStore.StartJob(stale.JobId, time.Now().UTC().Add(-time.Second)))
+
+		require.NoError(t, svc.RunPeriodicJob(jobType, time.Hour, func(progress func(percent int)) (interface{}, error) {
+			return nil, nil
+		}))
+		failed, err := svc.GetJob(constants.DefaultTenant, stale.JobId)
+		require.NoError(t, err)
+		require.Equal(t, constants.JobFailed, failed.Status)
+	})
+
+	t.Run("Periodic_job_runs_once_per_interval", func(t *testing.T) {
+		// A job type of its own keeps the schedule of this run apart from earlier runs against the same database.
+		jobType := "test_periodic_" + uuid.New().String()
+		runs := 0
+		run := func(progress func(percent int)) (interface{}, error) {
+			runs++
+			return nil, nil
+		}
+
+		require.NoError(t, svc.RunPeriodicJob(jobType, time.Hour, run))
+		require.Equal(t, 1, runs)
+		require.NoError(t, svc.RunPeriodicJob(jobType, time.Hour, run))
+		require.Equal(t, 1, runs, "a run claimed within the interval should not be repeated")
+
+		jobs, err := svc.ListJobs(constants.DefaultTenant, model.JobFilter{Type: jobType})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, constants.JobCompleted, jobs[0].Status)
+	})
+
+	t.Run("Periodic_job_runs_again_once_due", func(t *testing.T) {
+		jobType := "test_periodic_" + uuid.New().String()
+		runs := 0
+		run := func(progress func(percent int)) (interface{}, error) {
+			runs++
+			return nil, nil
+		}
+
+		require.NoError(t, svc.RunPeriodicJob(jobType, time.Second, run))
+		time.Sleep(1100 * time.Millisecond)
+		require.NoError(t, svc.RunPeriodicJob(jobType, time.Second, run))
+		require.Equal(t, 2, runs)
+	})
+}
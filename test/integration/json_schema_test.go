@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// schemaNode walks a rendered JSON Schema document along the given property names.
+func schemaNode(t *testing.T, schema map[string]interface{}, path ...string) map[string]interface{} {
+	node := schema
+	for _, name := range path {
+		properties, ok := node["properties"].(map[string]interface{})
+		require.True(t, ok, "no properties above %s", name)
+		node, ok = properties[name].(map[string]interface{})
+		require.True(t, ok, "property %s not found", name)
+	}
+	return node
+}
+
+func Test_Profile_Schema_As_JSON_Schema(t *testing.T) {
+	org := fmt.Sprintf("json-schema-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	email := createAttr(org, "identity_attributes.email", constants.StringDataType, constants.MergeStrategyOverwrite,
+		constants.MutabilityReadWrite)
+	email.Constraints = &model.AttributeConstraints{Required: true, Format: constants.ConstraintFormatEmail}
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{email},
+		constants.IdentityAttributes, org)
+	require.NoError(t, err)
+
+	tags := createAttr(org, "traits.tags", constants.StringDataType, constants.MergeStrategyCombine,
+		constants.MutabilityReadWrite)
+	tags.MultiValued = true
+	tags.CanonicalValues = []model.CanonicalValue{{Value: "vip", Label: "VIP"}, {Value: "new", Label: "New"}}
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		tags,
+		createAttr(org, "traits.pin", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityWriteOnly),
+		complexAttr(org, "traits.address", constants.MergeStrategyCombine,
+			createAttr(org, "traits.address.city", constants.StringDataType, constants.MergeStrategyOverwrite,
+				constants.MutabilityReadWrite),
+			createAttr(org, "traits.address.moved_at", constants.DateTimeDataType, constants.MergeStrategyOverwrite,
+				constants.MutabilityReadWrite)),
+	}, constants.Traits, org)
+	require.NoError(t, err)
+
+	visits := createAttr(org, "application_data.visits", constants.IntegerDataType, constants.MergeStrategyOverwrite,
+		constants.MutabilityReadWrite)
+	visits.ApplicationIdentifier = "shop_app"
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{visits},
+		constants.ApplicationData, org)
+	require.NoError(t, err)
+
+	schema, err := schemaSvc.GetProfileSchemaAsJSONSchema(org)
+	require.NoError(t, err)
+
+	t.Run("Document_uses_the_2020_12_dialect", func(t *testing.T) {
+		assert.Equal(t, constants.JSONSchemaDialect, schema["$schema"])
+		assert.Equal(t, "object", schema["type"])
+		assert.Equal(t, true, schemaNode(t, schema, "meta")["readOnly"])
+		assert.Equal(t, "date-time", schemaNode(t, schema, "meta", "created_at")["format"])
+	})
+
+	t.Run("Constraints_become_keywords", func(t *testing.T) {
+		identity := schemaNode(t, schema, constants.IdentityAttributes)
+		assert.Equal(t, []string{"email"}, identity["required"])
+		assert.Equal(t, false, identity["additionalProperties"])
+		assert.Equal(t, "email", schemaNode(t, schema, constants.IdentityAttributes, "email")["format"])
+	})
+
+	t.Run("Multi_valued_canonical_attributes_are_arrays_of_enums", func(t *testing.T) {
+		property := schemaNode(t, schema, constants.Traits, "tags")
+		assert.Equal(t, "array", property["type"])
+		items := property["items"].(map[string]interface{})
+		assert.Equal(t, "string", items["type"])
+		assert.Equal(t, []string{"vip", "new"}, items["enum"])
+	})
+
+	t.Run("Mutability_maps_to_write_only", func(t *testing.T) {
+		assert.Equal(t, true, schemaNode(t, schema, constants.Traits, "pin")["writeOnly"])
+		assert.NotContains(t, schemaNode(t, schema, constants.Traits, "tags"), "readOnly")
+	})
+
+	t.Run("Complex_attributes_are_nested_objects", func(t *testing.T) {
+		address := schemaNode(t, schema, constants.Traits, "address")
+		assert.Equal(t, "object", address["type"])
+		assert.Equal(t, "string", schemaNode(t, address, "city")["type"])
+		assert.Equal(t, "date-time", schemaNode(t, address, "moved_at")["format"])
+		assert.NotContains(t, schemaNode(t, schema, constants.Traits)["properties"], "address.city")
+	})
+
+	t.Run("Application_data_is_keyed_by_application", func(t *testing.T) {
+		assert.Equal(t, "integer", schemaNode(t, schema, constants.ApplicationData, "shop_app", "visits")["type"])
+	})
+}
@@ -44,6 +44,10 @@ func TestMain(m *testing.M) {
 		DataSource: config.DataSourceConfig{
 			Type: "postgres",
 		},
+		// Several workers, so that unification runs concurrently as in production.
+		MessageQueue: config.MessageQueueConfig{
+			Workers: 4,
+		},
 	}
 	config.OverrideCDSRuntime(conf)
 	_ = log.Init("DEBUG")
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+func Test_Timestamp_Aware_Merge_Strategies(t *testing.T) {
+	org := fmt.Sprintf("merge-strategy-org-%d", time.Now().UnixNano())
+	older := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	rules := []schemaModel.ProfileSchemaAttribute{
+		{AttributeName: "traits.city", ValueType: constants.StringDataType, MergeStrategy: constants.MergeStrategyLatest},
+		{AttributeName: "traits.first_seen", ValueType: constants.StringDataType, MergeStrategy: constants.MergeStrategyOldest},
+		{AttributeName: "identity_attributes.phone", ValueType: constants.StringDataType, MergeStrategy: constants.MergeStrategySourcePriority},
+		{AttributeName: "application_data.tier", ValueType: constants.StringDataType, MergeStrategy: constants.MergeStrategyLatest,
+			ApplicationIdentifier: "crm_app"},
+	}
+
+	existing := profileModel.Profile{
+		ProfileId:          "existing",
+		Traits:             map[string]interface{}{"city": "Colombo", "first_seen": "2025-01-01"},
+		IdentityAttributes: map[string]interface{}{"phone": "+94770000001"},
+		ApplicationData: []profileModel.ApplicationData{
+			{AppId: "crm_app", AppSpecificData: map[string]interface{}{"tier": "gold"}},
+		},
+		AttributeMetadata: map[string]profileModel.AttributeMetadata{
+			"traits.city":                   {UpdatedAt: older, SourceApplication: "web_app"},
+			"traits.first_seen":             {UpdatedAt: older, SourceApplication: "web_app"},
+			"identity_attributes.phone":     {UpdatedAt: older, SourceApplication: "crm_app"},
+			"application_data.crm_app.tier": {UpdatedAt: newer, SourceApplication: "crm_app"},
+		},
+	}
+	incoming := profileModel.Profile{
+		ProfileId:          "incoming",
+		OrgHandle:          org,
+		Traits:             map[string]interface{}{"city": "Kandy", "first_seen": "2025-06-01"},
+		IdentityAttributes: map[string]interface{}{"phone": "+94770000002"},
+		ApplicationData: []profileModel.ApplicationData{
+			{AppId: "crm_app", AppSpecificData: map[string]interface{}{"tier": "silver"}},
+		},
+		AttributeMetadata: map[string]profileModel.AttributeMetadata{
+			"traits.city":                   {UpdatedAt: newer, SourceApplication: "web_app"},
+			"traits.first_seen":             {UpdatedAt: newer, SourceApplication: "web_app"},
+			"identity_attributes.phone":     {UpdatedAt: newer, SourceApplication: "web_app"},
+			"application_data.crm_app.tier": {UpdatedAt: older, SourceApplication: "crm_app"},
+		},
+	}
+
+	t.Run("Latest_and_oldest_follow_write_time", func(t *testing.T) {
+		merged := workers.MergeProfiles(existing, incoming, rules, nil)
+		assert.Equal(t, "Kandy", merged.Traits["city"])
+		assert.Equal(t, "2025-01-01", merged.Traits["first_seen"])
+		require.Len(t, merged.ApplicationData, 1)
+		assert.Equal(t, "gold", merged.ApplicationData[0].AppSpecificData["tier"], "the newer existing app value wins")
+		assert.Equal(t, newer, merged.AttributeMetadata["traits.city"].UpdatedAt)
+		assert.Equal(t, older, merged.AttributeMetadata["traits.first_seen"].UpdatedAt, "the kept value keeps its write time")
+	})
+
+	t.Run("Source_priority_prefers_trusted_application", func(t *testing.T) {
+		merged := workers.MergeProfiles(existing, incoming, rules, []string{"crm_app", "web_app"})
+		assert.Equal(t, "+94770000001", merged.IdentityAttributes["phone"])
+		assert.Equal(t, "crm_app", merged.AttributeMetadata["identity_attributes.phone"].SourceApplication)
+
+		merged = workers.MergeProfiles(existing, incoming, rules, []string{"web_app", "crm_app"})
+		assert.Equal(t, "+94770000002", merged.IdentityAttributes["phone"])
+	})
+
+	t.Run("Source_priority_tie_falls_back_to_latest", func(t *testing.T) {
+		merged := workers.MergeProfiles(existing, incoming, rules, nil)
+		assert.Equal(t, "+94770000002", merged.IdentityAttributes["phone"])
+	})
+
+	t.Run("Empty_value_never_wins", func(t *testing.T) {
+		blank := incoming
+		blank.Traits = map[string]interface{}{"city": ""}
+		merged := workers.MergeProfiles(existing, blank, rules, nil)
+		assert.Equal(t, "Colombo", merged.Traits["city"])
+	})
+
+	t.Run("Writes_stamp_changed_attributes", func(t *testing.T) {
+		profileSvc := profileService.GetProfilesService()
+		req := mustUnmarshalProfile(`{"traits":{"city":"Galle"}}`)
+		req.SourceApplication = "web_app"
+		created, err := profileSvc.CreateProfile(req, org)
+		require.NoError(t, err)
+
+		stored, err := profileStore.GetProfile(created.ProfileId)
+		require.NoError(t, err)
+		require.Contains(t, stored.AttributeMetadata, "traits.city")
+		createdMeta := stored.AttributeMetadata["traits.city"]
+		assert.Equal(t, "web_app", createdMeta.SourceApplication)
+
+		update := mustUnmarshalProfile(`{"traits":{"city":"Galle","country":"LK"}}`)
+		update.SourceApplication = "crm_app"
+		_, err = profileSvc.UpdateProfile(created.ProfileId, org, update)
+		require.NoError(t, err)
+
+		stored, err = profileStore.GetProfile(created.ProfileId)
+		require.NoError(t, err)
+		assert.Equal(t, "web_app", stored.AttributeMetadata["traits.city"].SourceApplication, "unchanged values keep their source")
+		assert.Equal(t, "crm_app", stored.AttributeMetadata["traits.country"].SourceApplication)
+	})
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+)
+
+// metricValue returns the value of the series in the current metrics, or 0 when the series does not exist.
+func metricValue(t *testing.T, series string) float64 {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, metrics.Write(&buf))
+	match := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(series) + ` (\S+)$`).FindStringSubmatch(buf.String())
+	if match == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	require.NoError(t, err)
+	return value
+}
+
+func Test_Queue_And_Worker_Metrics(t *testing.T) {
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	profileSvc := profileService.GetProfilesService()
+	org := fmt.Sprintf("metrics-%d", time.Now().UnixNano())
+	setupEmailUnification(t, org)
+	defer cleanProfiles(profileSvc, org)
+
+	const (
+		enqueued   = `cds_queue_enqueued_total{provider="memory",queue="profile_unification"}`
+		lag        = `cds_queue_lag_seconds_count{provider="memory",queue="profile_unification"}`
+		processed  = `cds_worker_processing_duration_seconds_count{outcome="success",queue="profile_unification"}`
+		tempMerges = `cds_unification_merges_total{rule="email_based",type="TEMP_TEMP"}`
+	)
+	enqueuedBefore := metricValue(t, enqueued)
+	lagBefore := metricValue(t, lag)
+	processedBefore := metricValue(t, processed)
+	mergesBefore := metricValue(t, tempMerges)
+
+	var created []string
+	for i := 0; i < 2; i++ {
+		profile, err := profileSvc.CreateProfile(mustUnmarshalProfile(fmt.Sprintf(
+			`{"identity_attributes":{"email":["metrics@%s.com"]}}`, org)), org)
+		require.NoError(t, err)
+		created = append(created, profile.ProfileId)
+	}
+
+	require.Eventually(t, func() bool {
+		profile, err := profileSvc.GetProfile(created[1])
+		return err == nil && profile != nil && profile.MergedTo != nil
+	}, 30*time.Second, 200*time.Millisecond, "profiles should be merged")
+	require.Eventually(t, func() bool {
+		return metricValue(t, processed) >= processedBefore+2
+	}, 10*time.Second, 100*time.Millisecond, "both profiles should be processed")
+
+	require.GreaterOrEqual(t, metricValue(t, enqueued), enqueuedBefore+2)
+	require.GreaterOrEqual(t, metricValue(t, lag), lagBefore+2)
+	require.GreaterOrEqual(t, metricValue(t, tempMerges), mergesBefore+1)
+
+	var buf bytes.Buffer
+	require.NoError(t, metrics.Write(&buf))
+	require.Contains(t, buf.String(), "# TYPE cds_queue_lag_seconds histogram")
+	require.Contains(t, buf.String(), "# TYPE cds_queue_depth gauge")
+	require.Contains(t, buf.String(), "# TYPE go_goroutines gauge")
+	require.Contains(t, buf.String(), "# TYPE process_cpu_seconds_total counter")
+}
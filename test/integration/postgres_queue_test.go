@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/postgres"
+)
+
+func Test_PostgresQueue(t *testing.T) {
+
+	policy := delivery.Policy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+	queueName := func() string { return fmt.Sprintf("test-queue-%s", uuid.New().String()) }
+
+	t.Run("Profile_is_processed_once_and_removed", func(t *testing.T) {
+		q, err := postgres.NewProfileQueue(queueName(), policy, 1)
+		require.NoError(t, err)
+		defer q.Close()
+
+		processed := make(chan string, 2)
+		require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+			processed <- profile.ProfileId
+			return nil
+		}))
+		profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "pg-queue-org"}
+		require.NoError(t, q.Enqueue(profile))
+
+		select {
+		case id := <-processed:
+			require.Equal(t, profile.ProfileId, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("profile was not processed")
+		}
+		select {
+		case id := <-processed:
+			t.Fatalf("profile %s was processed twice", id)
+		case <-time.After(1500 * time.Millisecond):
+		}
+	})
+
+	t.Run("Jobs_survive_a_restart", func(t *testing.T) {
+		name := queueName()
+		producer, err := postgres.NewProfileQueue(name, policy, 1)
+		require.NoError(t, err)
+		profile := profileModel.UnificationMessage{ProfileId: uuid.New().String()}
+		require.NoError(t, producer.Enqueue(profile))
+		require.NoError(t, producer.Close())
+
+		consumer, err := postgres.NewProfileQueue(name, policy, 1)
+		require.NoError(t, err)
+		defer consumer.Close()
+		processed := make(chan string, 1)
+		require.NoError(t, consumer.Start(func(p profileModel.UnificationMessage) error {
+			processed <- p.ProfileId
+			return nil
+		}))
+		select {
+		case id := <-processed:
+			require.Equal(t, profile.ProfileId, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job stored before the restart was not processed")
+		}
+	})
+
+	t.Run("Consumers_share_jobs_without_duplicates", func(t *testing.T) {
+		name := queueName()
+		const jobs = 20
+		var mu sync.Mutex
+		seen := map[string]int{}
+		all := make(chan struct{})
+		handler := func(profile profileModel.UnificationMessage) error {
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			seen[profile.ProfileId]++
+			if len(seen) == jobs {
+				close(all)
+			}
+			return nil
+		}
+		for i := 0; i < 3; i++ {
+			consumer, err := postgres.NewProfileQueue(name, policy, 1)
+			require.NoError(t, err)
+			defer consumer.Close()
+			require.NoError(t, consumer.Start(handler))
+		}
+		producer, err := postgres.NewProfileQueue(name, policy, 1)
+		require.NoError(t, err)
+		defer producer.Close()
+		for i := 0; i < jobs; i++ {
+			require.NoError(t, producer.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String()}))
+		}
+
+		select {
+		case <-all:
+		case <-time.After(15 * time.Second):
+			t.Fatal("not all jobs were processed")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for id, count := range seen {
+			require.Equal(t, 1, count, "profile %s processed more than once", id)
+		}
+	})
+
+	t.Run("Failing_profile_is_dead_lettered_and_replayed", func(t *testing.T) {
+		q, err := postgres.NewProfileQueue(queueName(), policy, 1)
+		require.NoError(t, err)
+		defer q.Close()
+
+		var mu sync.Mutex
+		healed := false
+		attempts := 0
+		processed := make(chan string, 1)
+		require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			if !healed {
+				return errors.New("database unavailable")
+			}
+			processed <- profile.ProfileId
+			return nil
+		}))
+		profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "pg-queue-org"}
+		require.NoError(t, q.Enqueue(profile))
+
+		var deadLetters []queue.DeadLetter
+		require.Eventually(t, func() bool {
+			deadLetters, err = q.DeadLetters()
+			return err == nil && len(deadLetters) == 1
+		}, 10*time.Second, 50*time.Millisecond)
+		require.Equal(t, profile.ProfileId, deadLetters[0].Profile.ProfileId)
+		require.Equal(t, profile.OrgHandle, deadLetters[0].Profile.OrgHandle)
+		require.Equal(t, 3, deadLetters[0].Attempts)
+		require.Equal(t, "database unavailable", deadLetters[0].Error)
+		mu.Lock()
+		require.Equal(t, 3, attempts)
+		healed = true
+		mu.Unlock()
+
+		replayedId := deadLetters[0].Id
+		require.NoError(t, q.ReplayDeadLetter(replayedId))
+		select {
+		case id := <-processed:
+			require.Equal(t, profile.ProfileId, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("replayed profile was not processed")
+		}
+		deadLetters, err = q.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+
+		require.ErrorIs(t, q.ReplayDeadLetter(replayedId), queue.ErrDeadLetterNotFound)
+		require.ErrorIs(t, q.ReplayDeadLetter("not-a-job-id"), queue.ErrDeadLetterNotFound)
+	})
+
+	t.Run("Schema_sync_job_is_processed", func(t *testing.T) {
+		q, err := postgres.NewSchemaSyncQueue(queueName())
+		require.NoError(t, err)
+		defer q.Close()
+
+		processed := make(chan schemaModel.ProfileSchemaSync, 1)
+		require.NoError(t, q.Start(func(sync schemaModel.ProfileSchemaSync) {
+			processed <- sync
+		}))
+		require.NoError(t, q.Enqueue(schemaModel.ProfileSchemaSync{OrgId: "pg-queue-org", Event: "POST_ADD_LOCAL_CLAIM"}))
+		select {
+		case sync := <-processed:
+			require.Equal(t, "pg-queue-org", sync.OrgId)
+			require.Equal(t, "POST_ADD_LOCAL_CLAIM", sync.Event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("schema sync job was not processed")
+		}
+	})
+}
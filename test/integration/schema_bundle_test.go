@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	consentModel "github.com/wso2/identity-customer-data-service/internal/consent/model"
+	consentService "github.com/wso2/identity-customer-data-service/internal/consent/service"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	bundleModel "github.com/wso2/identity-customer-data-service/internal/schema_bundle/model"
+	bundleService "github.com/wso2/identity-customer-data-service/internal/schema_bundle/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	errors2 "github.com/wso2/identity-customer-data-service/internal/system/errors"
+	unificationModel "github.com/wso2/identity-customer-data-service/internal/unification_rules/model"
+	unificationService "github.com/wso2/identity-customer-data-service/internal/unification_rules/service"
+)
+
+func changeNames(changes []bundleModel.Change) []string {
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		names = append(names, change.Name)
+	}
+	return names
+}
+
+func Test_Schema_Bundle_Export_Import(t *testing.T) {
+	suffix := time.Now().UnixNano()
+	source := fmt.Sprintf("bundle-source-org-%d", suffix)
+	target := fmt.Sprintf("bundle-target-org-%d", suffix)
+	schemaSvc := schemaService.GetProfileSchemaService()
+	bundleSvc := bundleService.GetSchemaBundleService()
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	loyaltyTier := func(org, mergeStrategy string) model.ProfileSchemaAttribute {
+		attr := createAttr(org, "traits.loyalty_tier", constants.StringDataType, mergeStrategy, constants.MutabilityReadWrite)
+		attr.CanonicalValues = []model.CanonicalValue{{Value: "gold", Label: "Gold"}, {Value: "silver", Label: "Silver"}}
+		return attr
+	}
+
+	for _, org := range []string{source, target} {
+		_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+			createAttr(org, "identity_attributes.email", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite),
+		}, constants.IdentityAttributes, org)
+		require.NoError(t, err)
+	}
+
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		loyaltyTier(source, constants.MergeStrategyLatest),
+		complexAttr(source, "traits.address", constants.MergeStrategyCombine,
+			createAttr(source, "traits.address.city", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)),
+	}, constants.Traits, source)
+	require.NoError(t, err)
+	visits := createAttr(source, "application_data.visits", constants.IntegerDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	visits.ApplicationIdentifier = "shop_app"
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{visits}, constants.ApplicationData, source)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	require.NoError(t, unificationService.GetUnificationRuleService().AddUnificationRule(unificationModel.UnificationRule{
+		RuleId: fmt.Sprintf("bundle-rule-%d", suffix), OrgHandle: source, RuleName: "email_based",
+		PropertyName: "identity_attributes.email", Priority: 1, IsActive: true, CreatedAt: now, UpdatedAt: now,
+	}, source))
+	_, err = consentService.GetConsentCategoryService().AddConsentCategory(consentModel.ConsentCategory{
+		CategoryName: "Marketing", OrgHandle: source, Purpose: "personalization",
+		Attributes: []consentModel.ConsentAttribute{{AttributeName: "traits.loyalty_tier"}},
+	})
+	require.NoError(t, err)
+
+	// The target already has the tier attribute, with a different merge strategy.
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		loyaltyTier(target, constants.MergeStrategyOverwrite),
+	}, constants.Traits, target)
+	require.NoError(t, err)
+
+	exported, err := bundleSvc.ExportSchemaBundle(source)
+	require.NoError(t, err)
+
+	t.Run("Export_covers_the_schema_without_identifiers", func(t *testing.T) {
+		assert.Equal(t, constants.SchemaBundleVersion, exported.Version)
+		require.Len(t, exported.Traits, 2)
+		assert.Equal(t, "traits.address", exported.Traits[0].AttributeName)
+		require.Len(t, exported.Traits[0].SubAttributes, 1)
+		assert.Equal(t, "traits.address.city", exported.Traits[0].SubAttributes[0].AttributeName)
+		require.Len(t, exported.ApplicationData, 1)
+		assert.Equal(t, "shop_app", exported.ApplicationData[0].ApplicationIdentifier)
+		require.Len(t, exported.UnificationRules, 1)
+		require.Len(t, exported.ConsentCategories, 1)
+		assert.Equal(t, "traits.loyalty_tier", exported.ConsentCategories[0].Attributes[0].AttributeName)
+	})
+
+	t.Run("YAML_round_trip_preserves_the_bundle", func(t *testing.T) {
+		data, err := bundleService.EncodeSchemaBundle(*exported, constants.SchemaBundleFormatYAML)
+		require.NoError(t, err)
+		decoded, err := bundleService.DecodeSchemaBundle(data, constants.SchemaBundleFormatYAML)
+		require.NoError(t, err)
+		assert.Equal(t, *exported, decoded)
+	})
+
+	t.Run("Dry_run_reports_changes_without_applying_them", func(t *testing.T) {
+		summary, err := bundleSvc.ImportSchemaBundle(target, *exported, true)
+		require.NoError(t, err)
+		assert.True(t, summary.DryRun)
+		assert.ElementsMatch(t, []string{"traits.address", "traits.address.city", "application_data.visits"},
+			changeNames(summary.Attributes.Created))
+		require.Len(t, summary.Attributes.Updated, 1)
+		assert.Equal(t, "traits.loyalty_tier", summary.Attributes.Updated[0].Name)
+		assert.Equal(t, []string{"merge_strategy"}, summary.Attributes.Updated[0].Fields)
+		assert.Equal(t, []string{"identity_attributes.email"}, changeNames(summary.UnificationRules.Created))
+		assert.Equal(t, []string{"Marketing"}, changeNames(summary.ConsentCategories.Created))
+
+		address, err := schemaSvc.GetProfileSchemaAttributeByName("traits.address", target)
+		require.NoError(t, err)
+		assert.Nil(t, address, "a dry run must not change the schema")
+	})
+
+	t.Run("Import_reproduces_the_source_schema", func(t *testing.T) {
+		summary, err := bundleSvc.ImportSchemaBundle(target, *exported, false)
+		require.NoError(t, err)
+		assert.False(t, summary.DryRun)
+		assert.Len(t, summary.Attributes.Created, 3)
+
+		imported, err := bundleSvc.ExportSchemaBundle(target)
+		require.NoError(t, err)
+		assert.Equal(t, exported.Traits, imported.Traits)
+		assert.Equal(t, exported.ApplicationData, imported.ApplicationData)
+		assert.Equal(t, exported.UnificationRules, imported.UnificationRules)
+		assert.Equal(t, exported.ConsentCategories, imported.ConsentCategories)
+	})
+
+	t.Run("Reimport_is_a_no_op", func(t *testing.T) {
+		summary, err := bundleSvc.ImportSchemaBundle(target, *exported, false)
+		require.NoError(t, err)
+		assert.Empty(t, summary.Attributes.Created)
+		assert.Empty(t, summary.Attributes.Updated)
+		assert.Equal(t, 4, summary.Attributes.Unchanged)
+		assert.Equal(t, 1, summary.UnificationRules.Unchanged)
+		assert.Equal(t, 1, summary.ConsentCategories.Unchanged)
+	})
+
+	t.Run("Invalid_bundles_are_rejected", func(t *testing.T) {
+		unsupported := *exported
+		unsupported.Version = constants.SchemaBundleVersion + 1
+		_, err := bundleSvc.ImportSchemaBundle(target, unsupported, true)
+		require.Error(t, err)
+
+		dangling := *exported
+		dangling.UnificationRules = []unificationModel.UnificationRuleAPIRequest{
+			{RuleName: "phone_based", PropertyName: "identity_attributes.phone", Priority: 2, IsActive: true},
+		}
+		_, err = bundleSvc.ImportSchemaBundle(target, dangling, true)
+		require.Error(t, err, "rules must refer to attributes the organization has")
+	})
+
+	t.Run("Failed_import_leaves_the_schema_unchanged", func(t *testing.T) {
+		partial := fmt.Sprintf("bundle-partial-org-%d", suffix)
+		_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+			createAttr(partial, "identity_attributes.email", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite),
+		}, constants.IdentityAttributes, partial)
+		require.NoError(t, err)
+
+		// A category without a purpose passes the bundle checks but fails when it is created, after the
+		// attributes and rules are in.
+		invalid := *exported
+		invalid.ConsentCategories = append([]bundleModel.BundleConsentCategory(nil), exported.ConsentCategories...)
+		invalid.ConsentCategories[0].Purpose = ""
+		summary, err := bundleSvc.ImportSchemaBundle(partial, invalid, false)
+		require.Error(t, err)
+		require.NotNil(t, summary)
+		require.NotNil(t, summary.Failure)
+		assert.Equal(t, errors2.CONSENT_CAT_VALIDATION.Code, summary.Failure.Code)
+		assert.Empty(t, summary.Attributes.Created)
+		assert.Empty(t, summary.UnificationRules.Created)
+		assert.Empty(t, summary.ConsentCategories.Created)
+
+		address, err := schemaSvc.GetProfileSchemaAttributeByName("traits.address", partial)
+		require.NoError(t, err)
+		assert.Nil(t, address, "changes made before the failure are rolled back")
+
+		rules, err := unificationService.GetUnificationRuleService().GetUnificationRules(partial)
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+}
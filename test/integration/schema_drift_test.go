@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// identityAttr builds an identity attribute the way the schema sync does.
+func identityAttr(org, name, vType string, subs ...model.SubAttribute) model.ProfileSchemaAttribute {
+	attr := createAttr(org, name, vType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	attr.SCIMDialect = scimEnterpriseDialect
+	attr.SubAttributes = subs
+	return attr
+}
+
+func Test_Schema_Drift(t *testing.T) {
+	org := fmt.Sprintf("schema-drift-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+
+	department := identityAttr(org, "identity_attributes.department", constants.StringDataType)
+	level := identityAttr(org, "identity_attributes.level", constants.StringDataType)
+	legacy := identityAttr(org, "identity_attributes.legacy", constants.StringDataType)
+	for _, attr := range []model.ProfileSchemaAttribute{department, level, legacy} {
+		require.NoError(t, psstr.UpsertIdentityAttribute(org, attr))
+	}
+
+	// The identity server changed the type of level, added the manager claims and dropped legacy.
+	managerName := identityAttr(org, "identity_attributes.manager.displayName", constants.StringDataType)
+	claims := []model.ProfileSchemaAttribute{
+		identityAttr(org, "identity_attributes.department", constants.StringDataType),
+		identityAttr(org, "identity_attributes.level", constants.IntegerDataType),
+		managerName,
+		identityAttr(org, "identity_attributes.manager", constants.ComplexDataType, model.SubAttribute{
+			AttributeId:   managerName.AttributeId,
+			AttributeName: managerName.AttributeName,
+		}),
+	}
+	restore := schemaService.OverrideFetchIdentitySchemaForTest(
+		func(string) ([]model.ProfileSchemaAttribute, error) { return claims, nil })
+	defer restore()
+
+	t.Run("Report_lists_missing_extra_and_mismatched_attributes", func(t *testing.T) {
+		report, err := schemaSvc.GetSchemaDriftReport(org)
+		require.NoError(t, err)
+		assert.False(t, report.InSync)
+
+		require.Len(t, report.Missing, 2)
+		assert.Equal(t, "identity_attributes.manager", report.Missing[0].AttributeName)
+		assert.Equal(t, "identity_attributes.manager.displayName", report.Missing[1].AttributeName)
+
+		require.Len(t, report.Extra, 1)
+		assert.Equal(t, legacy.AttributeId, report.Extra[0].AttributeId)
+
+		require.Len(t, report.Mismatched, 1)
+		assert.Equal(t, level.AttributeId, report.Mismatched[0].AttributeId)
+		assert.Equal(t, []model.SchemaDriftDifference{{
+			Field: "value_type", Stored: constants.StringDataType, Expected: constants.IntegerDataType,
+		}}, report.Mismatched[0].Differences)
+	})
+
+	t.Run("Reconcile_applies_the_drift_and_keeps_attribute_ids", func(t *testing.T) {
+		report, err := schemaSvc.ReconcileSchemaDrift(org)
+		require.NoError(t, err)
+		assert.True(t, report.Reconciled)
+
+		reconciled, err := psstr.GetProfileSchemaAttributeByName(org, "identity_attributes.level")
+		require.NoError(t, err)
+		assert.Equal(t, level.AttributeId, reconciled.AttributeId)
+		assert.Equal(t, constants.IntegerDataType, reconciled.ValueType)
+
+		removed, err := psstr.GetProfileSchemaAttributeByName(org, "identity_attributes.legacy")
+		require.NoError(t, err)
+		assert.Nil(t, removed)
+
+		manager, err := psstr.GetProfileSchemaAttributeByName(org, "identity_attributes.manager")
+		require.NoError(t, err)
+		require.NotNil(t, manager)
+		child, err := psstr.GetProfileSchemaAttributeByName(org, "identity_attributes.manager.displayName")
+		require.NoError(t, err)
+		require.NotNil(t, child)
+		require.Len(t, manager.SubAttributes, 1)
+		assert.Equal(t, child.AttributeId, manager.SubAttributes[0].AttributeId)
+
+		report, err = schemaSvc.GetSchemaDriftReport(org)
+		require.NoError(t, err)
+		assert.True(t, report.InSync)
+	})
+}
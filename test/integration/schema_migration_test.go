@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// attributeUpdate builds the full set of updates for an attribute, as the attribute update API expects.
+func attributeUpdate(attr model.ProfileSchemaAttribute, changes map[string]interface{}) map[string]interface{} {
+	updates := map[string]interface{}{
+		"attribute_name": attr.AttributeName,
+		"value_type":     attr.ValueType,
+		"merge_strategy": attr.MergeStrategy,
+		"mutability":     attr.Mutability,
+		"multi_valued":   attr.MultiValued,
+	}
+	for key, value := range changes {
+		updates[key] = value
+	}
+	return updates
+}
+
+// awaitMigration waits for the latest migration of an attribute to finish.
+func awaitMigration(t *testing.T, org, attributeId string) model.SchemaMigration {
+	schemaSvc := schemaService.GetProfileSchemaService()
+	var latest model.SchemaMigration
+	require.Eventually(t, func() bool {
+		migrations, err := schemaSvc.GetSchemaMigrationsForAttribute(org, attributeId)
+		if err != nil || len(migrations) == 0 {
+			return false
+		}
+		latest = migrations[0]
+		return latest.Status == constants.SchemaMigrationCompleted || latest.Status == constants.SchemaMigrationFailed
+	}, 10*time.Second, 100*time.Millisecond)
+	return latest
+}
+
+func storedTraits(t *testing.T, profileId string) map[string]interface{} {
+	profile, err := profileStore.GetProfile(profileId)
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	return profile.Traits
+}
+
+func Test_Schema_Migration(t *testing.T) {
+	org := fmt.Sprintf("schema-migration-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+	profileSvc := profileService.GetProfilesService()
+
+	age := createAttr(org, "traits.age", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	tags := createAttr(org, "traits.tags", constants.StringDataType, constants.MergeStrategyCombine, constants.MutabilityReadWrite)
+	tags.MultiValued = true
+	tier := createAttr(org, "traits.tier", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	tier.CanonicalValues = []model.CanonicalValue{{Value: "gold", Label: "Gold"}, {Value: "bronze", Label: "Bronze"}}
+	zip := createAttr(org, "traits.address.zip", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{
+		age, tags, tier, complexAttr(org, "traits.address", constants.MergeStrategyCombine, zip),
+	}, constants.Traits, org)
+	require.NoError(t, err)
+
+	convertible, err := profileSvc.CreateProfile(mustUnmarshalProfile(
+		`{"traits": {"age": "42", "tags": ["vip"], "tier": "bronze", "address": {"zip": "10100"}}}`), org)
+	require.NoError(t, err)
+	unconvertible, err := profileSvc.CreateProfile(mustUnmarshalProfile(
+		`{"traits": {"age": "unknown", "tags": ["vip", "new"], "tier": "gold", "address": {"zip": "AB1"}}}`), org)
+	require.NoError(t, err)
+
+	t.Run("Preview_reports_unconvertible_values_without_writing", func(t *testing.T) {
+		preview, err := schemaSvc.PreviewSchemaMigration(org, age.AttributeId,
+			attributeUpdate(age, map[string]interface{}{"value_type": constants.IntegerDataType}), constants.Traits)
+		require.NoError(t, err)
+		assert.True(t, preview.MigrationRequired)
+		assert.Equal(t, 2, preview.TotalProfiles)
+		assert.Equal(t, 1, preview.ConvertibleValues)
+		assert.Equal(t, 1, preview.FailedValues)
+		require.Len(t, preview.Failures, 1)
+		assert.Equal(t, unconvertible.ProfileId, preview.Failures[0].ProfileId)
+		assert.Equal(t, "unknown", preview.Failures[0].Value)
+
+		assert.Equal(t, "42", storedTraits(t, convertible.ProfileId)["age"], "a preview must not change profiles")
+	})
+
+	t.Run("Type_change_converts_values_and_flags_the_rest", func(t *testing.T) {
+		require.NoError(t, schemaSvc.UpdateProfileSchemaAttributeById(org, age.AttributeId,
+			attributeUpdate(age, map[string]interface{}{"value_type": constants.IntegerDataType}), constants.Traits))
+
+		migration := awaitMigration(t, org, age.AttributeId)
+		assert.Equal(t, constants.SchemaMigrationCompleted, migration.Status)
+		assert.Equal(t, 2, migration.TotalProfiles)
+		assert.Equal(t, 2, migration.ProcessedProfiles)
+		assert.Equal(t, 1, migration.ConvertedValues)
+		assert.Equal(t, 1, migration.FailedValues)
+		assert.Equal(t, constants.StringDataType, migration.From.ValueType)
+
+		assert.Equal(t, float64(42), storedTraits(t, convertible.ProfileId)["age"])
+		assert.Equal(t, "unknown", storedTraits(t, unconvertible.ProfileId)["age"], "unconvertible values are kept")
+
+		fetched, err := schemaSvc.GetSchemaMigration(org, migration.MigrationId)
+		require.NoError(t, err)
+		assert.Equal(t, migration.MigrationId, fetched.MigrationId)
+	})
+
+	t.Run("Multi_valued_change_unwraps_single_values", func(t *testing.T) {
+		require.NoError(t, schemaSvc.UpdateProfileSchemaAttributeById(org, tags.AttributeId,
+			attributeUpdate(tags, map[string]interface{}{
+				"multi_valued":   false,
+				"merge_strategy": constants.MergeStrategyOverwrite,
+			}), constants.Traits))
+
+		migration := awaitMigration(t, org, tags.AttributeId)
+		assert.Equal(t, 1, migration.ConvertedValues)
+		assert.Equal(t, 1, migration.FailedValues)
+		assert.Equal(t, "vip", storedTraits(t, convertible.ProfileId)["tags"])
+	})
+
+	t.Run("Removed_canonical_values_are_flagged", func(t *testing.T) {
+		require.NoError(t, schemaSvc.UpdateProfileSchemaAttributeById(org, tier.AttributeId,
+			attributeUpdate(tier, map[string]interface{}{
+				"canonical_values": []interface{}{map[string]interface{}{"value": "gold", "label": "Gold"}},
+			}), constants.Traits))
+
+		migration := awaitMigration(t, org, tier.AttributeId)
+		assert.Equal(t, 0, migration.ConvertedValues)
+		require.Len(t, migration.Failures, 1)
+		assert.Equal(t, convertible.ProfileId, migration.Failures[0].ProfileId)
+		assert.Equal(t, "bronze", migration.Failures[0].Value)
+	})
+
+	t.Run("Sub_attribute_values_are_converted_in_place", func(t *testing.T) {
+		require.NoError(t, schemaSvc.UpdateProfileSchemaAttributeById(org, zip.AttributeId,
+			attributeUpdate(zip, map[string]interface{}{"value_type": constants.IntegerDataType}), constants.Traits))
+
+		migration := awaitMigration(t, org, zip.AttributeId)
+		assert.Equal(t, 1, migration.ConvertedValues)
+		assert.Equal(t, 1, migration.FailedValues)
+		address := storedTraits(t, convertible.ProfileId)["address"].(map[string]interface{})
+		assert.Equal(t, float64(10100), address["zip"])
+	})
+
+	t.Run("Changes_that_keep_values_valid_start_no_migration", func(t *testing.T) {
+		require.NoError(t, schemaSvc.UpdateProfileSchemaAttributeById(org, age.AttributeId,
+			attributeUpdate(age, map[string]interface{}{
+				"value_type":     constants.IntegerDataType,
+				"merge_strategy": constants.MergeStrategyLatest,
+			}), constants.Traits))
+
+		migrations, err := schemaSvc.GetSchemaMigrationsForAttribute(org, age.AttributeId)
+		require.NoError(t, err)
+		assert.Len(t, migrations, 1)
+	})
+}
+
+// claimStaleMigrations claims every stale migration for owner and returns the ids claimed.
+func claimStaleMigrations(t *testing.T, owner string) map[string]bool {
+	claimed := map[string]bool{}
+	for {
+		migration, err := psstr.ClaimStaleSchemaMigration(owner, time.Now().UTC().Add(-time.Minute))
+		require.NoError(t, err)
+		if migration == nil {
+			return claimed
+		}
+		assert.Equal(t, owner, migration.Owner)
+		claimed[migration.MigrationId] = true
+	}
+}
+
+func Test_Schema_Migration_Claims(t *testing.T) {
+	org := fmt.Sprintf("schema-migration-claim-org-%d", time.Now().UnixNano())
+
+	unfinished := func(owner string, updatedAt time.Time) model.SchemaMigration {
+		migration := model.SchemaMigration{
+			MigrationId:   uuid.New().String(),
+			OrgHandle:     org,
+			AttributeId:   uuid.New().String(),
+			AttributeName: "traits.age",
+			Operation:     constants.SchemaMigrationConvert,
+			Status:        constants.SchemaMigrationRunning,
+			Failures:      []model.SchemaMigrationFailure{},
+			Owner:         owner,
+			CreatedAt:     updatedAt,
+			UpdatedAt:     updatedAt,
+		}
+		require.NoError(t, psstr.InsertSchemaMigration(migration))
+		return migration
+	}
+	stale := unfinished("stopped-instance", time.Now().UTC().Add(-time.Hour))
+	live := unfinished("live-instance", time.Now().UTC())
+
+	t.Run("Only_stale_migrations_are_claimed", func(t *testing.T) {
+		claimed := claimStaleMigrations(t, "instance-a")
+		assert.True(t, claimed[stale.MigrationId])
+		assert.False(t, claimed[live.MigrationId])
+	})
+
+	t.Run("A_claimed_migration_is_not_claimed_again", func(t *testing.T) {
+		claimed := claimStaleMigrations(t, "instance-b")
+		assert.False(t, claimed[stale.MigrationId])
+	})
+
+	t.Run("The_previous_owner_can_no_longer_record_progress", func(t *testing.T) {
+		stale.UpdatedAt = time.Now().UTC()
+		active, err := psstr.UpdateSchemaMigrationProgress(stale)
+		require.NoError(t, err)
+		assert.False(t, active)
+
+		stale.Owner = "instance-a"
+		active, err = psstr.UpdateSchemaMigrationProgress(stale)
+		require.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("Heartbeats_keep_a_migration_from_going_stale", func(t *testing.T) {
+		lagging := unfinished("lagging-instance", time.Now().UTC().Add(-time.Hour))
+		require.NoError(t, psstr.TouchSchemaMigration(lagging.MigrationId, lagging.Owner))
+		claimed := claimStaleMigrations(t, "instance-b")
+		assert.False(t, claimed[lagging.MigrationId])
+	})
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+// runningPurge records an unfinished purge without running it, standing in for one that is still in progress.
+func runningPurge(t *testing.T, org, attributeName string) model.SchemaMigration {
+	now := time.Now().UTC()
+	purge := model.SchemaMigration{
+		MigrationId:   uuid.New().String(),
+		OrgHandle:     org,
+		AttributeId:   uuid.New().String(),
+		AttributeName: attributeName,
+		Operation:     constants.SchemaMigrationPurge,
+		Status:        constants.SchemaMigrationRunning,
+		Failures:      []model.SchemaMigrationFailure{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	require.NoError(t, psstr.InsertSchemaMigration(purge))
+	return purge
+}
+
+func completePurge(t *testing.T, purge model.SchemaMigration) {
+	purge.Status = constants.SchemaMigrationCompleted
+	purge.UpdatedAt = time.Now().UTC()
+	_, err := psstr.UpdateSchemaMigrationProgress(purge)
+	require.NoError(t, err)
+}
+
+func Test_Schema_Attribute_Purge(t *testing.T) {
+	org := fmt.Sprintf("schema-purge-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+	profileSvc := profileService.GetProfilesService()
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	nickname := createAttr(org, "traits.nickname", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	city := createAttr(org, "traits.address.city", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	zip := createAttr(org, "traits.address.zip", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	address := complexAttr(org, "traits.address", constants.MergeStrategyCombine, city, zip)
+	_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{nickname, address},
+		constants.Traits, org)
+	require.NoError(t, err)
+	visits := createAttr(org, "application_data.visits", constants.IntegerDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+	visits.ApplicationIdentifier = "shop_app"
+	_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{visits}, constants.ApplicationData, org)
+	require.NoError(t, err)
+
+	profileIds := make([]string, 0, 2)
+	for _, name := range []string{"ada", "grace"} {
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(fmt.Sprintf(
+			`{"traits": {"nickname": "%s", "address": {"city": "Colombo", "zip": "10100"}},
+			  "application_data": {"shop_app": {"visits": 3}}}`, name)), org)
+		require.NoError(t, err)
+		profileIds = append(profileIds, created.ProfileId)
+	}
+
+	t.Run("Profiles_hide_attributes_while_their_purge_runs", func(t *testing.T) {
+		purge := runningPurge(t, org, "traits.address.zip")
+		profile, err := profileSvc.GetProfile(profileIds[0])
+		require.NoError(t, err)
+		assert.NotContains(t, profile.Traits["address"], "zip")
+		assert.Equal(t, "Colombo", profile.Traits["address"].(map[string]interface{})["city"])
+
+		completePurge(t, purge)
+		profile, err = profileSvc.GetProfile(profileIds[0])
+		require.NoError(t, err)
+		assert.Contains(t, profile.Traits["address"], "zip")
+	})
+
+	t.Run("Names_cannot_be_reused_while_their_purge_runs", func(t *testing.T) {
+		purge := runningPurge(t, org, "traits.legacy")
+		legacy := createAttr(org, "traits.legacy", constants.StringDataType, constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)
+		_, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{legacy}, constants.Traits, org)
+		require.Error(t, err)
+
+		completePurge(t, purge)
+		_, err = schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{legacy}, constants.Traits, org)
+		require.NoError(t, err)
+	})
+
+	t.Run("Deleting_with_purge_removes_trait_values", func(t *testing.T) {
+		purge, err := schemaSvc.DeleteProfileSchemaAttributeAndPurgeData(org, nickname.AttributeId)
+		require.NoError(t, err)
+		require.NotNil(t, purge)
+		assert.Equal(t, constants.SchemaMigrationPurge, purge.Operation)
+
+		completed := awaitMigration(t, org, nickname.AttributeId)
+		assert.Equal(t, constants.SchemaMigrationCompleted, completed.Status)
+		assert.Equal(t, 2, completed.ConvertedValues)
+		for _, profileId := range profileIds {
+			assert.NotContains(t, storedTraits(t, profileId), "nickname")
+		}
+	})
+
+	t.Run("Deleting_a_sub_attribute_with_purge_removes_nested_values", func(t *testing.T) {
+		require.NoError(t, schemaSvc.UpdateProfileSchemaAttributeById(org, address.AttributeId,
+			attributeUpdate(address, map[string]interface{}{"sub_attributes": []interface{}{
+				map[string]interface{}{"attribute_id": city.AttributeId, "attribute_name": city.AttributeName},
+			}}), constants.Traits))
+
+		_, err := schemaSvc.DeleteProfileSchemaAttributeAndPurgeData(org, zip.AttributeId)
+		require.NoError(t, err)
+		awaitMigration(t, org, zip.AttributeId)
+		stored := storedTraits(t, profileIds[0])["address"].(map[string]interface{})
+		assert.NotContains(t, stored, "zip")
+		assert.Equal(t, "Colombo", stored["city"])
+	})
+
+	t.Run("Deleting_with_purge_removes_application_data", func(t *testing.T) {
+		_, err := schemaSvc.DeleteProfileSchemaAttributeAndPurgeData(org, visits.AttributeId)
+		require.NoError(t, err)
+		awaitMigration(t, org, visits.AttributeId)
+
+		appData, err := profileStore.FetchApplicationDataWithAppId(profileIds[1], "shop_app")
+		require.NoError(t, err)
+		assert.NotContains(t, appData.AppSpecificData, "visits")
+	})
+
+	t.Run("Purging_a_missing_attribute_is_a_no_op", func(t *testing.T) {
+		purge, err := schemaSvc.DeleteProfileSchemaAttributeAndPurgeData(org, uuid.New().String())
+		require.NoError(t, err)
+		assert.Nil(t, purge)
+	})
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	psstr "github.com/wso2/identity-customer-data-service/internal/profile_schema/store"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+)
+
+const scimEnterpriseDialect = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+
+func Test_Schema_Sync_Event(t *testing.T) {
+	org := fmt.Sprintf("schema-sync-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+
+	apply := func(event string, claim model.SchemaSyncClaim) {
+		require.NoError(t, schemaSvc.ApplySchemaSyncEvent(model.ProfileSchemaSync{
+			OrgId: org,
+			Event: event,
+			Claim: &claim,
+		}))
+	}
+	attribute := func(name string) *model.ProfileSchemaAttribute {
+		attr, err := psstr.GetProfileSchemaAttributeByName(org, name)
+		require.NoError(t, err)
+		return attr
+	}
+
+	t.Run("Added_external_claim_creates_only_its_attribute", func(t *testing.T) {
+		apply(constants.AddScimAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:            scimEnterpriseDialect + ":costCenter",
+			DialectURI:          scimEnterpriseDialect,
+			MappedLocalClaimURI: "http://wso2.org/claims/costcenter",
+			DisplayName:         "Cost Center",
+			ReadOnly:            true,
+			MultiValued:         true,
+		})
+
+		attr := attribute("identity_attributes.costcenter")
+		require.NotNil(t, attr)
+		assert.Equal(t, constants.StringDataType, attr.ValueType)
+		assert.Equal(t, constants.MutabilityReadOnly, attr.Mutability)
+		assert.Equal(t, constants.MergeStrategyOverwrite, attr.MergeStrategy)
+		assert.Equal(t, scimEnterpriseDialect, attr.SCIMDialect)
+		assert.True(t, attr.MultiValued)
+
+		attrs, err := psstr.GetProfileSchemaAttributesByScope(org, constants.IdentityAttributes)
+		require.NoError(t, err)
+		assert.Len(t, attrs, 1)
+	})
+
+	t.Run("Updated_claims_keep_the_attribute_id", func(t *testing.T) {
+		before := attribute("identity_attributes.costcenter")
+		require.NotNil(t, before)
+
+		apply(constants.UpdateScimAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:            scimEnterpriseDialect + ":costCenter",
+			DialectURI:          scimEnterpriseDialect,
+			MappedLocalClaimURI: "http://wso2.org/claims/costcenter",
+			DisplayName:         "Cost Centre",
+		})
+		after := attribute("identity_attributes.costcenter")
+		assert.Equal(t, before.AttributeId, after.AttributeId)
+		assert.Equal(t, "Cost Centre", after.DisplayName)
+		assert.Equal(t, constants.MutabilityReadWrite, after.Mutability)
+
+		apply(constants.UpdateLocalAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:        "http://wso2.org/claims/costcenter",
+			DisplayName:     "Cost Centre",
+			CanonicalValues: []model.CanonicalValue{{Value: "cc-1", Label: "CC 1"}},
+		})
+		after = attribute("identity_attributes.costcenter")
+		assert.Equal(t, before.AttributeId, after.AttributeId)
+		assert.Equal(t, scimEnterpriseDialect, after.SCIMDialect, "a local claim update keeps the dialect")
+		assert.Equal(t, []model.CanonicalValue{{Value: "cc-1", Label: "CC 1"}}, after.CanonicalValues)
+	})
+
+	t.Run("Local_claims_without_a_scim_mapping_are_ignored", func(t *testing.T) {
+		apply(constants.UpdateLocalAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:    "http://wso2.org/claims/unmapped",
+			DisplayName: "Unmapped",
+		})
+		assert.Nil(t, attribute("identity_attributes.unmapped"))
+	})
+
+	t.Run("Claims_of_other_dialects_are_ignored", func(t *testing.T) {
+		apply(constants.AddScimAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:            "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/nickname",
+			DialectURI:          "http://schemas.xmlsoap.org/ws/2005/05/identity",
+			MappedLocalClaimURI: "http://wso2.org/claims/nickname",
+		})
+		assert.Nil(t, attribute("identity_attributes.nickname"))
+	})
+
+	t.Run("Sub_attributes_are_linked_to_their_parent", func(t *testing.T) {
+		parent := model.ProfileSchemaAttribute{
+			OrgId:         org,
+			AttributeId:   "sync-event-manager-" + org,
+			AttributeName: "identity_attributes.manager",
+			ValueType:     constants.ComplexDataType,
+			MergeStrategy: constants.MergeStrategyOverwrite,
+			Mutability:    constants.MutabilityReadWrite,
+			DisplayName:   "Manager",
+			SCIMDialect:   scimEnterpriseDialect,
+		}
+		require.NoError(t, psstr.UpsertIdentityAttribute(org, parent))
+
+		apply(constants.AddScimAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:            scimEnterpriseDialect + ":manager.displayName",
+			DialectURI:          scimEnterpriseDialect,
+			MappedLocalClaimURI: "http://wso2.org/claims/manager.displayName",
+		})
+		child := attribute("identity_attributes.manager.displayName")
+		require.NotNil(t, child)
+		linked := attribute("identity_attributes.manager")
+		require.Len(t, linked.SubAttributes, 1)
+		assert.Equal(t, child.AttributeId, linked.SubAttributes[0].AttributeId)
+
+		apply(constants.DeleteScimAttributeEvent, model.SchemaSyncClaim{
+			ClaimURI:            scimEnterpriseDialect + ":manager.displayName",
+			DialectURI:          scimEnterpriseDialect,
+			MappedLocalClaimURI: "http://wso2.org/claims/manager.displayName",
+		})
+		assert.Nil(t, attribute("identity_attributes.manager.displayName"))
+		assert.Empty(t, attribute("identity_attributes.manager").SubAttributes)
+	})
+
+	t.Run("Deleted_local_claim_removes_its_attribute", func(t *testing.T) {
+		apply(constants.DeleteLocalClaimEvent, model.SchemaSyncClaim{ClaimURI: "http://wso2.org/claims/costcenter"})
+		assert.Nil(t, attribute("identity_attributes.costcenter"))
+
+		// Deleting it again finds the schema already in sync.
+		apply(constants.DeleteLocalClaimEvent, model.SchemaSyncClaim{ClaimURI: "http://wso2.org/claims/costcenter"})
+	})
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	"github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+func complexAttr(org, name, merge string, subAttributes ...model.ProfileSchemaAttribute) model.ProfileSchemaAttribute {
+	attr := createAttr(org, name, constants.ComplexDataType, merge, constants.MutabilityReadWrite)
+	attr.SubAttributes = subAttributes
+	return attr
+}
+
+func Test_Nested_Sub_Attributes(t *testing.T) {
+	org := fmt.Sprintf("sub-attribute-org-%d", time.Now().UnixNano())
+	schemaSvc := schemaService.GetProfileSchemaService()
+	profileSvc := profileService.GetProfilesService()
+
+	addressType := createAttr(org, "traits.address.type", constants.StringDataType, constants.MergeStrategyOverwrite,
+		constants.MutabilityImmutable)
+	addressType.CanonicalValues = []model.CanonicalValue{{Value: "home", Label: "Home"}, {Value: "work", Label: "Work"}}
+
+	address := complexAttr(org, "traits.address", constants.MergeStrategyCombine,
+		addressType,
+		createAttr(org, "traits.address.city", constants.StringDataType, "ignore", constants.MutabilityReadWrite),
+		complexAttr(org, "traits.address.geo", constants.MergeStrategyCombine,
+			createAttr(org, "traits.address.geo.lat", constants.DecimalDataType, constants.MergeStrategyOverwrite,
+				constants.MutabilityReadWrite),
+			complexAttr(org, "traits.address.geo.grid", constants.MergeStrategyCombine,
+				complexAttr(org, "traits.address.geo.grid.zone", constants.MergeStrategyCombine,
+					createAttr(org, "traits.address.geo.grid.zone.code", constants.StringDataType,
+						constants.MergeStrategyOverwrite, constants.MutabilityReadWrite)))),
+	)
+
+	added, err := schemaSvc.AddProfileSchemaAttributesForScope([]model.ProfileSchemaAttribute{address}, constants.Traits, org)
+	require.NoError(t, err, "inline sub-attribute definitions should be accepted at any depth")
+	assert.Len(t, added, 8, "each inline definition is added as an attribute of its own")
+
+	t.Run("Sub_attributes_are_returned_as_full_definitions", func(t *testing.T) {
+		stored, err := schemaSvc.GetProfileSchemaAttributeByName("traits.address", org)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		require.Len(t, stored.SubAttributes, 3)
+
+		byName := make(map[string]model.ProfileSchemaAttribute)
+		for _, attr := range model.FlattenSubAttributes([]model.ProfileSchemaAttribute{*stored}) {
+			byName[attr.AttributeName] = attr
+		}
+		assert.Equal(t, constants.MutabilityImmutable, byName["traits.address.type"].Mutability)
+		assert.Len(t, byName["traits.address.type"].CanonicalValues, 2)
+		assert.Equal(t, "ignore", byName["traits.address.city"].MergeStrategy)
+		assert.Equal(t, constants.StringDataType, byName["traits.address.geo.grid.zone.code"].ValueType)
+	})
+
+	t.Run("Profile_values_are_validated_against_nested_definitions", func(t *testing.T) {
+		_, err := profileSvc.CreateProfile(mustUnmarshalProfile(`{"traits": {"address": {"type": "office"}}}`), org)
+		require.Error(t, err, "canonical values of a sub-attribute should be enforced")
+
+		_, err = profileSvc.CreateProfile(mustUnmarshalProfile(
+			`{"traits": {"address": {"geo": {"grid": {"zone": {"code": 7}}}}}}`), org)
+		require.Error(t, err, "the value type of a deeply nested sub-attribute should be enforced")
+
+		created, err := profileSvc.CreateProfile(mustUnmarshalProfile(
+			`{"traits": {"address": {"type": "home", "geo": {"lat": 6.9, "grid": {"zone": {"code": "WP"}}}}}}`), org)
+		require.NoError(t, err)
+
+		_, err = profileSvc.PatchProfile(created.ProfileId, org, map[string]interface{}{
+			"traits": map[string]interface{}{"address": map[string]interface{}{"type": "work"}},
+		}, "")
+		require.Error(t, err, "the mutability of a sub-attribute should be enforced")
+	})
+
+	t.Run("Merge_applies_each_sub_attribute_strategy", func(t *testing.T) {
+		existing := profileModel.Profile{
+			ProfileId: "existing",
+			Traits: map[string]interface{}{"address": map[string]interface{}{
+				"city": "Colombo",
+				"geo":  map[string]interface{}{"lat": 6.9, "grid": map[string]interface{}{"zone": map[string]interface{}{"code": "WP"}}},
+			}},
+		}
+		incoming := profileModel.Profile{
+			ProfileId: "incoming",
+			OrgHandle: org,
+			Traits: map[string]interface{}{"address": map[string]interface{}{
+				"city": "Kandy",
+				"geo":  map[string]interface{}{"lat": 7.3, "grid": map[string]interface{}{"zone": map[string]interface{}{"code": "CP"}}},
+			}},
+		}
+
+		// Only the top-level attribute is passed in, so every rule below it comes from the nested definitions.
+		merged := workers.MergeProfiles(existing, incoming, []model.ProfileSchemaAttribute{address}, nil)
+		mergedAddress := merged.Traits["address"].(map[string]interface{})
+		assert.Equal(t, "Colombo", mergedAddress["city"], "city uses the ignore strategy")
+		geo := mergedAddress["geo"].(map[string]interface{})
+		assert.Equal(t, 7.3, geo["lat"])
+		assert.Equal(t, "CP", geo["grid"].(map[string]interface{})["zone"].(map[string]interface{})["code"])
+	})
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/constants"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+// stepFailure fails the merges at one step until it is healed, and records whether the step was reached.
+type stepFailure struct {
+	mu      sync.Mutex
+	step    string
+	healed  bool
+	reached chan struct{}
+	once    sync.Once
+}
+
+func (f *stepFailure) fail(step string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if step != f.step || f.healed {
+		return nil
+	}
+	f.once.Do(func() { close(f.reached) })
+	return errors.New("injected failure at " + step)
+}
+
+func (f *stepFailure) heal() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.healed = true
+}
+
+// Test_Transactional_Merge fails the merge of two temporary profiles at each of its steps, and checks that
+// nothing of the half-done merge is persisted and that the redelivered message then merges them completely.
+func Test_Transactional_Merge(t *testing.T) {
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	const appId = "merge-tx-app"
+	profileSvc := profileService.GetProfilesService()
+	steps := []string{
+		workers.MergeStepInsertMaster,
+		workers.MergeStepReferences,
+		workers.MergeStepAppData,
+		workers.MergeStepTraits,
+		workers.MergeStepIdentityData,
+		workers.MergeStepAttributeMetadata,
+	}
+
+	for _, step := range steps {
+		t.Run("Failure_at_"+step+"_is_rolled_back", func(t *testing.T) {
+			org := fmt.Sprintf("merge-tx-%s-%d", step, time.Now().UnixNano())
+			setupEmailUnification(t, org)
+			_, err := schemaService.GetProfileSchemaService().AddProfileSchemaAttributesForScope(
+				[]schemaModel.ProfileSchemaAttribute{
+					{OrgId: org, AttributeId: uuid.New().String(), AttributeName: "application_data.device_id",
+						ValueType: constants.StringDataType, MergeStrategy: "combine",
+						Mutability: constants.MutabilityReadWrite, MultiValued: true, ApplicationIdentifier: appId},
+				}, constants.ApplicationData, org)
+			require.NoError(t, err)
+			defer cleanProfiles(profileSvc, org)
+
+			failure := &stepFailure{step: step, reached: make(chan struct{})}
+			defer workers.OverrideFailMergeStepForTest(failure.fail)()
+
+			var created []string
+			for i := 0; i < 2; i++ {
+				request := mustUnmarshalProfile(fmt.Sprintf(`{
+					"identity_attributes":{"email":["merge@%s.com"]},
+					"traits":{"interests":["interest-%d"]},
+					"application_data":{"%s":{"device_id":["device-%d"]}}
+				}`, org, i, appId, i))
+				profile, err := profileSvc.CreateProfile(request, org)
+				require.NoError(t, err)
+				created = append(created, profile.ProfileId)
+			}
+
+			select {
+			case <-failure.reached:
+			case <-time.After(30 * time.Second):
+				t.Fatalf("merge did not reach step %s", step)
+			}
+
+			// Nothing of the failed merge is visible: no new master, and both profiles are unmerged.
+			profiles, _, err := profileStore.GetAllProfiles(org, 10, nil)
+			require.NoError(t, err)
+			require.Len(t, profiles, 2)
+			for i, profileId := range created {
+				profile, err := profileSvc.GetProfile(profileId)
+				require.NoError(t, err)
+				require.Nil(t, profile.MergedTo)
+				require.Empty(t, profile.MergedFrom)
+				require.ElementsMatch(t, []interface{}{fmt.Sprintf("interest-%d", i)},
+					profile.Traits["interests"].([]interface{}))
+			}
+
+			failure.heal()
+
+			var masterId string
+			require.Eventually(t, func() bool {
+				masters := map[string]bool{}
+				for _, profileId := range created {
+					profile, err := profileSvc.GetProfile(profileId)
+					if err != nil || profile == nil || profile.MergedTo == nil {
+						return false
+					}
+					masters[profile.MergedTo.ProfileId] = true
+					masterId = profile.MergedTo.ProfileId
+				}
+				return len(masters) == 1
+			}, 30*time.Second, 200*time.Millisecond, "profiles should be merged once the failure is healed")
+
+			master, err := profileSvc.GetProfile(masterId)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []interface{}{"interest-0", "interest-1"}, master.Traits["interests"].([]interface{}))
+			require.ElementsMatch(t, []interface{}{"device-0", "device-1"},
+				master.ApplicationData[appId]["device_id"].([]interface{}))
+			profiles, _, err = profileStore.GetAllProfiles(org, 10, nil)
+			require.NoError(t, err)
+			require.Len(t, profiles, 3)
+		})
+	}
+}
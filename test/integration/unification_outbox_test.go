@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package integration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	profileService "github.com/wso2/identity-customer-data-service/internal/profile/service"
+	profileStore "github.com/wso2/identity-customer-data-service/internal/profile/store"
+	schemaService "github.com/wso2/identity-customer-data-service/internal/profile_schema/service"
+	"github.com/wso2/identity-customer-data-service/internal/system/workers"
+)
+
+var errRollback = errors.New("rollback")
+
+// pendingOutboxEntries returns the number of outbox entries waiting for each profile, leaving out the profiles
+// written within the debounce window that have not waited maxWait. The entries are read in a transaction that is
+// rolled back.
+func pendingOutboxEntries(t *testing.T, debounce, maxWait int) map[string]int {
+	t.Helper()
+	pending := map[string]int{}
+	err := profileStore.WithTransaction(func(tx *sql.Tx) error {
+		entries, err := profileStore.LockUnificationOutboxEntries(tx, 1000, debounce, maxWait)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			pending[entry.Message.ProfileId]++
+		}
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+	return pending
+}
+
+// outboxEntriesOf returns how many outbox entries are waiting for the given profiles.
+func outboxEntriesOf(t *testing.T, profileIds ...string) int {
+	t.Helper()
+	pending := pendingOutboxEntries(t, 0, 0)
+	count := 0
+	for _, profileId := range profileIds {
+		count += pending[profileId]
+	}
+	return count
+}
+
+func Test_Unification_Outbox(t *testing.T) {
+
+	restore := schemaService.OverrideValidateApplicationIdentifierForTest(
+		func(appID, org string) (error, bool) { return nil, true })
+	defer restore()
+
+	profileSvc := profileService.GetProfilesService()
+
+	t.Run("Profiles_written_while_the_queue_is_down_are_unified_once_it_is_up", func(t *testing.T) {
+		org := fmt.Sprintf("outbox-%d", time.Now().UnixNano())
+		setupEmailUnification(t, org)
+		defer cleanProfiles(profileSvc, org)
+
+		require.NoError(t, workers.StopProfileWorker())
+		running := false
+		defer func() {
+			if !running {
+				require.NoError(t, workers.StartProfileWorker())
+			}
+		}()
+
+		var created []string
+		for i := 0; i < 2; i++ {
+			profile, err := profileSvc.CreateProfile(mustUnmarshalProfile(fmt.Sprintf(
+				`{"identity_attributes":{"email":["outbox@%s.com"]},"traits":{"interests":["interest-%d"]}}`, org, i)), org)
+			require.NoError(t, err)
+			created = append(created, profile.ProfileId)
+		}
+
+		// The writes are committed together with their outbox entries, which wait for the queue.
+		require.Equal(t, 2, outboxEntriesOf(t, created...))
+		for _, profileId := range created {
+			profile, err := profileSvc.GetProfile(profileId)
+			require.NoError(t, err)
+			require.Nil(t, profile.MergedTo)
+		}
+
+		require.NoError(t, workers.StartProfileWorker())
+		running = true
+
+		require.Eventually(t, func() bool {
+			masters := map[string]bool{}
+			for _, profileId := range created {
+				profile, err := profileSvc.GetProfile(profileId)
+				if err != nil || profile == nil || profile.MergedTo == nil {
+					return false
+				}
+				masters[profile.MergedTo.ProfileId] = true
+			}
+			return len(masters) == 1
+		}, 30*time.Second, 200*time.Millisecond, "profiles should be unified once the queue is up")
+		require.Zero(t, outboxEntriesOf(t, created...))
+	})
+
+	t.Run("Burst_of_writes_to_a_profile_is_published_once", func(t *testing.T) {
+		const enqueued = `cds_queue_enqueued_total{provider="memory",queue="profile_unification"}`
+		org := fmt.Sprintf("outbox-burst-%d", time.Now().UnixNano())
+		setupEmailUnification(t, org)
+		defer cleanProfiles(profileSvc, org)
+
+		require.NoError(t, workers.StopProfileWorker())
+		running := false
+		defer func() {
+			if !running {
+				require.NoError(t, workers.StartProfileWorker())
+			}
+		}()
+
+		profile, err := profileSvc.CreateProfile(mustUnmarshalProfile(fmt.Sprintf(
+			`{"identity_attributes":{"email":["burst@%s.com"]}}`, org)), org)
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			_, err := profileSvc.UpdateProfile(profile.ProfileId, org, mustUnmarshalProfile(fmt.Sprintf(
+				`{"identity_attributes":{"email":["burst@%s.com"]},"traits":{"interests":["interest-%d"]}}`, org, i)))
+			require.NoError(t, err)
+		}
+
+		// Each write added an entry, and the profile is held back while it is being written.
+		require.Equal(t, 4, outboxEntriesOf(t, profile.ProfileId))
+		require.NotContains(t, pendingOutboxEntries(t, 60000, 60000), profile.ProfileId)
+
+		// A profile still being written is published with all of its entries once it has waited the maximum wait.
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, 4, pendingOutboxEntries(t, 60000, 50)[profile.ProfileId])
+
+		// Every profile waiting in the outbox is published as one message, whatever its number of entries.
+		pending := pendingOutboxEntries(t, 0, 0)
+		enqueuedBefore := metricValue(t, enqueued)
+		require.NoError(t, workers.StartProfileWorker())
+		running = true
+
+		require.Eventually(t, func() bool {
+			return outboxEntriesOf(t, profile.ProfileId) == 0
+		}, 30*time.Second, 200*time.Millisecond, "the outbox entries of the profile should be published")
+		require.Eventually(t, func() bool {
+			return metricValue(t, enqueued)-enqueuedBefore >= float64(len(pending))
+		}, 30*time.Second, 200*time.Millisecond)
+		require.Equal(t, float64(len(pending)), metricValue(t, enqueued)-enqueuedBefore,
+			"each pending profile should be enqueued once")
+	})
+
+	t.Run("Rolled_back_write_leaves_no_outbox_entry", func(t *testing.T) {
+		profileId := uuid.New().String()
+		now := time.Now().UTC()
+		profile := profileModel.Profile{
+			ProfileId: profileId,
+			OrgHandle: fmt.Sprintf("outbox-rollback-%d", now.UnixNano()),
+			ProfileStatus: &profileModel.ProfileStatus{
+				IsReferenceProfile: true,
+				ListProfile:        true,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		err := profileStore.WithTransaction(func(tx *sql.Tx) error {
+			require.NoError(t, profileStore.InsertProfileTx(tx, profile))
+			require.NoError(t, profileStore.InsertUnificationOutboxEntry(tx, profile))
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		stored, err := profileStore.GetProfile(profileId)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+		require.Zero(t, outboxEntriesOf(t, profileId))
+	})
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package kafkabrokerintegration
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+)
+
+// waitTimeout leaves room for the consumer group to rebalance.
+const waitTimeout = 30 * time.Second
+
+var testRedelivery = config.RedeliveryConfig{MaxAttempts: 3, InitialBackoff: 10, MaxBackoff: 20}
+
+func waitFor[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(waitTimeout):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+	var zero T
+	return zero
+}
+
+// queueConfig returns a config using topics and a consumer group of its own,
+// so that the tests do not see each other's messages.
+func queueConfig(redelivery config.RedeliveryConfig, workers int) config.Config {
+	suffix := uuid.New().String()
+	return config.Config{
+		MessageQueue: config.MessageQueueConfig{
+			Type: queue.TypeKafka,
+			Kafka: config.KafkaConfig{
+				Brokers:                 []string{broker},
+				ProfileConsumerGroup:    "cds-profile-unification-" + suffix,
+				SchemaSyncConsumerGroup: "cds-schema-sync-" + suffix,
+				ProfileTopic:            "cds-profile-unification-" + suffix,
+				SchemaSyncTopic:         "cds-schema-sync-" + suffix,
+			},
+			Redelivery: redelivery,
+			Workers:    workers,
+		},
+	}
+}
+
+func newProfileQueue(t *testing.T, cfg config.Config) queue.ProfileUnificationQueue {
+	t.Helper()
+	q, err := queue.NewProfileUnificationQueue(cfg)
+	require.NoError(t, err)
+	return q
+}
+
+func Test_KafkaBroker_ProcessesProfiles(t *testing.T) {
+	q := newProfileQueue(t, queueConfig(testRedelivery, 1))
+	defer q.Close()
+
+	processed := make(chan profileModel.UnificationMessage, 1)
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		processed <- profile
+		return nil
+	}))
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "kafka-org"}
+	require.NoError(t, q.Enqueue(profile))
+
+	require.Equal(t, profile.ProfileId, waitFor(t, processed, "profile").ProfileId)
+}
+
+// Test_KafkaBroker_ProcessesOrgInOrder runs several workers; the profiles of an org share a partition, which is
+// consumed by one of them, so they are still processed in order.
+func Test_KafkaBroker_ProcessesOrgInOrder(t *testing.T) {
+	q := newProfileQueue(t, queueConfig(testRedelivery, 3))
+	defer q.Close()
+
+	var mu sync.Mutex
+	var order []string
+	done := make(chan struct{})
+	var ids []string
+	for i := 0; i < 10; i++ {
+		ids = append(ids, uuid.New().String())
+	}
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, profile.ProfileId)
+		if len(order) == len(ids) {
+			close(done)
+		}
+		return nil
+	}))
+	for _, id := range ids {
+		require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: id, OrgHandle: "ordered-org"}))
+	}
+	waitFor(t, done, "all profiles")
+	require.Equal(t, ids, order)
+}
+
+func Test_KafkaBroker_RedeliversUncommitted(t *testing.T) {
+	cfg := queueConfig(config.RedeliveryConfig{MaxAttempts: 10, InitialBackoff: 60000, MaxBackoff: 60000}, 1)
+
+	first := newProfileQueue(t, cfg)
+	failed := make(chan string, 1)
+	require.NoError(t, first.Start(func(profile profileModel.UnificationMessage) error {
+		failed <- profile.ProfileId
+		return errors.New("database unavailable")
+	}))
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "rebalance-org"}
+	require.NoError(t, first.Enqueue(profile))
+	waitFor(t, failed, "first attempt")
+
+	// The first consumer leaves the group while backing off; the profile was not committed.
+	require.NoError(t, first.Close())
+
+	cfg.MessageQueue.Redelivery = testRedelivery
+	second := newProfileQueue(t, cfg)
+	defer second.Close()
+	processed := make(chan string, 1)
+	require.NoError(t, second.Start(func(p profileModel.UnificationMessage) error {
+		processed <- p.ProfileId
+		return nil
+	}))
+	require.Equal(t, profile.ProfileId, waitFor(t, processed, "redelivered profile"))
+}
+
+func Test_KafkaBroker_DeadLetters(t *testing.T) {
+	q := newProfileQueue(t, queueConfig(testRedelivery, 1))
+	defer q.Close()
+
+	deadLetters, err := q.DeadLetters()
+	require.NoError(t, err, "a dead-letter topic that does not exist yet has no dead letters")
+	require.Empty(t, deadLetters)
+
+	var mu sync.Mutex
+	healed := false
+	processed := make(chan string, 1)
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healed {
+			return errors.New("database unavailable")
+		}
+		processed <- profile.ProfileId
+		return nil
+	}))
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "dlq-org"}
+	require.NoError(t, q.Enqueue(profile))
+
+	require.Eventually(t, func() bool {
+		deadLetters, err = q.DeadLetters()
+		return err == nil && len(deadLetters) == 1
+	}, waitTimeout, 100*time.Millisecond)
+	require.Equal(t, profile.ProfileId, deadLetters[0].Profile.ProfileId)
+	require.Equal(t, 3, deadLetters[0].Attempts)
+
+	mu.Lock()
+	healed = true
+	mu.Unlock()
+
+	require.NoError(t, q.ReplayDeadLetter(deadLetters[0].Id))
+	require.Equal(t, profile.ProfileId, waitFor(t, processed, "replayed profile"))
+	deadLetters, err = q.DeadLetters()
+	require.NoError(t, err)
+	require.Empty(t, deadLetters)
+}
+
+func Test_KafkaBroker_SchemaSync(t *testing.T) {
+	q, err := queue.NewSchemaSyncQueue(queueConfig(testRedelivery, 1))
+	require.NoError(t, err)
+	defer q.Close()
+
+	processed := make(chan schemaModel.ProfileSchemaSync, 1)
+	require.NoError(t, q.Start(func(sync schemaModel.ProfileSchemaSync) {
+		processed <- sync
+	}))
+	require.NoError(t, q.Enqueue(schemaModel.ProfileSchemaSync{OrgId: "kafka-org", Event: "POST_ADD_LOCAL_CLAIM"}))
+
+	sync := waitFor(t, processed, "schema sync job")
+	require.Equal(t, "kafka-org", sync.OrgId)
+	require.Equal(t, "POST_ADD_LOCAL_CLAIM", sync.Event)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package kafkabrokerintegration contains tests of the Kafka queue provider
+// against a real broker. The test binary starts a Kafka testcontainer and
+// connects to it through the franz-go client adapter, as the server does.
+package kafkabrokerintegration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/wso2/identity-customer-data-service/internal/system/log"
+	_ "github.com/wso2/identity-customer-data-service/internal/system/queue/kafka/franz" // registers the Kafka client adapter
+	"github.com/wso2/identity-customer-data-service/test/setup"
+)
+
+// broker is the bootstrap server of the test Kafka container.
+var broker string
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	_ = log.Init("DEBUG")
+
+	kafka, err := setup.SetupTestKafka(ctx)
+	if err != nil {
+		fmt.Println("Failed to start test Kafka:", err)
+		os.Exit(1)
+	}
+	broker = kafka.Broker
+
+	code := m.Run()
+
+	_ = kafka.Container.Terminate(ctx)
+	os.Exit(code)
+}
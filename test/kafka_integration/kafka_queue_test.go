@@ -0,0 +1,327 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package kafkaintegration
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	profileModel "github.com/wso2/identity-customer-data-service/internal/profile/model"
+	schemaModel "github.com/wso2/identity-customer-data-service/internal/profile_schema/model"
+	"github.com/wso2/identity-customer-data-service/internal/system/config"
+	"github.com/wso2/identity-customer-data-service/internal/system/metrics"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/delivery"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/kafka"
+	"github.com/wso2/identity-customer-data-service/internal/system/queue/kafka/kafkatest"
+)
+
+const (
+	profileTopic = "cds-profile-unification"
+	group        = "cds-profile-unification"
+)
+
+var testPolicy = delivery.Policy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+
+func waitFor[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+	var zero T
+	return zero
+}
+
+func Test_KafkaQueue_ProcessesAndCommits(t *testing.T) {
+	broker := kafkatest.NewBroker(4)
+	q := kafka.NewProfileQueue(broker, config.KafkaConfig{}, testPolicy, 1)
+	defer q.Close()
+
+	processed := make(chan profileModel.UnificationMessage, 1)
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		processed <- profile
+		return nil
+	}))
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "kafka-org"}
+	require.NoError(t, q.Enqueue(profile))
+
+	require.Equal(t, profile.ProfileId, waitFor(t, processed, "profile").ProfileId)
+	partition := broker.PartitionOf(profile.OrgHandle)
+	require.Eventually(t, func() bool {
+		return broker.Committed(group, profileTopic, partition) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_KafkaQueue_PartitionsByKey(t *testing.T) {
+	broker := kafkatest.NewBroker(8)
+
+	t.Run("By_org", func(t *testing.T) {
+		q := kafka.NewProfileQueue(broker, config.KafkaConfig{ProfileTopic: "by-org"}, testPolicy, 1)
+		defer q.Close()
+		for i := 0; i < 5; i++ {
+			require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "org-a"}))
+		}
+		require.Len(t, broker.Messages("by-org", broker.PartitionOf("org-a")), 5)
+	})
+
+	t.Run("By_profile", func(t *testing.T) {
+		q := kafka.NewProfileQueue(broker, config.KafkaConfig{ProfileTopic: "by-profile",
+			PartitionKey: kafka.PartitionByProfile}, testPolicy, 1)
+		defer q.Close()
+		profileId := uuid.New().String()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: profileId, OrgHandle: "org-a"}))
+		}
+		require.Len(t, broker.Messages("by-profile", broker.PartitionOf(profileId)), 3)
+	})
+}
+
+// Test_KafkaQueue_ProcessesOrgInOrder runs several workers; the profiles of an org share a partition, which is
+// consumed by one of them, so they are still processed in order.
+func Test_KafkaQueue_ProcessesOrgInOrder(t *testing.T) {
+	broker := kafkatest.NewBroker(4)
+	q := kafka.NewProfileQueue(broker, config.KafkaConfig{}, testPolicy, 3)
+	defer q.Close()
+
+	var mu sync.Mutex
+	var order []string
+	done := make(chan struct{})
+	var ids []string
+	for i := 0; i < 10; i++ {
+		ids = append(ids, uuid.New().String())
+	}
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, profile.ProfileId)
+		if len(order) == len(ids) {
+			close(done)
+		}
+		return nil
+	}))
+	for _, id := range ids {
+		require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: id, OrgHandle: "ordered-org"}))
+	}
+	waitFor(t, done, "all profiles")
+	require.Equal(t, ids, order)
+}
+
+func Test_KafkaQueue_ConsumerGroupSharesPartitions(t *testing.T) {
+	broker := kafkatest.NewBroker(6)
+	const profiles = 30
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	byConsumer := map[int]int{}
+	done := make(chan struct{})
+	for c := 0; c < 3; c++ {
+		c := c
+		consumer := kafka.NewProfileQueue(broker, config.KafkaConfig{}, testPolicy, 1)
+		defer consumer.Close()
+		require.NoError(t, consumer.Start(func(profile profileModel.UnificationMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			seen[profile.ProfileId]++
+			byConsumer[c]++
+			if len(seen) == profiles {
+				close(done)
+			}
+			return nil
+		}))
+	}
+	producer := kafka.NewProfileQueue(broker, config.KafkaConfig{}, testPolicy, 1)
+	for i := 0; i < profiles; i++ {
+		require.NoError(t, producer.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String(),
+			OrgHandle: uuid.New().String()}))
+	}
+
+	waitFor(t, done, "all profiles")
+	mu.Lock()
+	defer mu.Unlock()
+	for id, count := range seen {
+		require.Equal(t, 1, count, "profile %s processed more than once", id)
+	}
+	require.Len(t, byConsumer, 3, "every consumer should own partitions")
+}
+
+func Test_KafkaQueue_RedeliversUncommittedAfterRebalance(t *testing.T) {
+	broker := kafkatest.NewBroker(1)
+	slowPolicy := delivery.Policy{MaxAttempts: 10, InitialBackoff: time.Minute, MaxBackoff: time.Minute}
+
+	first := kafka.NewProfileQueue(broker, config.KafkaConfig{}, slowPolicy, 1)
+	failed := make(chan string, 1)
+	require.NoError(t, first.Start(func(profile profileModel.UnificationMessage) error {
+		failed <- profile.ProfileId
+		return errors.New("database unavailable")
+	}))
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "rebalance-org"}
+	require.NoError(t, first.Enqueue(profile))
+	waitFor(t, failed, "first attempt")
+
+	// The first consumer stops while backing off; the profile was not committed.
+	require.NoError(t, first.Close())
+	require.Equal(t, int64(0), broker.Committed(group, profileTopic, 0))
+
+	second := kafka.NewProfileQueue(broker, config.KafkaConfig{}, testPolicy, 1)
+	defer second.Close()
+	processed := make(chan string, 1)
+	require.NoError(t, second.Start(func(p profileModel.UnificationMessage) error {
+		processed <- p.ProfileId
+		return nil
+	}))
+	require.Equal(t, profile.ProfileId, waitFor(t, processed, "redelivered profile"))
+	require.Eventually(t, func() bool {
+		return broker.Committed(group, profileTopic, 0) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_KafkaQueue_DeadLetters(t *testing.T) {
+	broker := kafkatest.NewBroker(2)
+	q := kafka.NewProfileQueue(broker, config.KafkaConfig{}, testPolicy, 1)
+	defer q.Close()
+
+	var mu sync.Mutex
+	healed := false
+	attempts := 0
+	processed := make(chan string, 1)
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if !healed {
+			return errors.New("database unavailable")
+		}
+		processed <- profile.ProfileId
+		return nil
+	}))
+
+	// The first write of the dead letter fails too; it must be retried rather than committed past.
+	profile := profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "dlq-org"}
+	require.NoError(t, q.Enqueue(profile))
+	broker.FailProduce(1)
+
+	var deadLetters []queue.DeadLetter
+	var err error
+	require.Eventually(t, func() bool {
+		deadLetters, err = q.DeadLetters()
+		return err == nil && len(deadLetters) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, profile.ProfileId, deadLetters[0].Profile.ProfileId)
+	require.Equal(t, 3, deadLetters[0].Attempts)
+	require.Equal(t, "database unavailable", deadLetters[0].Error)
+	require.Eventually(t, func() bool {
+		return broker.Committed(group, profileTopic, broker.PartitionOf(profile.OrgHandle)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, 3, attempts)
+	healed = true
+	mu.Unlock()
+
+	require.NoError(t, q.ReplayDeadLetter(deadLetters[0].Id))
+	require.Equal(t, profile.ProfileId, waitFor(t, processed, "replayed profile"))
+	deadLetters, err = q.DeadLetters()
+	require.NoError(t, err)
+	require.Empty(t, deadLetters)
+	require.ErrorIs(t, q.ReplayDeadLetter(uuid.New().String()), queue.ErrDeadLetterNotFound)
+}
+
+func Test_KafkaQueue_SchemaSync(t *testing.T) {
+	broker := kafkatest.NewBroker(2)
+	q := kafka.NewSchemaSyncQueue(broker, config.KafkaConfig{})
+	defer q.Close()
+
+	processed := make(chan schemaModel.ProfileSchemaSync, 1)
+	require.NoError(t, q.Start(func(sync schemaModel.ProfileSchemaSync) {
+		processed <- sync
+	}))
+	require.NoError(t, q.Enqueue(schemaModel.ProfileSchemaSync{OrgId: "kafka-org", Event: "POST_ADD_LOCAL_CLAIM"}))
+
+	sync := waitFor(t, processed, "schema sync job")
+	require.Equal(t, "kafka-org", sync.OrgId)
+	require.Equal(t, "POST_ADD_LOCAL_CLAIM", sync.Event)
+
+	// The schema sync topic is consumed by a group of its own, apart from the profile group.
+	partition := broker.PartitionOf("kafka-org")
+	require.Eventually(t, func() bool {
+		return broker.Committed("cds-schema-sync", "cds-schema-sync", partition) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Zero(t, broker.Committed(group, "cds-schema-sync", partition))
+}
+
+// queueMetric returns the value of the series of the kafka provider in the current metrics, or 0 when it does not
+// exist.
+func queueMetric(t *testing.T, name string) float64 {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, metrics.Write(&buf))
+	series := name + `{provider="kafka",queue="profile_unification"}`
+	match := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(series) + ` (\S+)$`).FindStringSubmatch(buf.String())
+	if match == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	require.NoError(t, err)
+	return value
+}
+
+func Test_KafkaQueue_Metrics(t *testing.T) {
+	broker := kafkatest.NewBroker(2)
+	q := kafka.NewProfileQueue(broker, config.KafkaConfig{ProfileTopic: "metrics"}, testPolicy, 1)
+	defer q.Close()
+
+	enqueued := queueMetric(t, "cds_queue_enqueued_total")
+	failures := queueMetric(t, "cds_queue_enqueue_failures_total")
+	deadLettered := queueMetric(t, "cds_queue_dead_lettered_total")
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String(),
+			OrgHandle: "metrics-org"}))
+	}
+	broker.FailProduce(1)
+	require.Error(t, q.Enqueue(profileModel.UnificationMessage{ProfileId: uuid.New().String(), OrgHandle: "metrics-org"}))
+	require.Equal(t, enqueued+3, queueMetric(t, "cds_queue_enqueued_total"))
+	require.Equal(t, failures+1, queueMetric(t, "cds_queue_enqueue_failures_total"))
+	require.Equal(t, float64(3), queueMetric(t, "cds_queue_depth"), "the depth is the lag of the consumer group")
+
+	require.NoError(t, q.Start(func(profile profileModel.UnificationMessage) error {
+		return errors.New("database unavailable")
+	}))
+	require.Eventually(t, func() bool {
+		return queueMetric(t, "cds_queue_depth") == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, deadLettered+3, queueMetric(t, "cds_queue_dead_lettered_total"))
+}
+
+func Test_KafkaQueue_RequiresClient(t *testing.T) {
+	_, err := queue.NewProfileUnificationQueue(config.Config{
+		MessageQueue: config.MessageQueueConfig{Type: queue.TypeKafka},
+	})
+	require.ErrorIs(t, err, kafka.ErrNoClient)
+}
@@ -32,6 +32,7 @@ const (
 	activemqUser      = "admin"
 	activemqPassword  = "admin"
 	activemqSTOMPPort = "61613/tcp"
+	activemqWebPort   = "8161/tcp"
 )
 
 // TestActiveMQ holds the running testcontainer and the STOMP connection
@@ -39,9 +40,11 @@ const (
 type TestActiveMQ struct {
 	Container testcontainers.Container
 	// Addr is the STOMP endpoint in "host:port" format.
-	Addr     string
-	Username string
-	Password string
+	Addr string
+	// ManagementURL is the base URL of the web console.
+	ManagementURL string
+	Username      string
+	Password      string
 }
 
 // SetupTestActiveMQ starts an Apache ActiveMQ Classic container and returns a
@@ -49,7 +52,7 @@ type TestActiveMQ struct {
 func SetupTestActiveMQ(ctx context.Context) (*TestActiveMQ, error) {
 	req := testcontainers.ContainerRequest{
 		Image:        activemqImage,
-		ExposedPorts: []string{activemqSTOMPPort},
+		ExposedPorts: []string{activemqSTOMPPort, activemqWebPort},
 		// Wait until the STOMP connector is fully ready, not just until the
 		// TCP port is open (which can happen before the protocol handler is
 		// initialised, causing "connection reset by peer" errors).
@@ -75,13 +78,20 @@ func SetupTestActiveMQ(ctx context.Context) (*TestActiveMQ, error) {
 		return nil, fmt.Errorf("failed to get ActiveMQ STOMP port: %w", err)
 	}
 
+	webPort, err := container.MappedPort(ctx, "8161")
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get ActiveMQ web console port: %w", err)
+	}
+
 	addr := fmt.Sprintf("%s:%s", host, port.Port())
 	log.Printf("ActiveMQ container started – STOMP address: %s", addr)
 
 	return &TestActiveMQ{
-		Container: container,
-		Addr:      addr,
-		Username:  activemqUser,
-		Password:  activemqPassword,
+		Container:     container,
+		Addr:          addr,
+		ManagementURL: fmt.Sprintf("http://%s:%s", host, webPort.Port()),
+		Username:      activemqUser,
+		Password:      activemqPassword,
 	}, nil
 }
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2026, WSO2 LLC. (http://www.wso2.com).
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package setup
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	kafkaImage       = "apache/kafka:3.9.1"
+	kafkaPort        = "9092/tcp"
+	kafkaStartScript = "/tmp/cds-start-kafka.sh"
+)
+
+// TestKafka holds the running testcontainer and the bootstrap address of the
+// test Kafka broker.
+type TestKafka struct {
+	Container testcontainers.Container
+	// Broker is the bootstrap server in "host:port" format.
+	Broker string
+}
+
+// SetupTestKafka starts a single-node Apache Kafka container in KRaft mode and
+// returns a TestKafka whose Broker field points to its client listener. The
+// broker must advertise the mapped port, which is only known once the
+// container runs, so the container waits for a start script that is copied in
+// after it started.
+func SetupTestKafka(ctx context.Context) (*TestKafka, error) {
+	req := testcontainers.ContainerRequest{
+		Image:        kafkaImage,
+		ExposedPorts: []string{kafkaPort},
+		Env: map[string]string{
+			"KAFKA_NODE_ID":                                  "1",
+			"KAFKA_PROCESS_ROLES":                            "broker,controller",
+			"KAFKA_LISTENERS":                                "PLAINTEXT://0.0.0.0:9092,BROKER://0.0.0.0:9094,CONTROLLER://0.0.0.0:9093",
+			"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "PLAINTEXT:PLAINTEXT,BROKER:PLAINTEXT,CONTROLLER:PLAINTEXT",
+			"KAFKA_INTER_BROKER_LISTENER_NAME":               "BROKER",
+			"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+			"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9093",
+			"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+			"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+			"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+			"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+			"KAFKA_NUM_PARTITIONS":                           "4",
+		},
+		Entrypoint: []string{"sh"},
+		Cmd:        []string{"-c", fmt.Sprintf("while [ ! -f %[1]s ]; do sleep 0.1; done; sh %[1]s", kafkaStartScript)},
+		LifecycleHooks: []testcontainers.ContainerLifecycleHooks{{
+			PostStarts: []testcontainers.ContainerHook{
+				func(ctx context.Context, container testcontainers.Container) error {
+					host, err := container.Host(ctx)
+					if err != nil {
+						return err
+					}
+					port, err := container.MappedPort(ctx, "9092")
+					if err != nil {
+						return err
+					}
+					script := fmt.Sprintf("export KAFKA_ADVERTISED_LISTENERS=PLAINTEXT://%s:%s,BROKER://localhost:9094\n"+
+						"exec /etc/kafka/docker/run\n", host, port.Port())
+					return container.CopyToContainer(ctx, []byte(script), kafkaStartScript, 0o755)
+				},
+			},
+		}},
+		WaitingFor: wait.ForLog("Kafka Server started"),
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start Kafka container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get Kafka container host: %w", err)
+	}
+	port, err := container.MappedPort(ctx, "9092")
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get Kafka port: %w", err)
+	}
+
+	broker := fmt.Sprintf("%s:%s", host, port.Port())
+	log.Printf("Kafka container started – bootstrap server: %s", broker)
+
+	return &TestKafka{
+		Container: container,
+		Broker:    broker,
+	}, nil
+}